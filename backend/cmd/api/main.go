@@ -8,13 +8,20 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // embedded zoneinfo for user timezones
 
 	"github.com/bytetrack/backend/internal/api/handler"
 	"github.com/bytetrack/backend/internal/api/middleware"
 	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/cache"
 	"github.com/bytetrack/backend/internal/infrastructure/config"
 	"github.com/bytetrack/backend/internal/infrastructure/database"
+	"github.com/bytetrack/backend/internal/infrastructure/notify"
+	"github.com/bytetrack/backend/internal/infrastructure/openfoodfacts"
+	"github.com/bytetrack/backend/internal/infrastructure/report"
 	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/bytetrack/backend/internal/infrastructure/storage"
+	"github.com/bytetrack/backend/internal/infrastructure/usda"
 	"github.com/bytetrack/backend/internal/pkg/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
@@ -40,23 +47,138 @@ func main() {
 		// Don't fail on migration error, as they might have already been run
 	}
 
+	// Initialize blob storage
+	blobStore, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// Initialize the cache
+	cacheStore, err := cache.New(cfg, db.Pool)
+	if err != nil {
+		log.Fatalf("Failed to initialize cache: %v", err)
+	}
+	defer cacheStore.Close()
+
 	// Initialize dependencies
 	jwtManager := jwt.New(cfg)
 	userRepo := repository.NewUserRepository(db.Pool)
 	mealRepo := repository.NewMealRepository(db.Pool)
+	waterRepo := repository.NewWaterRepository(db.Pool)
+	exerciseRepo := repository.NewExerciseRepository(db.Pool)
+	weightRepo := repository.NewWeightRepository(db.Pool)
+	bodyRepo := repository.NewBodyRepository(db.Pool)
+	achievementRepo := repository.NewAchievementRepository(db.Pool)
+	notificationRepo := repository.NewNotificationRepository(db.Pool)
+	fastingRepo := repository.NewFastingRepository(db.Pool)
+	reportRepo := repository.NewReportRepository(db.Pool)
+	webhookRepo := repository.NewWebhookRepository(db.Pool)
+	offCacheRepo := repository.NewOFFCacheRepository(db.Pool)
+	usdaFoodRepo := repository.NewUSDAFoodRepository(db.Pool)
+	transactor := repository.NewTransactor(db.Pool)
+
+	// Initialize notification delivery
+	notifiers, err := notify.New(cfg, notificationRepo.DeleteSubscriptionsByEndpoint)
+	if err != nil {
+		log.Fatalf("Failed to initialize notifications: %v", err)
+	}
+
+	// Initialize report rendering
+	pdfRenderer, err := report.NewPDFRenderer(cfg.Report.FontPath)
+	if err != nil {
+		log.Fatalf("Failed to initialize reports: %v", err)
+	}
+
 	authService := service.NewAuthService(userRepo, jwtManager)
 	calorieService := service.NewCalorieService()
-	onboardingService := service.NewOnboardingService(userRepo, calorieService)
-	mealService := service.NewMealService(mealRepo)
-	foodService := service.NewFoodService(mealRepo, cfg.OFF.CacheEnabled)
+	statsCache := service.NewDailyStatsCache(cacheStore, cfg.Cache.StatsTTL)
+	webhookService := service.NewWebhookService(webhookRepo, transactor, cfg.Webhook.Timeout, cfg.Webhook.MaxAttempts, cfg.Webhook.Retention)
+	onboardingService := service.NewOnboardingService(userRepo, calorieService, webhookService)
+	photoService := service.NewPhotoService(mealRepo, blobStore, cfg.Photo.URLSecret, cfg.Photo.URLTTL, cfg.Photo.MaxUploadSize, cfg.Photo.MaxPixels, statsCache)
+	achievementService := service.NewAchievementService(achievementRepo, mealRepo, waterRepo, weightRepo, userRepo, calorieService)
+	waterService := service.NewWaterService(waterRepo, userRepo, calorieService, achievementService)
+	exerciseService := service.NewExerciseService(exerciseRepo, userRepo, calorieService)
+	weightService := service.NewWeightService(weightRepo, userRepo, calorieService, achievementService, webhookService)
+	bodyService := service.NewBodyService(bodyRepo, userRepo, calorieService, webhookService)
+	tdeeService := service.NewTDEEService(mealRepo, userRepo, weightService, calorieService, webhookService, cfg.TDEE.EstimateWeeks)
+	notificationService := service.NewNotificationService(notificationRepo, mealRepo, waterRepo, weightRepo, userRepo, calorieService, notifiers, cfg.Notification.VAPIDPublicKey)
+	goalService := service.NewGoalService(mealRepo, weightRepo, userRepo, calorieService)
+	fastingService := service.NewFastingService(fastingRepo, mealRepo, userRepo)
+	mealService := service.NewMealService(mealRepo, userRepo, calorieService, photoService, waterService, exerciseService, achievementService, fastingService, webhookService, statsCache)
+	var offCache *service.OpenFoodFactsCacheService
+	if cfg.OFF.CacheEnabled {
+		offCache = service.NewOpenFoodFactsCacheService(offCacheRepo, cfg.OFF.CacheMaxEntries, cfg.OFF.CacheTTL, cfg.OFF.CacheStaleTTL)
+	}
+	offClient := openfoodfacts.New(openfoodfacts.Options{
+		BaseURL:          cfg.OFF.BaseURL,
+		Timeout:          cfg.OFF.Timeout,
+		MaxRetries:       cfg.OFF.MaxRetries,
+		BreakerThreshold: cfg.OFF.BreakerThreshold,
+		BreakerCooldown:  cfg.OFF.BreakerCooldown,
+	})
+	thaiProvider := service.NewThaiFoodProvider(mealRepo, cacheStore, cfg.Cache.CatalogTTL)
+	if err := thaiProvider.IndexCatalog(context.Background()); err != nil {
+		log.Printf("Warning: Failed to index Thai foods for search: %v", err)
+	}
+	offProvider := service.NewOpenFoodFactsProvider(offClient, offCache, cacheStore, cfg.Cache.SearchTTL)
+	foodAggregator := service.NewFoodAggregator()
+	foodAggregator.Register(service.NewCustomFoodProvider(mealRepo), cfg.Food.LocalTimeout)
+	foodAggregator.Register(thaiProvider, cfg.Food.LocalTimeout)
+	if cfg.USDA.Enabled {
+		// Without an API key only foods imported with usda-import are searched
+		var usdaClient *usda.Client
+		if cfg.USDA.APIKey != "" {
+			usdaClient = usda.New(usda.Options{
+				BaseURL:   cfg.USDA.BaseURL,
+				APIKey:    cfg.USDA.APIKey,
+				Timeout:   cfg.USDA.Timeout,
+				DataTypes: cfg.USDA.DataTypes,
+			})
+		}
+		foodAggregator.Register(service.NewUSDAProvider(usdaClient, usdaFoodRepo, cacheStore, cfg.Cache.SearchTTL), cfg.USDA.ProviderTimeout)
+	}
+	foodAggregator.Register(offProvider, cfg.OFF.ProviderTimeout)
+	foodService := service.NewFoodService(foodAggregator, thaiProvider, offProvider, mealRepo, userRepo)
+	reportService := service.NewReportService(mealService, mealRepo, weightService, userRepo, reportRepo, calorieService, pdfRenderer, notifiers, cfg.Report.SendHour)
+	diaryService := service.NewDiaryService(mealRepo, userRepo, transactor, photoService, achievementService, webhookService, statsCache)
+	trashService := service.NewTrashService(mealRepo, photoService, cfg.Trash.Retention, achievementService, webhookService, statsCache)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
 	onboardingHandler := handler.NewOnboardingHandler(onboardingService)
 	mealHandler := handler.NewMealHandler(mealService)
+	diaryHandler := handler.NewDiaryHandler(diaryService)
 	foodHandler := handler.NewFoodHandler(foodService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	photoHandler := handler.NewPhotoHandler(photoService)
+	waterHandler := handler.NewWaterHandler(waterService)
+	exerciseHandler := handler.NewExerciseHandler(exerciseService)
+	weightHandler := handler.NewWeightHandler(weightService)
+	tdeeHandler := handler.NewTDEEHandler(tdeeService)
+	bodyHandler := handler.NewBodyHandler(bodyService)
+	achievementHandler := handler.NewAchievementHandler(achievementService)
+	goalHandler := handler.NewGoalHandler(goalService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	fastingHandler := handler.NewFastingHandler(fastingService)
+	reportHandler := handler.NewReportHandler(reportService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
 	healthHandler := handler.NewHealthHandler(db)
 
+	// Background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go trashService.StartPurger(jobsCtx, cfg.Trash.PurgeInterval)
+	go tdeeService.StartAdjuster(jobsCtx, cfg.TDEE.AdjustInterval)
+	go notificationService.StartScheduler(jobsCtx, cfg.Notification.SchedulerInterval)
+	go reportService.StartScheduler(jobsCtx, cfg.Report.SchedulerInterval)
+	go webhookService.StartDispatcher(jobsCtx, cfg.Webhook.DispatchInterval)
+	if offCache != nil {
+		go offCache.StartCleanup(jobsCtx, cfg.OFF.CacheCleanupInterval)
+	}
+	if cleaner, ok := cacheStore.(cache.Cleaner); ok {
+		go cache.StartCleanup(jobsCtx, cleaner, cfg.Cache.CleanupInterval)
+	}
+
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		AppName:               "ByteTrack API",
@@ -64,6 +186,7 @@ func main() {
 		ReadTimeout:           30 * time.Second,
 		WriteTimeout:          30 * time.Second,
 		IdleTimeout:           60 * time.Second,
+		BodyLimit:             int(cfg.Photo.MaxUploadSize) + 1<<20, // room for multipart overhead
 	})
 
 	// Global middleware
@@ -106,18 +229,30 @@ func main() {
 	meals.Get("/", mealHandler.GetMeals)
 	meals.Post("/", mealHandler.CreateMeal)
 	meals.Get("/daily/:date", mealHandler.GetDailyStats)
+	meals.Get("/stats", mealHandler.GetRangeStats)
+	meals.Get("/export", diaryHandler.Export)
+	meals.Post("/import", diaryHandler.Import)
 	meals.Get("/:id", mealHandler.GetMealByID)
 	meals.Put("/:id", mealHandler.UpdateMeal)
 	meals.Delete("/:id", mealHandler.DeleteMeal)
+	meals.Post("/:id/photo", photoHandler.UploadMealPhoto)
+	meals.Delete("/:id/photo", photoHandler.DeleteMealPhoto)
+
+	// Photo routes (authorized by signed URL)
+	v1.Get("/photos/*", photoHandler.GetPhoto)
 
 	// Food routes (protected)
 	foods := v1.Group("/foods")
 	foods.Get("/categories", foodHandler.GetCategories) // Public endpoint
+	foods.Get("/nutrients", foodHandler.GetNutrients)   // Public endpoint
 	foodsAuth := v1.Group("/foods")
 	foodsAuth.Use(middleware.AuthMiddleware(jwtManager, authService))
 	foodsAuth.Get("/search", foodHandler.SearchFoods)
 	foodsAuth.Get("/thai", foodHandler.GetThaiFoods)
+	foodsAuth.Get("/recent", foodHandler.GetRecentFoods)
+	foodsAuth.Get("/frequent", foodHandler.GetFrequentFoods)
 	foodsAuth.Get("/barcode/:barcode", foodHandler.LookupBarcode)
+	foodsAuth.Get("/:id", foodHandler.GetFood)
 
 	// Favorite foods routes (protected)
 	favorites := v1.Group("/favorites")
@@ -133,6 +268,121 @@ func main() {
 	customFoods.Post("/", mealHandler.CreateCustomFood)
 	customFoods.Delete("/:id", mealHandler.DeleteCustomFood)
 
+	// Water routes (protected)
+	water := v1.Group("/water")
+	water.Use(middleware.AuthMiddleware(jwtManager, authService))
+	water.Post("/", waterHandler.LogWater)
+	water.Get("/daily/:date", waterHandler.GetDailyHydration)
+	water.Get("/stats", waterHandler.GetRangeStats)
+	water.Get("/containers", waterHandler.GetContainers)
+	water.Post("/containers", waterHandler.CreateContainer)
+	water.Delete("/containers/:id", waterHandler.DeleteContainer)
+	water.Delete("/:id", waterHandler.DeleteLog)
+
+	// Exercise routes (protected)
+	exercises := v1.Group("/exercises")
+	exercises.Use(middleware.AuthMiddleware(jwtManager, authService))
+	exercises.Get("/", exerciseHandler.GetExercises)
+	exercises.Post("/", exerciseHandler.CreateExercise)
+	exercises.Get("/activities", exerciseHandler.GetActivities)
+	exercises.Post("/activities", exerciseHandler.CreateActivity)
+	exercises.Delete("/activities/:id", exerciseHandler.DeleteActivity)
+	exercises.Get("/:id", exerciseHandler.GetExerciseByID)
+	exercises.Put("/:id", exerciseHandler.UpdateExercise)
+	exercises.Delete("/:id", exerciseHandler.DeleteExercise)
+
+	// Weight routes (protected)
+	weight := v1.Group("/weight")
+	weight.Use(middleware.AuthMiddleware(jwtManager, authService))
+	weight.Get("/", weightHandler.GetEntries)
+	weight.Post("/", weightHandler.CreateEntry)
+	weight.Get("/trend", weightHandler.GetTrend)
+	weight.Get("/:id", weightHandler.GetEntryByID)
+	weight.Put("/:id", weightHandler.UpdateEntry)
+	weight.Delete("/:id", weightHandler.DeleteEntry)
+
+	// Body composition routes (protected)
+	body := v1.Group("/body")
+	body.Use(middleware.AuthMiddleware(jwtManager, authService))
+	body.Get("/", bodyHandler.GetMeasurements)
+	body.Post("/", bodyHandler.CreateMeasurement)
+	body.Get("/composition", bodyHandler.GetComposition)
+	body.Put("/bmr-formula", bodyHandler.SetBMRFormula)
+	body.Get("/:id", bodyHandler.GetMeasurementByID)
+	body.Put("/:id", bodyHandler.UpdateMeasurement)
+	body.Delete("/:id", bodyHandler.DeleteMeasurement)
+
+	// Achievement routes (protected)
+	achievements := v1.Group("/achievements")
+	achievements.Use(middleware.AuthMiddleware(jwtManager, authService))
+	achievements.Get("/", achievementHandler.GetAchievements)
+	achievements.Get("/unlocked", achievementHandler.GetUnlocked)
+	achievements.Get("/streaks", achievementHandler.GetStreaks)
+
+	// Goal routes (protected)
+	goals := v1.Group("/goals")
+	goals.Use(middleware.AuthMiddleware(jwtManager, authService))
+	goals.Get("/progress", goalHandler.GetProgress)
+
+	// Notification routes (protected)
+	notifications := v1.Group("/notifications")
+	notifications.Use(middleware.AuthMiddleware(jwtManager, authService))
+	notifications.Get("/reminders", notificationHandler.GetReminders)
+	notifications.Post("/reminders", notificationHandler.CreateReminder)
+	notifications.Put("/reminders/:id", notificationHandler.UpdateReminder)
+	notifications.Delete("/reminders/:id", notificationHandler.DeleteReminder)
+	notifications.Get("/settings", notificationHandler.GetSettings)
+	notifications.Put("/settings", notificationHandler.UpdateSettings)
+	notifications.Get("/vapid-public-key", notificationHandler.GetVAPIDPublicKey)
+	notifications.Post("/subscriptions", notificationHandler.Subscribe)
+	notifications.Delete("/subscriptions", notificationHandler.Unsubscribe)
+	notifications.Post("/test", notificationHandler.SendTest)
+
+	// Fasting routes (protected)
+	fasting := v1.Group("/fasting")
+	fasting.Use(middleware.AuthMiddleware(jwtManager, authService))
+	fasting.Get("/protocols", fastingHandler.GetProtocols)
+	fasting.Get("/current", fastingHandler.GetCurrent)
+	fasting.Post("/start", fastingHandler.Start)
+	fasting.Post("/end", fastingHandler.End)
+	fasting.Get("/history", fastingHandler.GetHistory)
+	fasting.Get("/streak", fastingHandler.GetStreak)
+	fasting.Get("/settings", fastingHandler.GetSettings)
+	fasting.Put("/settings", fastingHandler.UpdateSettings)
+	fasting.Delete("/:id", fastingHandler.Delete)
+
+	// Report routes (protected)
+	reports := v1.Group("/reports")
+	reports.Use(middleware.AuthMiddleware(jwtManager, authService))
+	reports.Get("/", reportHandler.GetReport)
+	reports.Get("/schedule", reportHandler.GetSchedule)
+	reports.Put("/schedule", reportHandler.UpdateSchedule)
+
+	// Webhook routes (protected); app webhooks are managed with the admin token
+	webhooks := v1.Group("/webhooks")
+	webhooks.Use(middleware.AuthMiddleware(jwtManager, authService))
+	registerWebhookRoutes(webhooks, webhookHandler)
+	adminWebhooks := v1.Group("/admin/webhooks")
+	adminWebhooks.Use(middleware.AdminMiddleware(cfg.Admin.Token))
+	registerWebhookRoutes(adminWebhooks, webhookHandler)
+
+	// Admin cache routes
+	adminCache := v1.Group("/admin/cache")
+	adminCache.Use(middleware.AdminMiddleware(cfg.Admin.Token))
+	adminCache.Get("/barcodes", foodHandler.GetBarcodeCacheStats)
+
+	// Adaptive TDEE routes (protected)
+	tdee := v1.Group("/tdee")
+	tdee.Use(middleware.AuthMiddleware(jwtManager, authService))
+	tdee.Get("/estimate", tdeeHandler.GetEstimate)
+	tdee.Put("/adaptive", tdeeHandler.SetAdaptive)
+
+	// Trash routes (protected)
+	trash := v1.Group("/trash")
+	trash.Use(middleware.AuthMiddleware(jwtManager, authService))
+	trash.Get("/", trashHandler.GetTrash)
+	trash.Post("/:id/restore", trashHandler.Restore)
+
 	// Start server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
 
@@ -150,6 +400,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -160,3 +411,19 @@ func main() {
 
 	log.Println("Server stopped")
 }
+
+// registerWebhookRoutes registers the webhook routes on a group, which serves
+// either the authenticated user's webhooks or app webhooks
+func registerWebhookRoutes(router fiber.Router, h *handler.WebhookHandler) {
+	router.Get("/", h.GetWebhooks)
+	router.Post("/", h.CreateWebhook)
+	router.Get("/events", h.GetEventTypes)
+	router.Get("/:id", h.GetWebhook)
+	router.Put("/:id", h.UpdateWebhook)
+	router.Delete("/:id", h.DeleteWebhook)
+	router.Post("/:id/rotate-secret", h.RotateSecret)
+	router.Post("/:id/replay", h.ReplayDead)
+	router.Get("/:id/deliveries", h.GetDeliveries)
+	router.Get("/:id/deliveries/:deliveryId", h.GetDelivery)
+	router.Post("/:id/deliveries/:deliveryId/replay", h.ReplayDelivery)
+}
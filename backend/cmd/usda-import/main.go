@@ -0,0 +1,93 @@
+// Command usda-import loads a USDA FoodData Central download into the
+// usda_foods table so foods can be searched without an API key.
+//
+//	usda-import -json FoodData_Central_foundation_food_json.json
+//	usda-import -csv FoodData_Central_sr_legacy_food_csv/
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/config"
+	"github.com/bytetrack/backend/internal/infrastructure/database"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/bytetrack/backend/internal/infrastructure/usda"
+)
+
+func main() {
+	jsonPath := flag.String("json", "", "FoodData Central JSON download")
+	csvDir := flag.String("csv", "", "directory of an extracted FoodData Central CSV download")
+	batchSize := flag.Int("batch", 500, "foods written per insert")
+	flag.Parse()
+
+	if (*jsonPath == "") == (*csvDir == "") {
+		log.Fatal("Exactly one of -json or -csv is required")
+	}
+	if *batchSize <= 0 {
+		log.Fatal("-batch must be positive")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.RunMigrations(ctx, database.GetMigrations()); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	provider := service.NewUSDAProvider(nil, repository.NewUSDAFoodRepository(db.Pool), nil, 0)
+
+	imported := 0
+	batch := make([]*usda.Food, 0, *batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := provider.Import(ctx, batch)
+		if err != nil {
+			return err
+		}
+		imported += n
+		batch = batch[:0]
+		log.Printf("Imported %d foods", imported)
+		return nil
+	}
+	add := func(f *usda.Food) error {
+		batch = append(batch, f)
+		if len(batch) < *batchSize {
+			return nil
+		}
+		return flush()
+	}
+
+	if *jsonPath != "" {
+		f, err := os.Open(*jsonPath)
+		if err != nil {
+			log.Fatalf("Failed to open dataset: %v", err)
+		}
+		defer f.Close()
+		err = usda.ReadJSONDataset(f, add)
+	} else {
+		err = usda.ReadCSVDataset(*csvDir, add)
+	}
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		log.Fatalf("Import failed after %d foods: %v", imported, err)
+	}
+
+	log.Printf("Done: %d foods imported", imported)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AchievementHandler handles streak and achievement HTTP requests
+type AchievementHandler struct {
+	achievementService *service.AchievementService
+}
+
+// NewAchievementHandler creates a new achievement handler
+func NewAchievementHandler(achievementService *service.AchievementService) *AchievementHandler {
+	return &AchievementHandler{
+		achievementService: achievementService,
+	}
+}
+
+// GetAchievements gets streaks and achievement progress
+// @Summary Get achievements
+// @Description Get logging, target, protein and water streaks plus progress towards every achievement with bilingual names and descriptions
+// @Tags achievements
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.AchievementSummary
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/achievements [get]
+func (h *AchievementHandler) GetAchievements(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	summary, err := h.achievementService.GetSummary(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get achievements",
+		})
+	}
+
+	return c.JSON(summary)
+}
+
+// GetUnlocked gets unlocked achievements
+// @Summary Get unlocked achievements
+// @Description Get the achievements the user has earned and when
+// @Tags achievements
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} entity.AchievementProgress
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/achievements/unlocked [get]
+func (h *AchievementHandler) GetUnlocked(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	unlocked, err := h.achievementService.GetUnlocked(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get achievements",
+		})
+	}
+
+	return c.JSON(unlocked)
+}
+
+// GetStreaks gets streaks
+// @Summary Get streaks
+// @Description Get current and longest streaks with available freeze days, evaluated in the user's timezone
+// @Tags achievements
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} entity.Streak
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/achievements/streaks [get]
+func (h *AchievementHandler) GetStreaks(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	streaks, err := h.achievementService.GetStreaks(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get streaks",
+		})
+	}
+
+	return c.JSON(streaks)
+}
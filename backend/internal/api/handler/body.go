@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// BodyHandler handles body composition HTTP requests
+type BodyHandler struct {
+	bodyService *service.BodyService
+}
+
+// NewBodyHandler creates a new body handler
+func NewBodyHandler(bodyService *service.BodyService) *BodyHandler {
+	return &BodyHandler{
+		bodyService: bodyService,
+	}
+}
+
+// GetMeasurements gets body measurements
+// @Summary Get body measurements
+// @Description Get body measurements with derived body fat and waist-to-height ratio; defaults to the last 90 days
+// @Tags body
+// @Produce json
+// @Security Bearer
+// @Param from query string false "Start date (YYYY-MM-DD format)"
+// @Param to query string false "End date (YYYY-MM-DD format, inclusive)"
+// @Success 200 {array} entity.BodyMeasurement
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/body [get]
+func (h *BodyHandler) GetMeasurements(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -(defaultTrendDays - 1))
+	if c.Query("from") != "" || c.Query("to") != "" {
+		var err error
+		from, to, err = parseStatsRange(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	measurements, err := h.bodyService.GetMeasurements(c.Context(), userID, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get body measurements",
+		})
+	}
+
+	return c.JSON(measurements)
+}
+
+// CreateMeasurement records body measurements
+// @Summary Record body measurements
+// @Description Record body fat percentage and/or waist, hip and neck circumference (cm) for a date, replacing any measurement already on that date. Body fat is estimated with the US Navy method when not entered. The latest measurement updates the profile body fat unless update_profile is false.
+// @Tags body
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.CreateBodyMeasurementRequest true "Create body measurement request"
+// @Success 201 {object} entity.BodyMeasurementResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/body [post]
+func (h *BodyHandler) CreateMeasurement(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.CreateBodyMeasurementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	result, err := h.bodyService.CreateMeasurement(c.Context(), userID, &req)
+	if err != nil {
+		return bodyError(c, err, "Failed to record body measurement")
+	}
+
+	if result.Profile != nil {
+		setETag(c, result.Profile.Version)
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// GetComposition gets the current body composition
+// @Summary Get body composition
+// @Description Get body fat, lean and fat mass, waist-to-height and waist-to-hip ratios with Asian risk thresholds, and BMR under each formula
+// @Tags body
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.BodyComposition
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/body/composition [get]
+func (h *BodyHandler) GetComposition(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	composition, err := h.bodyService.GetComposition(c.Context(), userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Profile not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get body composition",
+		})
+	}
+
+	return c.JSON(composition)
+}
+
+// SetBMRFormula chooses the BMR formula
+// @Summary Set BMR formula
+// @Description Choose Mifflin-St Jeor, Katch-McArdle or Cunningham for BMR and recalculate targets; lean mass formulas need a body fat percentage
+// @Tags body
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.SetBMRFormulaRequest true "BMR formula request"
+// @Success 200 {object} entity.UserProfile
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/body/bmr-formula [put]
+func (h *BodyHandler) SetBMRFormula(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.SetBMRFormulaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	profile, err := h.bodyService.SetBMRFormula(c.Context(), userID, req.Formula)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Profile not found",
+			})
+		}
+		return bodyError(c, err, "Failed to set BMR formula")
+	}
+
+	setETag(c, profile.Version)
+	return c.JSON(profile)
+}
+
+// GetMeasurementByID gets a body measurement by ID
+// @Summary Get body measurement by ID
+// @Description Get a specific body measurement by ID
+// @Tags body
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Body measurement ID"
+// @Success 200 {object} entity.BodyMeasurement
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/body/{id} [get]
+func (h *BodyHandler) GetMeasurementByID(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	measurementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid body measurement ID",
+		})
+	}
+
+	measurement, err := h.bodyService.GetMeasurementByID(c.Context(), measurementID, userID)
+	if err != nil {
+		return bodyError(c, err, "Failed to get body measurement")
+	}
+
+	return c.JSON(measurement)
+}
+
+// UpdateMeasurement updates a body measurement
+// @Summary Update body measurement
+// @Description Update a body measurement's values, date or note
+// @Tags body
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Body measurement ID"
+// @Param request body entity.UpdateBodyMeasurementRequest true "Update body measurement request"
+// @Success 200 {object} entity.BodyMeasurement
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/body/{id} [put]
+func (h *BodyHandler) UpdateMeasurement(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	measurementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid body measurement ID",
+		})
+	}
+
+	var req entity.UpdateBodyMeasurementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	measurement, err := h.bodyService.UpdateMeasurement(c.Context(), measurementID, userID, &req)
+	if err != nil {
+		return bodyError(c, err, "Failed to update body measurement")
+	}
+
+	return c.JSON(measurement)
+}
+
+// DeleteMeasurement deletes a body measurement
+// @Summary Delete body measurement
+// @Description Delete a body measurement
+// @Tags body
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Body measurement ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/body/{id} [delete]
+func (h *BodyHandler) DeleteMeasurement(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	measurementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid body measurement ID",
+		})
+	}
+
+	if err := h.bodyService.DeleteMeasurement(c.Context(), measurementID, userID); err != nil {
+		return bodyError(c, err, "Failed to delete body measurement")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Body measurement deleted successfully",
+	})
+}
+
+// bodyError maps body service errors to HTTP responses
+func bodyError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidMeasurement),
+		errors.Is(err, service.ErrInvalidBMRFormula),
+		errors.Is(err, service.ErrBodyFatRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, repository.ErrDuplicateDate):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err == repository.ErrUserNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Body measurement not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxImportSize caps the size of an uploaded food diary file
+const maxImportSize = 10 << 20
+
+// DiaryHandler handles food diary export and import HTTP requests
+type DiaryHandler struct {
+	diaryService *service.DiaryService
+}
+
+// NewDiaryHandler creates a new diary handler
+func NewDiaryHandler(diaryService *service.DiaryService) *DiaryHandler {
+	return &DiaryHandler{
+		diaryService: diaryService,
+	}
+}
+
+// Export exports the food diary
+// @Summary Export meals
+// @Description Download meals as CSV or JSON, optionally limited to a date range. The CSV format can be imported again without loss.
+// @Tags meals
+// @Produce json,text/csv
+// @Security Bearer
+// @Param format query string false "csv or json" default(csv)
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {array} entity.Meal
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/meals/export [get]
+func (h *DiaryHandler) Export(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var from, to *time.Time
+	for _, param := range []struct {
+		name string
+		dest **time.Time
+	}{{"from", &from}, {"to", &to}} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Invalid %s date. Use YYYY-MM-DD", param.name),
+			})
+		}
+		*param.dest = &date
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "to must not be before from",
+		})
+	}
+
+	format := entity.ExportFormat(c.Query("format", string(entity.ExportCSV)))
+	body, contentType, err := h.diaryService.Export(c.Context(), userID, format, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidImport) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to export meals",
+		})
+	}
+
+	filename := fmt.Sprintf("bytetrack-meals-%s.%s", time.Now().Format("2006-01-02"), format)
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Send(body)
+}
+
+// Import imports a food diary file
+// @Summary Import meals
+// @Description Import meals from a ByteTrack, MyFitnessPal (nutrition summary) or Lose It! CSV export, uploaded as the "file" form field or as the raw request body. The source is detected from the header unless given. Every row is validated and reported; with dry_run nothing is saved. Rows matching an existing meal's date, meal type, name and calories are skipped as duplicates unless allow_duplicates is set.
+// @Tags meals
+// @Accept multipart/form-data,text/csv
+// @Produce json
+// @Security Bearer
+// @Param file formData file false "CSV file"
+// @Param source query string false "auto, bytetrack, myfitnesspal or loseit" default(auto)
+// @Param locale query string false "Locale for dates and numbers, e.g. th, en-US or de"
+// @Param mapping query string false "JSON object mapping column headers to fields"
+// @Param dry_run query bool false "Validate without saving"
+// @Param allow_duplicates query bool false "Import rows that match existing meals"
+// @Success 200 {object} entity.ImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /api/v1/meals/import [post]
+func (h *DiaryHandler) Import(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	// Options may be form fields or query parameters
+	opts := entity.ImportOptions{
+		Source: entity.ImportSource(c.FormValue("source", string(entity.ImportAuto))),
+		Locale: c.FormValue("locale"),
+	}
+	if mapping := c.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "mapping must be a JSON object of column headers to fields",
+			})
+		}
+	}
+	for _, flag := range []struct {
+		name string
+		dest *bool
+	}{{"dry_run", &opts.DryRun}, {"allow_duplicates", &opts.AllowDuplicates}} {
+		value := c.FormValue(flag.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("%s must be true or false", flag.name),
+			})
+		}
+		*flag.dest = parsed
+	}
+
+	data := c.Body()
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		if fileHeader.Size > maxImportSize {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error": "File exceeds maximum import size",
+			})
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Failed to read file",
+			})
+		}
+		defer file.Close()
+
+		if data, err = io.ReadAll(io.LimitReader(file, maxImportSize)); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Failed to read file",
+			})
+		}
+	} else if len(data) > maxImportSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error": "File exceeds maximum import size",
+		})
+	}
+	if len(data) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "CSV file is required",
+		})
+	}
+
+	result, err := h.diaryService.Import(c.Context(), userID, data, &opts)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidImport) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to import meals",
+		})
+	}
+
+	return c.JSON(result)
+}
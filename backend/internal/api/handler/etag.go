@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	errMissingIfMatch = errors.New("missing If-Match header")
+	errInvalidIfMatch = errors.New("invalid If-Match header")
+)
+
+// formatETag formats a row version as a strong ETag
+func formatETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// setETag sets the ETag response header for a row version
+func setETag(c *fiber.Ctx, version int) {
+	c.Set(fiber.HeaderETag, formatETag(version))
+}
+
+// parseIfMatch parses the If-Match request header into an expected row version.
+// A wildcard ("*") returns a nil version, meaning any current version matches.
+func parseIfMatch(c *fiber.Ctx) (*int, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		return nil, errMissingIfMatch
+	}
+	if header == "*" {
+		return nil, nil
+	}
+
+	// Only a single entity tag is supported; weak tags compare by value
+	tag := strings.TrimPrefix(header, "W/")
+	tag = strings.Trim(tag, `"`)
+
+	version, err := strconv.Atoi(tag)
+	if err != nil {
+		return nil, errInvalidIfMatch
+	}
+
+	return &version, nil
+}
+
+// ifMatchError writes the response for a missing or malformed If-Match header
+func ifMatchError(c *fiber.Ctx, err error) error {
+	if err == errMissingIfMatch {
+		return c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{
+			"error": "If-Match header is required",
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": "Invalid If-Match header",
+	})
+}
@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ExerciseHandler handles exercise HTTP requests
+type ExerciseHandler struct {
+	exerciseService *service.ExerciseService
+}
+
+// NewExerciseHandler creates a new exercise handler
+func NewExerciseHandler(exerciseService *service.ExerciseService) *ExerciseHandler {
+	return &ExerciseHandler{
+		exerciseService: exerciseService,
+	}
+}
+
+// GetExercises gets exercise logs for the authenticated user
+// @Summary Get exercises
+// @Description Get exercise logs for the authenticated user with optional date filter
+// @Tags exercises
+// @Produce json
+// @Security Bearer
+// @Param date query string false "Date (YYYY-MM-DD format)"
+// @Success 200 {array} entity.ExerciseLog
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/exercises [get]
+func (h *ExerciseHandler) GetExercises(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	// Parse date parameter
+	var date *time.Time
+	dateStr := c.Query("date")
+	if dateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid date format. Use YYYY-MM-DD",
+			})
+		}
+		date = &parsedDate
+	}
+
+	logs, err := h.exerciseService.GetLogs(c.Context(), userID, date)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get exercises",
+		})
+	}
+
+	if logs == nil {
+		logs = []*entity.ExerciseLog{}
+	}
+
+	return c.JSON(logs)
+}
+
+// CreateExercise logs an exercise session
+// @Summary Create exercise
+// @Description Log an exercise session; calories burned are computed from the activity's MET and the user's current weight
+// @Tags exercises
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.CreateExerciseRequest true "Create exercise request"
+// @Success 201 {object} entity.ExerciseLog
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises [post]
+func (h *ExerciseHandler) CreateExercise(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.CreateExerciseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	log, err := h.exerciseService.CreateLog(c.Context(), userID, &req)
+	if err != nil {
+		return exerciseError(c, err, "Failed to create exercise")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(log)
+}
+
+// GetExerciseByID gets an exercise log by ID
+// @Summary Get exercise by ID
+// @Description Get a specific exercise log by ID
+// @Tags exercises
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Exercise ID"
+// @Success 200 {object} entity.ExerciseLog
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/{id} [get]
+func (h *ExerciseHandler) GetExerciseByID(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	logID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid exercise ID",
+		})
+	}
+
+	log, err := h.exerciseService.GetLogByID(c.Context(), logID, userID)
+	if err != nil {
+		return exerciseError(c, err, "Failed to get exercise")
+	}
+
+	return c.JSON(log)
+}
+
+// UpdateExercise updates an exercise log
+// @Summary Update exercise
+// @Description Update an exercise log; calories are recomputed when activity, intensity or duration change
+// @Tags exercises
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Exercise ID"
+// @Param request body entity.UpdateExerciseRequest true "Update exercise request"
+// @Success 200 {object} entity.ExerciseLog
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/{id} [put]
+func (h *ExerciseHandler) UpdateExercise(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	logID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid exercise ID",
+		})
+	}
+
+	var req entity.UpdateExerciseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	log, err := h.exerciseService.UpdateLog(c.Context(), logID, userID, &req)
+	if err != nil {
+		return exerciseError(c, err, "Failed to update exercise")
+	}
+
+	return c.JSON(log)
+}
+
+// DeleteExercise deletes an exercise log
+// @Summary Delete exercise
+// @Description Delete an exercise log
+// @Tags exercises
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Exercise ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/{id} [delete]
+func (h *ExerciseHandler) DeleteExercise(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	logID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid exercise ID",
+		})
+	}
+
+	if err := h.exerciseService.DeleteLog(c.Context(), logID, userID); err != nil {
+		return exerciseError(c, err, "Failed to delete exercise")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Exercise deleted successfully",
+	})
+}
+
+// GetActivities gets the activity catalog
+// @Summary Get activities
+// @Description Get built-in activities with per-intensity METs plus the user's custom activities
+// @Tags exercises
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} entity.ExerciseActivity
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/exercises/activities [get]
+func (h *ExerciseHandler) GetActivities(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	activities, err := h.exerciseService.GetActivities(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get activities",
+		})
+	}
+
+	return c.JSON(activities)
+}
+
+// CreateActivity adds a custom activity
+// @Summary Create activity
+// @Description Add a custom activity with its MET values
+// @Tags exercises
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.CreateActivityRequest true "Create activity request"
+// @Success 201 {object} entity.ExerciseActivity
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/exercises/activities [post]
+func (h *ExerciseHandler) CreateActivity(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.CreateActivityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	activity, err := h.exerciseService.CreateActivity(c.Context(), userID, &req)
+	if err != nil {
+		return exerciseError(c, err, "Failed to create activity")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(activity)
+}
+
+// DeleteActivity deletes a custom activity
+// @Summary Delete activity
+// @Description Delete a custom activity; built-in activities cannot be deleted
+// @Tags exercises
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Activity ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/activities/{id} [delete]
+func (h *ExerciseHandler) DeleteActivity(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	if err := h.exerciseService.DeleteActivity(c.Context(), c.Params("id"), userID); err != nil {
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Activity not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete activity",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Activity deleted successfully",
+	})
+}
+
+// exerciseError maps exercise service errors to HTTP responses
+func exerciseError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidExercise), errors.Is(err, service.ErrProfileRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err == repository.ErrUserNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Exercise or activity not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// defaultFastingHistoryDays is the history range when no dates are given
+const defaultFastingHistoryDays = 30
+
+// FastingHandler handles intermittent fasting HTTP requests
+type FastingHandler struct {
+	fastingService *service.FastingService
+}
+
+// NewFastingHandler creates a new fasting handler
+func NewFastingHandler(fastingService *service.FastingService) *FastingHandler {
+	return &FastingHandler{
+		fastingService: fastingService,
+	}
+}
+
+// GetProtocols gets fasting protocols
+// @Summary Get fasting protocols
+// @Description Get the preset fasting protocols (16:8, 18:6, 20:4 and OMAD) with their fasting and eating hours
+// @Tags fasting
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} entity.FastingProtocolInfo
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/fasting/protocols [get]
+func (h *FastingHandler) GetProtocols(c *fiber.Ctx) error {
+	if getUserID(c) == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	return c.JSON(h.fastingService.GetProtocols())
+}
+
+// GetCurrent gets the current fast
+// @Summary Get current fast
+// @Description Get the active fast with its elapsed and remaining time, or the time since the last meal when no fast is active
+// @Tags fasting
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.FastStatus
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/fasting/current [get]
+func (h *FastingHandler) GetCurrent(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	status, err := h.fastingService.GetStatus(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get current fast",
+		})
+	}
+
+	return c.JSON(status)
+}
+
+// Start starts a fast
+// @Summary Start fast
+// @Description Start a fast now, at started_at, or from the last logged meal's eaten_at. Without a protocol the preferred one is used; custom fasts need target_hours.
+// @Tags fasting
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.StartFastRequest true "Start fast request"
+// @Success 201 {object} entity.Fast
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/fasting/start [post]
+func (h *FastingHandler) Start(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.StartFastRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	fast, err := h.fastingService.Start(c.Context(), userID, &req)
+	if err != nil {
+		return fastingError(c, err, "Failed to start fast")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fast)
+}
+
+// End ends the active fast
+// @Summary End fast
+// @Description End the active fast now or at ended_at
+// @Tags fasting
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.EndFastRequest false "End fast request"
+// @Success 200 {object} entity.Fast
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/fasting/end [post]
+func (h *FastingHandler) End(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.EndFastRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	fast, err := h.fastingService.End(c.Context(), userID, &req)
+	if err != nil {
+		return fastingError(c, err, "Failed to end fast")
+	}
+
+	return c.JSON(fast)
+}
+
+// GetHistory gets past fasts
+// @Summary Get fasting history
+// @Description Get fasts started within a date range, newest first; defaults to the last 30 days
+// @Tags fasting
+// @Produce json
+// @Security Bearer
+// @Param from query string false "Start date (YYYY-MM-DD format)"
+// @Param to query string false "End date (YYYY-MM-DD format, inclusive)"
+// @Success 200 {array} entity.Fast
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/fasting/history [get]
+func (h *FastingHandler) GetHistory(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -(defaultFastingHistoryDays - 1))
+	if c.Query("from") != "" || c.Query("to") != "" {
+		var err error
+		from, to, err = parseStatsRange(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	fasts, err := h.fastingService.History(c.Context(), userID, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get fasting history",
+		})
+	}
+
+	return c.JSON(fasts)
+}
+
+// GetStreak gets the fasting streak
+// @Summary Get fasting streak
+// @Description Get the fasting streak and totals. A day counts when a completed fast ended on it; missed days spend streak freezes like other streaks.
+// @Tags fasting
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.FastingStats
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/fasting/streak [get]
+func (h *FastingHandler) GetStreak(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	stats, err := h.fastingService.GetStats(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get fasting streak",
+		})
+	}
+
+	return c.JSON(stats)
+}
+
+// GetSettings gets fasting preferences
+// @Summary Get fasting settings
+// @Description Get the preferred protocol and whether fasts start automatically from logged meals
+// @Tags fasting
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.FastingPreferences
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/fasting/settings [get]
+func (h *FastingHandler) GetSettings(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	prefs, err := h.fastingService.GetPreferences(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get fasting settings",
+		})
+	}
+
+	return c.JSON(prefs)
+}
+
+// UpdateSettings updates fasting preferences
+// @Summary Update fasting settings
+// @Description Update the preferred protocol and auto start. With auto start, logging a meal starts a fast from its eaten_at; turning it on starts one from a meal in the last 48 hours.
+// @Tags fasting
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.UpdateFastingPreferencesRequest true "Update fasting settings request"
+// @Success 200 {object} entity.FastingPreferences
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/fasting/settings [put]
+func (h *FastingHandler) UpdateSettings(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.UpdateFastingPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	prefs, err := h.fastingService.UpdatePreferences(c.Context(), userID, &req)
+	if err != nil {
+		return fastingError(c, err, "Failed to update fasting settings")
+	}
+
+	return c.JSON(prefs)
+}
+
+// Delete deletes a fast
+// @Summary Delete fast
+// @Description Delete a fast from the history
+// @Tags fasting
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Fast ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/fasting/{id} [delete]
+func (h *FastingHandler) Delete(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	fastID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid fast ID",
+		})
+	}
+
+	if err := h.fastingService.Delete(c.Context(), userID, fastID); err != nil {
+		return fastingError(c, err, "Failed to delete fast")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Fast deleted successfully",
+	})
+}
+
+// fastingError maps fasting service errors to responses
+func fastingError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidFast), errors.Is(err, service.ErrNoMealLogged):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err == repository.ErrFastInProgress:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err == repository.ErrUserNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Fast not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -1,13 +1,22 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"strconv"
 
+	"github.com/bytetrack/backend/internal/domain/entity"
 	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/openfoodfacts"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
+const (
+	defaultLoggedFoodsLimit = 30
+	maxLoggedFoodsLimit     = 100
+)
+
 // FoodHandler handles food HTTP requests
 type FoodHandler struct {
 	foodService *service.FoodService
@@ -22,7 +31,7 @@ func NewFoodHandler(foodService *service.FoodService) *FoodHandler {
 
 // SearchFoods searches foods
 // @Summary Search foods
-// @Description Search foods combining Thai foods and Open Food Facts API
+// @Description Search foods across custom foods, the Thai catalog, USDA and Open Food Facts. Thai matching ignores tone marks and accepts romanized spellings such as "pad krapow". Sources that failed are reported as degraded.
 // @Tags foods
 // @Produce json
 // @Security Bearer
@@ -32,6 +41,7 @@ func NewFoodHandler(foodService *service.FoodService) *FoodHandler {
 // @Success 200 {object} entity.SearchResult
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /api/v1/foods/search [get]
 func (h *FoodHandler) SearchFoods(c *fiber.Ctx) error {
 	userID := getUserID(c)
@@ -59,9 +69,7 @@ func (h *FoodHandler) SearchFoods(c *fiber.Ctx) error {
 
 	result, err := h.foodService.SearchFoods(c.Context(), userID, query, page, category)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to search foods",
-		})
+		return foodError(c, err, "Food not found", "Failed to search foods")
 	}
 
 	return c.JSON(result)
@@ -108,6 +116,8 @@ func (h *FoodHandler) GetThaiFoods(c *fiber.Ctx) error {
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /api/v1/foods/barcode/{barcode} [get]
 func (h *FoodHandler) LookupBarcode(c *fiber.Ctx) error {
 	userID := getUserID(c)
@@ -126,14 +136,123 @@ func (h *FoodHandler) LookupBarcode(c *fiber.Ctx) error {
 
 	food, err := h.foodService.LookupBarcode(c.Context(), barcode)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Product not found",
+		return foodError(c, err, "Product not found", "Failed to look up product")
+	}
+
+	return c.JSON(food)
+}
+
+// GetFood gets a food by ID
+// @Summary Get food
+// @Description Get a food by the ID it has in search results, from whichever provider it came from
+// @Tags foods
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Food ID, e.g. th_1, custom_<uuid> or off_<barcode>"
+// @Success 200 {object} entity.FoodItem
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/foods/{id} [get]
+func (h *FoodHandler) GetFood(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
 		})
 	}
 
+	food, err := h.foodService.GetFood(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return foodError(c, err, "Food not found", "Failed to get food")
+	}
+
 	return c.JSON(food)
 }
 
+// GetRecentFoods gets the foods the user logged most recently
+// @Summary Get recent foods
+// @Description Get the foods the user logged meals from in the last 90 days, most recent first. Each food is as last logged, with the portion eaten as its serving. Only meals logged with a food_id count.
+// @Tags foods
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Number of foods (1-100)" default(30)
+// @Success 200 {array} entity.LoggedFood
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/foods/recent [get]
+func (h *FoodHandler) GetRecentFoods(c *fiber.Ctx) error {
+	return h.loggedFoods(c, h.foodService.RecentFoods, "Failed to get recent foods")
+}
+
+// GetFrequentFoods gets the foods the user logs most often
+// @Summary Get frequent foods
+// @Description Get the foods the user logged the most meals from in the last 90 days, with counts per meal type. Each food is as last logged, with the portion eaten as its serving. Only meals logged with a food_id count.
+// @Tags foods
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Number of foods (1-100)" default(30)
+// @Success 200 {array} entity.LoggedFood
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/foods/frequent [get]
+func (h *FoodHandler) GetFrequentFoods(c *fiber.Ctx) error {
+	return h.loggedFoods(c, h.foodService.FrequentFoods, "Failed to get frequent foods")
+}
+
+// loggedFoods serves a list of the user's logged foods
+func (h *FoodHandler) loggedFoods(c *fiber.Ctx, list func(context.Context, uuid.UUID, int) ([]*entity.LoggedFood, error), failure string) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	limit := defaultLoggedFoodsLimit
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 || parsed > maxLoggedFoodsLimit {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be between 1 and " + strconv.Itoa(maxLoggedFoodsLimit),
+			})
+		}
+		limit = parsed
+	}
+
+	foods, err := list(c.Context(), userID, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": failure,
+		})
+	}
+	if foods == nil {
+		foods = []*entity.LoggedFood{}
+	}
+
+	return c.JSON(foods)
+}
+
+// GetBarcodeCacheStats gets the barcode cache counters
+// @Summary Get barcode cache stats
+// @Description Get hit, miss and eviction counters of the Open Food Facts barcode cache since startup
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} entity.BarcodeCacheStats
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/cache/barcodes [get]
+func (h *FoodHandler) GetBarcodeCacheStats(c *fiber.Ctx) error {
+	stats := h.foodService.BarcodeCacheStats()
+	if stats == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Barcode cache is disabled",
+		})
+	}
+	return c.JSON(stats)
+}
+
 // GetCategories gets food categories
 // @Summary Get categories
 // @Description Get all food categories
@@ -145,3 +264,36 @@ func (h *FoodHandler) GetCategories(c *fiber.Ctx) error {
 	categories := h.foodService.GetFoodCategories()
 	return c.JSON(categories)
 }
+
+// GetNutrients gets the nutrient registry
+// @Summary Get nutrients
+// @Description Get every nutrient that can be recorded on meals and foods, with its unit
+// @Tags foods
+// @Produce json
+// @Success 200 {array} entity.Nutrient
+// @Router /api/v1/foods/nutrients [get]
+func (h *FoodHandler) GetNutrients(c *fiber.Ctx) error {
+	return c.JSON(entity.NutrientRegistry)
+}
+
+// foodError maps food provider failures to a response
+func foodError(c *fiber.Ctx, err error, notFound, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrFoodNotFound), errors.Is(err, openfoodfacts.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": notFound,
+		})
+	case errors.Is(err, openfoodfacts.ErrRateLimited):
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error": "Open Food Facts is rate limiting requests, try again later",
+		})
+	case errors.Is(err, openfoodfacts.ErrUnavailable):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Open Food Facts is unavailable, try again later",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": fallback,
+		})
+	}
+}
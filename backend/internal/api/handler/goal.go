@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GoalHandler handles goal progress HTTP requests
+type GoalHandler struct {
+	goalService *service.GoalService
+}
+
+// NewGoalHandler creates a new goal handler
+func NewGoalHandler(goalService *service.GoalService) *GoalHandler {
+	return &GoalHandler{
+		goalService: goalService,
+	}
+}
+
+// GetProgress gets progress towards the goal weight
+// @Summary Get goal progress
+// @Description Get percent complete towards the goal weight, the projected completion date at the average deficit from logged meals with a safe-rate warning, BMI and category, and the ideal weight range. Suggests switching to maintain once the goal weight is reached.
+// @Tags goals
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.GoalProgress
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/goals/progress [get]
+func (h *GoalHandler) GetProgress(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	progress, err := h.goalService.GetProgress(c.Context(), userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Profile not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get goal progress",
+		})
+	}
+
+	return c.JSON(progress)
+}
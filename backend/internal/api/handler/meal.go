@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"time"
 
 	"github.com/bytetrack/backend/internal/domain/entity"
@@ -10,6 +11,28 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxStatsRange caps the span of a range stats request
+const maxStatsRange = 365 * 24 * time.Hour
+
+// parseStatsRange parses the from/to query dates of a range stats request
+func parseStatsRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Invalid from date. Use YYYY-MM-DD")
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Invalid to date. Use YYYY-MM-DD")
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+	if to.Sub(from) > maxStatsRange {
+		return time.Time{}, time.Time{}, errors.New("Date range must not exceed 366 days")
+	}
+	return from, to, nil
+}
+
 // MealHandler handles meal HTTP requests
 type MealHandler struct {
 	mealService *service.MealService
@@ -92,11 +115,17 @@ func (h *MealHandler) CreateMeal(c *fiber.Ctx) error {
 
 	meal, err := h.mealService.CreateMeal(c.Context(), userID, &req)
 	if err != nil {
+		if errors.Is(err, entity.ErrInvalidNutrients) || errors.Is(err, entity.ErrInvalidBarcode) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create meal",
 		})
 	}
 
+	setETag(c, meal.Version)
 	return c.Status(fiber.StatusCreated).JSON(meal)
 }
 
@@ -138,6 +167,7 @@ func (h *MealHandler) GetMealByID(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, meal.Version)
 	return c.JSON(meal)
 }
 
@@ -149,11 +179,14 @@ func (h *MealHandler) GetMealByID(c *fiber.Ctx) error {
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Meal ID"
+// @Param If-Match header string true "ETag of the meal being updated"
 // @Param request body entity.UpdateMealRequest true "Update meal request"
 // @Success 200 {object} entity.Meal
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} entity.Meal
+// @Failure 428 {object} map[string]string
 // @Router /api/v1/meals/{id} [put]
 func (h *MealHandler) UpdateMeal(c *fiber.Ctx) error {
 	userID := getUserID(c)
@@ -170,6 +203,11 @@ func (h *MealHandler) UpdateMeal(c *fiber.Ctx) error {
 		})
 	}
 
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return ifMatchError(c, err)
+	}
+
 	var req entity.UpdateMealRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -177,8 +215,17 @@ func (h *MealHandler) UpdateMeal(c *fiber.Ctx) error {
 		})
 	}
 
-	meal, err := h.mealService.UpdateMeal(c.Context(), mealID, userID, &req)
+	meal, err := h.mealService.UpdateMeal(c.Context(), mealID, userID, expectedVersion, &req)
 	if err != nil {
+		if errors.Is(err, entity.ErrInvalidNutrients) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err == service.ErrVersionMismatch {
+			setETag(c, meal.Version)
+			return c.Status(fiber.StatusPreconditionFailed).JSON(meal)
+		}
 		if err == repository.ErrUserNotFound {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "Meal not found",
@@ -189,6 +236,7 @@ func (h *MealHandler) UpdateMeal(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, meal.Version)
 	return c.JSON(meal)
 }
 
@@ -271,6 +319,43 @@ func (h *MealHandler) GetDailyStats(c *fiber.Ctx) error {
 	return c.JSON(stats)
 }
 
+// GetRangeStats gets nutrition stats across a date range
+// @Summary Get range stats
+// @Description Get per-day nutrition totals, range totals and daily averages between two dates
+// @Tags meals
+// @Produce json
+// @Security Bearer
+// @Param from query string true "Start date (YYYY-MM-DD format)"
+// @Param to query string true "End date (YYYY-MM-DD format, inclusive)"
+// @Success 200 {object} entity.RangeStats
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/meals/stats [get]
+func (h *MealHandler) GetRangeStats(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	from, to, err := parseStatsRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	stats, err := h.mealService.GetRangeStats(c.Context(), userID, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get range stats",
+		})
+	}
+
+	return c.JSON(stats)
+}
+
 // Favorite foods handlers
 
 // GetFavorites gets favorite foods
@@ -329,6 +414,11 @@ func (h *MealHandler) AddFavorite(c *fiber.Ctx) error {
 
 	fav, err := h.mealService.AddFavorite(c.Context(), userID, &req)
 	if err != nil {
+		if errors.Is(err, entity.ErrInvalidNutrients) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to add favorite",
 		})
@@ -431,6 +521,11 @@ func (h *MealHandler) CreateCustomFood(c *fiber.Ctx) error {
 
 	food, err := h.mealService.CreateCustomFood(c.Context(), userID, &req)
 	if err != nil {
+		if errors.Is(err, entity.ErrInvalidNutrients) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create custom food",
 		})
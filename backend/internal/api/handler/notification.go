@@ -0,0 +1,374 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// NotificationHandler handles reminder and notification HTTP requests
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetReminders gets reminders
+// @Summary Get reminders
+// @Description Get the user's meal, water and weigh-in reminders
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} entity.Reminder
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/notifications/reminders [get]
+func (h *NotificationHandler) GetReminders(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	reminders, err := h.notificationService.GetReminders(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get reminders",
+		})
+	}
+
+	return c.JSON(reminders)
+}
+
+// CreateReminder creates a reminder
+// @Summary Create reminder
+// @Description Create a daily reminder at a local time (HH:MM) in the profile timezone, optionally limited to weekdays (0 = Sunday). Meal reminders need a meal_type and are skipped when that meal is already logged; water reminders are skipped once the hydration goal is reached and weigh-in reminders once weighed that day.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.CreateReminderRequest true "Create reminder request"
+// @Success 201 {object} entity.Reminder
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/notifications/reminders [post]
+func (h *NotificationHandler) CreateReminder(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.CreateReminderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	reminder, err := h.notificationService.CreateReminder(c.Context(), userID, &req)
+	if err != nil {
+		return notificationError(c, err, "Failed to create reminder")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(reminder)
+}
+
+// UpdateReminder updates a reminder
+// @Summary Update reminder
+// @Description Update a reminder's time, weekdays, language, meal type or enabled state
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Reminder ID"
+// @Param request body entity.UpdateReminderRequest true "Update reminder request"
+// @Success 200 {object} entity.Reminder
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/notifications/reminders/{id} [put]
+func (h *NotificationHandler) UpdateReminder(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	reminderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid reminder ID",
+		})
+	}
+
+	var req entity.UpdateReminderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	reminder, err := h.notificationService.UpdateReminder(c.Context(), reminderID, userID, &req)
+	if err != nil {
+		return notificationError(c, err, "Failed to update reminder")
+	}
+
+	return c.JSON(reminder)
+}
+
+// DeleteReminder deletes a reminder
+// @Summary Delete reminder
+// @Description Delete a reminder
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Reminder ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/notifications/reminders/{id} [delete]
+func (h *NotificationHandler) DeleteReminder(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	reminderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid reminder ID",
+		})
+	}
+
+	if err := h.notificationService.DeleteReminder(c.Context(), reminderID, userID); err != nil {
+		return notificationError(c, err, "Failed to delete reminder")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Reminder deleted successfully",
+	})
+}
+
+// GetSettings gets notification settings
+// @Summary Get notification settings
+// @Description Get quiet hours, enabled delivery channels and the timezone reminders run in
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.NotificationSettings
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/notifications/settings [get]
+func (h *NotificationHandler) GetSettings(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	settings, err := h.notificationService.GetSettings(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get notification settings",
+		})
+	}
+
+	return c.JSON(settings)
+}
+
+// UpdateSettings updates notification settings
+// @Summary Update notification settings
+// @Description Update quiet hours (HH:MM, may span midnight; empty strings turn them off) and whether push and email delivery are enabled
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.UpdateNotificationSettingsRequest true "Update notification settings request"
+// @Success 200 {object} entity.NotificationSettings
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/notifications/settings [put]
+func (h *NotificationHandler) UpdateSettings(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.UpdateNotificationSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	settings, err := h.notificationService.UpdateSettings(c.Context(), userID, &req)
+	if err != nil {
+		return notificationError(c, err, "Failed to update notification settings")
+	}
+
+	return c.JSON(settings)
+}
+
+// GetVAPIDPublicKey gets the Web Push application server key
+// @Summary Get VAPID public key
+// @Description Get the application server key to pass to PushManager.subscribe
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/notifications/vapid-public-key [get]
+func (h *NotificationHandler) GetVAPIDPublicKey(c *fiber.Ctx) error {
+	key := h.notificationService.VAPIDPublicKey()
+	if key == "" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Web Push is not configured",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"public_key": key,
+	})
+}
+
+// Subscribe saves a push subscription
+// @Summary Subscribe to push notifications
+// @Description Save the browser's PushSubscription (as returned by toJSON) so reminders can be delivered by Web Push
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.SubscribePushRequest true "Push subscription"
+// @Success 201 {object} entity.PushSubscription
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/notifications/subscriptions [post]
+func (h *NotificationHandler) Subscribe(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.SubscribePushRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	sub, err := h.notificationService.Subscribe(c.Context(), userID, &req)
+	if err != nil {
+		return notificationError(c, err, "Failed to save push subscription")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(sub)
+}
+
+// Unsubscribe removes a push subscription
+// @Summary Unsubscribe from push notifications
+// @Description Remove a browser's push subscription by endpoint
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.UnsubscribePushRequest true "Unsubscribe request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/notifications/subscriptions [delete]
+func (h *NotificationHandler) Unsubscribe(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.UnsubscribePushRequest
+	if err := c.BodyParser(&req); err != nil || req.Endpoint == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.notificationService.Unsubscribe(c.Context(), userID, req.Endpoint); err != nil {
+		return notificationError(c, err, "Failed to remove push subscription")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Push subscription removed successfully",
+	})
+}
+
+// SendTest sends a test notification
+// @Summary Send test notification
+// @Description Send a test notification on every enabled channel in the user's language
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} map[string][]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /api/v1/notifications/test [post]
+func (h *NotificationHandler) SendTest(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	channels, err := h.notificationService.SendTest(c.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotDelivered) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to send test notification",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"channels": channels,
+	})
+}
+
+// notificationError maps notification service errors to HTTP responses
+func notificationError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidReminder), errors.Is(err, service.ErrInvalidNotificationSettings):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err == repository.ErrUserNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Reminder or subscription not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"github.com/bytetrack/backend/internal/domain/entity"
 	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
@@ -48,11 +49,17 @@ func (h *OnboardingHandler) CompleteOnboarding(c *fiber.Ctx) error {
 
 	result, err := h.onboardingService.CompleteOnboarding(c.Context(), userID, &req)
 	if err != nil {
+		if err == service.ErrInvalidTimezone {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to complete onboarding",
 		})
 	}
 
+	setETag(c, result.Version)
 	return c.JSON(result)
 }
 
@@ -90,10 +97,14 @@ func (h *OnboardingHandler) GetOnboardingStatus(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Security Bearer
+// @Param If-Match header string true "ETag of the profile being updated"
 // @Param request body entity.OnboardingRequest true "Profile update request"
 // @Success 200 {object} entity.OnboardingResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} entity.UserProfile
+// @Failure 428 {object} map[string]string
 // @Router /api/v1/user/profile [put]
 func (h *OnboardingHandler) UpdateProfile(c *fiber.Ctx) error {
 	userID := getUserID(c)
@@ -103,6 +114,11 @@ func (h *OnboardingHandler) UpdateProfile(c *fiber.Ctx) error {
 		})
 	}
 
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return ifMatchError(c, err)
+	}
+
 	var req entity.OnboardingRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -110,13 +126,28 @@ func (h *OnboardingHandler) UpdateProfile(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.onboardingService.UpdateProfile(c.Context(), userID, &req)
+	result, profile, err := h.onboardingService.UpdateProfile(c.Context(), userID, expectedVersion, &req)
 	if err != nil {
+		if err == service.ErrInvalidTimezone {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err == service.ErrVersionMismatch {
+			setETag(c, profile.Version)
+			return c.Status(fiber.StatusPreconditionFailed).JSON(profile)
+		}
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Profile not found. Please complete onboarding.",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update profile",
 		})
 	}
 
+	setETag(c, profile.Version)
 	return c.JSON(result)
 }
 
@@ -152,5 +183,6 @@ func (h *OnboardingHandler) GetProfile(c *fiber.Ctx) error {
 		})
 	}
 
+	setETag(c, profile.Version)
 	return c.JSON(profile)
 }
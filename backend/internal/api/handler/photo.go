@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/bytetrack/backend/internal/infrastructure/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// PhotoHandler handles meal photo HTTP requests
+type PhotoHandler struct {
+	photoService *service.PhotoService
+}
+
+// NewPhotoHandler creates a new photo handler
+func NewPhotoHandler(photoService *service.PhotoService) *PhotoHandler {
+	return &PhotoHandler{
+		photoService: photoService,
+	}
+}
+
+// UploadMealPhoto uploads a photo for a meal
+// @Summary Upload meal photo
+// @Description Upload a JPEG, PNG or GIF photo for a meal. EXIF metadata is stripped and a thumbnail is generated.
+// @Tags meals
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Meal ID"
+// @Param photo formData file true "Photo file"
+// @Success 200 {object} entity.Meal
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /api/v1/meals/{id}/photo [post]
+func (h *PhotoHandler) UploadMealPhoto(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	mealID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meal ID",
+		})
+	}
+
+	fileHeader, err := c.FormFile("photo")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Photo file is required",
+		})
+	}
+
+	maxSize := h.photoService.MaxUploadSize()
+	if fileHeader.Size > maxSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error": "Photo exceeds maximum upload size",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Failed to read photo",
+		})
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Failed to read photo",
+		})
+	}
+
+	meal, err := h.photoService.UploadMealPhoto(c.Context(), mealID, userID, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPhotoTooLarge):
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error": "Photo exceeds maximum upload size",
+			})
+		case errors.Is(err, service.ErrPhotoTooManyPixels):
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error": "Photo dimensions are too large",
+			})
+		case errors.Is(err, service.ErrUnsupportedPhotoType):
+			return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+				"error": "Unsupported photo type. Use JPEG, PNG or GIF",
+			})
+		case err == repository.ErrUserNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Meal not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to upload photo",
+		})
+	}
+
+	setETag(c, meal.Version)
+	return c.JSON(meal)
+}
+
+// DeleteMealPhoto removes the photo from a meal
+// @Summary Delete meal photo
+// @Description Remove the uploaded photo from a meal
+// @Tags meals
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Meal ID"
+// @Success 200 {object} entity.Meal
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/meals/{id}/photo [delete]
+func (h *PhotoHandler) DeleteMealPhoto(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	mealID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meal ID",
+		})
+	}
+
+	meal, err := h.photoService.DeleteMealPhoto(c.Context(), mealID, userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Meal not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete photo",
+		})
+	}
+
+	setETag(c, meal.Version)
+	return c.JSON(meal)
+}
+
+// GetPhoto serves a stored photo from a signed URL
+// @Summary Get photo
+// @Description Serve a meal photo. The URL must be signed and unexpired.
+// @Tags meals
+// @Produce image/jpeg
+// @Param key path string true "Photo key"
+// @Param expires query int true "Expiry (Unix seconds)"
+// @Param sig query string true "Signature"
+// @Success 200 {file} binary
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/photos/{key} [get]
+func (h *PhotoHandler) GetPhoto(c *fiber.Ctx) error {
+	key := c.Params("*")
+	expires := c.Query("expires")
+
+	reader, contentType, err := h.photoService.Open(c.Context(), key, expires, c.Query("sig"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPhotoURL) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Invalid or expired photo URL",
+			})
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Photo not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get photo",
+		})
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get photo",
+		})
+	}
+
+	// Cache privately until the signed URL expires
+	maxAge := int64(0)
+	if exp, err := strconv.ParseInt(expires, 10, 64); err == nil {
+		maxAge = max(0, exp-time.Now().Unix())
+	}
+	c.Set(fiber.HeaderCacheControl, "private, max-age="+strconv.FormatInt(maxAge, 10))
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set("X-Content-Type-Options", "nosniff")
+
+	return c.Send(data)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ReportHandler handles nutrition report HTTP requests
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// GetReport gets a nutrition report
+// @Summary Get nutrition report
+// @Description Get a weekly (Monday to Sunday) or monthly report containing date, defaulting to the current period. Covers totals against targets, macro split, top foods, daily calories and the weight trend. Rendered as JSON, HTML or PDF in Thai or English.
+// @Tags reports
+// @Produce json,html,application/pdf
+// @Security Bearer
+// @Param period query string false "weekly or monthly" default(weekly)
+// @Param date query string false "A day in the period (YYYY-MM-DD)"
+// @Param format query string false "json, html or pdf" default(json)
+// @Param lang query string false "th or en; defaults to the profile language"
+// @Success 200 {object} entity.Report
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/reports [get]
+func (h *ReportHandler) GetReport(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	period := entity.ReportPeriod(c.Query("period", string(entity.ReportWeekly)))
+	format := entity.ReportFormat(c.Query("format", string(entity.ReportJSON)))
+
+	var date *time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid date format. Use YYYY-MM-DD",
+			})
+		}
+		date = &parsed
+	}
+
+	lang := c.Query("lang")
+	if lang != "" && lang != "th" && lang != "en" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "lang must be th or en",
+		})
+	}
+
+	report, err := h.reportService.Generate(c.Context(), userID, period, date, lang)
+	if err != nil {
+		return reportError(c, err, "Failed to generate report")
+	}
+
+	if format == entity.ReportJSON {
+		return c.JSON(report)
+	}
+
+	body, contentType, err := h.reportService.Render(report, format)
+	if err != nil {
+		return reportError(c, err, "Failed to render report")
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	if format == entity.ReportPDF {
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", h.reportService.Filename(report, format)))
+	}
+	return c.Send(body)
+}
+
+// GetSchedule gets the emailed report schedule
+// @Summary Get report schedule
+// @Description Get which reports are emailed, in what language and whether a PDF is attached
+// @Tags reports
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.ReportSchedule
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/reports/schedule [get]
+func (h *ReportHandler) GetSchedule(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	schedule, err := h.reportService.GetSchedule(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get report schedule",
+		})
+	}
+
+	return c.JSON(schedule)
+}
+
+// UpdateSchedule updates the emailed report schedule
+// @Summary Update report schedule
+// @Description Turn emailed weekly reports (sent Monday) and monthly reports (sent on the 1st) on or off. An empty language follows the profile language.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.UpdateReportScheduleRequest true "Report schedule"
+// @Success 200 {object} entity.ReportSchedule
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/reports/schedule [put]
+func (h *ReportHandler) UpdateSchedule(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.UpdateReportScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	schedule, err := h.reportService.UpdateSchedule(c.Context(), userID, &req)
+	if err != nil {
+		return reportError(c, err, "Failed to update report schedule")
+	}
+
+	return c.JSON(schedule)
+}
+
+// reportError maps report service errors to HTTP responses
+func reportError(c *fiber.Ctx, err error, fallback string) error {
+	if errors.Is(err, service.ErrInvalidReport) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
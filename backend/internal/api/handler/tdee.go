@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// TDEEHandler handles adaptive TDEE HTTP requests
+type TDEEHandler struct {
+	tdeeService *service.TDEEService
+}
+
+// NewTDEEHandler creates a new TDEE handler
+func NewTDEEHandler(tdeeService *service.TDEEService) *TDEEHandler {
+	return &TDEEHandler{
+		tdeeService: tdeeService,
+	}
+}
+
+// GetEstimate estimates TDEE from logged intake and weight change
+// @Summary Estimate TDEE
+// @Description Estimate maintenance calories from logged intake and the smoothed weight trend over the last complete weeks, with a confidence measure and a suggested TDEE blended with the formula estimate
+// @Tags tdee
+// @Produce json
+// @Security Bearer
+// @Param weeks query int false "Window in weeks (2-12, default 4)"
+// @Success 200 {object} entity.TDEEEstimate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/tdee/estimate [get]
+func (h *TDEEHandler) GetEstimate(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	estimate, err := h.tdeeService.Estimate(c.Context(), userID, c.QueryInt("weeks", 0))
+	if err != nil {
+		return tdeeError(c, err, "Failed to estimate TDEE")
+	}
+
+	return c.JSON(estimate)
+}
+
+// SetAdaptive opts in or out of adaptive TDEE
+// @Summary Set adaptive TDEE
+// @Description Opt in to weekly adjustment of targets from the TDEE estimate, or opt out to restore the formula targets
+// @Tags tdee
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.SetAdaptiveTDEERequest true "Adaptive TDEE request"
+// @Success 200 {object} entity.UserProfile
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/tdee/adaptive [put]
+func (h *TDEEHandler) SetAdaptive(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.SetAdaptiveTDEERequest
+	if err := c.BodyParser(&req); err != nil || req.Enabled == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	profile, err := h.tdeeService.SetAdaptive(c.Context(), userID, *req.Enabled)
+	if err != nil {
+		return tdeeError(c, err, "Failed to update adaptive TDEE")
+	}
+
+	setETag(c, profile.Version)
+	return c.JSON(profile)
+}
+
+// tdeeError maps TDEE service errors to HTTP responses
+func tdeeError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidTDEEWeeks):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err == repository.ErrUserNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Profile not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
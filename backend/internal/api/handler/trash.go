@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// TrashHandler handles trash HTTP requests
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+// NewTrashHandler creates a new trash handler
+func NewTrashHandler(trashService *service.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// GetTrash gets deleted meals and custom foods
+// @Summary Get trash
+// @Description Get soft-deleted meals and custom foods that can still be restored
+// @Tags trash
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.Trash
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/trash [get]
+func (h *TrashHandler) GetTrash(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	trash, err := h.trashService.GetTrash(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get trash",
+		})
+	}
+
+	return c.JSON(trash)
+}
+
+// Restore restores a deleted meal or custom food
+// @Summary Restore from trash
+// @Description Restore a soft-deleted meal or custom food
+// @Tags trash
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Meal or Custom Food ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/trash/{id}/restore [post]
+func (h *TrashHandler) Restore(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ID",
+		})
+	}
+
+	restored, err := h.trashService.Restore(c.Context(), id, userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Item not found in trash",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to restore item",
+		})
+	}
+
+	return c.JSON(restored)
+}
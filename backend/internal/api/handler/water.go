@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// WaterHandler handles water intake HTTP requests
+type WaterHandler struct {
+	waterService *service.WaterService
+}
+
+// NewWaterHandler creates a new water handler
+func NewWaterHandler(waterService *service.WaterService) *WaterHandler {
+	return &WaterHandler{
+		waterService: waterService,
+	}
+}
+
+// LogWater logs a drink
+// @Summary Log water
+// @Description Log a drink by amount or by a saved container
+// @Tags water
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.LogWaterRequest true "Log water request"
+// @Success 201 {object} entity.WaterLog
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/water [post]
+func (h *WaterHandler) LogWater(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.LogWaterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	log, err := h.waterService.LogWater(c.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidWaterAmount) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Container not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to log water",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(log)
+}
+
+// DeleteLog deletes a logged drink
+// @Summary Delete water log
+// @Description Delete a logged drink
+// @Tags water
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Water log ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/water/{id} [delete]
+func (h *WaterHandler) DeleteLog(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	logID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid water log ID",
+		})
+	}
+
+	if err := h.waterService.DeleteLog(c.Context(), logID, userID); err != nil {
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Water log not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete water log",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Water log deleted successfully",
+	})
+}
+
+// GetDailyHydration gets water intake for a date
+// @Summary Get daily hydration
+// @Description Get drinks and progress towards the recommended intake for a specific date
+// @Tags water
+// @Produce json
+// @Security Bearer
+// @Param date path string true "Date (YYYY-MM-DD format)"
+// @Success 200 {object} entity.DailyHydration
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/water/daily/{date} [get]
+func (h *WaterHandler) GetDailyHydration(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	date, err := time.Parse("2006-01-02", c.Params("date"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid date format. Use YYYY-MM-DD",
+		})
+	}
+
+	hydration, err := h.waterService.GetDailyHydration(c.Context(), userID, date)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get daily hydration",
+		})
+	}
+
+	return c.JSON(hydration)
+}
+
+// GetRangeStats gets water intake across a date range
+// @Summary Get water range stats
+// @Description Get per-day water totals, days on target and the daily average between two dates
+// @Tags water
+// @Produce json
+// @Security Bearer
+// @Param from query string true "Start date (YYYY-MM-DD format)"
+// @Param to query string true "End date (YYYY-MM-DD format, inclusive)"
+// @Success 200 {object} entity.WaterRangeStats
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/water/stats [get]
+func (h *WaterHandler) GetRangeStats(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	from, to, err := parseStatsRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	stats, err := h.waterService.GetRangeStats(c.Context(), userID, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get water stats",
+		})
+	}
+
+	return c.JSON(stats)
+}
+
+// GetContainers gets quick-add sizes and custom containers
+// @Summary Get water containers
+// @Description Get the preset quick-add sizes and the user's custom containers
+// @Tags water
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} entity.WaterContainers
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/water/containers [get]
+func (h *WaterHandler) GetContainers(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	containers, err := h.waterService.GetContainers(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get containers",
+		})
+	}
+
+	return c.JSON(containers)
+}
+
+// CreateContainer saves a custom container size
+// @Summary Create water container
+// @Description Save a custom container size for one-tap logging
+// @Tags water
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.CreateWaterContainerRequest true "Create container request"
+// @Success 201 {object} entity.WaterContainer
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/water/containers [post]
+func (h *WaterHandler) CreateContainer(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.CreateWaterContainerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	container, err := h.waterService.CreateContainer(c.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidWaterAmount) || errors.Is(err, service.ErrInvalidContainerName) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create container",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(container)
+}
+
+// DeleteContainer deletes a custom container
+// @Summary Delete water container
+// @Description Delete a custom container; past logs keep their amount
+// @Tags water
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Container ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/water/containers/{id} [delete]
+func (h *WaterHandler) DeleteContainer(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	containerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid container ID",
+		})
+	}
+
+	if err := h.waterService.DeleteContainer(c.Context(), containerID, userID); err != nil {
+		if err == repository.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Container not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete container",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Container deleted successfully",
+	})
+}
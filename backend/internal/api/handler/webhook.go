@@ -0,0 +1,404 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+// WebhookHandler handles webhook HTTP requests. The same routes serve a
+// user's own webhooks and, behind the admin middleware, app webhooks.
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// owner returns the webhook owner for the request: nil for admin requests,
+// otherwise the authenticated user
+func owner(c *fiber.Ctx) (*uuid.UUID, bool) {
+	if admin, _ := c.Locals("admin").(bool); admin {
+		return nil, true
+	}
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return nil, false
+	}
+	return &userID, true
+}
+
+// GetEventTypes lists the event types webhooks can subscribe to
+// @Summary List webhook event types
+// @Description List the event types webhooks can subscribe to
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} string
+// @Router /api/v1/webhooks/events [get]
+func (h *WebhookHandler) GetEventTypes(c *fiber.Ctx) error {
+	return c.JSON(entity.EventTypes)
+}
+
+// GetWebhooks lists webhooks
+// @Summary List webhooks
+// @Description List the user's webhooks, or app webhooks under /api/v1/admin/webhooks. Secrets are not included.
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} entity.Webhook
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *fiber.Ctx) error {
+	ownerID, ok := owner(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(c.Context(), ownerID)
+	if err != nil {
+		return webhookError(c, err, "Failed to get webhooks")
+	}
+
+	return c.JSON(webhooks)
+}
+
+// CreateWebhook registers a webhook
+// @Summary Create webhook
+// @Description Register an https endpoint for events; app webhooks may also use http. Private and loopback addresses are refused. An empty events list receives every event. Each delivery is a JSON POST signed in the X-ByteTrack-Signature header as t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">. The signing secret is only returned here and when rotated.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.CreateWebhookRequest true "Webhook"
+// @Success 201 {object} entity.Webhook
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	ownerID, ok := owner(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Context(), ownerID, &req)
+	if err != nil {
+		return webhookError(c, err, "Failed to create webhook")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(webhook)
+}
+
+// GetWebhook gets a webhook
+// @Summary Get webhook
+// @Description Get a webhook. The secret is not included.
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} entity.Webhook
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	ownerID, id, ok := webhookParams(c)
+	if !ok {
+		return nil
+	}
+
+	webhook, err := h.webhookService.GetWebhook(c.Context(), ownerID, id)
+	if err != nil {
+		return webhookError(c, err, "Failed to get webhook")
+	}
+
+	return c.JSON(webhook)
+}
+
+// UpdateWebhook updates a webhook
+// @Summary Update webhook
+// @Description Update a webhook's URL, events, description or active flag. Events for an inactive webhook are queued and sent once it is active again.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param request body entity.UpdateWebhookRequest true "Changes"
+// @Success 200 {object} entity.Webhook
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	ownerID, id, ok := webhookParams(c)
+	if !ok {
+		return nil
+	}
+
+	var req entity.UpdateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Context(), ownerID, id, &req)
+	if err != nil {
+		return webhookError(c, err, "Failed to update webhook")
+	}
+
+	return c.JSON(webhook)
+}
+
+// DeleteWebhook deletes a webhook
+// @Summary Delete webhook
+// @Description Delete a webhook with its deliveries and logs
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	ownerID, id, ok := webhookParams(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Context(), ownerID, id); err != nil {
+		return webhookError(c, err, "Failed to delete webhook")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// RotateSecret rotates a webhook's signing secret
+// @Summary Rotate webhook secret
+// @Description Replace the signing secret. The new secret is returned once; deliveries from now on are signed with it.
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} entity.Webhook
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/webhooks/{id}/rotate-secret [post]
+func (h *WebhookHandler) RotateSecret(c *fiber.Ctx) error {
+	ownerID, id, ok := webhookParams(c)
+	if !ok {
+		return nil
+	}
+
+	webhook, err := h.webhookService.RotateSecret(c.Context(), ownerID, id)
+	if err != nil {
+		return webhookError(c, err, "Failed to rotate webhook secret")
+	}
+
+	return c.JSON(webhook)
+}
+
+// GetDeliveries lists a webhook's deliveries
+// @Summary List webhook deliveries
+// @Description List a webhook's most recent deliveries, newest first. Dead deliveries ran out of retries or got 410 Gone and form the dead-letter queue.
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param status query string false "pending, succeeded or dead"
+// @Param limit query int false "Maximum deliveries" default(50)
+// @Success 200 {array} entity.WebhookDelivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *fiber.Ctx) error {
+	ownerID, id, ok := webhookParams(c)
+	if !ok {
+		return nil
+	}
+
+	var status *entity.DeliveryStatus
+	if s := c.Query("status"); s != "" {
+		value := entity.DeliveryStatus(s)
+		status = &value
+	}
+
+	limit := defaultDeliveryLimit
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 || parsed > maxDeliveryLimit {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be between 1 and " + strconv.Itoa(maxDeliveryLimit),
+			})
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(c.Context(), ownerID, id, status, limit)
+	if err != nil {
+		return webhookError(c, err, "Failed to get deliveries")
+	}
+
+	return c.JSON(deliveries)
+}
+
+// GetDelivery gets a delivery with its attempt log
+// @Summary Get webhook delivery
+// @Description Get a delivery with a log of every attempt: status code, error, duration and, for app webhooks, the start of the response body
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} entity.WebhookDelivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryId} [get]
+func (h *WebhookHandler) GetDelivery(c *fiber.Ctx) error {
+	ownerID, id, ok := webhookParams(c)
+	if !ok {
+		return nil
+	}
+	deliveryID, err := uuid.Parse(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid delivery ID",
+		})
+	}
+
+	delivery, err := h.webhookService.GetDelivery(c.Context(), ownerID, id, deliveryID)
+	if err != nil {
+		return webhookError(c, err, "Failed to get delivery")
+	}
+
+	return c.JSON(delivery)
+}
+
+// ReplayDelivery sends a delivery again
+// @Summary Replay webhook delivery
+// @Description Queue a delivery to be sent again with a fresh set of retries, whatever its status
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} entity.WebhookDelivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryId}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(c *fiber.Ctx) error {
+	ownerID, id, ok := webhookParams(c)
+	if !ok {
+		return nil
+	}
+	deliveryID, err := uuid.Parse(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid delivery ID",
+		})
+	}
+
+	delivery, err := h.webhookService.ReplayDelivery(c.Context(), ownerID, id, deliveryID)
+	if err != nil {
+		return webhookError(c, err, "Failed to replay delivery")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(delivery)
+}
+
+// ReplayDead sends every dead-lettered delivery again
+// @Summary Replay dead webhook deliveries
+// @Description Queue every dead delivery of a webhook to be sent again with a fresh set of retries
+// @Tags webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 202 {object} entity.ReplayResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/webhooks/{id}/replay [post]
+func (h *WebhookHandler) ReplayDead(c *fiber.Ctx) error {
+	ownerID, id, ok := webhookParams(c)
+	if !ok {
+		return nil
+	}
+
+	result, err := h.webhookService.ReplayDead(c.Context(), ownerID, id)
+	if err != nil {
+		return webhookError(c, err, "Failed to replay deliveries")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(result)
+}
+
+// webhookParams resolves the owner and webhook ID. When either is invalid
+// the error response is written and ok is false.
+func webhookParams(c *fiber.Ctx) (ownerID *uuid.UUID, id uuid.UUID, ok bool) {
+	ownerID, ok = owner(c)
+	if !ok {
+		_ = c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+		return nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+		return nil, uuid.Nil, false
+	}
+	return ownerID, id, true
+}
+
+// webhookError maps webhook service errors to HTTP responses
+func webhookError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidWebhook):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, repository.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Webhook not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/domain/service"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultTrendDays = 90
+	maxTrendDays     = 730
+)
+
+// WeightHandler handles body weight HTTP requests
+type WeightHandler struct {
+	weightService *service.WeightService
+}
+
+// NewWeightHandler creates a new weight handler
+func NewWeightHandler(weightService *service.WeightService) *WeightHandler {
+	return &WeightHandler{
+		weightService: weightService,
+	}
+}
+
+// GetEntries gets weight entries
+// @Summary Get weight entries
+// @Description Get weight entries with their trend weight; defaults to the last 90 days
+// @Tags weight
+// @Produce json
+// @Security Bearer
+// @Param from query string false "Start date (YYYY-MM-DD format)"
+// @Param to query string false "End date (YYYY-MM-DD format, inclusive)"
+// @Success 200 {array} entity.WeightEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/weight [get]
+func (h *WeightHandler) GetEntries(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -(defaultTrendDays - 1))
+	if c.Query("from") != "" || c.Query("to") != "" {
+		var err error
+		from, to, err = parseStatsRange(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	entries, err := h.weightService.GetEntries(c.Context(), userID, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get weight entries",
+		})
+	}
+
+	return c.JSON(entries)
+}
+
+// CreateEntry records a weight
+// @Summary Record weight
+// @Description Record a weight for a date, replacing any entry already on that date. The latest entry updates the profile weight and targets unless update_profile is false.
+// @Tags weight
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body entity.CreateWeightRequest true "Create weight request"
+// @Success 201 {object} entity.WeightEntryResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/weight [post]
+func (h *WeightHandler) CreateEntry(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.CreateWeightRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	result, err := h.weightService.CreateEntry(c.Context(), userID, &req)
+	if err != nil {
+		return weightError(c, err, "Failed to record weight")
+	}
+
+	if result.Profile != nil {
+		setETag(c, result.Profile.Version)
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// GetTrend gets the smoothed weight trend
+// @Summary Get weight trend
+// @Description Get exponentially smoothed trend weight and weekly rate of change
+// @Tags weight
+// @Produce json
+// @Security Bearer
+// @Param days query int false "Window in days (default 90, max 730)"
+// @Success 200 {object} entity.WeightTrend
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/weight/trend [get]
+func (h *WeightHandler) GetTrend(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	days := c.QueryInt("days", defaultTrendDays)
+	if days < 2 || days > maxTrendDays {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "days must be between 2 and 730",
+		})
+	}
+
+	trend, err := h.weightService.GetTrend(c.Context(), userID, days)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get weight trend",
+		})
+	}
+
+	return c.JSON(trend)
+}
+
+// GetEntryByID gets a weight entry by ID
+// @Summary Get weight entry by ID
+// @Description Get a specific weight entry by ID
+// @Tags weight
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Weight entry ID"
+// @Success 200 {object} entity.WeightEntry
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/weight/{id} [get]
+func (h *WeightHandler) GetEntryByID(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	entryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid weight entry ID",
+		})
+	}
+
+	entry, err := h.weightService.GetEntryByID(c.Context(), entryID, userID)
+	if err != nil {
+		return weightError(c, err, "Failed to get weight entry")
+	}
+
+	return c.JSON(entry)
+}
+
+// UpdateEntry updates a weight entry
+// @Summary Update weight entry
+// @Description Update a weight entry's weight, date or note
+// @Tags weight
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Weight entry ID"
+// @Param request body entity.UpdateWeightRequest true "Update weight request"
+// @Success 200 {object} entity.WeightEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/weight/{id} [put]
+func (h *WeightHandler) UpdateEntry(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	entryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid weight entry ID",
+		})
+	}
+
+	var req entity.UpdateWeightRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	entry, err := h.weightService.UpdateEntry(c.Context(), entryID, userID, &req)
+	if err != nil {
+		return weightError(c, err, "Failed to update weight entry")
+	}
+
+	return c.JSON(entry)
+}
+
+// DeleteEntry deletes a weight entry
+// @Summary Delete weight entry
+// @Description Delete a weight entry
+// @Tags weight
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Weight entry ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/weight/{id} [delete]
+func (h *WeightHandler) DeleteEntry(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	if userID == uuid.Nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	entryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid weight entry ID",
+		})
+	}
+
+	if err := h.weightService.DeleteEntry(c.Context(), entryID, userID); err != nil {
+		return weightError(c, err, "Failed to delete weight entry")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Weight entry deleted successfully",
+	})
+}
+
+// weightError maps weight service errors to HTTP responses
+func weightError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidWeight):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, repository.ErrDuplicateDate):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err == repository.ErrUserNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Weight entry not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": fallback,
+	})
+}
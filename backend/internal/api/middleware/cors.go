@@ -13,7 +13,8 @@ func CORSConfig(cfg *config.Config) fiber.Handler {
 	return cors.New(cors.Config{
 		AllowOrigins:     strings.Join(cfg.CORS.AllowedOrigins, ","),
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,If-Match",
+		ExposeHeaders:    "ETag",
 		AllowCredentials: true,
 		MaxAge:           86400, // 24 hours
 	})
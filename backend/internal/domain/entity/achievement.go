@@ -0,0 +1,77 @@
+package entity
+
+import "time"
+
+// AchievementMetric identifies the value an achievement's threshold applies to
+type AchievementMetric string
+
+const (
+	MetricMealsLogged   AchievementMetric = "meals_logged"
+	MetricLoggingStreak AchievementMetric = "logging_streak" // longest streak
+	MetricTargetStreak  AchievementMetric = "target_streak"
+	MetricProteinStreak AchievementMetric = "protein_streak"
+	MetricWaterStreak   AchievementMetric = "water_streak"
+	MetricTargetDays    AchievementMetric = "target_days"
+	MetricBarcodeScans  AchievementMetric = "barcode_scans"
+	MetricWeighIns      AchievementMetric = "weigh_ins"
+	MetricWeightLost    AchievementMetric = "weight_lost" // kg below the first recorded weight
+)
+
+// StreakKind represents what a day must achieve to count towards a streak
+type StreakKind string
+
+const (
+	StreakLogging StreakKind = "logging" // at least one meal logged
+	StreakTarget  StreakKind = "target"  // calories within the target tolerance
+	StreakProtein StreakKind = "protein" // protein target reached
+	StreakWater   StreakKind = "water"   // hydration goal reached
+	StreakFasting StreakKind = "fasting" // a fast completed; derived from fasts, not streak_days
+)
+
+// StreakKinds lists the streak kinds recorded in streak_days
+var StreakKinds = []StreakKind{StreakLogging, StreakTarget, StreakProtein, StreakWater}
+
+// Achievement represents a badge from the achievement catalog
+type Achievement struct {
+	ID            string            `json:"id" db:"id"`
+	Category      string            `json:"category" db:"category"`
+	Metric        AchievementMetric `json:"metric" db:"metric"`
+	Threshold     float64           `json:"threshold" db:"threshold"`
+	Name          string            `json:"name" db:"name"`
+	NameEn        string            `json:"name_en" db:"name_en"`
+	Description   string            `json:"description" db:"description"`
+	DescriptionEn string            `json:"description_en" db:"description_en"`
+	Emoji         *string           `json:"emoji,omitempty" db:"emoji"`
+	SortOrder     int               `json:"-" db:"sort_order"`
+}
+
+// AchievementProgress represents an achievement with the user's progress towards it
+type AchievementProgress struct {
+	*Achievement
+	Current    float64    `json:"current"`
+	Progress   float64    `json:"progress"` // 0-100 percent
+	Unlocked   bool       `json:"unlocked"`
+	UnlockedAt *time.Time `json:"unlocked_at,omitempty"`
+}
+
+// Streak represents a run of consecutive qualifying days in the user's
+// timezone. A missed day uses a freeze instead of breaking the streak;
+// freezes are earned every 7 streak days. Today does not break a streak
+// until it is over.
+type Streak struct {
+	Kind             StreakKind `json:"kind"`
+	Current          int        `json:"current"`
+	Longest          int        `json:"longest"`
+	FreezesAvailable int        `json:"freezes_available"`
+	FreezesUsed      int        `json:"freezes_used"` // frozen days in the current streak
+	LastDate         *time.Time `json:"last_date,omitempty"`
+	TodayComplete    bool       `json:"today_complete"`
+}
+
+// AchievementSummary represents streaks and achievement progress
+type AchievementSummary struct {
+	Streaks      []*Streak              `json:"streaks"`
+	Achievements []*AchievementProgress `json:"achievements"`
+	Unlocked     int                    `json:"unlocked"`
+	Total        int                    `json:"total"`
+}
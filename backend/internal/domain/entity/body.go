@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BMRFormula represents the equation used to calculate BMR
+type BMRFormula string
+
+const (
+	// BMRMifflinStJeor uses weight, height, age and gender
+	BMRMifflinStJeor BMRFormula = "mifflin_st_jeor"
+	// BMRKatchMcArdle uses lean body mass: 370 + 21.6 × LBM
+	BMRKatchMcArdle BMRFormula = "katch_mcardle"
+	// BMRCunningham uses lean body mass: 500 + 22 × LBM
+	BMRCunningham BMRFormula = "cunningham"
+)
+
+// Valid reports whether f is a known BMR formula
+func (f BMRFormula) Valid() bool {
+	switch f {
+	case BMRMifflinStJeor, BMRKatchMcArdle, BMRCunningham:
+		return true
+	}
+	return false
+}
+
+// NeedsLeanMass reports whether the formula requires a body fat percentage
+func (f BMRFormula) NeedsLeanMass() bool {
+	return f == BMRKatchMcArdle || f == BMRCunningham
+}
+
+// BodyFatSource describes where a body fat percentage came from
+type BodyFatSource string
+
+const (
+	BodyFatMeasured BodyFatSource = "measured"
+	BodyFatNavy     BodyFatSource = "us_navy"
+)
+
+// RiskLevel represents a health risk category from body measurements
+type RiskLevel string
+
+const (
+	RiskLow       RiskLevel = "low"
+	RiskIncreased RiskLevel = "increased"
+	RiskHigh      RiskLevel = "high"
+)
+
+// BodyMeasurement represents body fat and circumference measurements (cm);
+// there is at most one per day. BodyFat and WaistToHeightRatio are derived.
+type BodyMeasurement struct {
+	ID                 uuid.UUID      `json:"id" db:"id"`
+	UserID             uuid.UUID      `json:"user_id" db:"user_id"`
+	Date               time.Time      `json:"date" db:"date"`
+	BodyFatPercent     *float64       `json:"body_fat_percent,omitempty" db:"body_fat_percent"`
+	WaistCM            *float64       `json:"waist_cm,omitempty" db:"waist_cm"`
+	HipCM              *float64       `json:"hip_cm,omitempty" db:"hip_cm"`
+	NeckCM             *float64       `json:"neck_cm,omitempty" db:"neck_cm"`
+	Note               *string        `json:"note,omitempty" db:"note"`
+	BodyFat            *float64       `json:"body_fat,omitempty" db:"-"`
+	BodyFatSource      *BodyFatSource `json:"body_fat_source,omitempty" db:"-"`
+	WaistToHeightRatio *float64       `json:"waist_to_height_ratio,omitempty" db:"-"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// CreateBodyMeasurementRequest represents a request to log body measurements.
+// At least one value is required; a measurement for a date that already has
+// one replaces it. UpdateProfile defaults to true and only applies when the
+// measurement is the user's most recent one.
+type CreateBodyMeasurementRequest struct {
+	BodyFatPercent *float64   `json:"body_fat_percent,omitempty" validate:"omitempty,min=2,max=70"`
+	WaistCM        *float64   `json:"waist_cm,omitempty" validate:"omitempty,min=20,max=250"`
+	HipCM          *float64   `json:"hip_cm,omitempty" validate:"omitempty,min=20,max=250"`
+	NeckCM         *float64   `json:"neck_cm,omitempty" validate:"omitempty,min=20,max=250"`
+	Date           *time.Time `json:"date,omitempty"`
+	Note           *string    `json:"note,omitempty"`
+	UpdateProfile  *bool      `json:"update_profile,omitempty"`
+}
+
+// UpdateBodyMeasurementRequest represents update body measurement request
+type UpdateBodyMeasurementRequest struct {
+	BodyFatPercent *float64   `json:"body_fat_percent,omitempty" validate:"omitempty,min=2,max=70"`
+	WaistCM        *float64   `json:"waist_cm,omitempty" validate:"omitempty,min=20,max=250"`
+	HipCM          *float64   `json:"hip_cm,omitempty" validate:"omitempty,min=20,max=250"`
+	NeckCM         *float64   `json:"neck_cm,omitempty" validate:"omitempty,min=20,max=250"`
+	Date           *time.Time `json:"date,omitempty"`
+	Note           *string    `json:"note,omitempty"`
+}
+
+// BodyMeasurementResult represents a logged measurement and, when the profile
+// body fat was updated from it, the recalculated profile
+type BodyMeasurementResult struct {
+	Measurement *BodyMeasurement `json:"measurement"`
+	Profile     *UserProfile     `json:"profile,omitempty"`
+}
+
+// SetBMRFormulaRequest represents a request to choose the BMR formula
+type SetBMRFormulaRequest struct {
+	Formula BMRFormula `json:"formula" validate:"required,oneof=mifflin_st_jeor katch_mcardle cunningham"`
+}
+
+// BodyComposition summarises the user's current body composition and the
+// risk indicators derived from their latest circumferences
+type BodyComposition struct {
+	Weight         float64        `json:"weight"`
+	Height         float64        `json:"height"`
+	BodyFatPercent *float64       `json:"body_fat_percent,omitempty"`
+	BodyFatSource  *BodyFatSource `json:"body_fat_source,omitempty"`
+	LeanMass       *float64       `json:"lean_mass,omitempty"` // kg
+	FatMass        *float64       `json:"fat_mass,omitempty"`  // kg
+	MeasuredAt     *time.Time     `json:"measured_at,omitempty"`
+
+	WaistCM            *float64   `json:"waist_cm,omitempty"`
+	HipCM              *float64   `json:"hip_cm,omitempty"`
+	WaistToHeightRatio *float64   `json:"waist_to_height_ratio,omitempty"`
+	WaistToHeightRisk  *RiskLevel `json:"waist_to_height_risk,omitempty"`
+	WaistRisk          *RiskLevel `json:"waist_risk,omitempty"` // Asian waist cut-offs
+	WaistToHipRatio    *float64   `json:"waist_to_hip_ratio,omitempty"`
+	WaistToHipRisk     *RiskLevel `json:"waist_to_hip_risk,omitempty"`
+
+	BMRFormula   BMRFormula         `json:"bmr_formula"`
+	BMR          int                `json:"bmr"`
+	BMRByFormula map[BMRFormula]int `json:"bmr_by_formula"` // formulas that can be calculated
+}
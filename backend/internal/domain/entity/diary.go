@@ -0,0 +1,71 @@
+package entity
+
+// ExportFormat is the file format of a food diary export
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+)
+
+// ImportSource identifies the app a food diary import was exported from
+type ImportSource string
+
+const (
+	ImportAuto         ImportSource = "auto" // detected from the header row
+	ImportByteTrack    ImportSource = "bytetrack"
+	ImportMyFitnessPal ImportSource = "myfitnesspal"
+	ImportLoseIt       ImportSource = "loseit"
+)
+
+// ImportSources lists the sources that can be imported
+var ImportSources = []ImportSource{ImportByteTrack, ImportMyFitnessPal, ImportLoseIt}
+
+// ImportOptions controls how a food diary file is read and imported
+type ImportOptions struct {
+	Source ImportSource `json:"source"`
+	// Locale decides day/month order and decimal separators, e.g. "th",
+	// "en-US" or "de". Defaults to en-US for MyFitnessPal and Lose It! and
+	// to th otherwise.
+	Locale string `json:"locale,omitempty"`
+	// Mapping maps file column headers to fields: date, time, eaten_at,
+	// meal_type, name, name_en, grams, quantity, units, calories, protein,
+	// carbs, fat, fiber, sugar, sodium, note, deleted, a registry nutrient ID,
+	// or "ignore". It extends or overrides the source's own columns.
+	Mapping         map[string]string `json:"mapping,omitempty"`
+	DryRun          bool              `json:"dry_run"`
+	AllowDuplicates bool              `json:"allow_duplicates"` // import rows matching an existing meal
+}
+
+// ImportRowStatus is the outcome of importing one row
+type ImportRowStatus string
+
+const (
+	ImportRowValid     ImportRowStatus = "valid" // would be imported (dry run)
+	ImportRowImported  ImportRowStatus = "imported"
+	ImportRowDuplicate ImportRowStatus = "duplicate"
+	ImportRowInvalid   ImportRowStatus = "invalid"
+	ImportRowSkipped   ImportRowStatus = "skipped" // deleted or exercise entries
+)
+
+// ImportRow reports the outcome of one data row. Row numbers count the
+// header as row 1, matching spreadsheet line numbers.
+type ImportRow struct {
+	Row    int             `json:"row"`
+	Status ImportRowStatus `json:"status"`
+	Errors []string        `json:"errors,omitempty"`
+	Meal   *Meal           `json:"meal,omitempty"`
+}
+
+// ImportResult summarizes a food diary import
+type ImportResult struct {
+	Source     ImportSource `json:"source"`
+	DryRun     bool         `json:"dry_run"`
+	Rows       int          `json:"rows"`
+	Valid      int          `json:"valid"`
+	Imported   int          `json:"imported"`
+	Duplicates int          `json:"duplicates"`
+	Invalid    int          `json:"invalid"`
+	Skipped    int          `json:"skipped"`
+	Results    []ImportRow  `json:"results"`
+}
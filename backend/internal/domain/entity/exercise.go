@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExerciseIntensity represents how hard an activity was performed
+type ExerciseIntensity string
+
+const (
+	IntensityLight    ExerciseIntensity = "light"
+	IntensityModerate ExerciseIntensity = "moderate"
+	IntensityVigorous ExerciseIntensity = "vigorous"
+)
+
+// Valid reports whether the intensity is one of the known levels
+func (i ExerciseIntensity) Valid() bool {
+	switch i {
+	case IntensityLight, IntensityModerate, IntensityVigorous:
+		return true
+	}
+	return false
+}
+
+// ExerciseActivity represents an activity in the MET catalog
+type ExerciseActivity struct {
+	ID          string     `json:"id" db:"id"`
+	UserID      *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	Name        string     `json:"name" db:"name"`
+	NameEn      string     `json:"name_en" db:"name_en"`
+	Category    string     `json:"category" db:"category"`
+	METLight    float64    `json:"met_light" db:"met_light"`
+	METModerate float64    `json:"met_moderate" db:"met_moderate"`
+	METVigorous float64    `json:"met_vigorous" db:"met_vigorous"`
+	Emoji       *string    `json:"emoji,omitempty" db:"emoji"`
+	IsCustom    bool       `json:"is_custom" db:"-"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// MET gets the metabolic equivalent for an intensity
+func (a *ExerciseActivity) MET(intensity ExerciseIntensity) float64 {
+	switch intensity {
+	case IntensityLight:
+		return a.METLight
+	case IntensityVigorous:
+		return a.METVigorous
+	default:
+		return a.METModerate
+	}
+}
+
+// CreateActivityRequest represents a request to add a custom activity.
+// MET values left at zero fall back to met_moderate.
+type CreateActivityRequest struct {
+	Name        string  `json:"name" validate:"required"`
+	NameEn      string  `json:"name_en"`
+	Category    string  `json:"category"`
+	METLight    float64 `json:"met_light"`
+	METModerate float64 `json:"met_moderate" validate:"required,min=0"`
+	METVigorous float64 `json:"met_vigorous"`
+	Emoji       *string `json:"emoji,omitempty"`
+}
+
+// ExerciseLog represents a logged exercise session. The activity name, MET
+// and body weight are stored so past entries are unaffected by later changes.
+type ExerciseLog struct {
+	ID              uuid.UUID         `json:"id" db:"id"`
+	UserID          uuid.UUID         `json:"user_id" db:"user_id"`
+	ActivityID      *string           `json:"activity_id" db:"activity_id"`
+	ActivityName    string            `json:"activity_name" db:"activity_name"`
+	ActivityNameEn  string            `json:"activity_name_en" db:"activity_name_en"`
+	Intensity       ExerciseIntensity `json:"intensity" db:"intensity"`
+	DurationMinutes int               `json:"duration_minutes" db:"duration_minutes"`
+	MET             float64           `json:"met" db:"met"`
+	Weight          float64           `json:"weight" db:"weight"`
+	CaloriesBurned  int               `json:"calories_burned" db:"calories_burned"`
+	Notes           *string           `json:"notes,omitempty" db:"notes"`
+	Date            time.Time         `json:"date" db:"date"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// CreateExerciseRequest represents create exercise log request
+type CreateExerciseRequest struct {
+	ActivityID      string            `json:"activity_id" validate:"required"`
+	Intensity       ExerciseIntensity `json:"intensity"`
+	DurationMinutes int               `json:"duration_minutes" validate:"required,min=1,max=1440"`
+	Notes           *string           `json:"notes,omitempty"`
+	Date            *time.Time        `json:"date,omitempty"`
+}
+
+// UpdateExerciseRequest represents update exercise log request
+type UpdateExerciseRequest struct {
+	ActivityID      *string            `json:"activity_id,omitempty"`
+	Intensity       *ExerciseIntensity `json:"intensity,omitempty"`
+	DurationMinutes *int               `json:"duration_minutes,omitempty"`
+	Notes           *string            `json:"notes,omitempty"`
+	Date            *time.Time         `json:"date,omitempty"`
+}
+
+// DailyExercise represents exercise totals for a single day
+type DailyExercise struct {
+	Logs           []*ExerciseLog `json:"logs"`
+	CaloriesBurned int            `json:"calories_burned"`
+	Minutes        int            `json:"minutes"`
+}
@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FastingProtocol represents an intermittent fasting schedule
+type FastingProtocol string
+
+const (
+	Fasting16_8   FastingProtocol = "16:8"
+	Fasting18_6   FastingProtocol = "18:6"
+	Fasting20_4   FastingProtocol = "20:4"
+	FastingOMAD   FastingProtocol = "omad" // one meal a day
+	FastingCustom FastingProtocol = "custom"
+)
+
+// FastingProtocolInfo describes a preset fasting protocol
+type FastingProtocolInfo struct {
+	Protocol     FastingProtocol `json:"protocol"`
+	Name         string          `json:"name"`
+	NameEn       string          `json:"name_en"`
+	FastingHours float64         `json:"fasting_hours"`
+	EatingHours  float64         `json:"eating_hours"`
+}
+
+// FastingProtocols lists the preset protocols
+var FastingProtocols = []FastingProtocolInfo{
+	{Protocol: Fasting16_8, Name: "อด 16 ชั่วโมง กิน 8 ชั่วโมง", NameEn: "16:8", FastingHours: 16, EatingHours: 8},
+	{Protocol: Fasting18_6, Name: "อด 18 ชั่วโมง กิน 6 ชั่วโมง", NameEn: "18:6", FastingHours: 18, EatingHours: 6},
+	{Protocol: Fasting20_4, Name: "อด 20 ชั่วโมง กิน 4 ชั่วโมง", NameEn: "20:4 (Warrior)", FastingHours: 20, EatingHours: 4},
+	{Protocol: FastingOMAD, Name: "กินมื้อเดียวต่อวัน", NameEn: "One meal a day", FastingHours: 23, EatingHours: 1},
+}
+
+// Fast represents a fasting session. A fast is broken when a meal is logged
+// inside its window, which also ends it.
+type Fast struct {
+	ID             uuid.UUID       `json:"id" db:"id"`
+	UserID         uuid.UUID       `json:"user_id" db:"user_id"`
+	Protocol       FastingProtocol `json:"protocol" db:"protocol"`
+	TargetHours    float64         `json:"target_hours" db:"target_hours"`
+	StartedAt      time.Time       `json:"started_at" db:"started_at"`
+	EndedAt        *time.Time      `json:"ended_at,omitempty" db:"ended_at"`
+	Broken         bool            `json:"broken" db:"broken"`
+	BrokenByMealID *uuid.UUID      `json:"broken_by_meal_id,omitempty" db:"broken_by_meal_id"`
+	AutoStarted    bool            `json:"auto_started" db:"auto_started"` // started from a logged meal
+	Note           *string         `json:"note,omitempty" db:"note"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+
+	// Derived
+	TargetEndAt   time.Time `json:"target_end_at" db:"-"`
+	DurationHours float64   `json:"duration_hours" db:"-"` // so far for an active fast
+	Completed     bool      `json:"completed" db:"-"`      // ended unbroken after reaching the target
+}
+
+// FastStatus represents the current fast's progress, or the time since the
+// last meal when no fast is active
+type FastStatus struct {
+	Active             bool       `json:"active"`
+	Fast               *Fast      `json:"fast,omitempty"`
+	ElapsedSeconds     int64      `json:"elapsed_seconds"`
+	RemainingSeconds   int64      `json:"remaining_seconds"`
+	Progress           float64    `json:"progress"` // 0-100 percent of the target
+	TargetReached      bool       `json:"target_reached"`
+	LastMealAt         *time.Time `json:"last_meal_at,omitempty"`
+	HoursSinceLastMeal *float64   `json:"hours_since_last_meal,omitempty"`
+}
+
+// StartFastRequest represents a request to start a fast. Without a protocol
+// the preferred one is used; target_hours is required for custom.
+type StartFastRequest struct {
+	Protocol     FastingProtocol `json:"protocol,omitempty"`
+	TargetHours  *float64        `json:"target_hours,omitempty"`
+	StartedAt    *time.Time      `json:"started_at,omitempty"`     // defaults to now
+	FromLastMeal bool            `json:"from_last_meal,omitempty"` // start at the last meal's eaten_at
+	Note         *string         `json:"note,omitempty"`
+}
+
+// EndFastRequest represents a request to end the active fast
+type EndFastRequest struct {
+	EndedAt *time.Time `json:"ended_at,omitempty"` // defaults to now
+}
+
+// FastingPreferences represents a user's default protocol. With auto start,
+// logging a meal starts a fast from its eaten_at.
+type FastingPreferences struct {
+	UserID      uuid.UUID       `json:"user_id" db:"user_id"`
+	Protocol    FastingProtocol `json:"protocol" db:"protocol"`
+	TargetHours float64         `json:"target_hours" db:"target_hours"`
+	AutoStart   bool            `json:"auto_start" db:"auto_start"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateFastingPreferencesRequest represents a request to update fasting preferences
+type UpdateFastingPreferencesRequest struct {
+	Protocol    *FastingProtocol `json:"protocol,omitempty"`
+	TargetHours *float64         `json:"target_hours,omitempty"` // required for custom
+	AutoStart   *bool            `json:"auto_start,omitempty"`
+}
+
+// FastingStats represents the fasting streak and totals. A day counts towards
+// the streak when a completed fast ended on it in the user's timezone.
+type FastingStats struct {
+	Streak         *Streak `json:"streak"`
+	TotalFasts     int     `json:"total_fasts"`
+	CompletedFasts int     `json:"completed_fasts"`
+	BrokenFasts    int     `json:"broken_fasts"`
+	CompletionRate float64 `json:"completion_rate"` // percent of ended fasts
+	AverageHours   float64 `json:"average_hours"`
+	LongestHours   float64 `json:"longest_hours"`
+}
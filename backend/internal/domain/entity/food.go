@@ -13,6 +13,7 @@ const (
 	FoodSourceLocal          FoodSource = "local"
 	FoodSourceOpenFoodFacts FoodSource = "openfoodfacts"
 	FoodSourceUSDA          FoodSource = "usda"
+	FoodSourceCustom        FoodSource = "custom"
 )
 
 // NutritionInfo represents nutritional information
@@ -26,6 +27,15 @@ type NutritionInfo struct {
 	Sodium       *int     `json:"sodium,omitempty"`
 	ServingSize  float64 `json:"serving_size"`
 	ServingUnit  string  `json:"serving_unit"`
+	Nutrients    Nutrients `json:"nutrients,omitempty"`
+	Portions     []FoodPortion `json:"portions,omitempty"`
+}
+
+// FoodPortion is a household portion of a food, such as "1 cup, chopped",
+// and its weight, so amounts per 100 g can be scaled to it
+type FoodPortion struct {
+	Name       string  `json:"name"`
+	GramWeight float64 `json:"gram_weight"`
 }
 
 // FoodItem represents a food item
@@ -58,6 +68,7 @@ type ThaiFood struct {
 	ServingSize float64      `json:"serving_size" db:"serving_size"`
 	ServingUnit string       `json:"serving_unit" db:"serving_unit"`
 	Emoji       *string      `json:"emoji,omitempty" db:"emoji"`
+	Nutrients   Nutrients    `json:"nutrients,omitempty" db:"nutrients"`
 }
 
 // ToFoodItem converts ThaiFood to FoodItem
@@ -77,6 +88,7 @@ func (t *ThaiFood) ToFoodItem() FoodItem {
 			Sodium:      t.Sodium,
 			ServingSize: t.ServingSize,
 			ServingUnit: t.ServingUnit,
+			Nutrients:   t.Nutrients,
 		},
 		Source: FoodSourceLocal,
 		Emoji:  t.Emoji,
@@ -97,8 +109,28 @@ type SearchResult struct {
 	Total   int        `json:"total"`
 	Page    int        `json:"page"`
 	HasMore bool       `json:"has_more"`
+
+	// Sources reports how each search source fared, so clients can tell
+	// partial results from complete ones
+	Sources map[string]SourceStatus `json:"sources"`
 }
 
+// Search sources
+const (
+	SearchSourceLocal         = "local"
+	SearchSourceOpenFoodFacts = "openfoodfacts"
+	SearchSourceUSDA          = "usda"
+)
+
+// SourceStatus is how a search source fared
+type SourceStatus string
+
+const (
+	SourceOK       SourceStatus = "ok"
+	SourceDegraded SourceStatus = "degraded" // failed; results are partial
+	SourceSkipped  SourceStatus = "skipped"  // not needed for this page
+)
+
 // OpenFoodFactsCache represents cached Open Food Facts data
 type OpenFoodFactsCache struct {
 	Barcode     string       `json:"barcode" db:"barcode"`
@@ -125,6 +157,7 @@ type OnboardingRequest struct {
 	GoalWeight    float64     `json:"goal_weight" validate:"min=30,max=300"`
 	ActivityLevel ActivityLevel `json:"activity_level" validate:"required,oneof=sedentary light moderate very extreme"`
 	Goal          Goal        `json:"goal" validate:"required,oneof=lose maintain gain"`
+	Timezone      string      `json:"timezone,omitempty"` // IANA name; keeps the current timezone when empty
 }
 
 // OnboardingResponse represents the response after completing onboarding
@@ -136,4 +169,43 @@ type OnboardingResponse struct {
 	ProteinTarget  int          `json:"protein_target"`
 	CarbsTarget    int          `json:"carbs_target"`
 	FatTarget      int          `json:"fat_target"`
+	Version        int          `json:"version"`
+}
+
+// BarcodeCacheStats reports how barcode lookups were served since startup
+type BarcodeCacheStats struct {
+	Entries       int    `json:"entries"`
+	MaxEntries    int    `json:"max_entries"`
+	Hits          uint64 `json:"hits"`
+	StaleHits     uint64 `json:"stale_hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Fetches       uint64 `json:"fetches"`
+	SharedFetches uint64 `json:"shared_fetches"` // lookups that joined an in-flight fetch
+	Refreshes     uint64 `json:"refreshes"`
+	FetchErrors   uint64 `json:"fetch_errors"`
+}
+
+// LoggedFood is a food the user has logged meals from. Food is as last
+// logged, with the portion eaten as its serving, so it can be logged again
+// as is.
+type LoggedFood struct {
+	Food         FoodItem         `json:"food"`
+	Count        int              `json:"count"` // meals logged from it
+	LastLoggedAt time.Time        `json:"last_logged_at"`
+	MealTypes    map[MealType]int `json:"meal_types"` // meals logged from it per meal type
+}
+
+// USDA food sources
+const (
+	USDASourceAPI     = "api"
+	USDASourceDataset = "dataset"
+)
+
+// USDAFood is a USDA FoodData Central food stored locally
+type USDAFood struct {
+	FdcID    int      `json:"fdc_id"`
+	DataType string   `json:"data_type"` // e.g. Foundation or SR Legacy
+	Source   string   `json:"source"`    // api or dataset
+	Food     FoodItem `json:"food"`
 }
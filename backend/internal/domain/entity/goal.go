@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+// DeficitSource describes where the daily energy balance of a projection comes from
+type DeficitSource string
+
+const (
+	DeficitLogged DeficitSource = "logged" // average intake from logged meals
+	DeficitTarget DeficitSource = "target" // planned from the calorie target, used until enough meals are logged
+)
+
+// GoalProgress represents progress towards the profile's goal weight with a
+// projection at the current energy balance. Projection fields are nil when
+// there is no goal weight, the goal is reached, or the balance does not move
+// towards it.
+type GoalProgress struct {
+	Goal            Goal       `json:"goal"`
+	StartWeight     float64    `json:"start_weight"`
+	StartDate       *time.Time `json:"start_date,omitempty"`
+	CurrentWeight   float64    `json:"current_weight"`
+	CurrentDate     *time.Time `json:"current_date,omitempty"`
+	GoalWeight      *float64   `json:"goal_weight,omitempty"`
+	Remaining       *float64   `json:"remaining,omitempty"` // kg still to lose or gain
+	PercentComplete *float64   `json:"percent_complete,omitempty"`
+	GoalReached     bool       `json:"goal_reached"`
+
+	TDEE           int           `json:"tdee"`
+	TargetCalories int           `json:"target_calories"`
+	AverageIntake  *int          `json:"average_intake,omitempty"`
+	DaysLogged     int           `json:"days_logged"`
+	DailyDeficit   int           `json:"daily_deficit"` // TDEE minus intake, negative for a surplus
+	DeficitSource  DeficitSource `json:"deficit_source"`
+	WeeklyRate     float64       `json:"weekly_rate"` // expected kg per week, negative when losing
+
+	WeeksRemaining  *int       `json:"weeks_remaining,omitempty"`
+	MonthsRemaining *float64   `json:"months_remaining,omitempty"`
+	ProjectedDate   *time.Time `json:"projected_date,omitempty"`
+	SafeRate        *bool      `json:"safe_rate,omitempty"`
+	Warning         string     `json:"warning,omitempty"`
+
+	BMI            float64 `json:"bmi"`
+	BMICategory    string  `json:"bmi_category"`
+	IdealWeightMin float64 `json:"ideal_weight_min"`
+	IdealWeightMax float64 `json:"ideal_weight_max"`
+
+	SuggestedGoal *Goal  `json:"suggested_goal,omitempty"`
+	Suggestion    string `json:"suggestion,omitempty"`
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -35,10 +37,24 @@ type Meal struct {
 	Sugar   *float64 `json:"sugar,omitempty" db:"sugar"`
 	Sodium  *int     `json:"sodium,omitempty" db:"sodium"`
 
+	// Additional nutrients from the nutrient registry
+	Nutrients Nutrients `json:"nutrients,omitempty" db:"nutrients"`
+
 	ImageURL *string    `json:"image_url,omitempty" db:"image_url"`
 	Date     time.Time  `json:"date" db:"date"`
+	EatenAt  *time.Time `json:"eaten_at,omitempty" db:"eaten_at"` // when the meal was eaten, if known
+	FoodID   *string    `json:"food_id,omitempty" db:"food_id"`   // search result ID of the food it was logged from
+
+	// Uploaded photo blob keys; exposed to clients only as signed URLs
+	PhotoKey     *string `json:"-" db:"photo_key"`
+	ThumbnailKey *string `json:"-" db:"thumbnail_key"`
+	PhotoURL     *string `json:"photo_url,omitempty" db:"-"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty" db:"-"`
+
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	Version   int        `json:"version" db:"version"`
 }
 
 // CreateMealRequest represents a request to create a meal
@@ -54,8 +70,36 @@ type CreateMealRequest struct {
 	Fiber     *float64  `json:"fiber,omitempty"`
 	Sugar     *float64  `json:"sugar,omitempty"`
 	Sodium    *int      `json:"sodium,omitempty"`
+	Nutrients Nutrients `json:"nutrients,omitempty"`
 	ImageURL  *string   `json:"image_url,omitempty"`
 	Date      *time.Time `json:"date,omitempty"`
+	EatenAt   *time.Time `json:"eaten_at,omitempty"` // defaults to now when logging for today
+	Barcode   *string   `json:"barcode,omitempty" validate:"omitempty,number,min=8,max=14"` // set when the food was scanned; not stored
+	FoodID    *string   `json:"food_id,omitempty" validate:"omitempty,max=255"` // search result ID the meal was logged from
+}
+
+// ErrInvalidBarcode is returned when a scanned barcode is not 8 to 14 digits
+var ErrInvalidBarcode = errors.New("barcode must be 8 to 14 digits")
+
+// Scanned reports whether the meal was logged from a scanned barcode. An
+// empty barcode is not a scan; anything else must be an 8 to 14 digit GTIN.
+func (r *CreateMealRequest) Scanned() (bool, error) {
+	if r.Barcode == nil {
+		return false, nil
+	}
+	barcode := strings.TrimSpace(*r.Barcode)
+	if barcode == "" {
+		return false, nil
+	}
+	if len(barcode) < 8 || len(barcode) > 14 {
+		return false, ErrInvalidBarcode
+	}
+	for _, c := range barcode {
+		if c < '0' || c > '9' {
+			return false, ErrInvalidBarcode
+		}
+	}
+	return true, nil
 }
 
 // UpdateMealRequest represents a request to update a meal
@@ -71,8 +115,10 @@ type UpdateMealRequest struct {
 	Fiber     *float64  `json:"fiber,omitempty"`
 	Sugar     *float64  `json:"sugar,omitempty"`
 	Sodium    *int      `json:"sodium,omitempty"`
+	Nutrients Nutrients `json:"nutrients,omitempty"`
 	ImageURL  *string   `json:"image_url,omitempty"`
 	Date      *time.Time `json:"date,omitempty"`
+	EatenAt   *time.Time `json:"eaten_at,omitempty"`
 }
 
 // DailyMacros represents daily macro totals
@@ -81,13 +127,92 @@ type DailyMacros struct {
 	Protein  float64 `json:"protein"`
 	Carbs    float64 `json:"carbs"`
 	Fat      float64 `json:"fat"`
+	Fiber    float64 `json:"fiber"`
+	Sugar    float64 `json:"sugar"`
+	Sodium   int     `json:"sodium"`
+
+	Nutrients Nutrients `json:"nutrients,omitempty"`
+}
+
+// TargetStatus describes how a consumed amount compares to its target
+type TargetStatus string
+
+const (
+	TargetStatusUnder   TargetStatus = "under"
+	TargetStatusOnTrack TargetStatus = "on_track"
+	TargetStatusOver    TargetStatus = "over"
+)
+
+// DailyTargets represents a user's daily nutrition targets.
+// Sugar and sodium are upper limits rather than goals.
+type DailyTargets struct {
+	Calories int     `json:"calories"`
+	Protein  int     `json:"protein"`
+	Carbs    int     `json:"carbs"`
+	Fat      int     `json:"fat"`
+	Fiber    float64 `json:"fiber"`
+	Sugar    float64 `json:"sugar"`
+	Sodium   int     `json:"sodium"`
+}
+
+// NutrientProgress represents progress towards a single daily target
+type NutrientProgress struct {
+	Consumed  float64      `json:"consumed"`
+	Target    float64      `json:"target"`
+	Remaining float64      `json:"remaining"`
+	Percent   float64      `json:"percent"`
+	Status    TargetStatus `json:"status"`
+}
+
+// DailyProgress represents progress towards each daily target
+type DailyProgress struct {
+	Calories NutrientProgress `json:"calories"`
+	Protein  NutrientProgress `json:"protein"`
+	Carbs    NutrientProgress `json:"carbs"`
+	Fat      NutrientProgress `json:"fat"`
+	Fiber    NutrientProgress `json:"fiber"`
+	Sugar    NutrientProgress `json:"sugar"`
+	Sodium   NutrientProgress `json:"sodium"`
+}
+
+// MealTypeBreakdown represents totals for one meal slot
+type MealTypeBreakdown struct {
+	MealType          MealType    `json:"meal_type"`
+	MealCount         int         `json:"meal_count"`
+	Totals            DailyMacros `json:"totals"`
+	PercentOfCalories float64     `json:"percent_of_calories"`
 }
 
 // DailyStats represents daily statistics
 type DailyStats struct {
-	Date   time.Time    `json:"date"`
-	Meals  []*Meal      `json:"meals"`
-	Totals DailyMacros  `json:"totals"`
+	Date        time.Time           `json:"date"`
+	Meals       []*Meal             `json:"meals"`
+	Totals      DailyMacros         `json:"totals"`
+	Targets     *DailyTargets       `json:"targets,omitempty"`
+	Progress    *DailyProgress      `json:"progress,omitempty"`
+	ByMealType  []MealTypeBreakdown `json:"by_meal_type"`
+	Hydration   *DailyHydration     `json:"hydration,omitempty"`
+	Exercise    *DailyExercise      `json:"exercise,omitempty"`
+	NetCalories int                 `json:"net_calories"` // consumed minus burned by exercise
+	OverTarget  bool                `json:"over_target"`
+	UnderTarget bool                `json:"under_target"`
+}
+
+// DayTotals represents nutrition totals for a single day
+type DayTotals struct {
+	Date      time.Time   `json:"date"`
+	MealCount int         `json:"meal_count"`
+	Totals    DailyMacros `json:"totals"`
+}
+
+// RangeStats represents nutrition statistics over a date range
+type RangeStats struct {
+	From       time.Time    `json:"from"`
+	To         time.Time    `json:"to"`
+	Days       []*DayTotals `json:"days"`
+	DaysLogged int          `json:"days_logged"`
+	Totals     DailyMacros  `json:"totals"`
+	Averages   DailyMacros  `json:"averages"` // per logged day
 }
 
 // FavoriteFood represents a user's favorite food
@@ -108,6 +233,7 @@ type FavoriteFood struct {
 	ServingSize float64  `json:"serving_size" db:"serving_size"`
 	ServingUnit string   `json:"serving_unit" db:"serving_unit"`
 	Emoji      *string   `json:"emoji,omitempty" db:"emoji"`
+	Nutrients  Nutrients `json:"nutrients,omitempty" db:"nutrients"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -125,8 +251,36 @@ type CustomFood struct {
 	Sodium        *int      `json:"sodium,omitempty" db:"sodium"`
 	ServingSize   float64   `json:"serving_size" db:"serving_size"`
 	ServingUnit   string    `json:"serving_unit" db:"serving_unit"`
+	Nutrients     Nutrients `json:"nutrients,omitempty" db:"nutrients"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// CustomFoodIDPrefix prefixes custom food IDs in food results
+const CustomFoodIDPrefix = "custom_"
+
+// ToFoodItem converts CustomFood to FoodItem
+func (f *CustomFood) ToFoodItem() FoodItem {
+	return FoodItem{
+		ID:       CustomFoodIDPrefix + f.ID.String(),
+		Name:     f.Name,
+		NameEn:   f.Name,
+		Category: "custom",
+		Nutrition: NutritionInfo{
+			Calories:    f.Calories,
+			Protein:     f.Protein,
+			Carbs:       f.Carbs,
+			Fat:         f.Fat,
+			Fiber:       f.Fiber,
+			Sugar:       f.Sugar,
+			Sodium:      f.Sodium,
+			ServingSize: f.ServingSize,
+			ServingUnit: f.ServingUnit,
+			Nutrients:   f.Nutrients,
+		},
+		Source: FoodSourceCustom,
+	}
 }
 
 // CreateCustomFoodRequest represents a request to create a custom food
@@ -141,6 +295,7 @@ type CreateCustomFoodRequest struct {
 	Sodium      *int     `json:"sodium,omitempty"`
 	ServingSize float64  `json:"serving_size"`
 	ServingUnit string   `json:"serving_unit"`
+	Nutrients   Nutrients `json:"nutrients,omitempty"`
 }
 
 // AddFavoriteRequest represents a request to add a favorite food
@@ -159,4 +314,11 @@ type AddFavoriteRequest struct {
 	ServingSize  float64  `json:"serving_size" validate:"min=0"`
 	ServingUnit  string   `json:"serving_unit"`
 	Emoji        *string  `json:"emoji,omitempty"`
+	Nutrients    Nutrients `json:"nutrients,omitempty"`
+}
+
+// Trash represents soft-deleted items that can still be restored
+type Trash struct {
+	Meals       []*Meal       `json:"meals"`
+	CustomFoods []*CustomFood `json:"custom_foods"`
 }
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReminderKind represents what a reminder prompts the user to log
+type ReminderKind string
+
+const (
+	ReminderMeal    ReminderKind = "meal"
+	ReminderWater   ReminderKind = "water"
+	ReminderWeighIn ReminderKind = "weigh_in"
+)
+
+// Reminder represents a daily reminder at a local time in the user's timezone.
+// Meal reminders are skipped when that meal is already logged, water reminders
+// once the hydration goal is reached and weigh-in reminders once weighed.
+type Reminder struct {
+	ID         uuid.UUID    `json:"id" db:"id"`
+	UserID     uuid.UUID    `json:"user_id" db:"user_id"`
+	Kind       ReminderKind `json:"kind" db:"kind"`
+	MealType   *MealType    `json:"meal_type,omitempty" db:"meal_type"`
+	Time       string       `json:"time" db:"remind_at"`              // HH:MM
+	Weekdays   []int        `json:"weekdays" db:"weekdays"`           // 0 = Sunday; empty means every day
+	Language   *string      `json:"language,omitempty" db:"language"` // th or en; the profile language when unset
+	Enabled    bool         `json:"enabled" db:"enabled"`
+	LastSentOn *time.Time   `json:"last_sent_on,omitempty" db:"last_sent_on"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// CreateReminderRequest represents a request to create a reminder
+type CreateReminderRequest struct {
+	Kind     ReminderKind `json:"kind" validate:"required,oneof=meal water weigh_in"`
+	MealType *MealType    `json:"meal_type,omitempty"` // required for meal reminders
+	Time     string       `json:"time" validate:"required"`
+	Weekdays []int        `json:"weekdays,omitempty"`
+	Language *string      `json:"language,omitempty"`
+	Enabled  *bool        `json:"enabled,omitempty"`
+}
+
+// UpdateReminderRequest represents a request to update a reminder. An empty
+// weekdays list means every day and an empty language the profile language.
+type UpdateReminderRequest struct {
+	MealType *MealType `json:"meal_type,omitempty"`
+	Time     *string   `json:"time,omitempty"`
+	Weekdays []int     `json:"weekdays,omitempty"`
+	Language *string   `json:"language,omitempty"`
+	Enabled  *bool     `json:"enabled,omitempty"`
+}
+
+// NotificationSettings represents a user's quiet hours and delivery channels.
+// Reminders due within quiet hours are not sent.
+type NotificationSettings struct {
+	UserID       uuid.UUID `json:"user_id" db:"user_id"`
+	QuietStart   *string   `json:"quiet_start,omitempty" db:"quiet_start"` // HH:MM
+	QuietEnd     *string   `json:"quiet_end,omitempty" db:"quiet_end"`     // HH:MM
+	PushEnabled  bool      `json:"push_enabled" db:"push_enabled"`
+	EmailEnabled bool      `json:"email_enabled" db:"email_enabled"`
+	Timezone     string    `json:"timezone"` // from the profile
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateNotificationSettingsRequest represents a request to update notification
+// settings. Empty quiet hour times turn quiet hours off.
+type UpdateNotificationSettingsRequest struct {
+	QuietStart   *string `json:"quiet_start,omitempty"`
+	QuietEnd     *string `json:"quiet_end,omitempty"`
+	PushEnabled  *bool   `json:"push_enabled,omitempty"`
+	EmailEnabled *bool   `json:"email_enabled,omitempty"`
+}
+
+// PushSubscription represents a browser's Web Push subscription
+type PushSubscription struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Endpoint  string    `json:"endpoint" db:"endpoint"`
+	P256dh    string    `json:"-" db:"p256dh"`
+	Auth      string    `json:"-" db:"auth"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// PushSubscriptionKeys holds a subscription's client public key and auth secret
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh" validate:"required"`
+	Auth   string `json:"auth" validate:"required"`
+}
+
+// SubscribePushRequest represents a browser PushSubscription as serialized by toJSON()
+type SubscribePushRequest struct {
+	Endpoint string               `json:"endpoint" validate:"required,url"`
+	Keys     PushSubscriptionKeys `json:"keys"`
+}
+
+// UnsubscribePushRequest represents a request to remove a push subscription
+type UnsubscribePushRequest struct {
+	Endpoint string `json:"endpoint" validate:"required"`
+}
@@ -0,0 +1,169 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidNutrients is returned when a nutrient set contains unknown IDs or negative amounts
+var ErrInvalidNutrients = errors.New("invalid nutrients")
+
+// NutrientID identifies a nutrient in the registry
+type NutrientID string
+
+const (
+	NutrientSaturatedFat    NutrientID = "saturated_fat"
+	NutrientTransFat        NutrientID = "trans_fat"
+	NutrientMonounsaturated NutrientID = "monounsaturated_fat"
+	NutrientPolyunsaturated NutrientID = "polyunsaturated_fat"
+	NutrientCholesterol     NutrientID = "cholesterol"
+	NutrientAddedSugar      NutrientID = "added_sugar"
+	NutrientPotassium       NutrientID = "potassium"
+	NutrientCalcium         NutrientID = "calcium"
+	NutrientIron            NutrientID = "iron"
+	NutrientMagnesium       NutrientID = "magnesium"
+	NutrientZinc            NutrientID = "zinc"
+	NutrientPhosphorus      NutrientID = "phosphorus"
+	NutrientVitaminA        NutrientID = "vitamin_a"
+	NutrientVitaminC        NutrientID = "vitamin_c"
+	NutrientVitaminD        NutrientID = "vitamin_d"
+	NutrientVitaminE        NutrientID = "vitamin_e"
+	NutrientVitaminK        NutrientID = "vitamin_k"
+	NutrientVitaminB1       NutrientID = "vitamin_b1"
+	NutrientVitaminB2       NutrientID = "vitamin_b2"
+	NutrientVitaminB6       NutrientID = "vitamin_b6"
+	NutrientVitaminB12      NutrientID = "vitamin_b12"
+	NutrientFolate          NutrientID = "folate"
+	NutrientCaffeine        NutrientID = "caffeine"
+)
+
+// NutrientUnit is the unit a nutrient amount is stored in
+type NutrientUnit string
+
+const (
+	UnitGram      NutrientUnit = "g"
+	UnitMilligram NutrientUnit = "mg"
+	UnitMicrogram NutrientUnit = "µg"
+)
+
+// Nutrient describes a nutrient beyond the core macros
+type Nutrient struct {
+	ID     NutrientID   `json:"id"`
+	Name   string       `json:"name"`
+	NameEn string       `json:"name_en"`
+	Unit   NutrientUnit `json:"unit"`
+	// OFFKey is the Open Food Facts nutriments key prefix (e.g. "saturated-fat").
+	// OFF reports all amounts in grams.
+	OFFKey string `json:"-"`
+	// FDCID is the USDA FoodData Central nutrient ID
+	FDCID int `json:"-"`
+}
+
+// GramsToUnit converts an amount in grams to the nutrient's unit
+func (n Nutrient) GramsToUnit(grams float64) float64 {
+	switch n.Unit {
+	case UnitMilligram:
+		return grams * 1e3
+	case UnitMicrogram:
+		return grams * 1e6
+	default:
+		return grams
+	}
+}
+
+// NutrientRegistry lists every supported nutrient in display order
+var NutrientRegistry = []Nutrient{
+	{ID: NutrientSaturatedFat, Name: "ไขมันอิ่มตัว", NameEn: "Saturated fat", Unit: UnitGram, OFFKey: "saturated-fat", FDCID: 1258},
+	{ID: NutrientTransFat, Name: "ไขมันทรานส์", NameEn: "Trans fat", Unit: UnitGram, OFFKey: "trans-fat", FDCID: 1257},
+	{ID: NutrientMonounsaturated, Name: "ไขมันไม่อิ่มตัวเชิงเดี่ยว", NameEn: "Monounsaturated fat", Unit: UnitGram, OFFKey: "monounsaturated-fat", FDCID: 1292},
+	{ID: NutrientPolyunsaturated, Name: "ไขมันไม่อิ่มตัวเชิงซ้อน", NameEn: "Polyunsaturated fat", Unit: UnitGram, OFFKey: "polyunsaturated-fat", FDCID: 1293},
+	{ID: NutrientCholesterol, Name: "คอเลสเตอรอล", NameEn: "Cholesterol", Unit: UnitMilligram, OFFKey: "cholesterol", FDCID: 1253},
+	{ID: NutrientAddedSugar, Name: "น้ำตาลที่เติมเพิ่ม", NameEn: "Added sugar", Unit: UnitGram, OFFKey: "added-sugars", FDCID: 1235},
+	{ID: NutrientPotassium, Name: "โพแทสเซียม", NameEn: "Potassium", Unit: UnitMilligram, OFFKey: "potassium", FDCID: 1092},
+	{ID: NutrientCalcium, Name: "แคลเซียม", NameEn: "Calcium", Unit: UnitMilligram, OFFKey: "calcium", FDCID: 1087},
+	{ID: NutrientIron, Name: "ธาตุเหล็ก", NameEn: "Iron", Unit: UnitMilligram, OFFKey: "iron", FDCID: 1089},
+	{ID: NutrientMagnesium, Name: "แมกนีเซียม", NameEn: "Magnesium", Unit: UnitMilligram, OFFKey: "magnesium", FDCID: 1090},
+	{ID: NutrientZinc, Name: "สังกะสี", NameEn: "Zinc", Unit: UnitMilligram, OFFKey: "zinc", FDCID: 1095},
+	{ID: NutrientPhosphorus, Name: "ฟอสฟอรัส", NameEn: "Phosphorus", Unit: UnitMilligram, OFFKey: "phosphorus", FDCID: 1091},
+	{ID: NutrientVitaminA, Name: "วิตามินเอ", NameEn: "Vitamin A", Unit: UnitMicrogram, OFFKey: "vitamin-a", FDCID: 1106},
+	{ID: NutrientVitaminC, Name: "วิตามินซี", NameEn: "Vitamin C", Unit: UnitMilligram, OFFKey: "vitamin-c", FDCID: 1162},
+	{ID: NutrientVitaminD, Name: "วิตามินดี", NameEn: "Vitamin D", Unit: UnitMicrogram, OFFKey: "vitamin-d", FDCID: 1114},
+	{ID: NutrientVitaminE, Name: "วิตามินอี", NameEn: "Vitamin E", Unit: UnitMilligram, OFFKey: "vitamin-e", FDCID: 1109},
+	{ID: NutrientVitaminK, Name: "วิตามินเค", NameEn: "Vitamin K", Unit: UnitMicrogram, OFFKey: "vitamin-k", FDCID: 1185},
+	{ID: NutrientVitaminB1, Name: "วิตามินบี 1", NameEn: "Thiamin (B1)", Unit: UnitMilligram, OFFKey: "vitamin-b1", FDCID: 1165},
+	{ID: NutrientVitaminB2, Name: "วิตามินบี 2", NameEn: "Riboflavin (B2)", Unit: UnitMilligram, OFFKey: "vitamin-b2", FDCID: 1166},
+	{ID: NutrientVitaminB6, Name: "วิตามินบี 6", NameEn: "Vitamin B6", Unit: UnitMilligram, OFFKey: "vitamin-b6", FDCID: 1175},
+	{ID: NutrientVitaminB12, Name: "วิตามินบี 12", NameEn: "Vitamin B12", Unit: UnitMicrogram, OFFKey: "vitamin-b12", FDCID: 1178},
+	{ID: NutrientFolate, Name: "โฟเลต", NameEn: "Folate", Unit: UnitMicrogram, OFFKey: "folates", FDCID: 1177},
+	{ID: NutrientCaffeine, Name: "คาเฟอีน", NameEn: "Caffeine", Unit: UnitMilligram, OFFKey: "caffeine", FDCID: 1057},
+}
+
+var nutrientsByID = func() map[NutrientID]Nutrient {
+	m := make(map[NutrientID]Nutrient, len(NutrientRegistry))
+	for _, n := range NutrientRegistry {
+		m[n.ID] = n
+	}
+	return m
+}()
+
+// LookupNutrient finds a nutrient in the registry
+func LookupNutrient(id NutrientID) (Nutrient, bool) {
+	n, ok := nutrientsByID[id]
+	return n, ok
+}
+
+// Nutrients holds amounts of registry nutrients, each in the nutrient's unit.
+// It is stored as JSONB alongside the core macro columns.
+type Nutrients map[NutrientID]float64
+
+// Validate checks that every nutrient is in the registry and non-negative
+func (n Nutrients) Validate() error {
+	for id, amount := range n {
+		if _, ok := nutrientsByID[id]; !ok {
+			return fmt.Errorf("%w: unknown nutrient %q", ErrInvalidNutrients, id)
+		}
+		if amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+			return fmt.Errorf("%w: %s must be a non-negative number", ErrInvalidNutrients, id)
+		}
+	}
+	return nil
+}
+
+// Add adds other's amounts into n, allocating n if needed, and returns it
+func (n Nutrients) Add(other Nutrients) Nutrients {
+	if len(other) == 0 {
+		return n
+	}
+	if n == nil {
+		n = make(Nutrients, len(other))
+	}
+	for id, amount := range other {
+		n[id] += amount
+	}
+	return n
+}
+
+// Scale returns a copy of n with every amount multiplied by factor
+func (n Nutrients) Scale(factor float64) Nutrients {
+	if n == nil {
+		return nil
+	}
+	scaled := make(Nutrients, len(n))
+	for id, amount := range n {
+		scaled[id] = amount * factor
+	}
+	return scaled
+}
+
+// Rounded returns a copy of n with amounts rounded to 2 decimal places
+func (n Nutrients) Rounded() Nutrients {
+	if n == nil {
+		return nil
+	}
+	rounded := make(Nutrients, len(n))
+	for id, amount := range n {
+		rounded[id] = math.Round(amount*100) / 100
+	}
+	return rounded
+}
@@ -0,0 +1,121 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportPeriod represents the span a nutrition report covers
+type ReportPeriod string
+
+const (
+	ReportWeekly  ReportPeriod = "weekly"  // Monday to Sunday
+	ReportMonthly ReportPeriod = "monthly" // calendar month
+)
+
+// ReportFormat represents how a report is rendered
+type ReportFormat string
+
+const (
+	ReportJSON ReportFormat = "json"
+	ReportHTML ReportFormat = "html"
+	ReportPDF  ReportFormat = "pdf"
+)
+
+// Report represents a weekly or monthly nutrition report
+type Report struct {
+	Period       ReportPeriod `json:"period"`
+	Language     string       `json:"language"`
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	GeneratedAt  time.Time    `json:"generated_at"`
+	Days         int          `json:"days"`
+	DaysLogged   int          `json:"days_logged"`
+	DaysOnTarget int          `json:"days_on_target"` // calories within tolerance of the target
+
+	// Nutrients compares totals against targets for the logged days;
+	// targets are only set once onboarding is complete
+	Nutrients   []ReportNutrient `json:"nutrients"`
+	MacroSplit  MacroSplit       `json:"macro_split"`
+	TargetSplit *MacroSplit      `json:"target_split,omitempty"`
+	TopFoods    []*ReportFood    `json:"top_foods"`
+	Daily       []ReportDay      `json:"daily"`
+	Weight      *ReportWeight    `json:"weight,omitempty"`
+}
+
+// ReportNutrient represents a nutrient's total and daily average against its target
+type ReportNutrient struct {
+	Nutrient    string   `json:"nutrient"` // calories, protein, carbs, fat, fiber, sugar or sodium
+	Unit        string   `json:"unit"`
+	Total       float64  `json:"total"`
+	Average     float64  `json:"average"` // per logged day
+	DailyTarget *float64 `json:"daily_target,omitempty"`
+	Target      *float64 `json:"target,omitempty"`  // daily target times days logged
+	Percent     *float64 `json:"percent,omitempty"` // of target
+	IsLimit     bool     `json:"is_limit"`          // staying under the target is the goal
+}
+
+// MacroSplit represents the share of calories from each macronutrient
+type MacroSplit struct {
+	ProteinPercent float64 `json:"protein_percent"`
+	CarbsPercent   float64 `json:"carbs_percent"`
+	FatPercent     float64 `json:"fat_percent"`
+}
+
+// ReportFood represents one of the foods contributing the most calories
+type ReportFood struct {
+	Name     string  `json:"name"`
+	NameEn   string  `json:"name_en"`
+	Count    int     `json:"count"`
+	Calories int     `json:"calories"`
+	Percent  float64 `json:"percent"` // of total calories
+}
+
+// ReportDay represents a day's calories against the target
+type ReportDay struct {
+	Date      time.Time `json:"date"`
+	Calories  int       `json:"calories"`
+	MealCount int       `json:"meal_count"`
+	Target    int       `json:"target,omitempty"`
+}
+
+// ReportWeight represents the weight trend over the report period
+type ReportWeight struct {
+	Entries     int                 `json:"entries"`
+	StartWeight float64             `json:"start_weight"`
+	EndWeight   float64             `json:"end_weight"`
+	StartTrend  float64             `json:"start_trend"`
+	EndTrend    float64             `json:"end_trend"`
+	TrendChange float64             `json:"trend_change"` // negative is a loss
+	Points      []ReportWeightPoint `json:"points"`
+}
+
+// ReportWeightPoint represents a weigh-in with its trend weight
+type ReportWeightPoint struct {
+	Date   time.Time `json:"date"`
+	Weight float64   `json:"weight"`
+	Trend  float64   `json:"trend"`
+}
+
+// ReportSchedule represents a user's emailed report subscription. Weekly
+// reports are sent on Monday for the previous week and monthly reports on the
+// 1st for the previous month, in the profile timezone.
+type ReportSchedule struct {
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	Weekly        bool       `json:"weekly" db:"weekly"`
+	Monthly       bool       `json:"monthly" db:"monthly"`
+	Language      *string    `json:"language,omitempty" db:"language"` // defaults to the profile language
+	AttachPDF     bool       `json:"attach_pdf" db:"attach_pdf"`
+	LastWeeklyOn  *time.Time `json:"last_weekly_on,omitempty" db:"last_weekly_on"`
+	LastMonthlyOn *time.Time `json:"last_monthly_on,omitempty" db:"last_monthly_on"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateReportScheduleRequest represents a request to update the report schedule
+type UpdateReportScheduleRequest struct {
+	Weekly    *bool   `json:"weekly,omitempty"`
+	Monthly   *bool   `json:"monthly,omitempty"`
+	Language  *string `json:"language,omitempty"` // th or en; empty uses the profile language
+	AttachPDF *bool   `json:"attach_pdf,omitempty"`
+}
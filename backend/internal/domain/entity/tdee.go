@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// TDEEConfidence describes how much an adaptive TDEE estimate can be trusted
+type TDEEConfidence string
+
+const (
+	TDEEConfidenceInsufficient TDEEConfidence = "insufficient"
+	TDEEConfidenceLow          TDEEConfidence = "low"
+	TDEEConfidenceMedium       TDEEConfidence = "medium"
+	TDEEConfidenceHigh         TDEEConfidence = "high"
+)
+
+// TDEEEstimate represents maintenance calories estimated from logged intake
+// and the weight trend over a window. EstimatedTDEE is nil when there is not
+// enough data; SuggestedTDEE blends it with the formula TDEE by confidence.
+type TDEEEstimate struct {
+	From            time.Time      `json:"from"`
+	To              time.Time      `json:"to"`
+	Weeks           int            `json:"weeks"`
+	DaysInWindow    int            `json:"days_in_window"`
+	DaysLogged      int            `json:"days_logged"`     // days counted towards intake
+	DaysIncomplete  int            `json:"days_incomplete"` // logged days excluded as partial
+	WeighIns        int            `json:"weigh_ins"`
+	AverageIntake   *int           `json:"average_intake,omitempty"`
+	WeightChange    *float64       `json:"weight_change,omitempty"` // trend change over the window, kg
+	WeeklyRate      *float64       `json:"weekly_rate,omitempty"`   // kg per week, negative when losing
+	FormulaTDEE     int            `json:"formula_tdee"`
+	EstimatedTDEE   *int           `json:"estimated_tdee,omitempty"`
+	SuggestedTDEE   *int           `json:"suggested_tdee,omitempty"`
+	Difference      *int           `json:"difference,omitempty"` // suggested minus formula TDEE
+	Confidence      float64        `json:"confidence"`           // 0-1
+	ConfidenceLevel TDEEConfidence `json:"confidence_level"`
+	Reason          string         `json:"reason,omitempty"`
+	AdaptiveEnabled bool           `json:"adaptive_enabled"`
+	AdjustedTDEE    *int           `json:"adjusted_tdee,omitempty"`
+	ReviewedAt      *time.Time     `json:"reviewed_at,omitempty"`
+}
+
+// SetAdaptiveTDEERequest represents a request to opt in or out of weekly
+// target adjustment from the adaptive TDEE estimate
+type SetAdaptiveTDEERequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
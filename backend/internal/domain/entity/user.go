@@ -63,6 +63,9 @@ type MacroTargets struct {
 	FatCalories     int `json:"fat_calories"`
 }
 
+// DefaultTimezone is used for profiles without a valid timezone
+const DefaultTimezone = "Asia/Bangkok"
+
 // UserProfile represents user's profile from onboarding
 type UserProfile struct {
 	UserID     uuid.UUID `json:"user_id" db:"user_id"`
@@ -75,6 +78,7 @@ type UserProfile struct {
 	ActivityLevel     ActivityLevel `json:"activity_level" db:"activity_level"`
 	Goal              Goal          `json:"goal" db:"goal"`
 	PreferredLanguage string        `json:"preferred_language" db:"preferred_language"`
+	Timezone          string        `json:"timezone" db:"timezone"`
 
 	// Calculated values
 	BMR            int           `json:"bmr" db:"bmr"`
@@ -87,9 +91,19 @@ type UserProfile struct {
 	CarbsCalories   int          `json:"carbs_calories" db:"carbs_calories"`
 	FatCalories     int          `json:"fat_calories" db:"fat_calories"`
 
+	// BMR formula and the latest body fat, which lean-mass formulas require
+	BMRFormula     BMRFormula `json:"bmr_formula" db:"bmr_formula"`
+	BodyFatPercent *float64   `json:"body_fat_percent,omitempty" db:"body_fat_percent"`
+
+	// Adaptive TDEE replaces the activity-based estimate once enabled and reviewed
+	AdaptiveTDEE   bool       `json:"adaptive_tdee" db:"adaptive_tdee"`
+	AdjustedTDEE   *int       `json:"adjusted_tdee,omitempty" db:"adjusted_tdee"`
+	TDEEReviewedAt *time.Time `json:"tdee_reviewed_at,omitempty" db:"tdee_reviewed_at"`
+
 	CompletedOnboarding bool      `json:"completed_onboarding" db:"completed_onboarding"`
 	CreatedAt           time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+	Version             int       `json:"version" db:"version"`
 }
 
 // RegisterRequest represents user registration request
@@ -118,3 +132,21 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
 }
+
+// Location returns the profile's timezone, falling back to DefaultTimezone
+func (p *UserProfile) Location() *time.Location {
+	if p.Timezone != "" {
+		if loc, err := time.LoadLocation(p.Timezone); err == nil {
+			return loc
+		}
+	}
+	return DefaultLocation()
+}
+
+// DefaultLocation returns the DefaultTimezone location, or UTC when zoneinfo is unavailable
+func DefaultLocation() *time.Location {
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
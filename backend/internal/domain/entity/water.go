@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WaterContainer represents a drink size that can be logged in one tap
+type WaterContainer struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	AmountML  int       `json:"amount_ml" db:"amount_ml"`
+	Emoji     *string   `json:"emoji,omitempty" db:"emoji"`
+	CreatedAt time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+// WaterLog represents a single logged drink
+type WaterLog struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	AmountML    int        `json:"amount_ml" db:"amount_ml"`
+	ContainerID *uuid.UUID `json:"container_id,omitempty" db:"container_id"`
+	Date        time.Time  `json:"date" db:"date"`
+	LoggedAt    time.Time  `json:"logged_at" db:"logged_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// LogWaterRequest represents a request to log a drink.
+// Either AmountML or ContainerID must be set; AmountML overrides the
+// container's size if both are.
+type LogWaterRequest struct {
+	AmountML    *int       `json:"amount_ml,omitempty"`
+	ContainerID *uuid.UUID `json:"container_id,omitempty"`
+	Date        *time.Time `json:"date,omitempty"`
+}
+
+// CreateWaterContainerRequest represents a request to save a custom container size
+type CreateWaterContainerRequest struct {
+	Name     string  `json:"name" validate:"required,max=100"`
+	AmountML int     `json:"amount_ml" validate:"required,min=1,max=5000"`
+	Emoji    *string `json:"emoji,omitempty"`
+}
+
+// HydrationProgress represents water intake against the recommended amount
+type HydrationProgress struct {
+	ConsumedML  int     `json:"consumed_ml"`
+	TargetML    int     `json:"target_ml"`
+	RemainingML int     `json:"remaining_ml"`
+	Percent     float64 `json:"percent"`
+	GoalReached bool    `json:"goal_reached"`
+}
+
+// DailyHydration represents water intake for a single day
+type DailyHydration struct {
+	Date     time.Time          `json:"date"`
+	Logs     []*WaterLog        `json:"logs"`
+	TotalML  int                `json:"total_ml"`
+	Progress *HydrationProgress `json:"progress,omitempty"`
+}
+
+// DayWater represents total water intake for a single day
+type DayWater struct {
+	Date        time.Time `json:"date"`
+	TotalML     int       `json:"total_ml"`
+	LogCount    int       `json:"log_count"`
+	GoalReached bool      `json:"goal_reached"`
+}
+
+// WaterRangeStats represents water intake over a date range
+type WaterRangeStats struct {
+	From         time.Time   `json:"from"`
+	To           time.Time   `json:"to"`
+	Days         []*DayWater `json:"days"`
+	DaysLogged   int         `json:"days_logged"`
+	DaysOnTarget int         `json:"days_on_target"`
+	TotalML      int         `json:"total_ml"`
+	AverageML    int         `json:"average_ml"` // per logged day
+	TargetML     int         `json:"target_ml,omitempty"`
+}
+
+// WaterContainers lists the sizes available for one-tap logging
+type WaterContainers struct {
+	QuickAddML []int             `json:"quick_add_ml"`
+	Custom     []*WaterContainer `json:"custom"`
+}
+
+// MaxWaterLogML caps a single drink or container size
+const MaxWaterLogML = 5000
+
+// WaterQuickAddAmounts are the preset drink sizes (ml) offered to every user
+var WaterQuickAddAmounts = []int{150, 250, 350, 500, 750, 1000}
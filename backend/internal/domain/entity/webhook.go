@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType names a change that webhooks can subscribe to
+type EventType string
+
+const (
+	EventMealCreated    EventType = "meal.created"
+	EventMealUpdated    EventType = "meal.updated"
+	EventMealDeleted    EventType = "meal.deleted"
+	EventMealRestored   EventType = "meal.restored"
+	EventWeightLogged   EventType = "weight.logged"
+	EventWeightUpdated  EventType = "weight.updated"
+	EventWeightDeleted  EventType = "weight.deleted"
+	EventProfileCreated EventType = "profile.created"
+	EventProfileUpdated EventType = "profile.updated"
+)
+
+// EventTypes lists every event type
+var EventTypes = []EventType{
+	EventMealCreated, EventMealUpdated, EventMealDeleted, EventMealRestored,
+	EventWeightLogged, EventWeightUpdated, EventWeightDeleted,
+	EventProfileCreated, EventProfileUpdated,
+}
+
+// Webhook is an endpoint that receives events. User webhooks receive the
+// user's own events; app webhooks (no user) receive every user's events.
+type Webhook struct {
+	ID          uuid.UUID   `json:"id" db:"id"`
+	UserID      *uuid.UUID  `json:"user_id,omitempty" db:"user_id"`
+	URL         string      `json:"url" db:"url"`
+	Events      []EventType `json:"events" db:"events"`           // empty receives every event
+	Secret      string      `json:"secret,omitempty" db:"secret"` // only returned when created or rotated
+	Description string      `json:"description" db:"description"`
+	Active      bool        `json:"active" db:"active"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes reports whether the webhook receives an event type
+func (w *Webhook) Subscribes(eventType EventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookRequest represents a request to register a webhook
+type CreateWebhookRequest struct {
+	URL         string      `json:"url" validate:"required,url"`
+	Events      []EventType `json:"events,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// UpdateWebhookRequest represents a request to update a webhook
+type UpdateWebhookRequest struct {
+	URL         *string      `json:"url,omitempty"`
+	Events      *[]EventType `json:"events,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+}
+
+// WebhookEvent is an event in the outbox
+type WebhookEvent struct {
+	ID           uuid.UUID       `json:"id" db:"id"`
+	UserID       uuid.UUID       `json:"user_id" db:"user_id"`
+	Type         EventType       `json:"type" db:"type"`
+	Payload      json.RawMessage `json:"data" db:"payload"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+	DispatchedAt *time.Time      `json:"-" db:"dispatched_at"`
+}
+
+// DeliveryStatus is the state of a webhook delivery
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryDead      DeliveryStatus = "dead" // out of retries; in the dead-letter queue
+)
+
+// WebhookDelivery is one event queued for one webhook
+type WebhookDelivery struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	WebhookID      uuid.UUID      `json:"webhook_id" db:"webhook_id"`
+	EventID        uuid.UUID      `json:"event_id" db:"event_id"`
+	EventType      EventType      `json:"event_type" db:"-"`
+	Status         DeliveryStatus `json:"status" db:"status"`
+	Attempts       int            `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at" db:"next_attempt_at"`
+	LastStatusCode *int           `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      *string        `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+
+	// Attempt log, included when a single delivery is fetched
+	Log []*WebhookAttempt `json:"log,omitempty" db:"-"`
+}
+
+// WebhookAttempt logs one delivery attempt
+type WebhookAttempt struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	DeliveryID   uuid.UUID `json:"delivery_id" db:"delivery_id"`
+	Attempt      int       `json:"attempt" db:"attempt"`
+	StatusCode   *int      `json:"status_code,omitempty" db:"status_code"`
+	Error        *string   `json:"error,omitempty" db:"error"`
+	ResponseBody *string   `json:"response_body,omitempty" db:"response_body"` // truncated
+	DurationMS   int       `json:"duration_ms" db:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ReplayResult reports how many deliveries were queued again
+type ReplayResult struct {
+	Requeued int `json:"requeued"`
+}
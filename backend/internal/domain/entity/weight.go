@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WeightEntry represents a body weight measurement; there is at most one per day
+type WeightEntry struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	Weight      float64   `json:"weight" db:"weight"`
+	Date        time.Time `json:"date" db:"date"`
+	Note        *string   `json:"note,omitempty" db:"note"`
+	TrendWeight *float64  `json:"trend_weight,omitempty" db:"-"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateWeightRequest represents a request to record a weight. An entry for a
+// date that already has one replaces it. UpdateProfile defaults to true and
+// only applies when the entry is the user's most recent one.
+type CreateWeightRequest struct {
+	Weight        float64    `json:"weight" validate:"required,min=30,max=300"`
+	Date          *time.Time `json:"date,omitempty"`
+	Note          *string    `json:"note,omitempty"`
+	UpdateProfile *bool      `json:"update_profile,omitempty"`
+}
+
+// UpdateWeightRequest represents update weight entry request
+type UpdateWeightRequest struct {
+	Weight *float64   `json:"weight,omitempty" validate:"omitempty,min=30,max=300"`
+	Date   *time.Time `json:"date,omitempty"`
+	Note   *string    `json:"note,omitempty"`
+}
+
+// WeightEntryResult represents a recorded weight and, when the profile was
+// updated from it, the recalculated profile
+type WeightEntryResult struct {
+	Entry   *WeightEntry `json:"entry"`
+	Profile *UserProfile `json:"profile,omitempty"`
+}
+
+// TrendDirection describes the direction of the smoothed weight trend
+type TrendDirection string
+
+const (
+	TrendLosing  TrendDirection = "losing"
+	TrendStable  TrendDirection = "stable"
+	TrendGaining TrendDirection = "gaining"
+)
+
+// WeightTrend represents smoothed weight over a window
+type WeightTrend struct {
+	From          time.Time      `json:"from"`
+	To            time.Time      `json:"to"`
+	Entries       []*WeightEntry `json:"entries"`
+	StartWeight   *float64       `json:"start_weight,omitempty"`
+	CurrentWeight *float64       `json:"current_weight,omitempty"`
+	TrendWeight   *float64       `json:"trend_weight,omitempty"`
+	Change        float64        `json:"change"`      // trend change over the window, kg
+	WeeklyRate    float64        `json:"weekly_rate"` // kg per week, negative when losing
+	Direction     TrendDirection `json:"direction"`
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// streakWindowDays bounds how far back streaks are recalculated
+	streakWindowDays = 400
+	// freezeEveryDays of streak earn one freeze, up to maxStreakFreezes banked
+	freezeEveryDays  = 7
+	maxStreakFreezes = 2
+)
+
+// AchievementService evaluates streaks and achievements on meal, weight and
+// water events
+type AchievementService struct {
+	achievementRepo *repository.AchievementRepository
+	mealRepo        *repository.MealRepository
+	waterRepo       *repository.WaterRepository
+	weightRepo      *repository.WeightRepository
+	userRepo        *repository.UserRepository
+	calorieService  *CalorieService
+}
+
+// NewAchievementService creates a new achievement service
+func NewAchievementService(
+	achievementRepo *repository.AchievementRepository,
+	mealRepo *repository.MealRepository,
+	waterRepo *repository.WaterRepository,
+	weightRepo *repository.WeightRepository,
+	userRepo *repository.UserRepository,
+	calorieService *CalorieService,
+) *AchievementService {
+	return &AchievementService{
+		achievementRepo: achievementRepo,
+		mealRepo:        mealRepo,
+		waterRepo:       waterRepo,
+		weightRepo:      weightRepo,
+		userRepo:        userRepo,
+		calorieService:  calorieService,
+	}
+}
+
+// MealsChanged re-evaluates the meal streaks for the given dates and unlocks
+// any achievements earned. barcodeScan records that the meal was logged from
+// a scanned barcode. Failures are logged rather than returned so they never
+// fail the meal request.
+func (s *AchievementService) MealsChanged(ctx context.Context, userID uuid.UUID, barcodeScan bool, dates ...time.Time) {
+	s.handle(ctx, userID, func(profile *entity.UserProfile) error {
+		if barcodeScan {
+			if err := s.achievementRepo.IncrementCounter(ctx, userID, entity.MetricBarcodeScans); err != nil {
+				return err
+			}
+		}
+		for _, date := range dates {
+			if err := s.refreshMealDay(ctx, userID, date, profile); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WaterChanged re-evaluates the hydration streak for a date and unlocks any
+// achievements earned
+func (s *AchievementService) WaterChanged(ctx context.Context, userID uuid.UUID, date time.Time) {
+	s.handle(ctx, userID, func(profile *entity.UserProfile) error {
+		target := waterTargetML(s.calorieService, profile)
+		if target == 0 {
+			return nil
+		}
+		total, err := s.waterRepo.GetDailyTotal(ctx, userID, date)
+		if err != nil {
+			return err
+		}
+		return s.achievementRepo.SetStreakDay(ctx, userID, entity.StreakWater, date, total >= target)
+	})
+}
+
+// WeightChanged unlocks any weight achievements earned
+func (s *AchievementService) WeightChanged(ctx context.Context, userID uuid.UUID) {
+	s.handle(ctx, userID, func(*entity.UserProfile) error { return nil })
+}
+
+// GetSummary gets the user's streaks and progress towards every achievement
+func (s *AchievementService) GetSummary(ctx context.Context, userID uuid.UUID) (*entity.AchievementSummary, error) {
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	streaks, metrics, err := s.metrics(ctx, userID, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	achievements, err := s.achievementRepo.FindAchievements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked, err := s.achievementRepo.FindUnlocked(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &entity.AchievementSummary{
+		Streaks:      streaks,
+		Achievements: make([]*entity.AchievementProgress, 0, len(achievements)),
+		Total:        len(achievements),
+	}
+	for _, achievement := range achievements {
+		current := metrics[achievement.Metric]
+		progress := &entity.AchievementProgress{
+			Achievement: achievement,
+			Current:     current,
+			Progress:    math.Min(100, math.Round(current/achievement.Threshold*1000)/10),
+		}
+		if at, ok := unlocked[achievement.ID]; ok {
+			progress.Unlocked = true
+			progress.UnlockedAt = &at
+			progress.Progress = 100
+			summary.Unlocked++
+		}
+		summary.Achievements = append(summary.Achievements, progress)
+	}
+
+	return summary, nil
+}
+
+// GetUnlocked gets the user's unlocked achievements
+func (s *AchievementService) GetUnlocked(ctx context.Context, userID uuid.UUID) ([]*entity.AchievementProgress, error) {
+	summary, err := s.GetSummary(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked := []*entity.AchievementProgress{}
+	for _, achievement := range summary.Achievements {
+		if achievement.Unlocked {
+			unlocked = append(unlocked, achievement)
+		}
+	}
+	return unlocked, nil
+}
+
+// GetStreaks gets the user's streaks
+func (s *AchievementService) GetStreaks(ctx context.Context, userID uuid.UUID) ([]*entity.Streak, error) {
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.streaks(ctx, userID, profile)
+}
+
+// handle applies an event's streak updates and then unlocks earned
+// achievements, logging any failure
+func (s *AchievementService) handle(ctx context.Context, userID uuid.UUID, update func(*entity.UserProfile) error) {
+	err := func() error {
+		profile, err := s.findProfile(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := update(profile); err != nil {
+			return err
+		}
+		return s.unlockEarned(ctx, userID, profile)
+	}()
+	if err != nil {
+		log.Printf("Achievement evaluation failed for user %s: %v", userID, err)
+	}
+}
+
+// refreshMealDay records which meal streaks a date counts towards. Target
+// and protein days need a profile to compare against.
+func (s *AchievementService) refreshMealDay(ctx context.Context, userID uuid.UUID, date time.Time, profile *entity.UserProfile) error {
+	day, err := s.mealRepo.GetRangeTotals(ctx, userID, date, date)
+	if err != nil {
+		return err
+	}
+
+	logged := len(day) > 0 && day[0].MealCount > 0
+	onTarget, proteinHit := false, false
+	if logged && profile != nil {
+		totals := day[0].Totals
+		targets := s.calorieService.CalculateDailyTargets(profile)
+		progress := s.calorieService.CalculateDailyProgress(totals, targets)
+		onTarget = progress.Calories.Status == entity.TargetStatusOnTrack
+		proteinHit = targets.Protein > 0 && totals.Protein >= float64(targets.Protein)
+	}
+
+	days := map[entity.StreakKind]bool{
+		entity.StreakLogging: logged,
+		entity.StreakTarget:  onTarget,
+		entity.StreakProtein: proteinHit,
+	}
+	for kind, qualifies := range days {
+		if err := s.achievementRepo.SetStreakDay(ctx, userID, kind, date, qualifies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unlockEarned unlocks every achievement whose metric has reached its threshold
+func (s *AchievementService) unlockEarned(ctx context.Context, userID uuid.UUID, profile *entity.UserProfile) error {
+	_, metrics, err := s.metrics(ctx, userID, profile)
+	if err != nil {
+		return err
+	}
+
+	achievements, err := s.achievementRepo.FindAchievements(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlocked, err := s.achievementRepo.FindUnlocked(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, achievement := range achievements {
+		if _, ok := unlocked[achievement.ID]; ok || metrics[achievement.Metric] < achievement.Threshold {
+			continue
+		}
+		if _, err := s.achievementRepo.Unlock(ctx, userID, achievement.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metrics computes the user's streaks and the value of every achievement metric
+func (s *AchievementService) metrics(ctx context.Context, userID uuid.UUID, profile *entity.UserProfile) ([]*entity.Streak, map[entity.AchievementMetric]float64, error) {
+	metrics := map[entity.AchievementMetric]float64{}
+
+	streaks, err := s.streaks(ctx, userID, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	streakMetrics := map[entity.StreakKind]entity.AchievementMetric{
+		entity.StreakLogging: entity.MetricLoggingStreak,
+		entity.StreakTarget:  entity.MetricTargetStreak,
+		entity.StreakProtein: entity.MetricProteinStreak,
+		entity.StreakWater:   entity.MetricWaterStreak,
+	}
+	for _, streak := range streaks {
+		metrics[streakMetrics[streak.Kind]] = float64(streak.Longest)
+	}
+
+	meals, err := s.mealRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	metrics[entity.MetricMealsLogged] = float64(meals)
+
+	targetDays, err := s.achievementRepo.CountStreakDays(ctx, userID, entity.StreakTarget)
+	if err != nil {
+		return nil, nil, err
+	}
+	metrics[entity.MetricTargetDays] = float64(targetDays)
+
+	counters, err := s.achievementRepo.FindCounters(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	metrics[entity.MetricBarcodeScans] = float64(counters[entity.MetricBarcodeScans])
+
+	weighIns, err := s.weightRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	metrics[entity.MetricWeighIns] = float64(weighIns)
+
+	if weighIns > 1 {
+		first, err := s.weightRepo.FindFirst(ctx, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		latest, err := s.weightRepo.FindLatest(ctx, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		metrics[entity.MetricWeightLost] = math.Max(0, round2(first.Weight-latest.Weight))
+	}
+
+	return streaks, metrics, nil
+}
+
+// streaks calculates every streak kind as of today in the user's timezone
+func (s *AchievementService) streaks(ctx context.Context, userID uuid.UUID, profile *entity.UserProfile) ([]*entity.Streak, error) {
+	loc := time.UTC
+	if profile != nil {
+		loc = profile.Location()
+	}
+	today := truncateDay(time.Now().In(loc))
+
+	streaks := make([]*entity.Streak, 0, len(entity.StreakKinds))
+	for _, kind := range entity.StreakKinds {
+		days, err := s.achievementRepo.FindStreakDays(ctx, userID, kind, today.AddDate(0, 0, -streakWindowDays))
+		if err != nil {
+			return nil, err
+		}
+		streaks = append(streaks, calculateStreak(kind, days, today))
+	}
+	return streaks, nil
+}
+
+// findProfile gets the user's profile, or nil before onboarding
+func (s *AchievementService) findProfile(ctx context.Context, userID uuid.UUID) (*entity.UserProfile, error) {
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+// calculateStreak walks day by day from the first qualifying day. Each
+// qualifying day extends the streak and every freezeEveryDays earns a freeze;
+// a missed day spends a freeze if one is banked and otherwise resets the
+// streak. Today only counts once it qualifies, so an unlogged today never
+// breaks a streak.
+func calculateStreak(kind entity.StreakKind, days []time.Time, today time.Time) *entity.Streak {
+	streak := &entity.Streak{Kind: kind}
+	if len(days) == 0 {
+		return streak
+	}
+
+	qualifying := make(map[time.Time]bool, len(days))
+	for _, day := range days {
+		qualifying[truncateDay(day)] = true
+	}
+	last := truncateDay(days[len(days)-1])
+	streak.LastDate = &last
+	streak.TodayComplete = qualifying[today]
+
+	end := today
+	if !streak.TodayComplete {
+		end = today.AddDate(0, 0, -1)
+	}
+
+	for day := truncateDay(days[0]); !day.After(end); day = day.AddDate(0, 0, 1) {
+		switch {
+		case qualifying[day]:
+			streak.Current++
+			if streak.Current > streak.Longest {
+				streak.Longest = streak.Current
+			}
+			if streak.Current%freezeEveryDays == 0 && streak.FreezesAvailable < maxStreakFreezes {
+				streak.FreezesAvailable++
+			}
+		case streak.Current > 0 && streak.FreezesAvailable > 0:
+			streak.FreezesAvailable--
+			streak.FreezesUsed++
+		default:
+			streak.Current = 0
+			streak.FreezesUsed = 0
+		}
+	}
+
+	return streak
+}
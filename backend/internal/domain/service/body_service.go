@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidMeasurement is returned when body measurements are missing or out of range
+	ErrInvalidMeasurement = errors.New("invalid body measurement")
+	// ErrBodyFatRequired is returned when a lean mass BMR formula is chosen without a body fat percentage
+	ErrBodyFatRequired = errors.New("log body fat percentage, or waist and neck (and hip for women), to use this BMR formula")
+	// ErrInvalidBMRFormula is returned for an unknown BMR formula
+	ErrInvalidBMRFormula = errors.New("formula must be mifflin_st_jeor, katch_mcardle or cunningham")
+)
+
+// Risk thresholds used by our dietitians for Asian adults
+const (
+	// Waist-to-height ratio: 0.5 is the boundary value recommended for Asian
+	// populations, 0.6 marks substantially increased risk
+	waistToHeightIncreased = 0.5
+	waistToHeightHigh      = 0.6
+	// Waist circumference cut-offs for Asians (WHO Western Pacific Region), cm
+	asianWaistMale   = 90.0
+	asianWaistFemale = 80.0
+	// Waist-to-hip ratio cut-offs (WHO)
+	waistToHipMale   = 0.90
+	waistToHipFemale = 0.85
+)
+
+// BodyService handles body composition measurements
+type BodyService struct {
+	bodyRepo       *repository.BodyRepository
+	userRepo       *repository.UserRepository
+	calorieService *CalorieService
+	events         *WebhookService
+}
+
+// NewBodyService creates a new body service
+func NewBodyService(bodyRepo *repository.BodyRepository, userRepo *repository.UserRepository, calorieService *CalorieService, events *WebhookService) *BodyService {
+	return &BodyService{
+		bodyRepo:       bodyRepo,
+		userRepo:       userRepo,
+		calorieService: calorieService,
+		events:         events,
+	}
+}
+
+// CreateMeasurement records body measurements. When it is the most recent
+// measurement, has a measured or estimated body fat and UpdateProfile is not
+// false, the profile body fat is updated and its targets recalculated.
+func (s *BodyService) CreateMeasurement(ctx context.Context, userID uuid.UUID, req *entity.CreateBodyMeasurementRequest) (*entity.BodyMeasurementResult, error) {
+	measurement := &entity.BodyMeasurement{
+		ID:             uuid.New(),
+		UserID:         userID,
+		BodyFatPercent: req.BodyFatPercent,
+		WaistCM:        req.WaistCM,
+		HipCM:          req.HipCM,
+		NeckCM:         req.NeckCM,
+		Note:           req.Note,
+	}
+	if err := validateMeasurement(measurement); err != nil {
+		return nil, err
+	}
+
+	// Set date - use provided date or today
+	if req.Date != nil {
+		measurement.Date = *req.Date
+	} else {
+		measurement.Date = time.Now()
+	}
+
+	if err := s.bodyRepo.Upsert(ctx, measurement); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.derive(measurement, profile)
+
+	result := &entity.BodyMeasurementResult{Measurement: measurement}
+
+	if profile != nil && measurement.BodyFat != nil && (req.UpdateProfile == nil || *req.UpdateProfile) {
+		latest, err := s.bodyRepo.FindLatest(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if latest.ID == measurement.ID {
+			bodyFat := *measurement.BodyFat
+			result.Profile, err = updateProfile(ctx, s.userRepo, s.calorieService, s.events, userID, func(profile *entity.UserProfile) error {
+				profile.BodyFatPercent = &bodyFat
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetMeasurements gets body measurements between two dates (inclusive)
+func (s *BodyService) GetMeasurements(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entity.BodyMeasurement, error) {
+	measurements, err := s.bodyRepo.FindByUserID(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if measurements == nil {
+		measurements = []*entity.BodyMeasurement{}
+	}
+	for _, measurement := range measurements {
+		s.derive(measurement, profile)
+	}
+
+	return measurements, nil
+}
+
+// GetMeasurementByID gets a body measurement by ID
+func (s *BodyService) GetMeasurementByID(ctx context.Context, measurementID, userID uuid.UUID) (*entity.BodyMeasurement, error) {
+	measurement, err := s.bodyRepo.FindByID(ctx, measurementID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if measurement.UserID != userID {
+		return nil, repository.ErrUserNotFound
+	}
+
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.derive(measurement, profile)
+
+	return measurement, nil
+}
+
+// UpdateMeasurement updates a body measurement
+func (s *BodyService) UpdateMeasurement(ctx context.Context, measurementID, userID uuid.UUID, req *entity.UpdateBodyMeasurementRequest) (*entity.BodyMeasurement, error) {
+	measurement, err := s.GetMeasurementByID(ctx, measurementID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BodyFatPercent != nil {
+		measurement.BodyFatPercent = req.BodyFatPercent
+	}
+	if req.WaistCM != nil {
+		measurement.WaistCM = req.WaistCM
+	}
+	if req.HipCM != nil {
+		measurement.HipCM = req.HipCM
+	}
+	if req.NeckCM != nil {
+		measurement.NeckCM = req.NeckCM
+	}
+	if req.Date != nil {
+		measurement.Date = *req.Date
+	}
+	if req.Note != nil {
+		measurement.Note = req.Note
+	}
+
+	if err := validateMeasurement(measurement); err != nil {
+		return nil, err
+	}
+
+	if err := s.bodyRepo.Update(ctx, measurement); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.derive(measurement, profile)
+
+	return measurement, nil
+}
+
+// DeleteMeasurement deletes a body measurement
+func (s *BodyService) DeleteMeasurement(ctx context.Context, measurementID, userID uuid.UUID) error {
+	return s.bodyRepo.Delete(ctx, measurementID, userID)
+}
+
+// GetComposition gets the user's body composition, risk indicators from the
+// latest measurement and BMR under each formula that can be calculated
+func (s *BodyService) GetComposition(ctx context.Context, userID uuid.UUID) (*entity.BodyComposition, error) {
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	formula := profile.BMRFormula
+	if formula == "" {
+		formula = entity.BMRMifflinStJeor
+	}
+
+	composition := &entity.BodyComposition{
+		Weight:         profile.Weight,
+		Height:         profile.Height,
+		BodyFatPercent: profile.BodyFatPercent,
+		BMRFormula:     formula,
+		BMR:            s.calorieService.ProfileBMR(profile),
+		BMRByFormula:   map[entity.BMRFormula]int{},
+	}
+
+	latest, err := s.bodyRepo.FindLatest(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+	if latest != nil {
+		s.derive(latest, profile)
+		composition.MeasuredAt = &latest.Date
+		if latest.BodyFat != nil {
+			composition.BodyFatPercent = latest.BodyFat
+			composition.BodyFatSource = latest.BodyFatSource
+		}
+		s.assessRisk(composition, latest, profile.Gender)
+	}
+
+	for _, f := range []entity.BMRFormula{entity.BMRMifflinStJeor, entity.BMRKatchMcArdle, entity.BMRCunningham} {
+		if f.NeedsLeanMass() && composition.BodyFatPercent == nil {
+			continue
+		}
+		composition.BMRByFormula[f] = s.calorieService.CalculateBMRWithFormula(
+			f, profile.Weight, profile.Height, profile.Age, profile.Gender, composition.BodyFatPercent,
+		)
+	}
+
+	if composition.BodyFatPercent != nil {
+		leanMass := s.calorieService.CalculateLeanMass(profile.Weight, *composition.BodyFatPercent)
+		fatMass := math.Round((profile.Weight-leanMass)*10) / 10
+		composition.LeanMass = &leanMass
+		composition.FatMass = &fatMass
+	}
+
+	return composition, nil
+}
+
+// SetBMRFormula chooses the BMR formula and recalculates the profile's
+// targets. Lean mass formulas require a body fat percentage on the profile.
+func (s *BodyService) SetBMRFormula(ctx context.Context, userID uuid.UUID, formula entity.BMRFormula) (*entity.UserProfile, error) {
+	if !formula.Valid() {
+		return nil, ErrInvalidBMRFormula
+	}
+
+	return updateProfile(ctx, s.userRepo, s.calorieService, s.events, userID, func(profile *entity.UserProfile) error {
+		if formula.NeedsLeanMass() && profile.BodyFatPercent == nil {
+			return ErrBodyFatRequired
+		}
+		profile.BMRFormula = formula
+		return nil
+	})
+}
+
+// derive fills a measurement's body fat (measured, or US Navy estimate from
+// circumferences) and waist-to-height ratio. Estimates need the profile height.
+func (s *BodyService) derive(measurement *entity.BodyMeasurement, profile *entity.UserProfile) {
+	if measurement.BodyFatPercent != nil {
+		source := entity.BodyFatMeasured
+		measurement.BodyFat = measurement.BodyFatPercent
+		measurement.BodyFatSource = &source
+	}
+
+	if profile == nil || measurement.WaistCM == nil {
+		return
+	}
+
+	ratio := round2(*measurement.WaistCM / profile.Height)
+	measurement.WaistToHeightRatio = &ratio
+
+	if measurement.BodyFat == nil && measurement.NeckCM != nil {
+		bodyFat, ok := s.calorieService.EstimateBodyFatNavy(
+			profile.Gender, profile.Height, *measurement.WaistCM, *measurement.NeckCM, measurement.HipCM,
+		)
+		if ok {
+			source := entity.BodyFatNavy
+			measurement.BodyFat = &bodyFat
+			measurement.BodyFatSource = &source
+		}
+	}
+}
+
+// assessRisk fills the composition's circumference-based risk indicators
+func (s *BodyService) assessRisk(composition *entity.BodyComposition, measurement *entity.BodyMeasurement, gender entity.Gender) {
+	if measurement.WaistCM == nil {
+		return
+	}
+	waist := *measurement.WaistCM
+	composition.WaistCM = measurement.WaistCM
+	composition.HipCM = measurement.HipCM
+	composition.WaistToHeightRatio = measurement.WaistToHeightRatio
+
+	waistCutoff, ratioCutoff := asianWaistFemale, waistToHipFemale
+	if gender == entity.GenderMale {
+		waistCutoff, ratioCutoff = asianWaistMale, waistToHipMale
+	}
+
+	if ratio := measurement.WaistToHeightRatio; ratio != nil {
+		risk := entity.RiskLow
+		switch {
+		case *ratio >= waistToHeightHigh:
+			risk = entity.RiskHigh
+		case *ratio >= waistToHeightIncreased:
+			risk = entity.RiskIncreased
+		}
+		composition.WaistToHeightRisk = &risk
+	}
+
+	waistRisk := entity.RiskLow
+	if waist >= waistCutoff {
+		waistRisk = entity.RiskIncreased
+	}
+	composition.WaistRisk = &waistRisk
+
+	if measurement.HipCM != nil {
+		ratio := round2(waist / *measurement.HipCM)
+		risk := entity.RiskLow
+		if ratio >= ratioCutoff {
+			risk = entity.RiskIncreased
+		}
+		composition.WaistToHipRatio = &ratio
+		composition.WaistToHipRisk = &risk
+	}
+}
+
+// findProfile gets the user's profile, or nil before onboarding
+func (s *BodyService) findProfile(ctx context.Context, userID uuid.UUID) (*entity.UserProfile, error) {
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+// validateMeasurement checks at least one value is present and all are in range
+func validateMeasurement(measurement *entity.BodyMeasurement) error {
+	if measurement.BodyFatPercent == nil && measurement.WaistCM == nil &&
+		measurement.HipCM == nil && measurement.NeckCM == nil {
+		return fmt.Errorf("%w: at least one measurement is required", ErrInvalidMeasurement)
+	}
+	if bf := measurement.BodyFatPercent; bf != nil && (*bf < 2 || *bf > 70) {
+		return fmt.Errorf("%w: body fat must be between 2 and 70%%", ErrInvalidMeasurement)
+	}
+	for _, cm := range []*float64{measurement.WaistCM, measurement.HipCM, measurement.NeckCM} {
+		if cm != nil && (*cm < 20 || *cm > 250) {
+			return fmt.Errorf("%w: circumferences must be between 20 and 250 cm", ErrInvalidMeasurement)
+		}
+	}
+	return nil
+}
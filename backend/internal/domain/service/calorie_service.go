@@ -30,6 +30,66 @@ func (s *CalorieService) CalculateBMR(weight, height float64, age int, gender en
 	return int(math.Round(result))
 }
 
+// CalculateBMRWithFormula calculates BMR using the given formula. Lean mass
+// formulas fall back to Mifflin-St Jeor when body fat is unknown.
+// Katch-McArdle: 370 + 21.6*lean mass
+// Cunningham: 500 + 22*lean mass
+func (s *CalorieService) CalculateBMRWithFormula(formula entity.BMRFormula, weight, height float64, age int, gender entity.Gender, bodyFat *float64) int {
+	if bodyFat == nil || !formula.NeedsLeanMass() {
+		return s.CalculateBMR(weight, height, age, gender)
+	}
+
+	leanMass := s.CalculateLeanMass(weight, *bodyFat)
+	if formula == entity.BMRCunningham {
+		return int(math.Round(500 + 22*leanMass))
+	}
+	return int(math.Round(370 + 21.6*leanMass))
+}
+
+// ProfileBMR calculates BMR with the profile's chosen formula
+func (s *CalorieService) ProfileBMR(profile *entity.UserProfile) int {
+	return s.CalculateBMRWithFormula(profile.BMRFormula, profile.Weight, profile.Height, profile.Age, profile.Gender, profile.BodyFatPercent)
+}
+
+// CalculateLeanMass calculates lean body mass in kg from weight and body fat percentage
+func (s *CalorieService) CalculateLeanMass(weight, bodyFat float64) float64 {
+	return math.Round(weight*(1-bodyFat/100)*10) / 10
+}
+
+// EstimateBodyFatNavy estimates body fat percentage with the US Navy
+// circumference method (measurements in cm). Women need a hip measurement;
+// other genders use the female equation when a hip measurement is given.
+// Returns false when the measurements cannot produce an estimate.
+// Male: 495 / (1.0324 - 0.19077*log10(waist - neck) + 0.15456*log10(height)) - 450
+// Female: 495 / (1.29579 - 0.35004*log10(waist + hip - neck) + 0.22100*log10(height)) - 450
+func (s *CalorieService) EstimateBodyFatNavy(gender entity.Gender, height, waist, neck float64, hip *float64) (float64, bool) {
+	female := gender == entity.GenderFemale || (gender == entity.GenderOther && hip != nil)
+	if female && hip == nil {
+		return 0, false
+	}
+
+	var density float64
+	if female {
+		girth := waist + *hip - neck
+		if girth <= 0 {
+			return 0, false
+		}
+		density = 1.29579 - 0.35004*math.Log10(girth) + 0.22100*math.Log10(height)
+	} else {
+		girth := waist - neck
+		if girth <= 0 {
+			return 0, false
+		}
+		density = 1.0324 - 0.19077*math.Log10(girth) + 0.15456*math.Log10(height)
+	}
+
+	bodyFat := 495/density - 450
+	if bodyFat < 2 || bodyFat > 70 {
+		return 0, false
+	}
+	return math.Round(bodyFat*10) / 10, true
+}
+
 // CalculateTDEE calculates Total Daily Energy Expenditure
 // using activity level multipliers
 func (s *CalorieService) CalculateTDEE(bmr int, activityLevel entity.ActivityLevel) int {
@@ -141,29 +201,9 @@ func (s *CalorieService) CalculateWaterIntake(weight float64, activityLevel enti
 	return math.Round(water*10) / 10
 }
 
-// MET values for different activities
-var metValues = map[string]float64{
-	"walking":   3.5,
-	"jogging":   7.0,
-	"running":   10.0,
-	"cycling":   8.0,
-	"swimming":  8.0,
-	"yoga":      3.0,
-	"strength":  6.0,
-	"dancing":   5.0,
-	"hiking":    6.0,
-	"basketball": 8.0,
-	"tennis":    7.0,
-	"soccer":    10.0,
-}
-
 // CalculateCaloriesBurned calculates calories burned from exercise
-func (s *CalorieService) CalculateCaloriesBurned(weight float64, activityType string, duration int) int {
-	met := 4.0 // default
-	if val, ok := metValues[activityType]; ok {
-		met = val
-	}
-
+// kcal/min = MET × 3.5 × weight (kg) / 200
+func (s *CalorieService) CalculateCaloriesBurned(weight, met float64, duration int) int {
 	caloriesPerMinute := (met * weight * 3.5) / 200
 	return int(math.Round(caloriesPerMinute * float64(duration)))
 }
@@ -184,6 +224,68 @@ func (s *CalorieService) CalculateWeightChangeTimeline(currentWeight, targetWeig
 	return weeks, months, safeRate
 }
 
+// Daily limits and goals not covered by the macro split
+const (
+	fiberPer1000Calories = 14.0 // g, Dietary Guidelines for Americans
+	sugarCaloriesPercent = 0.10 // WHO free sugar limit as share of calories
+	sodiumLimitMg        = 2000 // mg, WHO recommendation
+	targetTolerance      = 0.10 // within ±10% counts as on track
+)
+
+// CalculateDailyTargets calculates the daily nutrition targets for a profile
+func (s *CalorieService) CalculateDailyTargets(profile *entity.UserProfile) entity.DailyTargets {
+	calories := float64(profile.TargetCalories)
+
+	return entity.DailyTargets{
+		Calories: profile.TargetCalories,
+		Protein:  profile.ProteinTarget,
+		Carbs:    profile.CarbsTarget,
+		Fat:      profile.FatTarget,
+		Fiber:    math.Round(calories/1000*fiberPer1000Calories*10) / 10,
+		Sugar:    math.Round(calories*sugarCaloriesPercent/4*10) / 10, // 4 calories per gram
+		Sodium:   sodiumLimitMg,
+	}
+}
+
+// CalculateDailyProgress compares daily totals against targets
+func (s *CalorieService) CalculateDailyProgress(totals entity.DailyMacros, targets entity.DailyTargets) entity.DailyProgress {
+	return entity.DailyProgress{
+		Calories: nutrientProgress(float64(totals.Calories), float64(targets.Calories), false),
+		Protein:  nutrientProgress(totals.Protein, float64(targets.Protein), false),
+		Carbs:    nutrientProgress(totals.Carbs, float64(targets.Carbs), false),
+		Fat:      nutrientProgress(totals.Fat, float64(targets.Fat), false),
+		Fiber:    nutrientProgress(totals.Fiber, targets.Fiber, false),
+		Sugar:    nutrientProgress(totals.Sugar, targets.Sugar, true),
+		Sodium:   nutrientProgress(float64(totals.Sodium), float64(targets.Sodium), true),
+	}
+}
+
+// nutrientProgress calculates progress towards a target. For limits, anything up to
+// the target is on track; for goals, the consumed amount should be within tolerance.
+func nutrientProgress(consumed, target float64, isLimit bool) entity.NutrientProgress {
+	progress := entity.NutrientProgress{
+		Consumed:  math.Round(consumed*10) / 10,
+		Target:    math.Round(target*10) / 10,
+		Remaining: math.Round((target-consumed)*10) / 10,
+		Status:    entity.TargetStatusOnTrack,
+	}
+
+	if target > 0 {
+		progress.Percent = math.Round(consumed/target*1000) / 10
+	}
+
+	switch {
+	case isLimit && consumed > target:
+		progress.Status = entity.TargetStatusOver
+	case !isLimit && consumed > target*(1+targetTolerance):
+		progress.Status = entity.TargetStatusOver
+	case !isLimit && consumed < target*(1-targetTolerance):
+		progress.Status = entity.TargetStatusUnder
+	}
+
+	return progress
+}
+
 // CalculateProfile calculates all profile metrics from onboarding data
 func (s *CalorieService) CalculateProfile(req *entity.OnboardingRequest) *entity.OnboardingResponse {
 	bmr := s.CalculateBMR(req.Weight, req.Height, req.Age, req.Gender)
@@ -200,3 +302,29 @@ func (s *CalorieService) CalculateProfile(req *entity.OnboardingRequest) *entity
 		FatTarget:      macroTargets.Fat,
 	}
 }
+
+// RecalculateProfile recomputes a profile's BMR, TDEE, calorie target and
+// macros from its current body stats, BMR formula, activity level and goal.
+// When adaptive TDEE is enabled and an adjusted TDEE has been estimated, it
+// replaces the activity-based TDEE.
+func (s *CalorieService) RecalculateProfile(profile *entity.UserProfile) {
+	bmr := s.ProfileBMR(profile)
+
+	tdee := s.CalculateTDEE(bmr, profile.ActivityLevel)
+	if profile.AdaptiveTDEE && profile.AdjustedTDEE != nil {
+		tdee = *profile.AdjustedTDEE
+	}
+
+	targetCalories := s.CalculateTargetCalories(tdee, profile.Goal)
+	macroTargets := s.CalculateMacroTargets(targetCalories, profile.Goal)
+
+	profile.BMR = bmr
+	profile.TDEE = tdee
+	profile.TargetCalories = targetCalories
+	profile.ProteinTarget = macroTargets.Protein
+	profile.CarbsTarget = macroTargets.Carbs
+	profile.FatTarget = macroTargets.Fat
+	profile.ProteinCalories = macroTargets.Protein * 4
+	profile.CarbsCalories = macroTargets.Carbs * 4
+	profile.FatCalories = macroTargets.Fat * 9
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/diary"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidImport is returned when an import file or its options can't be used
+var ErrInvalidImport = errors.New("invalid import")
+
+// maxImportRows caps the data rows in one import file
+const maxImportRows = 20000
+
+// DiaryService exports and imports a user's food diary
+type DiaryService struct {
+	mealRepo     *repository.MealRepository
+	userRepo     *repository.UserRepository
+	transactor   *repository.Transactor
+	photos       *PhotoService
+	achievements *AchievementService
+	events       *WebhookService
+	stats        *DailyStatsCache
+}
+
+// NewDiaryService creates a new diary service
+func NewDiaryService(mealRepo *repository.MealRepository, userRepo *repository.UserRepository, transactor *repository.Transactor, photos *PhotoService, achievements *AchievementService, events *WebhookService, stats *DailyStatsCache) *DiaryService {
+	return &DiaryService{
+		mealRepo:     mealRepo,
+		userRepo:     userRepo,
+		transactor:   transactor,
+		photos:       photos,
+		achievements: achievements,
+		events:       events,
+		stats:        stats,
+	}
+}
+
+// Export exports meals in an optional date range as CSV or JSON, returning
+// the content type
+func (s *DiaryService) Export(ctx context.Context, userID uuid.UUID, format entity.ExportFormat, from, to *time.Time) ([]byte, string, error) {
+	if format != entity.ExportCSV && format != entity.ExportJSON {
+		return nil, "", fmt.Errorf("%w: format must be csv or json", ErrInvalidImport)
+	}
+
+	meals, err := s.mealRepo.FindByUserIDRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, "", err
+	}
+	if meals == nil {
+		meals = []*entity.Meal{}
+	}
+
+	if format == entity.ExportJSON {
+		s.photos.SignMeals(meals)
+		body, err := json.Marshal(meals)
+		return body, "application/json", err
+	}
+
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	var buf bytes.Buffer
+	if err := diary.WriteCSV(&buf, meals, loc); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "text/csv; charset=utf-8", nil
+}
+
+// Import imports meals from a CSV file. Every row is validated and reported;
+// valid rows are saved unless this is a dry run. Rows matching an existing
+// meal's date, meal type, name and calories are duplicates and are skipped
+// unless duplicates are allowed. The meals are saved in one transaction, so
+// an import that fails part way saves nothing.
+func (s *DiaryService) Import(ctx context.Context, userID uuid.UUID, data []byte, opts *entity.ImportOptions) (*entity.ImportResult, error) {
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	source, rows, err := diary.Parse(data, opts, loc, maxImportRows)
+	if err != nil {
+		if errors.Is(err, diary.ErrInvalidFile) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		return nil, err
+	}
+
+	result := &entity.ImportResult{
+		Source:  source,
+		DryRun:  opts.DryRun,
+		Rows:    len(rows),
+		Results: make([]entity.ImportRow, 0, len(rows)),
+	}
+
+	existing, err := s.existingMeals(ctx, userID, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	today := truncateDay(time.Now().In(loc))
+	var dates []time.Time
+	seenDates := make(map[time.Time]bool)
+	importRows := func(ctx context.Context) error {
+		for _, row := range rows {
+			out := entity.ImportRow{Row: row.Line, Errors: row.Errors}
+			switch {
+			case row.Skip:
+				out.Status = entity.ImportRowSkipped
+				result.Skipped++
+			case row.Meal == nil:
+				out.Status = entity.ImportRowInvalid
+				result.Invalid++
+			case row.Meal.Date.After(today):
+				out.Status = entity.ImportRowInvalid
+				out.Errors = append(out.Errors, "date is in the future")
+				result.Invalid++
+			default:
+				meal := row.Meal
+				meal.UserID = userID
+				out.Meal = meal
+
+				key := duplicateKey(meal)
+				if existing[key] > 0 && !opts.AllowDuplicates {
+					existing[key]--
+					out.Status = entity.ImportRowDuplicate
+					result.Duplicates++
+					break
+				}
+				result.Valid++
+
+				if opts.DryRun {
+					out.Status = entity.ImportRowValid
+					break
+				}
+				meal.ID = uuid.New()
+				err := s.events.Record(ctx, userID, entity.EventMealCreated, func(ctx context.Context) (interface{}, error) {
+					return meal, s.mealRepo.Create(ctx, meal)
+				})
+				if err != nil {
+					return err
+				}
+				out.Status = entity.ImportRowImported
+				result.Imported++
+
+				if !seenDates[meal.Date] {
+					seenDates[meal.Date] = true
+					dates = append(dates, meal.Date)
+				}
+			}
+			result.Results = append(result.Results, out)
+		}
+		return nil
+	}
+
+	if opts.DryRun {
+		err = importRows(ctx)
+	} else {
+		err = s.transactor.WithinTx(ctx, importRows)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dates) == 0 {
+		return result, nil
+	}
+	s.stats.Invalidate(ctx, userID, dates...)
+
+	// Only recent days can affect streaks
+	var recent []time.Time
+	for _, date := range dates {
+		if today.Sub(date) <= streakWindowDays*24*time.Hour {
+			recent = append(recent, date)
+		}
+	}
+	if len(recent) > 0 {
+		s.achievements.MealsChanged(ctx, userID, false, recent...)
+	}
+	return result, nil
+}
+
+// existingMeals counts the user's meals by duplicate key across the dates
+// covered by the parsed rows
+func (s *DiaryService) existingMeals(ctx context.Context, userID uuid.UUID, rows []diary.Row) (map[string]int, error) {
+	var from, to *time.Time
+	for _, row := range rows {
+		if row.Meal == nil {
+			continue
+		}
+		date := row.Meal.Date
+		if from == nil || date.Before(*from) {
+			from = &date
+		}
+		if to == nil || date.After(*to) {
+			to = &date
+		}
+	}
+	counts := make(map[string]int)
+	if from == nil {
+		return counts, nil
+	}
+
+	meals, err := s.mealRepo.FindByUserIDRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, meal := range meals {
+		counts[duplicateKey(meal)]++
+	}
+	return counts, nil
+}
+
+// location returns the user's timezone
+func (s *DiaryService) location(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return entity.DefaultLocation(), nil
+		}
+		return nil, err
+	}
+	return profile.Location(), nil
+}
+
+// duplicateKey identifies a meal for duplicate detection: the same food
+// logged with the same calories in the same slot on the same day
+func duplicateKey(meal *entity.Meal) string {
+	name := strings.ToLower(strings.Join(strings.Fields(meal.Name), " "))
+	return fmt.Sprintf("%s|%s|%s|%d", meal.Date.Format("2006-01-02"), meal.MealType, name, meal.Calories)
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrProfileRequired is returned when an operation needs the user's body weight
+	ErrProfileRequired = errors.New("complete onboarding to log exercise")
+	// ErrInvalidExercise is returned when an exercise log or activity is malformed
+	ErrInvalidExercise = errors.New("invalid exercise")
+)
+
+// maxMET bounds MET values; the highest Compendium entries are around 23
+const maxMET = 25
+
+// ExerciseService handles exercise logging and the activity catalog
+type ExerciseService struct {
+	exerciseRepo   *repository.ExerciseRepository
+	userRepo       *repository.UserRepository
+	calorieService *CalorieService
+}
+
+// NewExerciseService creates a new exercise service
+func NewExerciseService(exerciseRepo *repository.ExerciseRepository, userRepo *repository.UserRepository, calorieService *CalorieService) *ExerciseService {
+	return &ExerciseService{
+		exerciseRepo:   exerciseRepo,
+		userRepo:       userRepo,
+		calorieService: calorieService,
+	}
+}
+
+// GetActivities gets the activity catalog, including the user's custom activities
+func (s *ExerciseService) GetActivities(ctx context.Context, userID uuid.UUID) ([]*entity.ExerciseActivity, error) {
+	activities, err := s.exerciseRepo.FindActivities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if activities == nil {
+		activities = []*entity.ExerciseActivity{}
+	}
+	return activities, nil
+}
+
+// CreateActivity adds a custom activity to the user's catalog
+func (s *ExerciseService) CreateActivity(ctx context.Context, userID uuid.UUID, req *entity.CreateActivityRequest) (*entity.ExerciseActivity, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidExercise)
+	}
+
+	activity := &entity.ExerciseActivity{
+		ID:          "custom_" + uuid.New().String(),
+		UserID:      &userID,
+		Name:        name,
+		NameEn:      strings.TrimSpace(req.NameEn),
+		Category:    req.Category,
+		METLight:    req.METLight,
+		METModerate: req.METModerate,
+		METVigorous: req.METVigorous,
+		Emoji:       req.Emoji,
+		IsCustom:    true,
+	}
+	if activity.NameEn == "" {
+		activity.NameEn = activity.Name
+	}
+	if activity.Category == "" {
+		activity.Category = "custom"
+	}
+	if activity.METLight == 0 {
+		activity.METLight = activity.METModerate
+	}
+	if activity.METVigorous == 0 {
+		activity.METVigorous = activity.METModerate
+	}
+
+	for _, met := range []float64{activity.METLight, activity.METModerate, activity.METVigorous} {
+		if met <= 0 || met > maxMET {
+			return nil, fmt.Errorf("%w: MET values must be between 0 and 25", ErrInvalidExercise)
+		}
+	}
+
+	if err := s.exerciseRepo.CreateActivity(ctx, activity); err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+// DeleteActivity deletes a custom activity; existing logs keep their snapshot
+func (s *ExerciseService) DeleteActivity(ctx context.Context, activityID string, userID uuid.UUID) error {
+	return s.exerciseRepo.DeleteActivity(ctx, activityID, userID)
+}
+
+// CreateLog logs an exercise session, computing calories from the user's current weight
+func (s *ExerciseService) CreateLog(ctx context.Context, userID uuid.UUID, req *entity.CreateExerciseRequest) (*entity.ExerciseLog, error) {
+	log := &entity.ExerciseLog{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Intensity:       req.Intensity,
+		DurationMinutes: req.DurationMinutes,
+		Notes:           req.Notes,
+	}
+	if log.Intensity == "" {
+		log.Intensity = entity.IntensityModerate
+	}
+
+	// Set date - use provided date or today
+	if req.Date != nil {
+		log.Date = *req.Date
+	} else {
+		log.Date = time.Now()
+	}
+
+	activity, err := s.exerciseRepo.FindActivityByID(ctx, req.ActivityID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.calculate(ctx, log, activity); err != nil {
+		return nil, err
+	}
+
+	if err := s.exerciseRepo.CreateLog(ctx, log); err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+// GetLogs gets exercise logs for a user, optionally filtered by date
+func (s *ExerciseService) GetLogs(ctx context.Context, userID uuid.UUID, date *time.Time) ([]*entity.ExerciseLog, error) {
+	return s.exerciseRepo.FindLogsByUserID(ctx, userID, date)
+}
+
+// GetLogByID gets an exercise log by ID
+func (s *ExerciseService) GetLogByID(ctx context.Context, logID, userID uuid.UUID) (*entity.ExerciseLog, error) {
+	log, err := s.exerciseRepo.FindLogByID(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if log.UserID != userID {
+		return nil, repository.ErrUserNotFound
+	}
+
+	return log, nil
+}
+
+// UpdateLog updates an exercise log. Calories are recomputed from the
+// user's current weight when the activity, intensity or duration changes.
+func (s *ExerciseService) UpdateLog(ctx context.Context, logID, userID uuid.UUID, req *entity.UpdateExerciseRequest) (*entity.ExerciseLog, error) {
+	log, err := s.GetLogByID(ctx, logID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	recalculate := false
+	var activity *entity.ExerciseActivity
+
+	if req.ActivityID != nil {
+		activity, err = s.exerciseRepo.FindActivityByID(ctx, *req.ActivityID, userID)
+		if err != nil {
+			return nil, err
+		}
+		recalculate = true
+	}
+	if req.Intensity != nil {
+		log.Intensity = *req.Intensity
+		recalculate = true
+	}
+	if req.DurationMinutes != nil {
+		log.DurationMinutes = *req.DurationMinutes
+		recalculate = true
+	}
+	if req.Notes != nil {
+		log.Notes = req.Notes
+	}
+	if req.Date != nil {
+		log.Date = *req.Date
+	}
+
+	if recalculate {
+		if activity == nil && log.ActivityID != nil {
+			activity, err = s.exerciseRepo.FindActivityByID(ctx, *log.ActivityID, userID)
+			if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+				return nil, err
+			}
+		}
+		if activity == nil {
+			// The activity was deleted; keep the stored snapshot
+			activity = &entity.ExerciseActivity{
+				Name:        log.ActivityName,
+				NameEn:      log.ActivityNameEn,
+				METLight:    log.MET,
+				METModerate: log.MET,
+				METVigorous: log.MET,
+			}
+		}
+		if err := s.calculate(ctx, log, activity); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.exerciseRepo.UpdateLog(ctx, log); err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+// DeleteLog deletes an exercise log
+func (s *ExerciseService) DeleteLog(ctx context.Context, logID, userID uuid.UUID) error {
+	return s.exerciseRepo.DeleteLog(ctx, logID, userID)
+}
+
+// GetDailyExercise gets exercise logs and totals for a date
+func (s *ExerciseService) GetDailyExercise(ctx context.Context, userID uuid.UUID, date time.Time) (*entity.DailyExercise, error) {
+	logs, err := s.exerciseRepo.FindLogsByUserID(ctx, userID, &date)
+	if err != nil {
+		return nil, err
+	}
+
+	daily := &entity.DailyExercise{Logs: logs}
+	if daily.Logs == nil {
+		daily.Logs = []*entity.ExerciseLog{}
+	}
+	for _, log := range logs {
+		daily.CaloriesBurned += log.CaloriesBurned
+		daily.Minutes += log.DurationMinutes
+	}
+
+	return daily, nil
+}
+
+// calculate validates the log and fills in the activity snapshot, MET,
+// body weight and calories burned
+func (s *ExerciseService) calculate(ctx context.Context, log *entity.ExerciseLog, activity *entity.ExerciseActivity) error {
+	if !log.Intensity.Valid() {
+		return fmt.Errorf("%w: intensity must be light, moderate or vigorous", ErrInvalidExercise)
+	}
+	if log.DurationMinutes <= 0 || log.DurationMinutes > 24*60 {
+		return fmt.Errorf("%w: duration_minutes must be between 1 and 1440", ErrInvalidExercise)
+	}
+
+	profile, err := s.userRepo.FindProfileByUserID(ctx, log.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ErrProfileRequired
+		}
+		return err
+	}
+
+	if activity.ID != "" {
+		log.ActivityID = &activity.ID
+	}
+	log.ActivityName = activity.Name
+	log.ActivityNameEn = activity.NameEn
+	log.MET = activity.MET(log.Intensity)
+	log.Weight = profile.Weight
+	log.CaloriesBurned = s.calorieService.CalculateCaloriesBurned(profile.Weight, log.MET, log.DurationMinutes)
+
+	return nil
+}
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidFast is returned when a fast or fasting preference is malformed
+	ErrInvalidFast = errors.New("invalid fast")
+	// ErrNoMealLogged is returned when starting from the last meal before any meal has a time
+	ErrNoMealLogged = errors.New("no meal with a time has been logged")
+)
+
+const (
+	// maxFastHours matches the fasts.target_hours check constraint
+	maxFastHours = 72
+	// autoStartLookback bounds how old a meal can be to auto start a fast from it
+	autoStartLookback = 48 * time.Hour
+	// fastClockSkew tolerates client clocks slightly ahead of the server
+	fastClockSkew = 5 * time.Minute
+)
+
+// FastingService handles intermittent fasting operations
+type FastingService struct {
+	fastingRepo *repository.FastingRepository
+	mealRepo    *repository.MealRepository
+	userRepo    *repository.UserRepository
+}
+
+// NewFastingService creates a new fasting service
+func NewFastingService(fastingRepo *repository.FastingRepository, mealRepo *repository.MealRepository, userRepo *repository.UserRepository) *FastingService {
+	return &FastingService{
+		fastingRepo: fastingRepo,
+		mealRepo:    mealRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// GetProtocols gets the preset fasting protocols
+func (s *FastingService) GetProtocols() []entity.FastingProtocolInfo {
+	return entity.FastingProtocols
+}
+
+// GetStatus gets the active fast's progress, or the time since the last meal
+// when no fast is active
+func (s *FastingService) GetStatus(ctx context.Context, userID uuid.UUID) (*entity.FastStatus, error) {
+	now := time.Now()
+
+	fast, err := s.fastingRepo.FindActive(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if fast != nil {
+		decorateFast(fast, now)
+		target := time.Duration(fast.TargetHours * float64(time.Hour))
+		elapsed := now.Sub(fast.StartedAt)
+
+		status := &entity.FastStatus{
+			Active:         true,
+			Fast:           fast,
+			ElapsedSeconds: int64(elapsed.Seconds()),
+			Progress:       roundToOne(math.Min(100, elapsed.Hours()/fast.TargetHours*100)),
+			TargetReached:  elapsed >= target,
+		}
+		if remaining := target - elapsed; remaining > 0 {
+			status.RemainingSeconds = int64(remaining.Seconds())
+		}
+		return status, nil
+	}
+
+	status := &entity.FastStatus{}
+	lastMeal, err := s.mealRepo.FindLastEatenAt(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if lastMeal != nil {
+		hours := round2(now.Sub(*lastMeal).Hours())
+		status.LastMealAt = lastMeal
+		status.HoursSinceLastMeal = &hours
+	}
+	return status, nil
+}
+
+// Start starts a fast. It begins now unless a start time is given or it is
+// started from the last meal's eaten_at.
+func (s *FastingService) Start(ctx context.Context, userID uuid.UUID, req *entity.StartFastRequest) (*entity.Fast, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	protocol, targetHours := prefs.Protocol, prefs.TargetHours
+	if req.Protocol != "" {
+		protocol = req.Protocol
+		if targetHours, err = resolveTargetHours(protocol, req.TargetHours); err != nil {
+			return nil, err
+		}
+	} else if req.TargetHours != nil {
+		return nil, fmt.Errorf("%w: target_hours requires a protocol", ErrInvalidFast)
+	}
+
+	now := time.Now()
+	startedAt := now
+	switch {
+	case req.FromLastMeal:
+		if req.StartedAt != nil {
+			return nil, fmt.Errorf("%w: started_at and from_last_meal are mutually exclusive", ErrInvalidFast)
+		}
+		lastMeal, err := s.mealRepo.FindLastEatenAt(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if lastMeal == nil {
+			return nil, ErrNoMealLogged
+		}
+		startedAt = *lastMeal
+	case req.StartedAt != nil:
+		startedAt = *req.StartedAt
+	}
+
+	if startedAt.After(now.Add(fastClockSkew)) {
+		return nil, fmt.Errorf("%w: a fast cannot start in the future", ErrInvalidFast)
+	}
+	if now.Sub(startedAt) > maxFastHours*time.Hour {
+		return nil, fmt.Errorf("%w: a fast cannot start more than %d hours ago", ErrInvalidFast, maxFastHours)
+	}
+
+	fast := &entity.Fast{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Protocol:    protocol,
+		TargetHours: targetHours,
+		StartedAt:   startedAt,
+		Note:        req.Note,
+	}
+	if err := s.fastingRepo.Create(ctx, fast); err != nil {
+		return nil, err
+	}
+
+	decorateFast(fast, now)
+	return fast, nil
+}
+
+// End ends the active fast. Ending before the target leaves it incomplete but
+// not broken; only a logged meal breaks a fast.
+func (s *FastingService) End(ctx context.Context, userID uuid.UUID, req *entity.EndFastRequest) (*entity.Fast, error) {
+	fast, err := s.fastingRepo.FindActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	endedAt := now
+	if req.EndedAt != nil {
+		endedAt = *req.EndedAt
+	}
+	if endedAt.Before(fast.StartedAt) {
+		return nil, fmt.Errorf("%w: ended_at is before the fast started", ErrInvalidFast)
+	}
+	if endedAt.After(now.Add(fastClockSkew)) {
+		return nil, fmt.Errorf("%w: a fast cannot end in the future", ErrInvalidFast)
+	}
+
+	fast.EndedAt = &endedAt
+	if err := s.fastingRepo.Update(ctx, fast); err != nil {
+		return nil, err
+	}
+
+	decorateFast(fast, now)
+	return fast, nil
+}
+
+// History gets a user's fasts started within a date range, newest first
+func (s *FastingService) History(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entity.Fast, error) {
+	fasts, err := s.fastingRepo.FindByUserID(ctx, userID, truncateDay(from), truncateDay(to).AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, fast := range fasts {
+		decorateFast(fast, now)
+	}
+	return fasts, nil
+}
+
+// Delete deletes a fast
+func (s *FastingService) Delete(ctx context.Context, userID, fastID uuid.UUID) error {
+	return s.fastingRepo.Delete(ctx, fastID, userID)
+}
+
+// GetStats gets the fasting streak and totals. A day counts towards the streak
+// when a completed fast ended on it in the user's timezone.
+func (s *FastingService) GetStats(ctx context.Context, userID uuid.UUID) (*entity.FastingStats, error) {
+	loc := entity.DefaultLocation()
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+	if profile != nil {
+		loc = profile.Location()
+	}
+
+	now := time.Now()
+	today := truncateDay(now.In(loc))
+	fasts, err := s.fastingRepo.FindByUserID(ctx, userID, today.AddDate(0, 0, -streakWindowDays), now.Add(time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &entity.FastingStats{TotalFasts: len(fasts)}
+	var days []time.Time
+	var ended int
+	var totalHours float64
+
+	// Fasts are newest first; streak days must be oldest first
+	for i := len(fasts) - 1; i >= 0; i-- {
+		fast := fasts[i]
+		decorateFast(fast, now)
+		if fast.EndedAt == nil {
+			continue
+		}
+
+		ended++
+		totalHours += fast.DurationHours
+		if fast.DurationHours > stats.LongestHours {
+			stats.LongestHours = fast.DurationHours
+		}
+		if fast.Broken {
+			stats.BrokenFasts++
+		}
+		if fast.Completed {
+			stats.CompletedFasts++
+			days = append(days, truncateDay(fast.EndedAt.In(loc)))
+		}
+	}
+
+	if ended > 0 {
+		stats.CompletionRate = roundToOne(float64(stats.CompletedFasts) / float64(ended) * 100)
+		stats.AverageHours = round2(totalHours / float64(ended))
+	}
+	stats.Streak = calculateStreak(entity.StreakFasting, days, today)
+
+	return stats, nil
+}
+
+// GetPreferences gets a user's fasting preferences, defaulting to 16:8
+// without auto start
+func (s *FastingService) GetPreferences(ctx context.Context, userID uuid.UUID) (*entity.FastingPreferences, error) {
+	prefs, err := s.fastingRepo.FindPreferences(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return &entity.FastingPreferences{UserID: userID, Protocol: entity.Fasting16_8, TargetHours: 16}, nil
+		}
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences updates a user's fasting preferences. Turning auto start
+// on while no fast is active starts one from a recent last meal.
+func (s *FastingService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *entity.UpdateFastingPreferencesRequest) (*entity.FastingPreferences, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	wasAutoStart := prefs.AutoStart
+
+	if req.Protocol != nil {
+		if prefs.TargetHours, err = resolveTargetHours(*req.Protocol, req.TargetHours); err != nil {
+			return nil, err
+		}
+		prefs.Protocol = *req.Protocol
+	} else if req.TargetHours != nil {
+		if prefs.TargetHours, err = resolveTargetHours(prefs.Protocol, req.TargetHours); err != nil {
+			return nil, err
+		}
+	}
+	if req.AutoStart != nil {
+		prefs.AutoStart = *req.AutoStart
+	}
+
+	if err := s.fastingRepo.UpsertPreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	if prefs.AutoStart && !wasAutoStart {
+		lastMeal, err := s.mealRepo.FindLastEatenAt(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if lastMeal != nil && time.Since(*lastMeal) <= autoStartLookback {
+			if err := s.autoStart(ctx, prefs, *lastMeal); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return prefs, nil
+}
+
+// MealLogged applies a newly logged meal to the active fast, logging any
+// failure. A meal inside the window breaks the fast and a meal after the
+// target completes it; with auto start a new fast then begins from the meal.
+// Auto-started fasts are still in their eating window for the first
+// 24 - target hours, so further meals move the start forward instead.
+func (s *FastingService) MealLogged(ctx context.Context, userID uuid.UUID, meal *entity.Meal) {
+	if meal.EatenAt == nil {
+		return
+	}
+	if err := s.mealLogged(ctx, userID, meal); err != nil {
+		log.Printf("Fasting update failed for user %s: %v", userID, err)
+	}
+}
+
+func (s *FastingService) mealLogged(ctx context.Context, userID uuid.UUID, meal *entity.Meal) error {
+	eatenAt := *meal.EatenAt
+
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	fast, err := s.fastingRepo.FindActive(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return err
+	}
+
+	if fast != nil {
+		// Meals backfilled from before the fast don't affect it
+		if eatenAt.Before(fast.StartedAt) {
+			return nil
+		}
+
+		elapsed := eatenAt.Sub(fast.StartedAt)
+		if fast.AutoStarted && elapsed < eatingWindow(fast.TargetHours) {
+			fast.StartedAt = eatenAt
+			return s.fastingRepo.Update(ctx, fast)
+		}
+
+		fast.EndedAt = &eatenAt
+		if elapsed < time.Duration(fast.TargetHours*float64(time.Hour)) {
+			fast.Broken = true
+			fast.BrokenByMealID = &meal.ID
+		}
+		if err := s.fastingRepo.Update(ctx, fast); err != nil {
+			return err
+		}
+	}
+
+	if prefs.AutoStart && time.Since(eatenAt) <= autoStartLookback {
+		return s.autoStart(ctx, prefs, eatenAt)
+	}
+	return nil
+}
+
+// autoStart starts a fast from a meal using the preferred protocol. An
+// already active fast is left alone.
+func (s *FastingService) autoStart(ctx context.Context, prefs *entity.FastingPreferences, startedAt time.Time) error {
+	fast := &entity.Fast{
+		ID:          uuid.New(),
+		UserID:      prefs.UserID,
+		Protocol:    prefs.Protocol,
+		TargetHours: prefs.TargetHours,
+		StartedAt:   startedAt,
+		AutoStarted: true,
+	}
+	if err := s.fastingRepo.Create(ctx, fast); err != nil && !errors.Is(err, repository.ErrFastInProgress) {
+		return err
+	}
+	return nil
+}
+
+// resolveTargetHours returns a protocol's fasting hours. Presets have fixed
+// hours; custom requires target hours.
+func resolveTargetHours(protocol entity.FastingProtocol, targetHours *float64) (float64, error) {
+	if protocol == entity.FastingCustom {
+		if targetHours == nil || *targetHours <= 0 || *targetHours > maxFastHours {
+			return 0, fmt.Errorf("%w: custom protocol requires target_hours between 0 and %d", ErrInvalidFast, maxFastHours)
+		}
+		return roundToOne(*targetHours), nil
+	}
+
+	for _, preset := range entity.FastingProtocols {
+		if preset.Protocol == protocol {
+			if targetHours != nil && *targetHours != preset.FastingHours {
+				return 0, fmt.Errorf("%w: %s fasts are %g hours; use the custom protocol", ErrInvalidFast, protocol, preset.FastingHours)
+			}
+			return preset.FastingHours, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: unknown protocol %q", ErrInvalidFast, protocol)
+}
+
+// eatingWindow returns how long an auto-started fast accepts meals before
+// counting them against the fast
+func eatingWindow(targetHours float64) time.Duration {
+	return time.Duration(math.Max(1, 24-targetHours) * float64(time.Hour))
+}
+
+// decorateFast sets a fast's derived fields as of now
+func decorateFast(fast *entity.Fast, now time.Time) {
+	end := now
+	if fast.EndedAt != nil {
+		end = *fast.EndedAt
+	}
+
+	target := time.Duration(fast.TargetHours * float64(time.Hour))
+	fast.TargetEndAt = fast.StartedAt.Add(target)
+	fast.DurationHours = round2(end.Sub(fast.StartedAt).Hours())
+	fast.Completed = fast.EndedAt != nil && !fast.Broken && end.Sub(fast.StartedAt) >= target
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+)
+
+const (
+	// foodHistoryDays is how far back logged meals count towards recent and
+	// frequent foods and personalized search
+	foodHistoryDays = 90
+	// foodHistoryLimit caps the logged foods loaded for a user
+	foodHistoryLimit = 200
+)
+
+// foodAffinity is what a user's history says about foods, used to boost the
+// search results they are likely to log
+type foodAffinity struct {
+	logged    map[string]*entity.LoggedFood
+	favorites map[string]bool
+	mealType  entity.MealType // the meal the search falls in
+	now       time.Time
+}
+
+// newFoodAffinity indexes a user's logged and favorite foods at a local time
+func newFoodAffinity(logged []*entity.LoggedFood, favorites []*entity.FavoriteFood, now time.Time) *foodAffinity {
+	a := &foodAffinity{
+		logged:    make(map[string]*entity.LoggedFood, len(logged)),
+		favorites: make(map[string]bool, len(favorites)),
+		mealType:  mealTypeAt(now),
+		now:       now,
+	}
+	for _, lf := range logged {
+		a.logged[lf.Food.ID] = lf
+	}
+	for _, fav := range favorites {
+		a.favorites[fav.FoodID] = true
+	}
+	return a
+}
+
+// boost scores a food by how often and how recently the user logged it,
+// whether it is a favorite or one of their custom foods, and whether they
+// usually eat it at this meal. It is on the same scale as name relevance,
+// so a food logged every day can outrank a closer name match.
+func (a *foodAffinity) boost(food entity.FoodItem) int {
+	if a == nil {
+		return 0
+	}
+
+	boost := 0
+	if a.favorites[food.ID] {
+		boost += 25
+	}
+	if food.Source == entity.FoodSourceCustom {
+		boost += 15
+	}
+
+	logged, ok := a.logged[food.ID]
+	if !ok {
+		return boost
+	}
+	boost += frequencyBoost(logged.Count)
+	switch age := a.now.Sub(logged.LastLoggedAt); {
+	case age < 24*time.Hour:
+		boost += 20
+	case age < 7*24*time.Hour:
+		boost += 12
+	case age < 30*24*time.Hour:
+		boost += 5
+	}
+	if n := logged.MealTypes[a.mealType]; n > 0 && 2*n >= logged.Count {
+		boost += 15
+	}
+	return boost
+}
+
+// frequencyBoost grows by 10 each time the meal count doubles: 10 for one
+// meal, 20 for three, 30 for seven, up to 40
+func frequencyBoost(count int) int {
+	boost := int(10 * math.Log2(float64(count+1)))
+	if boost > 40 {
+		boost = 40
+	}
+	return boost
+}
+
+// mealTypeAt is the meal usually eaten at a local time
+func mealTypeAt(t time.Time) entity.MealType {
+	switch hour := t.Hour(); {
+	case hour >= 5 && hour < 11:
+		return entity.MealTypeBreakfast
+	case hour >= 11 && hour < 15:
+		return entity.MealTypeLunch
+	case hour >= 17 && hour < 22:
+		return entity.MealTypeDinner
+	}
+	return entity.MealTypeSnack
+}
+
+// foodSourceOf is the source of a food by its search result ID
+func foodSourceOf(id string) entity.FoodSource {
+	switch {
+	case strings.HasPrefix(id, entity.CustomFoodIDPrefix):
+		return entity.FoodSourceCustom
+	case strings.HasPrefix(id, usdaFoodIDPrefix):
+		return entity.FoodSourceUSDA
+	case strings.HasPrefix(id, offFoodIDPrefix):
+		return entity.FoodSourceOpenFoodFacts
+	}
+	return entity.FoodSourceLocal
+}
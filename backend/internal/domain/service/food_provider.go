@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/pkg/thai"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrFoodNotFound is returned when no provider knows a food
+	ErrFoodNotFound = errors.New("food not found")
+	// ErrProviderSkipped is returned by a provider with nothing to add to a
+	// search, such as a local catalog asked for a later page
+	ErrProviderSkipped = errors.New("provider skipped")
+)
+
+// FoodQuery is a food search
+type FoodQuery struct {
+	UserID   uuid.UUID
+	Query    string
+	Category string // "" or "all" for every category
+	Page     int
+	PageSize int
+
+	// affinity boosts the user's own foods in the merged ranking
+	affinity *foodAffinity
+}
+
+// FoodProvider is a source of foods
+type FoodProvider interface {
+	// Name identifies the provider in search result sources
+	Name() string
+	// Search returns the foods matching a query and whether the provider
+	// has more pages
+	Search(ctx context.Context, q FoodQuery) ([]entity.FoodItem, bool, error)
+	// LookupBarcode returns the food with a barcode, or ErrFoodNotFound
+	LookupBarcode(ctx context.Context, barcode string) (*entity.FoodItem, error)
+	// Handles reports whether a food ID belongs to the provider
+	Handles(id string) bool
+	// GetByID returns one of the provider's foods, or ErrFoodNotFound
+	GetByID(ctx context.Context, userID uuid.UUID, id string) (*entity.FoodItem, error)
+}
+
+// FoodAggregator searches every registered provider concurrently and merges
+// their results. Providers registered first win ties and duplicates.
+type FoodAggregator struct {
+	providers []registeredProvider
+}
+
+type registeredProvider struct {
+	provider FoodProvider
+	timeout  time.Duration
+}
+
+// providerResult is one provider's answer to a search
+type providerResult struct {
+	foods   []entity.FoodItem
+	hasMore bool
+	err     error
+}
+
+// NewFoodAggregator creates an aggregator without providers
+func NewFoodAggregator() *FoodAggregator {
+	return &FoodAggregator{}
+}
+
+// Register adds a provider whose calls are cut off after timeout
+func (a *FoodAggregator) Register(provider FoodProvider, timeout time.Duration) {
+	a.providers = append(a.providers, registeredProvider{provider: provider, timeout: timeout})
+}
+
+// Search queries every provider concurrently, then de-duplicates and ranks
+// the merged results by name relevance and the user's affinity for them.
+// Providers that fail or time out are reported as degraded; an error is only
+// returned when every provider that took part failed.
+func (a *FoodAggregator) Search(ctx context.Context, q FoodQuery) (*entity.SearchResult, error) {
+	results := make([]providerResult, len(a.providers))
+	var wg sync.WaitGroup
+	for i, p := range a.providers {
+		wg.Add(1)
+		go func(i int, p registeredProvider) {
+			defer wg.Done()
+			pctx, cancel := context.WithTimeout(ctx, p.timeout)
+			defer cancel()
+			foods, hasMore, err := p.provider.Search(pctx, q)
+			results[i] = providerResult{foods: foods, hasMore: hasMore, err: err}
+		}(i, p)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	sources := make(map[string]entity.SourceStatus, len(a.providers))
+	var merged []rankedFood
+	var firstErr error
+	answered, hasMore := false, false
+	seen := make(map[string]bool)
+	for i, res := range results {
+		name := a.providers[i].provider.Name()
+		switch {
+		case errors.Is(res.err, ErrProviderSkipped):
+			sources[name] = entity.SourceSkipped
+			continue
+		case res.err != nil:
+			log.Printf("Food provider %s search failed: %v", name, res.err)
+			sources[name] = entity.SourceDegraded
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			continue
+		}
+
+		sources[name] = entity.SourceOK
+		answered = true
+		hasMore = hasMore || res.hasMore
+		for j, food := range res.foods {
+			key := foodDedupeKey(food)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, rankedFood{
+				food:     food,
+				score:    foodRelevance(food, q.Query) + q.affinity.boost(food),
+				provider: i,
+				position: j,
+			})
+		}
+	}
+	if !answered && firstErr != nil {
+		return nil, firstErr
+	}
+
+	rankFoods(merged)
+	foods := make([]entity.FoodItem, len(merged))
+	for i, rf := range merged {
+		foods[i] = rf.food
+	}
+
+	return &entity.SearchResult{
+		Foods:   foods,
+		Total:   len(foods),
+		Page:    q.Page,
+		HasMore: hasMore,
+		Sources: sources,
+	}, nil
+}
+
+// LookupBarcode asks providers in registration order for a barcode. Provider
+// errors are returned only when no provider found it.
+func (a *FoodAggregator) LookupBarcode(ctx context.Context, barcode string) (*entity.FoodItem, error) {
+	var firstErr error
+	for _, p := range a.providers {
+		pctx, cancel := context.WithTimeout(ctx, p.timeout)
+		food, err := p.provider.LookupBarcode(pctx, barcode)
+		cancel()
+		if err == nil {
+			return food, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, ErrFoodNotFound) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, ErrFoodNotFound
+}
+
+// GetByID returns a food from the provider its ID belongs to
+func (a *FoodAggregator) GetByID(ctx context.Context, userID uuid.UUID, id string) (*entity.FoodItem, error) {
+	for _, p := range a.providers {
+		if !p.provider.Handles(id) {
+			continue
+		}
+		pctx, cancel := context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+		return p.provider.GetByID(pctx, userID, id)
+	}
+	return nil, ErrFoodNotFound
+}
+
+// rankedFood is a merged search result with what it is ranked by
+type rankedFood struct {
+	food     entity.FoodItem
+	score    int
+	provider int // registration order
+	position int // order within the provider's results
+}
+
+// rankFoods sorts by relevance, then provider, then each provider's own order
+func rankFoods(foods []rankedFood) {
+	sort.SliceStable(foods, func(i, j int) bool {
+		a, b := foods[i], foods[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.provider != b.provider {
+			return a.provider < b.provider
+		}
+		return a.position < b.position
+	})
+}
+
+// foodRelevance scores how well a food's names match a query: exact matches
+// beat prefixes, which beat word prefixes, then substrings, then foods
+// matching every query word. Thai is compared word by word ignoring tone
+// marks, and romanized queries such as "pad krapow" are also compared with
+// Thai names by their phonetic keys.
+func foodRelevance(food entity.FoodItem, query string) int {
+	words := strings.Join(thai.Segment(query), " ")
+	if words == "" {
+		return 0
+	}
+
+	best := 0
+	for _, name := range []string{food.Name, food.NameEn} {
+		if score := nameRelevance(strings.Join(thai.Segment(name), " "), words); score > best {
+			best = score
+		}
+	}
+	if key := thai.Key(query); key != "" && thai.ContainsThai(food.Name) {
+		if score := nameRelevance(thai.Key(food.Name), key); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// nameRelevance scores a normalized name against a normalized query
+func nameRelevance(name, query string) int {
+	switch {
+	case name == "":
+		return 0
+	case name == query:
+		return 100
+	case strings.HasPrefix(name, query):
+		return 75
+	case strings.Contains(" "+name, " "+query):
+		return 50
+	case strings.Contains(name, query):
+		return 25
+	case containsAllWords(name, query):
+		return 10
+	}
+	return 0
+}
+
+func containsAllWords(name, query string) bool {
+	for _, word := range strings.Fields(query) {
+		if !strings.Contains(name, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// foodDedupeKey identifies the same food across providers: by barcode, or
+// by name and calories when there is none
+func foodDedupeKey(food entity.FoodItem) string {
+	if food.Barcode != nil && *food.Barcode != "" {
+		return "barcode:" + *food.Barcode
+	}
+	name := food.NameEn
+	if name == "" {
+		name = food.Name
+	}
+	return "name:" + normalizeQuery(name) + ":" + strconv.Itoa(food.Nutrition.Calories)
+}
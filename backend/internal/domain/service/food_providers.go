@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/cache"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/bytetrack/backend/internal/pkg/thai"
+	"github.com/google/uuid"
+)
+
+// thaiFoodIDPrefix prefixes the IDs of the Thai food catalog
+const thaiFoodIDPrefix = "th_"
+
+// ThaiFoodProvider serves the local Thai food catalog, cached in the
+// "thai-foods" namespace
+type ThaiFoodProvider struct {
+	mealRepo *repository.MealRepository
+	catalog  cache.Store
+	ttl      time.Duration
+}
+
+// NewThaiFoodProvider creates a new Thai food provider
+func NewThaiFoodProvider(mealRepo *repository.MealRepository, store cache.Store, ttl time.Duration) *ThaiFoodProvider {
+	return &ThaiFoodProvider{
+		mealRepo: mealRepo,
+		catalog:  cache.Namespace(store, "thai-foods"),
+		ttl:      ttl,
+	}
+}
+
+// Name returns "local"
+func (p *ThaiFoodProvider) Name() string {
+	return entity.SearchSourceLocal
+}
+
+// Search searches the catalog, matching Thai words whatever their tone
+// marks and romanized spellings of them. Every match is returned on the
+// first page, best first.
+func (p *ThaiFoodProvider) Search(ctx context.Context, q FoodQuery) ([]entity.FoodItem, bool, error) {
+	if q.Page > 1 {
+		return nil, false, ErrProviderSkipped
+	}
+
+	// Matching ignores tone marks, so spellings differing only in them share
+	// an entry
+	key := "match:" + thai.Normalize(q.Query)
+	var thaiFoods []*entity.ThaiFood
+	if !getCached(ctx, p.catalog, key, &thaiFoods) {
+		terms := thai.Terms(q.Query)
+		patterns := make([]string, len(terms))
+		fuzzy := make([]string, len(terms))
+		for i, term := range terms {
+			patterns[i] = term.Pattern()
+			fuzzy[i] = term.Fuzzy()
+		}
+
+		var err error
+		thaiFoods, err = p.mealRepo.SearchThaiFoods(ctx, patterns, fuzzy)
+		if err != nil {
+			return nil, false, err
+		}
+		setCached(ctx, p.catalog, key, thaiFoods, p.ttl)
+	}
+
+	var foods []entity.FoodItem
+	for _, tf := range thaiFoods {
+		if q.Category != "" && q.Category != "all" && tf.Category != q.Category {
+			continue
+		}
+		foods = append(foods, tf.ToFoodItem())
+	}
+	return foods, false, nil
+}
+
+// IndexCatalog brings the search text of catalog foods up to date with the
+// segmentation dictionary; foods added by migrations have none until it runs
+func (p *ThaiFoodProvider) IndexCatalog(ctx context.Context) error {
+	thaiFoods, err := p.mealRepo.FindAllThaiFoods(ctx)
+	if err != nil {
+		return err
+	}
+
+	texts := make(map[string]string, len(thaiFoods))
+	for _, tf := range thaiFoods {
+		texts[tf.ID] = thai.SearchText(tf.Name, tf.NameEn)
+	}
+	changed, err := p.mealRepo.UpdateThaiFoodSearchText(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if changed > 0 {
+		log.Printf("Indexed %d Thai foods for search", changed)
+	}
+	return nil
+}
+
+// LookupBarcode always returns ErrFoodNotFound; Thai dishes have no barcodes
+func (p *ThaiFoodProvider) LookupBarcode(ctx context.Context, barcode string) (*entity.FoodItem, error) {
+	return nil, ErrFoodNotFound
+}
+
+// Handles reports whether id is a catalog ID
+func (p *ThaiFoodProvider) Handles(id string) bool {
+	return strings.HasPrefix(id, thaiFoodIDPrefix)
+}
+
+// GetByID gets a catalog food
+func (p *ThaiFoodProvider) GetByID(ctx context.Context, userID uuid.UUID, id string) (*entity.FoodItem, error) {
+	tf, err := p.mealRepo.FindThaiFoodByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrFoodNotFound
+		}
+		return nil, err
+	}
+	food := tf.ToFoodItem()
+	return &food, nil
+}
+
+// Catalog lists the catalog, optionally filtered by category
+func (p *ThaiFoodProvider) Catalog(ctx context.Context, category string) ([]entity.FoodItem, error) {
+	if category == "" {
+		category = "all"
+	}
+	key := "category:" + category
+
+	var thaiFoods []*entity.ThaiFood
+	if !getCached(ctx, p.catalog, key, &thaiFoods) {
+		var err error
+		if category != "all" {
+			thaiFoods, err = p.mealRepo.FindThaiFoodsByCategory(ctx, category)
+		} else {
+			thaiFoods, err = p.mealRepo.FindAllThaiFoods(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		setCached(ctx, p.catalog, key, thaiFoods, p.ttl)
+	}
+
+	var foods []entity.FoodItem
+	for _, tf := range thaiFoods {
+		foods = append(foods, tf.ToFoodItem())
+	}
+
+	return foods, nil
+}
+
+// CustomFoodProvider serves the searching user's own custom foods
+type CustomFoodProvider struct {
+	mealRepo *repository.MealRepository
+}
+
+// NewCustomFoodProvider creates a new custom food provider
+func NewCustomFoodProvider(mealRepo *repository.MealRepository) *CustomFoodProvider {
+	return &CustomFoodProvider{mealRepo: mealRepo}
+}
+
+// Name returns "custom"
+func (p *CustomFoodProvider) Name() string {
+	return string(entity.FoodSourceCustom)
+}
+
+// Search searches the user's custom foods. Every match is returned on the
+// first page; custom foods have no category, so category searches skip them.
+func (p *CustomFoodProvider) Search(ctx context.Context, q FoodQuery) ([]entity.FoodItem, bool, error) {
+	if q.Page > 1 || q.UserID == uuid.Nil || (q.Category != "" && q.Category != "all") {
+		return nil, false, ErrProviderSkipped
+	}
+
+	customFoods, err := p.mealRepo.SearchCustomFoods(ctx, q.UserID, q.Query)
+	if err != nil {
+		return nil, false, err
+	}
+
+	foods := make([]entity.FoodItem, len(customFoods))
+	for i, cf := range customFoods {
+		foods[i] = cf.ToFoodItem()
+	}
+	return foods, false, nil
+}
+
+// LookupBarcode always returns ErrFoodNotFound; custom foods have no barcodes
+func (p *CustomFoodProvider) LookupBarcode(ctx context.Context, barcode string) (*entity.FoodItem, error) {
+	return nil, ErrFoodNotFound
+}
+
+// Handles reports whether id is a custom food ID
+func (p *CustomFoodProvider) Handles(id string) bool {
+	return strings.HasPrefix(id, entity.CustomFoodIDPrefix)
+}
+
+// GetByID gets one of the user's custom foods
+func (p *CustomFoodProvider) GetByID(ctx context.Context, userID uuid.UUID, id string) (*entity.FoodItem, error) {
+	foodID, err := uuid.Parse(strings.TrimPrefix(id, entity.CustomFoodIDPrefix))
+	if err != nil {
+		return nil, ErrFoodNotFound
+	}
+
+	cf, err := p.mealRepo.FindCustomFoodByID(ctx, foodID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrFoodNotFound
+		}
+		return nil, err
+	}
+	food := cf.ToFoodItem()
+	return &food, nil
+}
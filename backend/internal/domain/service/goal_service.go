@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+// goalIntakeDays is the window of complete days averaged for the logged deficit
+const goalIntakeDays = 14
+
+// GoalService reports progress towards the goal weight
+type GoalService struct {
+	mealRepo       *repository.MealRepository
+	weightRepo     *repository.WeightRepository
+	userRepo       *repository.UserRepository
+	calorieService *CalorieService
+}
+
+// NewGoalService creates a new goal service
+func NewGoalService(mealRepo *repository.MealRepository, weightRepo *repository.WeightRepository, userRepo *repository.UserRepository, calorieService *CalorieService) *GoalService {
+	return &GoalService{
+		mealRepo:       mealRepo,
+		weightRepo:     weightRepo,
+		userRepo:       userRepo,
+		calorieService: calorieService,
+	}
+}
+
+// GetProgress reports progress from the first to the latest weigh-in towards
+// the goal weight, and projects when it will be reached at the average daily
+// deficit from logged meals. Until enough meals are logged the planned deficit
+// from the calorie target is used instead.
+func (s *GoalService) GetProgress(ctx context.Context, userID uuid.UUID) (*entity.GoalProgress, error) {
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &entity.GoalProgress{
+		Goal:           profile.Goal,
+		StartWeight:    profile.Weight,
+		CurrentWeight:  profile.Weight,
+		GoalWeight:     profile.GoalWeight,
+		TDEE:           profile.TDEE,
+		TargetCalories: profile.TargetCalories,
+	}
+
+	first, err := s.weightRepo.FindFirst(ctx, userID)
+	switch {
+	case err == nil:
+		progress.StartWeight = first.Weight
+		progress.StartDate = &first.Date
+	case err != repository.ErrUserNotFound:
+		return nil, err
+	}
+
+	latest, err := s.weightRepo.FindLatest(ctx, userID)
+	switch {
+	case err == nil:
+		progress.CurrentWeight = latest.Weight
+		progress.CurrentDate = &latest.Date
+	case err != repository.ErrUserNotFound:
+		return nil, err
+	}
+
+	progress.BMI = s.calorieService.CalculateBMI(progress.CurrentWeight, profile.Height)
+	progress.BMICategory = s.calorieService.GetBMICategory(progress.BMI)
+	progress.IdealWeightMin, progress.IdealWeightMax = s.calorieService.CalculateIdealWeightRange(profile.Height)
+
+	today := truncateDay(time.Now().In(profile.Location()))
+	if err := s.applyDeficit(ctx, profile, progress, today); err != nil {
+		return nil, err
+	}
+
+	if profile.GoalWeight == nil {
+		progress.Suggestion = "Set a goal weight to see your progress and projected completion date"
+		return progress, nil
+	}
+
+	goalWeight := *profile.GoalWeight
+	current := progress.CurrentWeight
+	losing := goalWeight < progress.StartWeight
+	if goalWeight == progress.StartWeight {
+		losing = goalWeight < current
+	}
+
+	progress.GoalReached = current == goalWeight || (losing && current < goalWeight) || (!losing && current > goalWeight)
+
+	percent := 100.0
+	remaining := 0.0
+	if !progress.GoalReached {
+		remaining = round2(math.Abs(goalWeight - current))
+		total := math.Abs(goalWeight - progress.StartWeight)
+		moved := current - progress.StartWeight
+		if losing {
+			moved = -moved
+		}
+		percent = 0
+		if total > 0 {
+			percent = math.Max(0, math.Min(100, moved/total*100))
+		}
+	}
+	percent = roundToOne(percent)
+	progress.Remaining = &remaining
+	progress.PercentComplete = &percent
+
+	if progress.GoalReached {
+		if profile.Goal != entity.GoalMaintain {
+			maintain := entity.GoalMaintain
+			progress.SuggestedGoal = &maintain
+			progress.Suggestion = "You have reached your goal weight. Switch your goal to maintain to keep it"
+		}
+		return progress, nil
+	}
+
+	// A surplus while losing or a deficit while gaining never reaches the goal
+	deficit := progress.DailyDeficit
+	if deficit == 0 || (losing && deficit < 0) || (!losing && deficit > 0) {
+		progress.Warning = "At your current intake your weight is not moving towards your goal weight"
+		return progress, nil
+	}
+
+	weeks, months, safeRate := s.calorieService.CalculateWeightChangeTimeline(current, goalWeight, deficit*7)
+	projected := today.AddDate(0, 0, weeks*7)
+	progress.WeeksRemaining = &weeks
+	progress.MonthsRemaining = &months
+	progress.ProjectedDate = &projected
+	progress.SafeRate = &safeRate
+
+	if !safeRate {
+		if math.Abs(progress.WeeklyRate) > 1 {
+			progress.Warning = "Your rate is faster than the safe maximum of 1 kg per week; consider a smaller calorie difference"
+		} else {
+			progress.Warning = "Your rate is slower than 0.25 kg per week, so progress may be hard to notice"
+		}
+	}
+
+	return progress, nil
+}
+
+// applyDeficit sets the daily energy balance from the average intake over the
+// last complete days, falling back to the calorie target when too few are logged
+func (s *GoalService) applyDeficit(ctx context.Context, profile *entity.UserProfile, progress *entity.GoalProgress, today time.Time) error {
+	to := today.AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, -(goalIntakeDays - 1))
+
+	days, err := s.mealRepo.GetRangeTotals(ctx, profile.UserID, from, to)
+	if err != nil {
+		return err
+	}
+
+	// Days with very little logged are most likely incomplete rather than fasts
+	var intake int
+	for _, day := range days {
+		if day.Totals.Calories < minDayCalories {
+			continue
+		}
+		intake += day.Totals.Calories
+		progress.DaysLogged++
+	}
+
+	progress.DeficitSource = entity.DeficitTarget
+	progress.DailyDeficit = profile.TDEE - profile.TargetCalories
+	if progress.DaysLogged >= minLoggedDays {
+		average := int(math.Round(float64(intake) / float64(progress.DaysLogged)))
+		progress.AverageIntake = &average
+		progress.DeficitSource = entity.DeficitLogged
+		progress.DailyDeficit = profile.TDEE - average
+	}
+
+	progress.WeeklyRate = round2(-float64(progress.DailyDeficit) * 7 / energyPerKg)
+	return nil
+}
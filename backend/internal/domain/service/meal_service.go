@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/bytetrack/backend/internal/domain/entity"
@@ -9,48 +12,95 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrVersionMismatch is returned when an update is based on a stale version
+var ErrVersionMismatch = errors.New("resource has been modified")
+
 // MealService handles meal operations
 type MealService struct {
-	mealRepo *repository.MealRepository
+	mealRepo       *repository.MealRepository
+	userRepo       *repository.UserRepository
+	calorieService *CalorieService
+	photos         *PhotoService
+	water          *WaterService
+	exercise       *ExerciseService
+	achievements   *AchievementService
+	fasting        *FastingService
+	events         *WebhookService
+	stats          *DailyStatsCache
 }
 
 // NewMealService creates a new meal service
-func NewMealService(mealRepo *repository.MealRepository) *MealService {
+func NewMealService(mealRepo *repository.MealRepository, userRepo *repository.UserRepository, calorieService *CalorieService, photos *PhotoService, water *WaterService, exercise *ExerciseService, achievements *AchievementService, fasting *FastingService, events *WebhookService, stats *DailyStatsCache) *MealService {
 	return &MealService{
-		mealRepo: mealRepo,
+		mealRepo:       mealRepo,
+		userRepo:       userRepo,
+		calorieService: calorieService,
+		photos:         photos,
+		water:          water,
+		exercise:       exercise,
+		achievements:   achievements,
+		fasting:        fasting,
+		events:         events,
+		stats:          stats,
 	}
 }
 
 // CreateMeal creates a new meal
 func (s *MealService) CreateMeal(ctx context.Context, userID uuid.UUID, req *entity.CreateMealRequest) (*entity.Meal, error) {
+	if err := req.Nutrients.Validate(); err != nil {
+		return nil, err
+	}
+	scanned, err := req.Scanned()
+	if err != nil {
+		return nil, err
+	}
+
 	meal := &entity.Meal{
-		ID:       uuid.New(),
-		UserID:   userID,
-		Name:     req.Name,
-		NameEn:   req.NameEn,
-		Calories: req.Calories,
-		Grams:    req.Grams,
-		MealType: req.MealType,
-		Protein:  req.Protein,
-		Carbs:    req.Carbs,
-		Fat:      req.Fat,
-		Fiber:    req.Fiber,
-		Sugar:    req.Sugar,
-		Sodium:   req.Sodium,
-		ImageURL: req.ImageURL,
-	}
-
-	// Set date - use provided date or today
-	if req.Date != nil {
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		NameEn:    req.NameEn,
+		Calories:  req.Calories,
+		Grams:     req.Grams,
+		MealType:  req.MealType,
+		Protein:   req.Protein,
+		Carbs:     req.Carbs,
+		Fat:       req.Fat,
+		Fiber:     req.Fiber,
+		Sugar:     req.Sugar,
+		Sodium:    req.Sodium,
+		Nutrients: req.Nutrients,
+		ImageURL:  req.ImageURL,
+		EatenAt:   req.EatenAt,
+	}
+	if req.FoodID != nil && strings.TrimSpace(*req.FoodID) != "" {
+		foodID := strings.TrimSpace(*req.FoodID)
+		meal.FoodID = &foodID
+	}
+
+	// Set date - use provided date, the time eaten, or today. Meals logged
+	// for today without a time are eaten now.
+	switch {
+	case req.Date != nil:
 		meal.Date = *req.Date
-	} else {
-		meal.Date = time.Now()
+	case req.EatenAt != nil:
+		meal.Date = *req.EatenAt
+	default:
+		now := time.Now()
+		meal.Date = now
+		meal.EatenAt = &now
 	}
 
-	if err := s.mealRepo.Create(ctx, meal); err != nil {
+	err = s.events.Record(ctx, userID, entity.EventMealCreated, func(ctx context.Context) (interface{}, error) {
+		return meal, s.mealRepo.Create(ctx, meal)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.stats.Invalidate(ctx, userID, meal.Date)
+	s.achievements.MealsChanged(ctx, userID, scanned, meal.Date)
+	s.fasting.MealLogged(ctx, userID, meal)
 	return meal, nil
 }
 
@@ -66,21 +116,35 @@ func (s *MealService) GetMealByID(ctx context.Context, mealID, userID uuid.UUID)
 		return nil, repository.ErrUserNotFound
 	}
 
+	s.photos.SignMeal(meal)
 	return meal, nil
 }
 
 // GetMeals gets meals for a user with optional date filter
 func (s *MealService) GetMeals(ctx context.Context, userID uuid.UUID, date *time.Time) ([]*entity.Meal, error) {
-	return s.mealRepo.FindByUserID(ctx, userID, date)
+	meals, err := s.mealRepo.FindByUserID(ctx, userID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	s.photos.SignMeals(meals)
+	return meals, nil
 }
 
 // GetMealsByType gets meals for a user by meal type
 func (s *MealService) GetMealsByType(ctx context.Context, userID uuid.UUID, mealType entity.MealType, date *time.Time) ([]*entity.Meal, error) {
-	return s.mealRepo.FindByUserIDAndMealType(ctx, userID, mealType, date)
+	meals, err := s.mealRepo.FindByUserIDAndMealType(ctx, userID, mealType, date)
+	if err != nil {
+		return nil, err
+	}
+
+	s.photos.SignMeals(meals)
+	return meals, nil
 }
 
-// UpdateMeal updates a meal
-func (s *MealService) UpdateMeal(ctx context.Context, mealID, userID uuid.UUID, req *entity.UpdateMealRequest) (*entity.Meal, error) {
+// UpdateMeal updates a meal. When expectedVersion is set and no longer matches,
+// the current meal is returned together with ErrVersionMismatch.
+func (s *MealService) UpdateMeal(ctx context.Context, mealID, userID uuid.UUID, expectedVersion *int, req *entity.UpdateMealRequest) (*entity.Meal, error) {
 	meal, err := s.mealRepo.FindByID(ctx, mealID)
 	if err != nil {
 		return nil, err
@@ -91,6 +155,13 @@ func (s *MealService) UpdateMeal(ctx context.Context, mealID, userID uuid.UUID,
 		return nil, repository.ErrUserNotFound
 	}
 
+	if expectedVersion != nil && meal.Version != *expectedVersion {
+		s.photos.SignMeal(meal)
+		return meal, ErrVersionMismatch
+	}
+
+	previousDate := meal.Date
+
 	// Update fields if provided
 	if req.Name != nil {
 		meal.Name = *req.Name
@@ -125,17 +196,40 @@ func (s *MealService) UpdateMeal(ctx context.Context, mealID, userID uuid.UUID,
 	if req.Sodium != nil {
 		meal.Sodium = req.Sodium
 	}
+	if req.Nutrients != nil {
+		if err := req.Nutrients.Validate(); err != nil {
+			return nil, err
+		}
+		meal.Nutrients = req.Nutrients
+	}
 	if req.ImageURL != nil {
 		meal.ImageURL = req.ImageURL
 	}
 	if req.Date != nil {
 		meal.Date = *req.Date
 	}
+	if req.EatenAt != nil {
+		meal.EatenAt = req.EatenAt
+	}
 
-	if err := s.mealRepo.Update(ctx, meal); err != nil {
+	err = s.events.Record(ctx, userID, entity.EventMealUpdated, func(ctx context.Context) (interface{}, error) {
+		return meal, s.mealRepo.Update(ctx, meal, expectedVersion)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			current, findErr := s.mealRepo.FindByID(ctx, mealID)
+			if findErr != nil {
+				return nil, findErr
+			}
+			s.photos.SignMeal(current)
+			return current, ErrVersionMismatch
+		}
 		return nil, err
 	}
 
+	s.stats.Invalidate(ctx, userID, previousDate, meal.Date)
+	s.achievements.MealsChanged(ctx, userID, false, previousDate, meal.Date)
+	s.photos.SignMeal(meal)
 	return meal, nil
 }
 
@@ -151,32 +245,176 @@ func (s *MealService) DeleteMeal(ctx context.Context, mealID, userID uuid.UUID)
 		return repository.ErrUserNotFound
 	}
 
-	return s.mealRepo.Delete(ctx, mealID)
+	err = s.events.Record(ctx, userID, entity.EventMealDeleted, func(ctx context.Context) (interface{}, error) {
+		return meal, s.mealRepo.Delete(ctx, mealID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.stats.Invalidate(ctx, userID, meal.Date)
+	s.achievements.MealsChanged(ctx, userID, false, meal.Date)
+	return nil
 }
 
-// GetDailyStats gets daily nutrition stats for a user
+// GetDailyStats gets daily nutrition stats for a user, including progress
+// towards the profile's targets, a breakdown by meal type, hydration and
+// net calories after exercise
 func (s *MealService) GetDailyStats(ctx context.Context, userID uuid.UUID, date time.Time) (*entity.DailyStats, error) {
-	meals, err := s.mealRepo.FindByUserID(ctx, userID, &date)
+	meals, totals, cached := s.stats.get(ctx, userID, date)
+	if !cached {
+		var err error
+		if meals, err = s.mealRepo.FindByUserID(ctx, userID, &date); err != nil {
+			return nil, err
+		}
+		if totals, err = s.mealRepo.GetDailyTotals(ctx, userID, date); err != nil {
+			return nil, err
+		}
+		s.stats.set(ctx, userID, date, meals, totals)
+	}
+
+	s.photos.SignMeals(meals)
+
+	totals.Nutrients = totals.Nutrients.Rounded()
+
+	stats := &entity.DailyStats{
+		Date:       date,
+		Meals:      meals,
+		Totals:     *totals,
+		ByMealType: mealTypeBreakdown(meals, totals.Calories),
+	}
+
+	// Targets are only available once onboarding is complete
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+	if profile != nil {
+		targets := s.calorieService.CalculateDailyTargets(profile)
+		progress := s.calorieService.CalculateDailyProgress(*totals, targets)
+		stats.Targets = &targets
+		stats.Progress = &progress
+		stats.OverTarget = progress.Calories.Status == entity.TargetStatusOver
+		stats.UnderTarget = progress.Calories.Status == entity.TargetStatusUnder
+	}
+
+	hydration, err := s.water.dailyHydration(ctx, userID, date, profile)
 	if err != nil {
 		return nil, err
 	}
+	stats.Hydration = hydration
 
-	totals, err := s.mealRepo.GetDailyTotals(ctx, userID, date)
+	exercise, err := s.exercise.GetDailyExercise(ctx, userID, date)
 	if err != nil {
 		return nil, err
 	}
+	stats.Exercise = exercise
+	stats.NetCalories = totals.Calories - exercise.CaloriesBurned
 
-	return &entity.DailyStats{
-		Date:   date,
-		Meals:  meals,
-		Totals: *totals,
-	}, nil
+	return stats, nil
+}
+
+// GetRangeStats gets per-day nutrition totals and averages between two dates (inclusive)
+func (s *MealService) GetRangeStats(ctx context.Context, userID uuid.UUID, from, to time.Time) (*entity.RangeStats, error) {
+	days, err := s.mealRepo.GetRangeTotals(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &entity.RangeStats{
+		From:       from,
+		To:         to,
+		Days:       days,
+		DaysLogged: len(days),
+	}
+	if stats.Days == nil {
+		stats.Days = []*entity.DayTotals{}
+	}
+
+	for _, day := range days {
+		t := &stats.Totals
+		t.Calories += day.Totals.Calories
+		t.Protein += day.Totals.Protein
+		t.Carbs += day.Totals.Carbs
+		t.Fat += day.Totals.Fat
+		t.Fiber += day.Totals.Fiber
+		t.Sugar += day.Totals.Sugar
+		t.Sodium += day.Totals.Sodium
+		t.Nutrients = t.Nutrients.Add(day.Totals.Nutrients)
+		day.Totals.Nutrients = day.Totals.Nutrients.Rounded()
+	}
+
+	if n := float64(len(days)); n > 0 {
+		stats.Averages = entity.DailyMacros{
+			Calories:  int(math.Round(float64(stats.Totals.Calories) / n)),
+			Protein:   math.Round(stats.Totals.Protein/n*10) / 10,
+			Carbs:     math.Round(stats.Totals.Carbs/n*10) / 10,
+			Fat:       math.Round(stats.Totals.Fat/n*10) / 10,
+			Fiber:     math.Round(stats.Totals.Fiber/n*10) / 10,
+			Sugar:     math.Round(stats.Totals.Sugar/n*10) / 10,
+			Sodium:    int(math.Round(float64(stats.Totals.Sodium) / n)),
+			Nutrients: stats.Totals.Nutrients.Scale(1 / n).Rounded(),
+		}
+	}
+	stats.Totals.Nutrients = stats.Totals.Nutrients.Rounded()
+
+	return stats, nil
+}
+
+// mealTypeBreakdown totals meals per meal type, always listing every slot in order
+func mealTypeBreakdown(meals []*entity.Meal, totalCalories int) []entity.MealTypeBreakdown {
+	mealTypes := []entity.MealType{
+		entity.MealTypeBreakfast, entity.MealTypeLunch, entity.MealTypeDinner, entity.MealTypeSnack,
+	}
+
+	breakdown := make([]entity.MealTypeBreakdown, len(mealTypes))
+	index := make(map[entity.MealType]int, len(mealTypes))
+	for i, mt := range mealTypes {
+		breakdown[i].MealType = mt
+		index[mt] = i
+	}
+
+	for _, meal := range meals {
+		i, ok := index[meal.MealType]
+		if !ok {
+			continue
+		}
+		b := &breakdown[i]
+		b.MealCount++
+		b.Totals.Calories += meal.Calories
+		b.Totals.Protein += meal.Protein
+		b.Totals.Carbs += meal.Carbs
+		b.Totals.Fat += meal.Fat
+		if meal.Fiber != nil {
+			b.Totals.Fiber += *meal.Fiber
+		}
+		if meal.Sugar != nil {
+			b.Totals.Sugar += *meal.Sugar
+		}
+		if meal.Sodium != nil {
+			b.Totals.Sodium += *meal.Sodium
+		}
+		b.Totals.Nutrients = b.Totals.Nutrients.Add(meal.Nutrients)
+	}
+
+	for i := range breakdown {
+		breakdown[i].Totals.Nutrients = breakdown[i].Totals.Nutrients.Rounded()
+		if totalCalories > 0 {
+			breakdown[i].PercentOfCalories = math.Round(float64(breakdown[i].Totals.Calories)/float64(totalCalories)*1000) / 10
+		}
+	}
+
+	return breakdown
 }
 
 // Favorite Food operations
 
 // AddFavorite adds a favorite food
 func (s *MealService) AddFavorite(ctx context.Context, userID uuid.UUID, req *entity.AddFavoriteRequest) (*entity.FavoriteFood, error) {
+	if err := req.Nutrients.Validate(); err != nil {
+		return nil, err
+	}
+
 	fav := &entity.FavoriteFood{
 		ID:           uuid.New(),
 		UserID:       userID,
@@ -194,6 +432,7 @@ func (s *MealService) AddFavorite(ctx context.Context, userID uuid.UUID, req *en
 		ServingSize:  req.ServingSize,
 		ServingUnit:  req.ServingUnit,
 		Emoji:        req.Emoji,
+		Nutrients:    req.Nutrients,
 	}
 
 	if err := s.mealRepo.AddFavorite(ctx, fav); err != nil {
@@ -217,6 +456,10 @@ func (s *MealService) RemoveFavorite(ctx context.Context, userID uuid.UUID, food
 
 // CreateCustomFood creates a custom food
 func (s *MealService) CreateCustomFood(ctx context.Context, userID uuid.UUID, req *entity.CreateCustomFoodRequest) (*entity.CustomFood, error) {
+	if err := req.Nutrients.Validate(); err != nil {
+		return nil, err
+	}
+
 	food := &entity.CustomFood{
 		ID:          uuid.New(),
 		UserID:      userID,
@@ -230,6 +473,7 @@ func (s *MealService) CreateCustomFood(ctx context.Context, userID uuid.UUID, re
 		Sodium:      req.Sodium,
 		ServingSize: req.ServingSize,
 		ServingUnit: req.ServingUnit,
+		Nutrients:   req.Nutrients,
 	}
 
 	if req.ServingUnit == "" {
@@ -0,0 +1,675 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/notify"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/bytetrack/backend/internal/pkg/netguard"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidReminder is returned when a reminder is malformed
+	ErrInvalidReminder = errors.New("invalid reminder")
+	// ErrInvalidNotificationSettings is returned when quiet hours or a push subscription are malformed
+	ErrInvalidNotificationSettings = errors.New("invalid notification settings")
+	// ErrNotDelivered is returned when a notification could not be delivered on any channel
+	ErrNotDelivered = errors.New("notification was not delivered on any channel")
+)
+
+const (
+	// reminderGrace is how many minutes late a reminder is still sent, e.g. after a restart
+	reminderGrace = 30
+	// maxRemindersPerUser bounds how many reminders a user can configure
+	maxRemindersPerUser = 20
+	clockLayout         = "15:04"
+)
+
+// reminderContent holds reminder titles and bodies; %s is the meal name
+var reminderContent = map[string]map[entity.ReminderKind]notify.Message{
+	"th": {
+		entity.ReminderMeal:    {Title: "ได้เวลาบันทึก%s", Body: "อย่าลืมบันทึก%sของวันนี้นะ", URL: "/meals/add"},
+		entity.ReminderWater:   {Title: "ได้เวลาดื่มน้ำ", Body: "ดื่มน้ำสักแก้วแล้วบันทึกไว้ เพื่อให้ถึงเป้าหมายวันนี้", URL: "/dashboard"},
+		entity.ReminderWeighIn: {Title: "ได้เวลาชั่งน้ำหนัก", Body: "ชั่งน้ำหนักแล้วบันทึกไว้ เพื่อติดตามแนวโน้มของคุณ", URL: "/goals"},
+	},
+	"en": {
+		entity.ReminderMeal:    {Title: "Time to log %s", Body: "Don't forget to log today's %s", URL: "/meals/add"},
+		entity.ReminderWater:   {Title: "Time for some water", Body: "Have a glass of water and log it to reach today's goal", URL: "/dashboard"},
+		entity.ReminderWeighIn: {Title: "Time to weigh in", Body: "Log your weight to keep your trend up to date", URL: "/goals"},
+	},
+}
+
+// mealNames holds meal type names used in reminder content
+var mealNames = map[string]map[entity.MealType]string{
+	"th": {
+		entity.MealTypeBreakfast: "อาหารเช้า",
+		entity.MealTypeLunch:     "อาหารกลางวัน",
+		entity.MealTypeDinner:    "อาหารเย็น",
+		entity.MealTypeSnack:     "ของว่าง",
+	},
+	"en": {
+		entity.MealTypeBreakfast: "breakfast",
+		entity.MealTypeLunch:     "lunch",
+		entity.MealTypeDinner:    "dinner",
+		entity.MealTypeSnack:     "snack",
+	},
+}
+
+// testContent holds the test notification in each language
+var testContent = map[string]notify.Message{
+	"th": {Title: "ByteTrack", Body: "การแจ้งเตือนพร้อมใช้งานแล้ว", URL: "/dashboard"},
+	"en": {Title: "ByteTrack", Body: "Notifications are working", URL: "/dashboard"},
+}
+
+// NotificationService handles reminders, notification settings and delivery
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	mealRepo         *repository.MealRepository
+	waterRepo        *repository.WaterRepository
+	weightRepo       *repository.WeightRepository
+	userRepo         *repository.UserRepository
+	calorieService   *CalorieService
+	notifiers        []notify.Notifier
+	vapidPublicKey   string
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo *repository.NotificationRepository, mealRepo *repository.MealRepository, waterRepo *repository.WaterRepository, weightRepo *repository.WeightRepository, userRepo *repository.UserRepository, calorieService *CalorieService, notifiers []notify.Notifier, vapidPublicKey string) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		mealRepo:         mealRepo,
+		waterRepo:        waterRepo,
+		weightRepo:       weightRepo,
+		userRepo:         userRepo,
+		calorieService:   calorieService,
+		notifiers:        notifiers,
+		vapidPublicKey:   vapidPublicKey,
+	}
+}
+
+// GetReminders gets a user's reminders
+func (s *NotificationService) GetReminders(ctx context.Context, userID uuid.UUID) ([]*entity.Reminder, error) {
+	reminders, err := s.notificationRepo.FindRemindersByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if reminders == nil {
+		reminders = []*entity.Reminder{}
+	}
+	return reminders, nil
+}
+
+// CreateReminder creates a reminder
+func (s *NotificationService) CreateReminder(ctx context.Context, userID uuid.UUID, req *entity.CreateReminderRequest) (*entity.Reminder, error) {
+	reminder := &entity.Reminder{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Kind:     req.Kind,
+		MealType: req.MealType,
+		Time:     req.Time,
+		Weekdays: req.Weekdays,
+		Language: req.Language,
+		Enabled:  true,
+	}
+	if req.Enabled != nil {
+		reminder.Enabled = *req.Enabled
+	}
+
+	if err := normalizeReminder(reminder); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.notificationRepo.FindRemindersByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxRemindersPerUser {
+		return nil, fmt.Errorf("%w: at most %d reminders are allowed", ErrInvalidReminder, maxRemindersPerUser)
+	}
+
+	if err := s.notificationRepo.CreateReminder(ctx, reminder); err != nil {
+		return nil, err
+	}
+
+	return reminder, nil
+}
+
+// UpdateReminder updates a reminder
+func (s *NotificationService) UpdateReminder(ctx context.Context, reminderID, userID uuid.UUID, req *entity.UpdateReminderRequest) (*entity.Reminder, error) {
+	reminder, err := s.notificationRepo.FindReminderByID(ctx, reminderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MealType != nil {
+		reminder.MealType = req.MealType
+	}
+	if req.Time != nil {
+		reminder.Time = *req.Time
+	}
+	if req.Weekdays != nil {
+		reminder.Weekdays = req.Weekdays
+	}
+	if req.Language != nil {
+		reminder.Language = req.Language
+	}
+	if req.Enabled != nil {
+		reminder.Enabled = *req.Enabled
+	}
+
+	if err := normalizeReminder(reminder); err != nil {
+		return nil, err
+	}
+
+	if err := s.notificationRepo.UpdateReminder(ctx, reminder); err != nil {
+		return nil, err
+	}
+
+	return reminder, nil
+}
+
+// DeleteReminder deletes a reminder
+func (s *NotificationService) DeleteReminder(ctx context.Context, reminderID, userID uuid.UUID) error {
+	return s.notificationRepo.DeleteReminder(ctx, reminderID, userID)
+}
+
+// GetSettings gets a user's notification settings, with defaults when never saved
+func (s *NotificationService) GetSettings(ctx context.Context, userID uuid.UUID) (*entity.NotificationSettings, error) {
+	settings, err := s.findSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.Timezone = entity.DefaultTimezone
+	if profile != nil {
+		settings.Timezone = profile.Location().String()
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings updates a user's quiet hours and delivery channels
+func (s *NotificationService) UpdateSettings(ctx context.Context, userID uuid.UUID, req *entity.UpdateNotificationSettingsRequest) (*entity.NotificationSettings, error) {
+	settings, err := s.findSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.QuietStart != nil {
+		settings.QuietStart = req.QuietStart
+	}
+	if req.QuietEnd != nil {
+		settings.QuietEnd = req.QuietEnd
+	}
+	if req.PushEnabled != nil {
+		settings.PushEnabled = *req.PushEnabled
+	}
+	if req.EmailEnabled != nil {
+		settings.EmailEnabled = *req.EmailEnabled
+	}
+
+	if err := normalizeQuietHours(settings); err != nil {
+		return nil, err
+	}
+
+	if err := s.notificationRepo.UpsertSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return s.GetSettings(ctx, userID)
+}
+
+// VAPIDPublicKey returns the application server key browsers subscribe with,
+// or an empty string when Web Push is not configured
+func (s *NotificationService) VAPIDPublicKey() string {
+	return s.vapidPublicKey
+}
+
+// Subscribe saves a browser's push subscription
+func (s *NotificationService) Subscribe(ctx context.Context, userID uuid.UUID, req *entity.SubscribePushRequest) (*entity.PushSubscription, error) {
+	endpoint := strings.TrimSpace(req.Endpoint)
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return nil, fmt.Errorf("%w: endpoint must be an https URL", ErrInvalidNotificationSettings)
+	}
+	// Hostnames resolving to private addresses are refused when pushed to
+	if !netguard.IsPublicHost(u.Hostname()) {
+		return nil, fmt.Errorf("%w: endpoint must not point to a private address", ErrInvalidNotificationSettings)
+	}
+	if req.Keys.P256dh == "" || req.Keys.Auth == "" {
+		return nil, fmt.Errorf("%w: keys.p256dh and keys.auth are required", ErrInvalidNotificationSettings)
+	}
+
+	sub := &entity.PushSubscription{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Endpoint: endpoint,
+		P256dh:   req.Keys.P256dh,
+		Auth:     req.Keys.Auth,
+	}
+
+	if err := s.notificationRepo.UpsertSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// Unsubscribe removes a browser's push subscription
+func (s *NotificationService) Unsubscribe(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	return s.notificationRepo.DeleteSubscription(ctx, userID, strings.TrimSpace(endpoint))
+}
+
+// SendTest sends a test notification on every enabled channel and returns
+// the channels it was delivered on
+func (s *NotificationService) SendTest(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	target, err := s.loadTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := testContent[target.language(nil)]
+	msg.Tag = "test"
+
+	channels := s.deliver(ctx, target, &msg)
+	if len(channels) == 0 {
+		return nil, ErrNotDelivered
+	}
+	return channels, nil
+}
+
+// RunDue sends reminders due at now in each user's timezone and returns how
+// many were sent. A reminder is handled once per local day: it is skipped
+// within quiet hours or when what it reminds about is already logged.
+func (s *NotificationService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	reminders, err := s.notificationRepo.FindEnabledReminders(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	targets := map[uuid.UUID]*notificationTarget{}
+	sent := 0
+	for _, reminder := range reminders {
+		target, ok := targets[reminder.UserID]
+		if !ok {
+			target, err = s.loadTarget(ctx, reminder.UserID)
+			if err != nil {
+				log.Printf("Failed to load notification settings for user %s: %v", reminder.UserID, err)
+			}
+			targets[reminder.UserID] = target
+		}
+		if target == nil {
+			continue
+		}
+
+		local := now.In(target.location)
+		today := truncateDay(local)
+		if reminder.LastSentOn != nil && truncateDay(*reminder.LastSentOn).Equal(today) {
+			continue
+		}
+		if !onWeekday(reminder.Weekdays, local.Weekday()) {
+			continue
+		}
+
+		due, err := parseClock(reminder.Time)
+		if err != nil {
+			continue
+		}
+		late := minuteOfDay(local) - due
+		if late < 0 || late > reminderGrace {
+			continue
+		}
+
+		claimed, err := s.notificationRepo.ClaimReminder(ctx, reminder.ID, today)
+		if err != nil {
+			log.Printf("Failed to claim reminder %s: %v", reminder.ID, err)
+			continue
+		}
+		if !claimed || target.inQuietHours(due) {
+			continue
+		}
+
+		done, err := s.alreadyLogged(ctx, reminder, target.profile, today)
+		if err != nil {
+			log.Printf("Failed to check reminder %s: %v", reminder.ID, err)
+			continue
+		}
+		if done {
+			continue
+		}
+
+		msg := reminderMessage(reminder, target.language(reminder.Language))
+		if len(s.deliver(ctx, target, msg)) > 0 {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// StartScheduler runs RunDue on the given interval until the context is cancelled
+func (s *NotificationService) StartScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sent, err := s.RunDue(ctx, now)
+			if err != nil {
+				log.Printf("Reminder scheduler failed: %v", err)
+				continue
+			}
+			if sent > 0 {
+				log.Printf("Sent %d reminders", sent)
+			}
+		}
+	}
+}
+
+// alreadyLogged reports whether what a reminder prompts for is already logged today
+func (s *NotificationService) alreadyLogged(ctx context.Context, reminder *entity.Reminder, profile *entity.UserProfile, today time.Time) (bool, error) {
+	switch reminder.Kind {
+	case entity.ReminderMeal:
+		meals, err := s.mealRepo.FindByUserIDAndMealType(ctx, reminder.UserID, *reminder.MealType, &today)
+		if err != nil {
+			return false, err
+		}
+		return len(meals) > 0, nil
+	case entity.ReminderWater:
+		target := waterTargetML(s.calorieService, profile)
+		if target == 0 {
+			return false, nil
+		}
+		total, err := s.waterRepo.GetDailyTotal(ctx, reminder.UserID, today)
+		if err != nil {
+			return false, err
+		}
+		return total >= target, nil
+	case entity.ReminderWeighIn:
+		entry, err := s.weightRepo.FindLatest(ctx, reminder.UserID)
+		if err != nil {
+			if err == repository.ErrUserNotFound {
+				return false, nil
+			}
+			return false, err
+		}
+		return !truncateDay(entry.Date).Before(today), nil
+	}
+	return false, nil
+}
+
+// deliver sends a message on each enabled channel and returns the channels it reached
+func (s *NotificationService) deliver(ctx context.Context, target *notificationTarget, msg *notify.Message) []string {
+	var channels []string
+	for _, notifier := range s.notifiers {
+		switch notifier.Channel() {
+		case notify.ChannelPush:
+			if !target.settings.PushEnabled {
+				continue
+			}
+		case notify.ChannelEmail:
+			if !target.settings.EmailEnabled {
+				continue
+			}
+		}
+
+		if err := notifier.Send(ctx, target.recipient, msg); err != nil {
+			if !errors.Is(err, notify.ErrNoAddress) {
+				log.Printf("Failed to send %s notification to user %s: %v", notifier.Channel(), target.recipient.UserID, err)
+			}
+			continue
+		}
+		channels = append(channels, notifier.Channel())
+	}
+	return channels
+}
+
+// notificationTarget holds what is needed to notify a user
+type notificationTarget struct {
+	profile   *entity.UserProfile // nil before onboarding
+	settings  *entity.NotificationSettings
+	recipient *notify.Recipient
+	location  *time.Location
+}
+
+// loadTarget loads a user's profile, notification settings and addresses
+func (s *NotificationService) loadTarget(ctx context.Context, userID uuid.UUID) (*notificationTarget, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.findSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := s.notificationRepo.FindSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &notificationTarget{
+		profile:  profile,
+		settings: settings,
+		recipient: &notify.Recipient{
+			UserID:        userID,
+			Email:         user.Email,
+			Subscriptions: subs,
+		},
+		location: entity.DefaultLocation(),
+	}
+	if profile != nil {
+		target.location = profile.Location()
+	}
+
+	return target, nil
+}
+
+// language picks the reminder's language, then the profile's, defaulting to Thai
+func (t *notificationTarget) language(preferred *string) string {
+	lang := ""
+	if preferred != nil {
+		lang = *preferred
+	} else if t.profile != nil {
+		lang = t.profile.PreferredLanguage
+	}
+	if lang == "en" {
+		return "en"
+	}
+	return "th"
+}
+
+// inQuietHours reports whether a minute of the day falls within quiet hours,
+// which may span midnight
+func (t *notificationTarget) inQuietHours(minute int) bool {
+	if t.settings.QuietStart == nil || t.settings.QuietEnd == nil {
+		return false
+	}
+	start, err := parseClock(*t.settings.QuietStart)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(*t.settings.QuietEnd)
+	if err != nil {
+		return false
+	}
+
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// findSettings gets a user's notification settings, with defaults when never saved
+func (s *NotificationService) findSettings(ctx context.Context, userID uuid.UUID) (*entity.NotificationSettings, error) {
+	settings, err := s.notificationRepo.FindSettings(ctx, userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return &entity.NotificationSettings{
+				UserID:      userID,
+				PushEnabled: true,
+			}, nil
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+// findProfile gets the user's profile, or nil if onboarding is incomplete
+func (s *NotificationService) findProfile(ctx context.Context, userID uuid.UUID) (*entity.UserProfile, error) {
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+// reminderMessage builds a reminder's notification in a language
+func reminderMessage(reminder *entity.Reminder, lang string) *notify.Message {
+	msg := reminderContent[lang][reminder.Kind]
+	msg.Tag = "reminder-" + string(reminder.Kind)
+	if reminder.Kind == entity.ReminderMeal {
+		name := mealNames[lang][*reminder.MealType]
+		msg.Title = fmt.Sprintf(msg.Title, name)
+		msg.Body = fmt.Sprintf(msg.Body, name)
+		msg.Tag += "-" + string(*reminder.MealType)
+	}
+	return &msg
+}
+
+// normalizeReminder validates a reminder and normalizes its time, weekdays and language
+func normalizeReminder(reminder *entity.Reminder) error {
+	switch reminder.Kind {
+	case entity.ReminderMeal:
+		if reminder.MealType == nil {
+			return fmt.Errorf("%w: meal_type is required for meal reminders", ErrInvalidReminder)
+		}
+		if _, ok := mealNames["en"][*reminder.MealType]; !ok {
+			return fmt.Errorf("%w: unknown meal_type %q", ErrInvalidReminder, *reminder.MealType)
+		}
+	case entity.ReminderWater, entity.ReminderWeighIn:
+		reminder.MealType = nil
+	default:
+		return fmt.Errorf("%w: kind must be meal, water or weigh_in", ErrInvalidReminder)
+	}
+
+	minute, err := parseClock(reminder.Time)
+	if err != nil {
+		return fmt.Errorf("%w: time must be HH:MM", ErrInvalidReminder)
+	}
+	reminder.Time = formatClock(minute)
+
+	seen := map[int]bool{}
+	weekdays := []int{}
+	for _, day := range reminder.Weekdays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("%w: weekdays must be between 0 (Sunday) and 6 (Saturday)", ErrInvalidReminder)
+		}
+		if !seen[day] {
+			seen[day] = true
+			weekdays = append(weekdays, day)
+		}
+	}
+	sort.Ints(weekdays)
+	reminder.Weekdays = weekdays
+
+	if reminder.Language != nil {
+		switch *reminder.Language {
+		case "":
+			reminder.Language = nil
+		case "th", "en":
+		default:
+			return fmt.Errorf("%w: language must be th or en", ErrInvalidReminder)
+		}
+	}
+
+	return nil
+}
+
+// normalizeQuietHours validates quiet hours; empty times turn them off
+func normalizeQuietHours(settings *entity.NotificationSettings) error {
+	if settings.QuietStart != nil && *settings.QuietStart == "" {
+		settings.QuietStart = nil
+	}
+	if settings.QuietEnd != nil && *settings.QuietEnd == "" {
+		settings.QuietEnd = nil
+	}
+	if (settings.QuietStart == nil) != (settings.QuietEnd == nil) {
+		return fmt.Errorf("%w: quiet_start and quiet_end must be set together", ErrInvalidNotificationSettings)
+	}
+	if settings.QuietStart == nil {
+		return nil
+	}
+
+	start, err := parseClock(*settings.QuietStart)
+	if err != nil {
+		return fmt.Errorf("%w: quiet_start must be HH:MM", ErrInvalidNotificationSettings)
+	}
+	end, err := parseClock(*settings.QuietEnd)
+	if err != nil {
+		return fmt.Errorf("%w: quiet_end must be HH:MM", ErrInvalidNotificationSettings)
+	}
+	if start == end {
+		return fmt.Errorf("%w: quiet hours must not start and end at the same time", ErrInvalidNotificationSettings)
+	}
+
+	startClock, endClock := formatClock(start), formatClock(end)
+	settings.QuietStart = &startClock
+	settings.QuietEnd = &endClock
+	return nil
+}
+
+// onWeekday reports whether a reminder runs on a weekday; no weekdays means every day
+func onWeekday(weekdays []int, day time.Weekday) bool {
+	if len(weekdays) == 0 {
+		return true
+	}
+	for _, d := range weekdays {
+		if d == int(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses an HH:MM time into minutes since midnight
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse(clockLayout, strings.TrimSpace(clock))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/cache"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"golang.org/x/sync/singleflight"
+)
+
+// barcodeFetchTimeout bounds an upstream fetch, which runs detached from the
+// request that started it so other waiting requests aren't failed when it
+// is cancelled
+const barcodeFetchTimeout = 15 * time.Second
+
+// OpenFoodFactsCacheService caches Open Food Facts barcode lookups in a
+// bounded in-process LRU backed by the off_cache table, which survives
+// restarts and is shared between instances. Concurrent lookups of a barcode
+// share one upstream fetch, and entries past their TTL are served for a
+// further stale TTL while they are refreshed in the background.
+type OpenFoodFactsCacheService struct {
+	cacheRepo *repository.OFFCacheRepository
+	entries   *cache.MemoryStore
+	ttl       time.Duration
+	staleTTL  time.Duration
+	group     singleflight.Group
+
+	hits          atomic.Uint64
+	staleHits     atomic.Uint64
+	misses        atomic.Uint64
+	fetches       atomic.Uint64
+	sharedFetches atomic.Uint64
+	refreshes     atomic.Uint64
+	fetchErrors   atomic.Uint64
+}
+
+// productEntry is a cached product with the time it was fetched
+type productEntry struct {
+	Food      *entity.FoodItem `json:"food"`
+	FetchedAt time.Time        `json:"fetched_at"`
+}
+
+// NewOpenFoodFactsCacheService creates a new cache service keeping at most
+// maxEntries products in memory
+func NewOpenFoodFactsCacheService(cacheRepo *repository.OFFCacheRepository, maxEntries int, ttl, staleTTL time.Duration) *OpenFoodFactsCacheService {
+	return &OpenFoodFactsCacheService{
+		cacheRepo: cacheRepo,
+		entries:   cache.NewMemoryStore(maxEntries),
+		ttl:       ttl,
+		staleTTL:  staleTTL,
+	}
+}
+
+// Lookup returns the product for a barcode, calling fetch on a miss. Fresh
+// entries are returned as they are; stale ones are returned while fetch
+// refreshes them in the background.
+func (c *OpenFoodFactsCacheService) Lookup(ctx context.Context, barcode string, fetch func(ctx context.Context, barcode string) (*entity.FoodItem, error)) (*entity.FoodItem, error) {
+	if entry, ok := c.get(ctx, barcode); ok {
+		if time.Since(entry.FetchedAt) < c.ttl {
+			c.hits.Add(1)
+		} else {
+			c.staleHits.Add(1)
+			c.refresh(ctx, barcode, fetch)
+		}
+		return entry.Food, nil
+	}
+	c.misses.Add(1)
+
+	leader := false
+	result := c.group.DoChan(barcode, func() (interface{}, error) {
+		leader = true
+		return c.fetch(ctx, barcode, fetch)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if !leader {
+			c.sharedFetches.Add(1)
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*entity.FoodItem), nil
+	}
+}
+
+// refresh fetches a stale barcode in the background, unless a fetch of it
+// is already running
+func (c *OpenFoodFactsCacheService) refresh(ctx context.Context, barcode string, fetch func(ctx context.Context, barcode string) (*entity.FoodItem, error)) {
+	c.group.DoChan(barcode, func() (interface{}, error) {
+		c.refreshes.Add(1)
+		food, err := c.fetch(ctx, barcode, fetch)
+		if err != nil {
+			log.Printf("Failed to refresh Open Food Facts product %s: %v", barcode, err)
+		}
+		return food, err
+	})
+}
+
+// fetch calls upstream and caches the product
+func (c *OpenFoodFactsCacheService) fetch(ctx context.Context, barcode string, fetch func(ctx context.Context, barcode string) (*entity.FoodItem, error)) (*entity.FoodItem, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), barcodeFetchTimeout)
+	defer cancel()
+
+	c.fetches.Add(1)
+	food, err := fetch(ctx, barcode)
+	if err != nil {
+		c.fetchErrors.Add(1)
+		return nil, err
+	}
+
+	entry := productEntry{Food: food, FetchedAt: time.Now()}
+	c.set(ctx, barcode, entry)
+	if err := c.cacheRepo.Upsert(ctx, barcode, food, entry.FetchedAt, entry.FetchedAt.Add(c.ttl+c.staleTTL)); err != nil {
+		log.Printf("Failed to cache Open Food Facts product %s: %v", barcode, err)
+	}
+	return food, nil
+}
+
+// get returns a cached entry from memory, falling back to off_cache. Cache
+// errors are logged and reported as misses.
+func (c *OpenFoodFactsCacheService) get(ctx context.Context, barcode string) (*productEntry, bool) {
+	var entry productEntry
+	if ok, err := cache.GetJSON(ctx, c.entries, barcode, &entry); err == nil && ok {
+		return &entry, true
+	}
+
+	food, cachedAt, err := c.cacheRepo.Find(ctx, barcode, time.Now())
+	if err != nil {
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			log.Printf("Open Food Facts cache lookup failed: %v", err)
+		}
+		return nil, false
+	}
+	entry = productEntry{Food: food, FetchedAt: cachedAt}
+	c.set(ctx, barcode, entry)
+	return &entry, true
+}
+
+// set keeps an entry in memory until it is too stale to serve
+func (c *OpenFoodFactsCacheService) set(ctx context.Context, barcode string, entry productEntry) {
+	ttl := c.ttl + c.staleTTL - time.Since(entry.FetchedAt)
+	if ttl <= 0 {
+		return
+	}
+	if err := cache.SetJSON(ctx, c.entries, barcode, entry, ttl); err != nil {
+		log.Printf("Failed to cache Open Food Facts product %s: %v", barcode, err)
+	}
+}
+
+// Stats returns the cache's counters
+func (c *OpenFoodFactsCacheService) Stats() *entity.BarcodeCacheStats {
+	store := c.entries.Stats()
+	return &entity.BarcodeCacheStats{
+		Entries:       store.Entries,
+		MaxEntries:    store.MaxEntries,
+		Hits:          c.hits.Load(),
+		StaleHits:     c.staleHits.Load(),
+		Misses:        c.misses.Load(),
+		Evictions:     store.Evictions,
+		Fetches:       c.fetches.Load(),
+		SharedFetches: c.sharedFetches.Load(),
+		Refreshes:     c.refreshes.Load(),
+		FetchErrors:   c.fetchErrors.Load(),
+	}
+}
+
+// Cleanup deletes expired entries and returns how many were deleted from off_cache
+func (c *OpenFoodFactsCacheService) Cleanup(ctx context.Context) (int64, error) {
+	c.entries.Cleanup(ctx)
+	return c.cacheRepo.DeleteExpired(ctx, time.Now())
+}
+
+// StartCleanup runs Cleanup on the given interval until the context is cancelled
+func (c *OpenFoodFactsCacheService) StartCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := c.Cleanup(ctx)
+			if err != nil {
+				log.Printf("Open Food Facts cache cleanup failed: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Deleted %d expired Open Food Facts cache entries", deleted)
+			}
+		}
+	}
+}
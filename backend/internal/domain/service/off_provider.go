@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/cache"
+	"github.com/bytetrack/backend/internal/infrastructure/openfoodfacts"
+	"github.com/google/uuid"
+)
+
+// offFoodIDPrefix prefixes the IDs of Open Food Facts products
+const offFoodIDPrefix = "off_"
+
+// OpenFoodFactsProvider serves Open Food Facts products. Barcode lookups go
+// through offCache when it is set, and searches are cached in the
+// "off:search" namespace of the store when one is given.
+type OpenFoodFactsProvider struct {
+	client    *openfoodfacts.Client
+	offCache  *OpenFoodFactsCacheService
+	searches  cache.Store
+	searchTTL time.Duration
+}
+
+// searchPage is a cached page of provider search results
+type searchPage struct {
+	Foods   []entity.FoodItem `json:"foods"`
+	HasMore bool              `json:"has_more"`
+}
+
+// NewOpenFoodFactsProvider creates a new Open Food Facts provider
+func NewOpenFoodFactsProvider(client *openfoodfacts.Client, offCache *OpenFoodFactsCacheService, store cache.Store, searchTTL time.Duration) *OpenFoodFactsProvider {
+	p := &OpenFoodFactsProvider{
+		client:    client,
+		offCache:  offCache,
+		searchTTL: searchTTL,
+	}
+	if store != nil {
+		p.searches = cache.Namespace(store, "off:search")
+	}
+	return p
+}
+
+// Name returns "openfoodfacts"
+func (p *OpenFoodFactsProvider) Name() string {
+	return entity.SearchSourceOpenFoodFacts
+}
+
+// Search searches Open Food Facts, cached by query and page
+func (p *OpenFoodFactsProvider) Search(ctx context.Context, q FoodQuery) ([]entity.FoodItem, bool, error) {
+	key := fmt.Sprintf("page:%s:%d:%d", normalizeQuery(q.Query), q.Page, q.PageSize)
+	var page searchPage
+	if p.searches != nil && getCached(ctx, p.searches, key, &page) {
+		return page.Foods, page.HasMore, nil
+	}
+
+	data, err := p.client.Search(ctx, q.Query, q.Page, q.PageSize)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, product := range data.Products {
+		food := mapOFFProductToFoodItem(product)
+		if food != nil {
+			page.Foods = append(page.Foods, *food)
+		}
+	}
+	page.HasMore = data.Count > q.Page*q.PageSize
+
+	if p.searches != nil {
+		setCached(ctx, p.searches, key, page, p.searchTTL)
+	}
+	return page.Foods, page.HasMore, nil
+}
+
+// LookupBarcode looks up a product by barcode
+func (p *OpenFoodFactsProvider) LookupBarcode(ctx context.Context, barcode string) (*entity.FoodItem, error) {
+	var food *entity.FoodItem
+	var err error
+	if p.offCache != nil {
+		food, err = p.offCache.Lookup(ctx, barcode, p.fetchProduct)
+	} else {
+		food, err = p.fetchProduct(ctx, barcode)
+	}
+	if errors.Is(err, openfoodfacts.ErrNotFound) {
+		return nil, ErrFoodNotFound
+	}
+	return food, err
+}
+
+// Handles reports whether id is an Open Food Facts ID
+func (p *OpenFoodFactsProvider) Handles(id string) bool {
+	return strings.HasPrefix(id, offFoodIDPrefix)
+}
+
+// GetByID gets a product by its ID, which is its barcode
+func (p *OpenFoodFactsProvider) GetByID(ctx context.Context, userID uuid.UUID, id string) (*entity.FoodItem, error) {
+	return p.LookupBarcode(ctx, strings.TrimPrefix(id, offFoodIDPrefix))
+}
+
+// Stats returns the barcode cache counters, or nil when the cache is disabled
+func (p *OpenFoodFactsProvider) Stats() *entity.BarcodeCacheStats {
+	if p.offCache == nil {
+		return nil
+	}
+	return p.offCache.Stats()
+}
+
+// fetchProduct calls the Open Food Facts product API
+func (p *OpenFoodFactsProvider) fetchProduct(ctx context.Context, barcode string) (*entity.FoodItem, error) {
+	product, err := p.client.Product(ctx, barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	food := mapOFFProductToFoodItem(*product)
+	if food == nil {
+		return nil, fmt.Errorf("%w: product has no name", openfoodfacts.ErrNotFound)
+	}
+
+	return food, nil
+}
+
+// offExtraNutrients maps the registry nutrients present in the OFF nutriments.
+// Per-serving values are preferred; per-100g values are scaled by
+// servingFactor (serving size / 100) so every amount shares one basis.
+func offExtraNutrients(n *openfoodfacts.Nutriments, servingFactor float64) entity.Nutrients {
+	var out entity.Nutrients
+	for _, nutrient := range entity.NutrientRegistry {
+		grams, ok := n.Value(nutrient.OFFKey + "_serving")
+		if !ok {
+			grams, ok = n.Value(nutrient.OFFKey + "_100g")
+			grams *= servingFactor
+		}
+		if !ok || grams < 0 {
+			continue
+		}
+		if out == nil {
+			out = make(entity.Nutrients)
+		}
+		out[nutrient.ID] = nutrient.GramsToUnit(grams)
+	}
+	return out.Rounded()
+}
+
+// mapOFFProductToFoodItem maps Open Food Facts product to FoodItem
+func mapOFFProductToFoodItem(product openfoodfacts.Product) *entity.FoodItem {
+	if product.ProductName == "" && product.ProductNameEn == "" {
+		return nil
+	}
+
+	nutriments := product.Nutriments
+	if nutriments == nil {
+		nutriments = &openfoodfacts.Nutriments{}
+	}
+
+	// Parse serving size
+	servingSize, servingUnit := parseServingSize(product.ServingSize)
+
+	// Extra nutrients are reported per serving when the serving is metric
+	servingFactor := 1.0
+	if product.ServingSize != "" && (servingUnit == "g" || servingUnit == "ml") {
+		servingFactor = servingSize / 100
+	}
+
+	// Use serving values if available, otherwise use per 100g values
+	calories := int(nutriments.EnergyKcal100g)
+	if nutriments.EnergyKcalServing > 0 && servingSize > 0 {
+		calories = int(nutriments.EnergyKcalServing)
+	}
+
+	protein := nutriments.Proteins100g
+	if nutriments.ProteinsServing > 0 && servingSize > 0 {
+		protein = nutriments.ProteinsServing
+	}
+
+	carbs := nutriments.Carbohydrates100g
+	if nutriments.CarbohydratesServing > 0 && servingSize > 0 {
+		carbs = nutriments.CarbohydratesServing
+	}
+
+	fat := nutriments.Fat100g
+	if nutriments.FatServing > 0 && servingSize > 0 {
+		fat = nutriments.FatServing
+	}
+
+	name := product.ProductName
+	if name == "" {
+		name = product.ProductNameEn
+	}
+
+	nameEn := product.ProductNameEn
+	if nameEn == "" {
+		nameEn = product.ProductName
+	}
+
+	food := &entity.FoodItem{
+		ID:       offFoodIDPrefix + product.Code,
+		Name:     name,
+		NameEn:   nameEn,
+		Category: getCategory(product.Categories),
+		Nutrition: entity.NutritionInfo{
+			Calories:    calories,
+			Protein:     roundToOne(protein),
+			Carbs:       roundToOne(carbs),
+			Fat:         roundToOne(fat),
+			Fiber:       roundToOnePtr(nutriments.Fiber100g),
+			Sugar:       roundToOnePtr(nutriments.Sugars100g),
+			Sodium:      toIntPtr(nutriments.Sodium100g * 1000), // OFF reports grams
+			ServingSize: servingSize,
+			ServingUnit: servingUnit,
+			Nutrients:   offExtraNutrients(nutriments, servingFactor),
+		},
+		Source:  entity.FoodSourceOpenFoodFacts,
+		Barcode: &product.Code,
+	}
+
+	if product.ImageURL != "" {
+		food.Image = &product.ImageURL
+	}
+
+	if product.Brands != "" {
+		food.Brand = &product.Brands
+	}
+
+	return food
+}
+
+// parseServingSize parses serving size string
+func parseServingSize(servingSize string) (float64, string) {
+	if servingSize == "" {
+		return 100, "g"
+	}
+
+	// Try to parse "100g", "250 ml", etc.
+	var size float64
+	var unit string
+	fmt.Sscanf(servingSize, "%f%s", &size, &unit)
+
+	if size == 0 {
+		size = 100
+	}
+	if unit == "" {
+		unit = "g"
+	}
+
+	return size, unit
+}
+
+// getCategory extracts first category from categories string
+func getCategory(categories string) string {
+	if categories == "" {
+		return "other"
+	}
+	// Take first category
+	for i, c := range categories {
+		if c == ',' {
+			return categories[:i]
+		}
+	}
+	return categories
+}
+
+// roundToOne rounds to 1 decimal place
+func roundToOne(val float64) float64 {
+	return float64(int(val*10+0.5)) / 10
+}
+
+// roundToOnePtr rounds to 1 decimal place and returns pointer
+func roundToOnePtr(val float64) *float64 {
+	rounded := roundToOne(val)
+	return &rounded
+}
+
+// toIntPtr converts int to *int
+func toIntPtr(val float64) *int {
+	if val == 0 {
+		return nil
+	}
+	iv := int(val)
+	return &iv
+}
@@ -2,28 +2,39 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/bytetrack/backend/internal/domain/entity"
 	"github.com/bytetrack/backend/internal/infrastructure/repository"
 	"github.com/google/uuid"
 )
 
+// ErrInvalidTimezone is returned for a timezone that is not a known IANA name
+var ErrInvalidTimezone = errors.New("timezone must be an IANA name such as Asia/Bangkok")
+
 // OnboardingService handles onboarding operations
 type OnboardingService struct {
 	userRepo        *repository.UserRepository
 	calorieService  *CalorieService
+	events          *WebhookService
 }
 
 // NewOnboardingService creates a new onboarding service
-func NewOnboardingService(userRepo *repository.UserRepository, calorieService *CalorieService) *OnboardingService {
+func NewOnboardingService(userRepo *repository.UserRepository, calorieService *CalorieService, events *WebhookService) *OnboardingService {
 	return &OnboardingService{
 		userRepo:       userRepo,
 		calorieService: calorieService,
+		events:         events,
 	}
 }
 
 // CompleteOnboarding completes the onboarding process
 func (s *OnboardingService) CompleteOnboarding(ctx context.Context, userID uuid.UUID, req *entity.OnboardingRequest) (*entity.OnboardingResponse, error) {
+	if !validTimezone(req.Timezone) {
+		return nil, ErrInvalidTimezone
+	}
+
 	// Calculate profile metrics
 	calculations := s.calorieService.CalculateProfile(req)
 
@@ -38,6 +49,8 @@ func (s *OnboardingService) CompleteOnboarding(ctx context.Context, userID uuid.
 		ActivityLevel:      req.ActivityLevel,
 		Goal:               req.Goal,
 		PreferredLanguage:  "th", // Default to Thai
+		Timezone:           req.Timezone,
+		BMRFormula:         entity.BMRMifflinStJeor,
 		BMR:                calculations.BMR,
 		TDEE:               calculations.TDEE,
 		TargetCalories:     calculations.TargetCalories,
@@ -50,7 +63,10 @@ func (s *OnboardingService) CompleteOnboarding(ctx context.Context, userID uuid.
 		CompletedOnboarding: true,
 	}
 
-	if err := s.userRepo.CreateProfile(ctx, profile); err != nil {
+	err := s.events.Record(ctx, userID, entity.EventProfileCreated, func(ctx context.Context) (interface{}, error) {
+		return profile, s.userRepo.CreateProfile(ctx, profile)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -62,6 +78,7 @@ func (s *OnboardingService) CompleteOnboarding(ctx context.Context, userID uuid.
 		ProteinTarget:  calculations.ProteinTarget,
 		CarbsTarget:    calculations.CarbsTarget,
 		FatTarget:      calculations.FatTarget,
+		Version:        profile.Version,
 	}, nil
 }
 
@@ -82,10 +99,21 @@ func (s *OnboardingService) GetOnboardingStatus(ctx context.Context, userID uuid
 	return profile, nil
 }
 
-// UpdateProfile updates a user's profile
-func (s *OnboardingService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *entity.OnboardingRequest) (*entity.OnboardingResponse, error) {
-	// Calculate new profile metrics
-	calculations := s.calorieService.CalculateProfile(req)
+// UpdateProfile updates a user's profile. When expectedVersion is set and no longer
+// matches, the current profile is returned together with ErrVersionMismatch.
+func (s *OnboardingService) UpdateProfile(ctx context.Context, userID uuid.UUID, expectedVersion *int, req *entity.OnboardingRequest) (*entity.OnboardingResponse, *entity.UserProfile, error) {
+	if !validTimezone(req.Timezone) {
+		return nil, nil, ErrInvalidTimezone
+	}
+
+	current, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if expectedVersion != nil && current.Version != *expectedVersion {
+		return nil, current, ErrVersionMismatch
+	}
 
 	// Update user profile
 	profile := &entity.UserProfile{
@@ -97,29 +125,85 @@ func (s *OnboardingService) UpdateProfile(ctx context.Context, userID uuid.UUID,
 		GoalWeight:         &req.GoalWeight,
 		ActivityLevel:      req.ActivityLevel,
 		Goal:               req.Goal,
-		BMR:                calculations.BMR,
-		TDEE:               calculations.TDEE,
-		TargetCalories:     calculations.TargetCalories,
-		ProteinTarget:      calculations.ProteinTarget,
-		CarbsTarget:        calculations.CarbsTarget,
-		FatTarget:          calculations.FatTarget,
-		ProteinCalories:    calculations.ProteinTarget * 4,
-		CarbsCalories:      calculations.CarbsTarget * 4,
-		FatCalories:        calculations.FatTarget * 9,
+		PreferredLanguage:  current.PreferredLanguage,
+		Timezone:           current.Timezone,
+		BMRFormula:         current.BMRFormula,
+		BodyFatPercent:     current.BodyFatPercent,
+		AdaptiveTDEE:       current.AdaptiveTDEE,
+		AdjustedTDEE:       current.AdjustedTDEE,
+		TDEEReviewedAt:     current.TDEEReviewedAt,
 		CompletedOnboarding: true,
 	}
 
-	if err := s.userRepo.UpdateProfile(ctx, profile); err != nil {
-		return nil, err
+	if req.Timezone != "" {
+		profile.Timezone = req.Timezone
+	}
+
+	// Calculate new profile metrics with the chosen BMR formula and any adjusted TDEE
+	s.calorieService.RecalculateProfile(profile)
+
+	err = s.events.Record(ctx, userID, entity.EventProfileUpdated, func(ctx context.Context) (interface{}, error) {
+		return profile, s.userRepo.UpdateProfile(ctx, profile, expectedVersion)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			latest, findErr := s.userRepo.FindProfileByUserID(ctx, userID)
+			if findErr != nil {
+				return nil, nil, findErr
+			}
+			return nil, latest, ErrVersionMismatch
+		}
+		return nil, nil, err
 	}
 
 	return &entity.OnboardingResponse{
 		UserID:         userID,
-		BMR:            calculations.BMR,
-		TDEE:           calculations.TDEE,
-		TargetCalories: calculations.TargetCalories,
-		ProteinTarget:  calculations.ProteinTarget,
-		CarbsTarget:    calculations.CarbsTarget,
-		FatTarget:      calculations.FatTarget,
-	}, nil
+		BMR:            profile.BMR,
+		TDEE:           profile.TDEE,
+		TargetCalories: profile.TargetCalories,
+		ProteinTarget:  profile.ProteinTarget,
+		CarbsTarget:    profile.CarbsTarget,
+		FatTarget:      profile.FatTarget,
+		Version:        profile.Version,
+	}, profile, nil
+}
+
+// validTimezone reports whether tz is empty or a loadable IANA timezone
+func validTimezone(tz string) bool {
+	if tz == "" {
+		return true
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
+
+// updateProfile loads the user's profile, applies change, recalculates its
+// targets and saves it with a profile.updated event, retrying if the profile
+// is modified concurrently
+func updateProfile(ctx context.Context, userRepo *repository.UserRepository, calorieService *CalorieService, events *WebhookService, userID uuid.UUID, change func(*entity.UserProfile) error) (*entity.UserProfile, error) {
+	for attempt := 0; attempt < profileUpdateAttempts; attempt++ {
+		profile, err := userRepo.FindProfileByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		version := profile.Version
+		if err := change(profile); err != nil {
+			return nil, err
+		}
+		calorieService.RecalculateProfile(profile)
+
+		err = events.Record(ctx, userID, entity.EventProfileUpdated, func(ctx context.Context) (interface{}, error) {
+			return profile, userRepo.UpdateProfile(ctx, profile, &version)
+		})
+		if errors.Is(err, repository.ErrVersionConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return profile, nil
+	}
+
+	return nil, repository.ErrVersionConflict
 }
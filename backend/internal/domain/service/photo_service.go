@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/bytetrack/backend/internal/infrastructure/storage"
+	"github.com/bytetrack/backend/internal/pkg/imaging"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPhotoTooLarge        = errors.New("photo exceeds maximum upload size")
+	ErrPhotoTooManyPixels   = errors.New("photo exceeds maximum dimensions")
+	ErrUnsupportedPhotoType = errors.New("unsupported photo type")
+	ErrInvalidPhotoURL      = errors.New("invalid or expired photo URL")
+)
+
+// PhotoService handles meal photo upload, storage and signed retrieval URLs
+type PhotoService struct {
+	mealRepo      *repository.MealRepository
+	store         storage.BlobStore
+	secret        []byte
+	urlTTL        time.Duration
+	maxUploadSize int64
+	imageOptions  imaging.Options
+	stats         *DailyStatsCache
+}
+
+// NewPhotoService creates a new photo service
+func NewPhotoService(mealRepo *repository.MealRepository, store storage.BlobStore, secret string, urlTTL time.Duration, maxUploadSize int64, maxPixels int, stats *DailyStatsCache) *PhotoService {
+	imageOptions := imaging.DefaultOptions
+	imageOptions.MaxPixels = maxPixels
+	return &PhotoService{
+		mealRepo:      mealRepo,
+		store:         store,
+		secret:        []byte(secret),
+		urlTTL:        urlTTL,
+		maxUploadSize: maxUploadSize,
+		imageOptions:  imageOptions,
+		stats:         stats,
+	}
+}
+
+// MaxUploadSize returns the maximum accepted upload size in bytes
+func (s *PhotoService) MaxUploadSize() int64 {
+	return s.maxUploadSize
+}
+
+// UploadMealPhoto processes an uploaded photo and attaches it to a meal,
+// replacing any previous photo
+func (s *PhotoService) UploadMealPhoto(ctx context.Context, mealID, userID uuid.UUID, data []byte) (*entity.Meal, error) {
+	if int64(len(data)) > s.maxUploadSize {
+		return nil, ErrPhotoTooLarge
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, mealID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if meal.UserID != userID {
+		return nil, repository.ErrUserNotFound
+	}
+
+	processed, err := imaging.Process(data, s.imageOptions)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrUnsupportedFormat):
+			return nil, ErrUnsupportedPhotoType
+		case errors.Is(err, imaging.ErrTooLarge):
+			return nil, ErrPhotoTooManyPixels
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedPhotoType, err)
+	}
+
+	// A fresh key per upload keeps previously signed URLs from serving the new photo
+	prefix := fmt.Sprintf("meals/%s/%s/%s", userID, mealID, uuid.New())
+	photoKey := prefix + ".jpg"
+	thumbnailKey := prefix + "_thumb.jpg"
+
+	if err := s.store.Put(ctx, photoKey, "image/jpeg", processed.Image); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, thumbnailKey, "image/jpeg", processed.Thumbnail); err != nil {
+		s.DeleteBlobs(ctx, []string{photoKey})
+		return nil, err
+	}
+
+	oldKeys := photoKeys(meal)
+	meal.PhotoKey = &photoKey
+	meal.ThumbnailKey = &thumbnailKey
+
+	if err := s.mealRepo.UpdatePhoto(ctx, meal); err != nil {
+		s.DeleteBlobs(ctx, []string{photoKey, thumbnailKey})
+		return nil, err
+	}
+
+	s.stats.Invalidate(ctx, userID, meal.Date)
+	s.DeleteBlobs(ctx, oldKeys)
+	s.SignMeal(meal)
+
+	return meal, nil
+}
+
+// DeleteMealPhoto removes the photo from a meal
+func (s *PhotoService) DeleteMealPhoto(ctx context.Context, mealID, userID uuid.UUID) (*entity.Meal, error) {
+	meal, err := s.mealRepo.FindByID(ctx, mealID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if meal.UserID != userID {
+		return nil, repository.ErrUserNotFound
+	}
+
+	oldKeys := photoKeys(meal)
+	meal.PhotoKey = nil
+	meal.ThumbnailKey = nil
+
+	if err := s.mealRepo.UpdatePhoto(ctx, meal); err != nil {
+		return nil, err
+	}
+
+	s.stats.Invalidate(ctx, userID, meal.Date)
+	s.DeleteBlobs(ctx, oldKeys)
+
+	return meal, nil
+}
+
+// Open opens a stored photo after verifying its signed URL parameters
+func (s *PhotoService) Open(ctx context.Context, key, expires, signature string) (io.ReadCloser, string, error) {
+	if err := s.verify(key, expires, signature); err != nil {
+		return nil, "", err
+	}
+	return s.store.Get(ctx, key)
+}
+
+// DeleteBlobs deletes stored photos, logging failures rather than returning them
+// since orphaned blobs are harmless
+func (s *PhotoService) DeleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete photo %s: %v", key, err)
+		}
+	}
+}
+
+// SignMeal fills in signed photo URLs for a meal
+func (s *PhotoService) SignMeal(meal *entity.Meal) {
+	if meal == nil {
+		return
+	}
+	if meal.PhotoKey != nil {
+		u := s.SignedURL(*meal.PhotoKey)
+		meal.PhotoURL = &u
+	}
+	if meal.ThumbnailKey != nil {
+		u := s.SignedURL(*meal.ThumbnailKey)
+		meal.ThumbnailURL = &u
+	}
+}
+
+// SignMeals fills in signed photo URLs for a list of meals
+func (s *PhotoService) SignMeals(meals []*entity.Meal) {
+	for _, meal := range meals {
+		s.SignMeal(meal)
+	}
+}
+
+// SignedURL returns a relative URL for a photo that expires after the configured TTL
+func (s *PhotoService) SignedURL(key string) string {
+	expires := strconv.FormatInt(time.Now().Add(s.urlTTL).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("sig", s.signature(key, expires))
+	return "/api/v1/photos/" + key + "?" + q.Encode()
+}
+
+// signature computes the HMAC-SHA256 signature of a key and expiry
+func (s *PhotoService) signature(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks a signed URL's expiry and signature
+func (s *PhotoService) verify(key, expires, signature string) error {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ErrInvalidPhotoURL
+	}
+
+	expected := s.signature(key, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidPhotoURL
+	}
+
+	return nil
+}
+
+// photoKeys returns the blob keys currently attached to a meal
+func photoKeys(meal *entity.Meal) []string {
+	var keys []string
+	if meal.PhotoKey != nil {
+		keys = append(keys, *meal.PhotoKey)
+	}
+	if meal.ThumbnailKey != nil {
+		keys = append(keys, *meal.ThumbnailKey)
+	}
+	return keys
+}
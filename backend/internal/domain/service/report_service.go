@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/notify"
+	"github.com/bytetrack/backend/internal/infrastructure/report"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidReport is returned when a report period, format or schedule is malformed
+var ErrInvalidReport = errors.New("invalid report request")
+
+// reportTopFoods is how many top calorie contributors a report lists
+const reportTopFoods = 5
+
+// ReportService builds, renders and emails nutrition reports
+type ReportService struct {
+	mealService    *MealService
+	mealRepo       *repository.MealRepository
+	weightService  *WeightService
+	userRepo       *repository.UserRepository
+	reportRepo     *repository.ReportRepository
+	calorieService *CalorieService
+	pdf            *report.PDFRenderer
+	mailer         notify.Notifier // nil when neither email nor log delivery is enabled
+	sendHour       int
+}
+
+// NewReportService creates a new report service. Scheduled reports go to the
+// email notifier, or the log notifier for local testing.
+func NewReportService(mealService *MealService, mealRepo *repository.MealRepository, weightService *WeightService, userRepo *repository.UserRepository, reportRepo *repository.ReportRepository, calorieService *CalorieService, pdf *report.PDFRenderer, notifiers []notify.Notifier, sendHour int) *ReportService {
+	s := &ReportService{
+		mealService:    mealService,
+		mealRepo:       mealRepo,
+		weightService:  weightService,
+		userRepo:       userRepo,
+		reportRepo:     reportRepo,
+		calorieService: calorieService,
+		pdf:            pdf,
+		sendHour:       sendHour,
+	}
+	for _, notifier := range notifiers {
+		switch notifier.Channel() {
+		case notify.ChannelEmail:
+			s.mailer = notifier
+		case notify.ChannelLog:
+			if s.mailer == nil {
+				s.mailer = notifier
+			}
+		}
+	}
+	return s
+}
+
+// Generate builds a report for the week (Monday to Sunday) or month
+// containing date, in the user's timezone. Periods still in progress end today.
+func (s *ReportService) Generate(ctx context.Context, userID uuid.UUID, period entity.ReportPeriod, date *time.Time, lang string) (*entity.Report, error) {
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+	return s.generate(ctx, userID, profile, period, date, lang, time.Now())
+}
+
+func (s *ReportService) generate(ctx context.Context, userID uuid.UUID, profile *entity.UserProfile, period entity.ReportPeriod, date *time.Time, lang string, now time.Time) (*entity.Report, error) {
+	loc := entity.DefaultLocation()
+	if profile != nil {
+		loc = profile.Location()
+		if lang == "" {
+			lang = profile.PreferredLanguage
+		}
+	}
+	if lang != "en" {
+		lang = "th"
+	}
+
+	today := truncateDay(now.In(loc))
+	anchor := today
+	if date != nil {
+		anchor = truncateDay(*date)
+	}
+	from, to, err := reportRange(period, anchor)
+	if err != nil {
+		return nil, err
+	}
+	if from.After(today) {
+		return nil, fmt.Errorf("%w: the period has not started", ErrInvalidReport)
+	}
+	if to.After(today) {
+		to = today
+	}
+
+	stats, err := s.mealService.GetRangeStats(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &entity.Report{
+		Period:      period,
+		Language:    lang,
+		From:        from,
+		To:          to,
+		GeneratedAt: now.In(loc),
+		Days:        int(to.Sub(from).Hours()/24) + 1,
+		DaysLogged:  stats.DaysLogged,
+		MacroSplit:  macroSplit(stats.Totals.Protein, stats.Totals.Carbs, stats.Totals.Fat),
+		TopFoods:    []*entity.ReportFood{},
+	}
+
+	var targets *entity.DailyTargets
+	if profile != nil && profile.TargetCalories > 0 {
+		t := s.calorieService.CalculateDailyTargets(profile)
+		targets = &t
+		split := macroSplit(float64(t.Protein), float64(t.Carbs), float64(t.Fat))
+		r.TargetSplit = &split
+	}
+
+	r.Nutrients = reportNutrients(stats, targets)
+
+	byDate := make(map[time.Time]*entity.DayTotals, len(stats.Days))
+	for _, day := range stats.Days {
+		byDate[truncateDay(day.Date)] = day
+		if targets != nil && s.calorieService.CalculateDailyProgress(day.Totals, *targets).Calories.Status == entity.TargetStatusOnTrack {
+			r.DaysOnTarget++
+		}
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		entry := entity.ReportDay{Date: day}
+		if totals, ok := byDate[day]; ok {
+			entry.Calories = totals.Totals.Calories
+			entry.MealCount = totals.MealCount
+		}
+		if targets != nil {
+			entry.Target = targets.Calories
+		}
+		r.Daily = append(r.Daily, entry)
+	}
+
+	foods, err := s.mealRepo.FindTopFoods(ctx, userID, from, to, reportTopFoods)
+	if err != nil {
+		return nil, err
+	}
+	for _, food := range foods {
+		if stats.Totals.Calories > 0 {
+			food.Percent = roundToOne(float64(food.Calories) / float64(stats.Totals.Calories) * 100)
+		}
+		r.TopFoods = append(r.TopFoods, food)
+	}
+
+	entries, err := s.weightService.GetEntries(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	r.Weight = reportWeight(entries)
+
+	return r, nil
+}
+
+// Render renders a report as HTML or PDF, returning the content type
+func (s *ReportService) Render(r *entity.Report, format entity.ReportFormat) ([]byte, string, error) {
+	switch format {
+	case entity.ReportHTML:
+		body, err := report.HTML(r)
+		return body, "text/html; charset=utf-8", err
+	case entity.ReportPDF:
+		return s.pdf.Render(r), "application/pdf", nil
+	}
+	return nil, "", fmt.Errorf("%w: unknown format %q", ErrInvalidReport, format)
+}
+
+// Filename returns a download filename for a rendered report
+func (s *ReportService) Filename(r *entity.Report, format entity.ReportFormat) string {
+	return fmt.Sprintf("bytetrack-%s-%s.%s", r.Period, r.From.Format("2006-01-02"), format)
+}
+
+// GetSchedule gets a user's report schedule, defaulting to no emailed reports
+func (s *ReportService) GetSchedule(ctx context.Context, userID uuid.UUID) (*entity.ReportSchedule, error) {
+	schedule, err := s.reportRepo.FindSchedule(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return &entity.ReportSchedule{UserID: userID, AttachPDF: true}, nil
+		}
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// UpdateSchedule updates which reports are emailed and in what language
+func (s *ReportService) UpdateSchedule(ctx context.Context, userID uuid.UUID, req *entity.UpdateReportScheduleRequest) (*entity.ReportSchedule, error) {
+	schedule, err := s.GetSchedule(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Weekly != nil {
+		schedule.Weekly = *req.Weekly
+	}
+	if req.Monthly != nil {
+		schedule.Monthly = *req.Monthly
+	}
+	if req.AttachPDF != nil {
+		schedule.AttachPDF = *req.AttachPDF
+	}
+	if req.Language != nil {
+		switch *req.Language {
+		case "":
+			schedule.Language = nil
+		case "th", "en":
+			schedule.Language = req.Language
+		default:
+			return nil, fmt.Errorf("%w: language must be th or en", ErrInvalidReport)
+		}
+	}
+	if (schedule.Weekly || schedule.Monthly) && s.mailer == nil {
+		return nil, fmt.Errorf("%w: email delivery is not configured", ErrInvalidReport)
+	}
+
+	if err := s.reportRepo.UpsertSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// RunDue emails the reports due at now. Weekly reports go out on Monday and
+// monthly reports on the 1st, from the send hour in each user's timezone,
+// covering the previous week or month.
+func (s *ReportService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	if s.mailer == nil {
+		return 0, nil
+	}
+
+	schedules, err := s.reportRepo.FindEnabledSchedules(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, schedule := range schedules {
+		profile, err := s.userRepo.FindProfileByUserID(ctx, schedule.UserID)
+		if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+			log.Printf("Failed to load profile for user %s: %v", schedule.UserID, err)
+			continue
+		}
+		loc := entity.DefaultLocation()
+		if profile != nil {
+			loc = profile.Location()
+		}
+
+		local := now.In(loc)
+		if local.Hour() < s.sendHour {
+			continue
+		}
+		today := truncateDay(local)
+
+		due := map[entity.ReportPeriod]bool{
+			entity.ReportWeekly:  schedule.Weekly && local.Weekday() == time.Monday && !sameDay(schedule.LastWeeklyOn, today),
+			entity.ReportMonthly: schedule.Monthly && local.Day() == 1 && !sameDay(schedule.LastMonthlyOn, today),
+		}
+		for _, period := range []entity.ReportPeriod{entity.ReportWeekly, entity.ReportMonthly} {
+			if !due[period] {
+				continue
+			}
+
+			claimed, err := s.reportRepo.ClaimSchedule(ctx, schedule.UserID, period, today)
+			if err != nil {
+				log.Printf("Failed to claim %s report for user %s: %v", period, schedule.UserID, err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+
+			// The day before today falls in the period just finished
+			previous := today.AddDate(0, 0, -1)
+			if err := s.send(ctx, schedule, profile, period, previous, now); err != nil {
+				log.Printf("Failed to send %s report to user %s: %v", period, schedule.UserID, err)
+				continue
+			}
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// StartScheduler runs RunDue on the given interval until the context is cancelled
+func (s *ReportService) StartScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sent, err := s.RunDue(ctx, now)
+			if err != nil {
+				log.Printf("Report scheduler failed: %v", err)
+				continue
+			}
+			if sent > 0 {
+				log.Printf("Sent %d reports", sent)
+			}
+		}
+	}
+}
+
+// send emails a report as an HTML body with an optional PDF attachment
+func (s *ReportService) send(ctx context.Context, schedule *entity.ReportSchedule, profile *entity.UserProfile, period entity.ReportPeriod, date time.Time, now time.Time) error {
+	user, err := s.userRepo.FindByID(ctx, schedule.UserID)
+	if err != nil {
+		return err
+	}
+
+	lang := ""
+	if schedule.Language != nil {
+		lang = *schedule.Language
+	}
+	r, err := s.generate(ctx, schedule.UserID, profile, period, &date, lang, now)
+	if err != nil {
+		return err
+	}
+
+	html, err := report.HTML(r)
+	if err != nil {
+		return err
+	}
+	msg := &notify.Message{
+		Title: report.Title(r),
+		Body:  report.Summary(r),
+		URL:   "/reports",
+		HTML:  string(html),
+	}
+	if schedule.AttachPDF {
+		msg.Attachments = []notify.Attachment{{
+			Filename:    s.Filename(r, entity.ReportPDF),
+			ContentType: "application/pdf",
+			Data:        s.pdf.Render(r),
+		}}
+	}
+
+	return s.mailer.Send(ctx, &notify.Recipient{UserID: user.ID, Email: user.Email}, msg)
+}
+
+// reportRange returns the first and last day of the period containing date
+func reportRange(period entity.ReportPeriod, date time.Time) (time.Time, time.Time, error) {
+	switch period {
+	case entity.ReportWeekly:
+		// Weeks start on Monday
+		offset := (int(date.Weekday()) + 6) % 7
+		from := date.AddDate(0, 0, -offset)
+		return from, from.AddDate(0, 0, 6), nil
+	case entity.ReportMonthly:
+		from := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(0, 1, -1), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("%w: period must be weekly or monthly", ErrInvalidReport)
+}
+
+// reportNutrients compares nutrient totals against targets scaled to the
+// days logged
+func reportNutrients(stats *entity.RangeStats, targets *entity.DailyTargets) []entity.ReportNutrient {
+	t, avg := stats.Totals, stats.Averages
+	nutrients := []entity.ReportNutrient{
+		{Nutrient: "calories", Unit: "kcal", Total: float64(t.Calories), Average: float64(avg.Calories)},
+		{Nutrient: "protein", Unit: "g", Total: round2(t.Protein), Average: avg.Protein},
+		{Nutrient: "carbs", Unit: "g", Total: round2(t.Carbs), Average: avg.Carbs},
+		{Nutrient: "fat", Unit: "g", Total: round2(t.Fat), Average: avg.Fat},
+		{Nutrient: "fiber", Unit: "g", Total: round2(t.Fiber), Average: avg.Fiber},
+		{Nutrient: "sugar", Unit: "g", Total: round2(t.Sugar), Average: avg.Sugar, IsLimit: true},
+		{Nutrient: "sodium", Unit: "mg", Total: float64(t.Sodium), Average: float64(avg.Sodium), IsLimit: true},
+	}
+	if targets == nil {
+		return nutrients
+	}
+
+	daily := []float64{
+		float64(targets.Calories), float64(targets.Protein), float64(targets.Carbs), float64(targets.Fat),
+		targets.Fiber, targets.Sugar, float64(targets.Sodium),
+	}
+	for i := range nutrients {
+		if daily[i] <= 0 {
+			continue
+		}
+		dailyTarget := daily[i]
+		target := round2(dailyTarget * float64(stats.DaysLogged))
+		nutrients[i].DailyTarget = &dailyTarget
+		nutrients[i].Target = &target
+		if target > 0 {
+			percent := roundToOne(nutrients[i].Total / target * 100)
+			nutrients[i].Percent = &percent
+		}
+	}
+	return nutrients
+}
+
+// macroSplit returns the share of calories from each macronutrient, at 4
+// calories per gram of protein and carbs and 9 per gram of fat
+func macroSplit(protein, carbs, fat float64) entity.MacroSplit {
+	total := protein*4 + carbs*4 + fat*9
+	if total == 0 {
+		return entity.MacroSplit{}
+	}
+	return entity.MacroSplit{
+		ProteinPercent: roundToOne(protein * 4 / total * 100),
+		CarbsPercent:   roundToOne(carbs * 4 / total * 100),
+		FatPercent:     roundToOne(fat * 9 / total * 100),
+	}
+}
+
+// reportWeight summarizes the period's weigh-ins and trend
+func reportWeight(entries []*entity.WeightEntry) *entity.ReportWeight {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	w := &entity.ReportWeight{Entries: len(entries)}
+	for _, entry := range entries {
+		trend := entry.Weight
+		if entry.TrendWeight != nil {
+			trend = *entry.TrendWeight
+		}
+		w.Points = append(w.Points, entity.ReportWeightPoint{Date: entry.Date, Weight: entry.Weight, Trend: trend})
+	}
+
+	first, last := w.Points[0], w.Points[len(w.Points)-1]
+	w.StartWeight, w.EndWeight = first.Weight, last.Weight
+	w.StartTrend, w.EndTrend = round2(first.Trend), round2(last.Trend)
+	w.TrendChange = math.Round((last.Trend-first.Trend)*10) / 10
+	return w
+}
+
+// sameDay reports whether an optional date falls on day
+func sameDay(date *time.Time, day time.Time) bool {
+	return date != nil && truncateDay(*date).Equal(day)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/cache"
+	"github.com/google/uuid"
+)
+
+// DailyStatsCache caches the meals and totals behind a user's daily stats.
+// Targets, hydration and exercise are not cached, so only meal changes need
+// to invalidate it.
+type DailyStatsCache struct {
+	store cache.Store
+	ttl   time.Duration
+}
+
+// dailyMeals is a cached day of meals with their totals
+type dailyMeals struct {
+	Meals  []cachedMeal       `json:"meals"`
+	Totals entity.DailyMacros `json:"totals"`
+}
+
+// cachedMeal keeps the photo keys that a meal's JSON omits, so cached meals
+// can still be signed
+type cachedMeal struct {
+	*entity.Meal
+	PhotoKey     *string `json:"photo_key,omitempty"`
+	ThumbnailKey *string `json:"thumbnail_key,omitempty"`
+}
+
+// NewDailyStatsCache creates a daily stats cache in the "stats" namespace of store
+func NewDailyStatsCache(store cache.Store, ttl time.Duration) *DailyStatsCache {
+	return &DailyStatsCache{
+		store: cache.Namespace(store, "stats"),
+		ttl:   ttl,
+	}
+}
+
+// get returns a cached day of meals and totals. Cache errors are logged and
+// reported as misses.
+func (c *DailyStatsCache) get(ctx context.Context, userID uuid.UUID, date time.Time) ([]*entity.Meal, *entity.DailyMacros, bool) {
+	var day dailyMeals
+	ok, err := cache.GetJSON(ctx, c.store, dailyStatsKey(userID, date), &day)
+	if err != nil {
+		log.Printf("Daily stats cache lookup failed: %v", err)
+		return nil, nil, false
+	}
+	if !ok {
+		return nil, nil, false
+	}
+
+	meals := make([]*entity.Meal, len(day.Meals))
+	for i, m := range day.Meals {
+		m.Meal.PhotoKey, m.Meal.ThumbnailKey = m.PhotoKey, m.ThumbnailKey
+		meals[i] = m.Meal
+	}
+	return meals, &day.Totals, true
+}
+
+// set caches a day of meals and totals before they are signed
+func (c *DailyStatsCache) set(ctx context.Context, userID uuid.UUID, date time.Time, meals []*entity.Meal, totals *entity.DailyMacros) {
+	day := dailyMeals{Meals: make([]cachedMeal, len(meals)), Totals: *totals}
+	for i, meal := range meals {
+		day.Meals[i] = cachedMeal{Meal: meal, PhotoKey: meal.PhotoKey, ThumbnailKey: meal.ThumbnailKey}
+	}
+	if err := cache.SetJSON(ctx, c.store, dailyStatsKey(userID, date), day, c.ttl); err != nil {
+		log.Printf("Failed to cache daily stats: %v", err)
+	}
+}
+
+// Invalidate drops the cached days a meal change touched
+func (c *DailyStatsCache) Invalidate(ctx context.Context, userID uuid.UUID, dates ...time.Time) {
+	keys := make([]string, len(dates))
+	for i, date := range dates {
+		keys[i] = dailyStatsKey(userID, date)
+	}
+	if err := c.store.Delete(ctx, keys...); err != nil {
+		log.Printf("Failed to invalidate daily stats: %v", err)
+	}
+}
+
+func dailyStatsKey(userID uuid.UUID, date time.Time) string {
+	return userID.String() + ":" + date.Format("2006-01-02")
+}
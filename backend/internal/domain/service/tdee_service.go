@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidTDEEWeeks is returned when the estimation window is out of range
+var ErrInvalidTDEEWeeks = fmt.Errorf("weeks must be between %d and %d", minTDEEWeeks, maxTDEEWeeks)
+
+const (
+	minTDEEWeeks = 2
+	maxTDEEWeeks = 12
+	// energyPerKg is the approximate energy content of a kilogram of body weight
+	energyPerKg = 7700.0
+	// minDayCalories is the intake below which a logged day is treated as partially logged
+	minDayCalories = 800
+	// minLoggedDays and minWeighInSpanDays are required before an estimate is made
+	minLoggedDays      = 7
+	minWeighInSpanDays = 7
+	// weighInsPerWeek is the weigh-in frequency that counts as full coverage
+	weighInsPerWeek = 3
+	// fullConfidenceWeeks is the window length below which confidence is scaled down,
+	// as shorter windows are dominated by water weight swings
+	fullConfidenceWeeks = 4
+	// maxTDEEDeviation caps the suggested TDEE at ±25% of the formula TDEE
+	maxTDEEDeviation = 0.25
+	// tdeeReviewInterval is how often opted-in users have their targets adjusted
+	tdeeReviewInterval = 7 * 24 * time.Hour
+)
+
+// TDEEService estimates maintenance calories from logged intake and weight
+// change, and adjusts targets for users who opt in
+type TDEEService struct {
+	mealRepo       *repository.MealRepository
+	userRepo       *repository.UserRepository
+	weightService  *WeightService
+	calorieService *CalorieService
+	events         *WebhookService
+	defaultWeeks   int
+}
+
+// NewTDEEService creates a new TDEE service
+func NewTDEEService(mealRepo *repository.MealRepository, userRepo *repository.UserRepository, weightService *WeightService, calorieService *CalorieService, events *WebhookService, defaultWeeks int) *TDEEService {
+	if defaultWeeks < minTDEEWeeks || defaultWeeks > maxTDEEWeeks {
+		defaultWeeks = fullConfidenceWeeks
+	}
+	return &TDEEService{
+		mealRepo:       mealRepo,
+		userRepo:       userRepo,
+		weightService:  weightService,
+		calorieService: calorieService,
+		events:         events,
+		defaultWeeks:   defaultWeeks,
+	}
+}
+
+// Estimate estimates the user's TDEE over the last `weeks` complete weeks;
+// zero uses the configured default
+func (s *TDEEService) Estimate(ctx context.Context, userID uuid.UUID, weeks int) (*entity.TDEEEstimate, error) {
+	if weeks == 0 {
+		weeks = s.defaultWeeks
+	}
+	if weeks < minTDEEWeeks || weeks > maxTDEEWeeks {
+		return nil, ErrInvalidTDEEWeeks
+	}
+
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.estimate(ctx, profile, weeks)
+}
+
+// SetAdaptive opts the user in or out of weekly target adjustment. Opting in
+// reviews the estimate straight away; opting out restores the formula targets.
+func (s *TDEEService) SetAdaptive(ctx context.Context, userID uuid.UUID, enabled bool) (*entity.UserProfile, error) {
+	return updateProfile(ctx, s.userRepo, s.calorieService, s.events, userID, func(profile *entity.UserProfile) error {
+		profile.AdaptiveTDEE = enabled
+		if !enabled {
+			profile.AdjustedTDEE = nil
+			profile.TDEEReviewedAt = nil
+			return nil
+		}
+		return s.review(ctx, profile)
+	})
+}
+
+// Adjust reviews an opted-in user's estimate and, when confidence is at least
+// medium, applies it to their targets. The review time is recorded either way.
+func (s *TDEEService) Adjust(ctx context.Context, userID uuid.UUID) (*entity.UserProfile, error) {
+	return updateProfile(ctx, s.userRepo, s.calorieService, s.events, userID, func(profile *entity.UserProfile) error {
+		if !profile.AdaptiveTDEE {
+			return nil
+		}
+		return s.review(ctx, profile)
+	})
+}
+
+// AdjustDue adjusts every opted-in user not reviewed within the last week
+// and returns how many were reviewed
+func (s *TDEEService) AdjustDue(ctx context.Context) (int, error) {
+	userIDs, err := s.userRepo.FindAdaptiveTDEEUsersDue(ctx, time.Now().Add(-tdeeReviewInterval))
+	if err != nil {
+		return 0, err
+	}
+
+	reviewed := 0
+	for _, userID := range userIDs {
+		if _, err := s.Adjust(ctx, userID); err != nil {
+			log.Printf("Adaptive TDEE adjustment failed for user %s: %v", userID, err)
+			continue
+		}
+		reviewed++
+	}
+
+	return reviewed, nil
+}
+
+// StartAdjuster runs AdjustDue on the given interval until the context is cancelled
+func (s *TDEEService) StartAdjuster(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reviewed, err := s.AdjustDue(ctx)
+			if err != nil {
+				log.Printf("Adaptive TDEE adjustment failed: %v", err)
+				continue
+			}
+			if reviewed > 0 {
+				log.Printf("Reviewed adaptive TDEE for %d users", reviewed)
+			}
+		}
+	}
+}
+
+// review estimates the profile's TDEE and stores it as the adjusted TDEE
+// when confident enough; otherwise the previous adjustment is kept
+func (s *TDEEService) review(ctx context.Context, profile *entity.UserProfile) error {
+	estimate, err := s.estimate(ctx, profile, s.defaultWeeks)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	profile.TDEEReviewedAt = &now
+
+	switch estimate.ConfidenceLevel {
+	case entity.TDEEConfidenceMedium, entity.TDEEConfidenceHigh:
+		profile.AdjustedTDEE = estimate.SuggestedTDEE
+	}
+
+	return nil
+}
+
+// estimate computes the TDEE estimate for a profile. Today is excluded as it
+// is usually still being logged.
+//
+// Energy balance: TDEE = average intake - daily weight change × 7700 kcal/kg,
+// with the weight change taken from the smoothed trend so single weigh-ins
+// and water swings carry less weight.
+func (s *TDEEService) estimate(ctx context.Context, profile *entity.UserProfile, weeks int) (*entity.TDEEEstimate, error) {
+	to := truncateDay(time.Now()).AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, -(weeks*7 - 1))
+
+	days, err := s.mealRepo.GetRangeTotals(ctx, profile.UserID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.weightService.entriesWithTrend(ctx, profile.UserID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	bmr := s.calorieService.ProfileBMR(profile)
+	estimate := &entity.TDEEEstimate{
+		From:            from,
+		To:              to,
+		Weeks:           weeks,
+		DaysInWindow:    weeks * 7,
+		WeighIns:        len(entries),
+		FormulaTDEE:     s.calorieService.CalculateTDEE(bmr, profile.ActivityLevel),
+		ConfidenceLevel: entity.TDEEConfidenceInsufficient,
+		AdaptiveEnabled: profile.AdaptiveTDEE,
+		AdjustedTDEE:    profile.AdjustedTDEE,
+		ReviewedAt:      profile.TDEEReviewedAt,
+	}
+
+	// Days with very little logged are most likely incomplete rather than fasts
+	var intake int
+	for _, day := range days {
+		if day.Totals.Calories < minDayCalories {
+			estimate.DaysIncomplete++
+			continue
+		}
+		intake += day.Totals.Calories
+		estimate.DaysLogged++
+	}
+
+	if estimate.DaysLogged > 0 {
+		average := int(math.Round(float64(intake) / float64(estimate.DaysLogged)))
+		estimate.AverageIntake = &average
+	}
+
+	var spanDays float64
+	if len(entries) >= 2 {
+		first, last := entries[0], entries[len(entries)-1]
+		spanDays = last.Date.Sub(first.Date).Hours() / 24
+		change := round2(*last.TrendWeight - *first.TrendWeight)
+		rate := round2(weeklyRate(entries))
+		estimate.WeightChange = &change
+		estimate.WeeklyRate = &rate
+	}
+
+	switch {
+	case estimate.DaysLogged < minLoggedDays:
+		estimate.Reason = fmt.Sprintf("at least %d fully logged days are needed", minLoggedDays)
+		return estimate, nil
+	case spanDays < minWeighInSpanDays:
+		estimate.Reason = fmt.Sprintf("at least two weigh-ins %d days apart are needed", minWeighInSpanDays)
+		return estimate, nil
+	}
+
+	dailyBalance := weeklyRate(entries) / 7 * energyPerKg
+	estimated := int(math.Round(float64(*estimate.AverageIntake) - dailyBalance))
+	estimate.EstimatedTDEE = &estimated
+
+	// Confidence combines intake coverage with how well weigh-ins cover the window
+	window := float64(estimate.DaysInWindow)
+	intakeCoverage := float64(estimate.DaysLogged) / window
+	weighInCoverage := math.Min(
+		float64(len(entries))/float64(weeks*weighInsPerWeek),
+		(spanDays+1)/window,
+	)
+	lengthFactor := math.Min(1, float64(weeks)/fullConfidenceWeeks)
+	confidence := (0.6*intakeCoverage + 0.4*math.Min(1, weighInCoverage)) * lengthFactor
+	estimate.Confidence = round2(confidence)
+
+	switch {
+	case confidence >= 0.75:
+		estimate.ConfidenceLevel = entity.TDEEConfidenceHigh
+	case confidence >= 0.5:
+		estimate.ConfidenceLevel = entity.TDEEConfidenceMedium
+	default:
+		estimate.ConfidenceLevel = entity.TDEEConfidenceLow
+	}
+
+	// Blend towards the formula TDEE when confidence is low and keep the
+	// result within a plausible range of it
+	formula := float64(estimate.FormulaTDEE)
+	suggested := formula + (float64(estimated)-formula)*confidence
+	suggested = math.Max(formula*(1-maxTDEEDeviation), math.Min(formula*(1+maxTDEEDeviation), suggested))
+	suggestedTDEE := int(math.Round(suggested))
+	difference := suggestedTDEE - estimate.FormulaTDEE
+	estimate.SuggestedTDEE = &suggestedTDEE
+	estimate.Difference = &difference
+
+	return estimate, nil
+}
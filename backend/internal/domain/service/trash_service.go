@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+// TrashService handles soft-deleted meals and custom foods
+type TrashService struct {
+	mealRepo  *repository.MealRepository
+	photos    *PhotoService
+	retention time.Duration
+
+	achievements *AchievementService
+	events       *WebhookService
+	stats        *DailyStatsCache
+}
+
+// NewTrashService creates a new trash service
+func NewTrashService(mealRepo *repository.MealRepository, photos *PhotoService, retention time.Duration, achievements *AchievementService, events *WebhookService, stats *DailyStatsCache) *TrashService {
+	return &TrashService{
+		mealRepo:     mealRepo,
+		photos:       photos,
+		retention:    retention,
+		achievements: achievements,
+		events:       events,
+		stats:        stats,
+	}
+}
+
+// GetTrash gets soft-deleted meals and custom foods for a user
+func (s *TrashService) GetTrash(ctx context.Context, userID uuid.UUID) (*entity.Trash, error) {
+	meals, err := s.mealRepo.FindDeleted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	customFoods, err := s.mealRepo.FindDeletedCustomFoods(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if meals == nil {
+		meals = []*entity.Meal{}
+	}
+	s.photos.SignMeals(meals)
+	if customFoods == nil {
+		customFoods = []*entity.CustomFood{}
+	}
+
+	return &entity.Trash{
+		Meals:       meals,
+		CustomFoods: customFoods,
+	}, nil
+}
+
+// Restore restores a soft-deleted meal or custom food.
+// Meals are tried first since IDs are UUIDs and cannot collide across tables.
+func (s *TrashService) Restore(ctx context.Context, id, userID uuid.UUID) (interface{}, error) {
+	var meal *entity.Meal
+	err := s.events.Record(ctx, userID, entity.EventMealRestored, func(ctx context.Context) (interface{}, error) {
+		var err error
+		meal, err = s.mealRepo.Restore(ctx, id, userID)
+		return meal, err
+	})
+	if err == nil {
+		s.stats.Invalidate(ctx, userID, meal.Date)
+		s.achievements.MealsChanged(ctx, userID, false, meal.Date)
+		s.photos.SignMeal(meal)
+		return meal, nil
+	}
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+
+	return s.mealRepo.RestoreCustomFood(ctx, id, userID)
+}
+
+// Purge permanently deletes items that have been in the trash longer than the retention period
+func (s *TrashService) Purge(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-s.retention)
+
+	meals, photoKeys, err := s.mealRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	s.photos.DeleteBlobs(ctx, photoKeys)
+
+	customFoods, err := s.mealRepo.PurgeDeletedCustomFoods(ctx, before)
+	if err != nil {
+		return meals, err
+	}
+
+	return meals + customFoods, nil
+}
+
+// StartPurger runs Purge on the given interval until the context is cancelled
+func (s *TrashService) StartPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.Purge(ctx)
+			if err != nil {
+				log.Printf("Trash purge failed: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Purged %d items from trash", purged)
+			}
+		}
+	}
+}
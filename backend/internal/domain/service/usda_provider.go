@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/cache"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/bytetrack/backend/internal/infrastructure/usda"
+	"github.com/google/uuid"
+)
+
+// usdaFoodIDPrefix prefixes the IDs of FoodData Central foods
+const usdaFoodIDPrefix = "usda_"
+
+// FoodData Central nutrient IDs of the core nutrition facts
+const (
+	fdcEnergyKcal        = 1008
+	fdcEnergyKJ          = 1062
+	fdcEnergyAtwaterGen  = 2047
+	fdcEnergyAtwaterSpec = 2048
+	fdcProtein           = 1003
+	fdcFat               = 1004
+	fdcFatNLEA           = 1085
+	fdcCarbs             = 1005
+	fdcCarbsBySummation  = 1050
+	fdcFiber             = 1079
+	fdcSugars            = 2000
+	fdcSugarsNLEA        = 1063
+	fdcSodium            = 1093
+)
+
+// USDAProvider serves USDA FoodData Central foods. Foods fetched from the
+// API are kept in usda_foods, which can also be filled from a downloaded
+// dataset; without an API key only those local foods are searched.
+type USDAProvider struct {
+	client    *usda.Client
+	repo      *repository.USDAFoodRepository
+	searches  cache.Store
+	searchTTL time.Duration
+}
+
+// NewUSDAProvider creates a new USDA provider. client may be nil to work
+// offline from usda_foods.
+func NewUSDAProvider(client *usda.Client, repo *repository.USDAFoodRepository, store cache.Store, searchTTL time.Duration) *USDAProvider {
+	return &USDAProvider{
+		client:    client,
+		repo:      repo,
+		searches:  cache.Namespace(store, "usda:search"),
+		searchTTL: searchTTL,
+	}
+}
+
+// Name returns "usda"
+func (p *USDAProvider) Name() string {
+	return entity.SearchSourceUSDA
+}
+
+// Search searches FoodData Central, falling back to the local foods when the
+// API fails. Category searches skip it; its categories aren't ours.
+func (p *USDAProvider) Search(ctx context.Context, q FoodQuery) ([]entity.FoodItem, bool, error) {
+	if q.Category != "" && q.Category != "all" {
+		return nil, false, ErrProviderSkipped
+	}
+	if p.client == nil {
+		return p.repo.Search(ctx, q.Query, q.PageSize, (q.Page-1)*q.PageSize)
+	}
+
+	key := fmt.Sprintf("page:%s:%d:%d", normalizeQuery(q.Query), q.Page, q.PageSize)
+	var page searchPage
+	if getCached(ctx, p.searches, key, &page) {
+		return page.Foods, page.HasMore, nil
+	}
+
+	data, err := p.client.Search(ctx, q.Query, q.Page, q.PageSize)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, false, err
+		}
+		foods, hasMore, localErr := p.repo.Search(ctx, q.Query, q.PageSize, (q.Page-1)*q.PageSize)
+		if localErr != nil || len(foods) == 0 {
+			return nil, false, err
+		}
+		log.Printf("FoodData Central search failed, serving local foods: %v", err)
+		return foods, hasMore, nil
+	}
+
+	var stored []*entity.USDAFood
+	for i := range data.Foods {
+		food := mapUSDAFood(&data.Foods[i])
+		if food == nil {
+			continue
+		}
+		page.Foods = append(page.Foods, *food)
+		stored = append(stored, &entity.USDAFood{
+			FdcID:    data.Foods[i].FdcID,
+			DataType: data.Foods[i].DataType,
+			Source:   entity.USDASourceAPI,
+			Food:     *food,
+		})
+	}
+	page.HasMore = data.CurrentPage < data.TotalPages
+
+	if err := p.repo.Upsert(ctx, stored); err != nil {
+		log.Printf("Failed to store FoodData Central foods: %v", err)
+	}
+	setCached(ctx, p.searches, key, page, p.searchTTL)
+	return page.Foods, page.HasMore, nil
+}
+
+// LookupBarcode finds a branded food by GTIN/UPC among the local foods
+func (p *USDAProvider) LookupBarcode(ctx context.Context, barcode string) (*entity.FoodItem, error) {
+	food, err := p.repo.FindByBarcode(ctx, barcode)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, ErrFoodNotFound
+	}
+	return food, err
+}
+
+// Handles reports whether id is a FoodData Central ID
+func (p *USDAProvider) Handles(id string) bool {
+	return strings.HasPrefix(id, usdaFoodIDPrefix)
+}
+
+// GetByID gets a food from the local foods, fetching and storing it when it
+// isn't there yet
+func (p *USDAProvider) GetByID(ctx context.Context, userID uuid.UUID, id string) (*entity.FoodItem, error) {
+	fdcID, err := strconv.Atoi(strings.TrimPrefix(id, usdaFoodIDPrefix))
+	if err != nil {
+		return nil, ErrFoodNotFound
+	}
+
+	food, err := p.repo.FindByID(ctx, fdcID)
+	if err == nil {
+		return food, nil
+	}
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
+	if p.client == nil {
+		return nil, ErrFoodNotFound
+	}
+
+	fdcFood, err := p.client.Food(ctx, fdcID)
+	if err != nil {
+		if errors.Is(err, usda.ErrNotFound) {
+			return nil, ErrFoodNotFound
+		}
+		return nil, err
+	}
+	food = mapUSDAFood(fdcFood)
+	if food == nil {
+		return nil, ErrFoodNotFound
+	}
+
+	stored := &entity.USDAFood{FdcID: fdcID, DataType: fdcFood.DataType, Source: entity.USDASourceAPI, Food: *food}
+	if err := p.repo.Upsert(ctx, []*entity.USDAFood{stored}); err != nil {
+		log.Printf("Failed to store FoodData Central food %d: %v", fdcID, err)
+	}
+	return food, nil
+}
+
+// Import stores foods read from a FoodData Central dataset and returns how
+// many were stored
+func (p *USDAProvider) Import(ctx context.Context, foods []*usda.Food) (int, error) {
+	stored := make([]*entity.USDAFood, 0, len(foods))
+	for _, f := range foods {
+		food := mapUSDAFood(f)
+		if food == nil {
+			continue
+		}
+		stored = append(stored, &entity.USDAFood{
+			FdcID:    f.FdcID,
+			DataType: f.DataType,
+			Source:   entity.USDASourceDataset,
+			Food:     *food,
+		})
+	}
+	if err := p.repo.Upsert(ctx, stored); err != nil {
+		return 0, err
+	}
+	return len(stored), nil
+}
+
+// mapUSDAFood maps a FoodData Central food to a FoodItem. Amounts are per
+// 100 g; portions carry gram weights to scale them by.
+func mapUSDAFood(f *usda.Food) *entity.FoodItem {
+	if f.FdcID == 0 || f.Description == "" {
+		return nil
+	}
+
+	amounts := make(map[int]usda.FoodNutrient, len(f.FoodNutrients))
+	for _, n := range f.FoodNutrients {
+		amounts[n.NutrientID] = n
+	}
+	grams := func(ids ...int) (float64, bool) {
+		for _, id := range ids {
+			if n, ok := amounts[id]; ok {
+				if g, ok := fdcGrams(n); ok {
+					return g, true
+				}
+			}
+		}
+		return 0, false
+	}
+
+	calories := 0.0
+	if n, ok := firstNutrient(amounts, fdcEnergyKcal, fdcEnergyAtwaterGen, fdcEnergyAtwaterSpec); ok {
+		calories = n.Amount
+	} else if n, ok := amounts[fdcEnergyKJ]; ok {
+		calories = n.Amount / 4.184
+	}
+	protein, _ := grams(fdcProtein)
+	carbs, _ := grams(fdcCarbs, fdcCarbsBySummation)
+	fat, _ := grams(fdcFat, fdcFatNLEA)
+
+	nutrition := entity.NutritionInfo{
+		Calories:    int(calories + 0.5),
+		Protein:     roundToOne(protein),
+		Carbs:       roundToOne(carbs),
+		Fat:         roundToOne(fat),
+		ServingSize: 100,
+		ServingUnit: "g",
+	}
+	if fiber, ok := grams(fdcFiber); ok {
+		nutrition.Fiber = roundToOnePtr(fiber)
+	}
+	if sugar, ok := grams(fdcSugars, fdcSugarsNLEA); ok {
+		nutrition.Sugar = roundToOnePtr(sugar)
+	}
+	if sodium, ok := grams(fdcSodium); ok {
+		nutrition.Sodium = toIntPtr(sodium * 1000)
+	}
+
+	var extra entity.Nutrients
+	for _, nutrient := range entity.NutrientRegistry {
+		if nutrient.FDCID == 0 {
+			continue
+		}
+		g, ok := grams(nutrient.FDCID)
+		if !ok || g < 0 {
+			continue
+		}
+		if extra == nil {
+			extra = make(entity.Nutrients)
+		}
+		extra[nutrient.ID] = nutrient.GramsToUnit(g)
+	}
+	nutrition.Nutrients = extra.Rounded()
+
+	for _, portion := range f.Portions() {
+		name := portion.Name()
+		if portion.GramWeight <= 0 || name == "" {
+			continue
+		}
+		nutrition.Portions = append(nutrition.Portions, entity.FoodPortion{
+			Name:       name,
+			GramWeight: roundToOne(portion.GramWeight),
+		})
+	}
+
+	food := &entity.FoodItem{
+		ID:        usdaFoodIDPrefix + strconv.Itoa(f.FdcID),
+		Name:      f.Description,
+		NameEn:    f.Description,
+		Category:  "other",
+		Nutrition: nutrition,
+		Source:    entity.FoodSourceUSDA,
+	}
+	if brand := f.BrandOwner; brand != "" || f.BrandName != "" {
+		if f.BrandName != "" {
+			brand = f.BrandName
+		}
+		food.Brand = &brand
+	}
+	if f.GtinUpc != "" {
+		barcode := f.GtinUpc
+		food.Barcode = &barcode
+	}
+	return food
+}
+
+func firstNutrient(amounts map[int]usda.FoodNutrient, ids ...int) (usda.FoodNutrient, bool) {
+	for _, id := range ids {
+		if n, ok := amounts[id]; ok {
+			return n, true
+		}
+	}
+	return usda.FoodNutrient{}, false
+}
+
+// fdcGrams converts a nutrient amount in a mass unit to grams
+func fdcGrams(n usda.FoodNutrient) (float64, bool) {
+	switch n.Unit {
+	case "G":
+		return n.Amount, true
+	case "MG":
+		return n.Amount / 1e3, true
+	case "UG":
+		return n.Amount / 1e6, true
+	default:
+		return 0, false
+	}
+}
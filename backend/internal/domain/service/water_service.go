@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidWaterAmount is returned when a drink or container size is out of range
+	ErrInvalidWaterAmount = errors.New("amount_ml must be between 1 and 5000")
+	// ErrInvalidContainerName is returned when a container has no name
+	ErrInvalidContainerName = errors.New("container name is required")
+)
+
+// WaterService handles water intake logging
+type WaterService struct {
+	waterRepo      *repository.WaterRepository
+	userRepo       *repository.UserRepository
+	calorieService *CalorieService
+	achievements   *AchievementService
+}
+
+// NewWaterService creates a new water service
+func NewWaterService(waterRepo *repository.WaterRepository, userRepo *repository.UserRepository, calorieService *CalorieService, achievements *AchievementService) *WaterService {
+	return &WaterService{
+		waterRepo:      waterRepo,
+		userRepo:       userRepo,
+		calorieService: calorieService,
+		achievements:   achievements,
+	}
+}
+
+// LogWater logs a drink, either by amount or by one of the user's containers
+func (s *WaterService) LogWater(ctx context.Context, userID uuid.UUID, req *entity.LogWaterRequest) (*entity.WaterLog, error) {
+	log := &entity.WaterLog{
+		ID:     uuid.New(),
+		UserID: userID,
+	}
+
+	if req.ContainerID != nil {
+		container, err := s.waterRepo.FindContainerByID(ctx, *req.ContainerID, userID)
+		if err != nil {
+			return nil, err
+		}
+		log.AmountML = container.AmountML
+		log.ContainerID = &container.ID
+	}
+	if req.AmountML != nil {
+		log.AmountML = *req.AmountML
+	}
+
+	if !validWaterAmount(log.AmountML) {
+		return nil, ErrInvalidWaterAmount
+	}
+
+	// Set date - use provided date or today
+	if req.Date != nil {
+		log.Date = *req.Date
+	} else {
+		log.Date = time.Now()
+	}
+
+	if err := s.waterRepo.CreateLog(ctx, log); err != nil {
+		return nil, err
+	}
+
+	s.achievements.WaterChanged(ctx, userID, log.Date)
+	return log, nil
+}
+
+// DeleteLog deletes a logged drink
+func (s *WaterService) DeleteLog(ctx context.Context, logID, userID uuid.UUID) error {
+	date, err := s.waterRepo.DeleteLog(ctx, logID, userID)
+	if err != nil {
+		return err
+	}
+
+	s.achievements.WaterChanged(ctx, userID, date)
+	return nil
+}
+
+// GetDailyHydration gets a user's drinks and progress for a date
+func (s *WaterService) GetDailyHydration(ctx context.Context, userID uuid.UUID, date time.Time) (*entity.DailyHydration, error) {
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dailyHydration(ctx, userID, date, profile)
+}
+
+// GetRangeStats gets per-day water totals between two dates (inclusive)
+func (s *WaterService) GetRangeStats(ctx context.Context, userID uuid.UUID, from, to time.Time) (*entity.WaterRangeStats, error) {
+	profile, err := s.findProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.waterRepo.GetRangeTotals(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &entity.WaterRangeStats{
+		From:       from,
+		To:         to,
+		Days:       days,
+		DaysLogged: len(days),
+		TargetML:   s.targetML(profile),
+	}
+	if stats.Days == nil {
+		stats.Days = []*entity.DayWater{}
+	}
+
+	for _, day := range days {
+		stats.TotalML += day.TotalML
+		if stats.TargetML > 0 && day.TotalML >= stats.TargetML {
+			day.GoalReached = true
+			stats.DaysOnTarget++
+		}
+	}
+	if len(days) > 0 {
+		stats.AverageML = int(math.Round(float64(stats.TotalML) / float64(len(days))))
+	}
+
+	return stats, nil
+}
+
+// GetContainers gets the quick-add sizes and the user's custom containers
+func (s *WaterService) GetContainers(ctx context.Context, userID uuid.UUID) (*entity.WaterContainers, error) {
+	custom, err := s.waterRepo.FindContainers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if custom == nil {
+		custom = []*entity.WaterContainer{}
+	}
+
+	return &entity.WaterContainers{
+		QuickAddML: entity.WaterQuickAddAmounts,
+		Custom:     custom,
+	}, nil
+}
+
+// CreateContainer saves a custom container size
+func (s *WaterService) CreateContainer(ctx context.Context, userID uuid.UUID, req *entity.CreateWaterContainerRequest) (*entity.WaterContainer, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrInvalidContainerName
+	}
+	if !validWaterAmount(req.AmountML) {
+		return nil, ErrInvalidWaterAmount
+	}
+
+	container := &entity.WaterContainer{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Name:     name,
+		AmountML: req.AmountML,
+		Emoji:    req.Emoji,
+	}
+
+	if err := s.waterRepo.CreateContainer(ctx, container); err != nil {
+		return nil, err
+	}
+
+	return container, nil
+}
+
+// DeleteContainer deletes a custom container; past logs keep their amount
+func (s *WaterService) DeleteContainer(ctx context.Context, containerID, userID uuid.UUID) error {
+	return s.waterRepo.DeleteContainer(ctx, containerID, userID)
+}
+
+// dailyHydration builds the hydration summary for a date. Progress is only
+// available when the user has a profile to base the recommendation on.
+func (s *WaterService) dailyHydration(ctx context.Context, userID uuid.UUID, date time.Time, profile *entity.UserProfile) (*entity.DailyHydration, error) {
+	logs, err := s.waterRepo.FindLogsByDate(ctx, userID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	hydration := &entity.DailyHydration{
+		Date: date,
+		Logs: logs,
+	}
+	if hydration.Logs == nil {
+		hydration.Logs = []*entity.WaterLog{}
+	}
+	for _, log := range logs {
+		hydration.TotalML += log.AmountML
+	}
+
+	if target := s.targetML(profile); target > 0 {
+		progress := &entity.HydrationProgress{
+			ConsumedML:  hydration.TotalML,
+			TargetML:    target,
+			RemainingML: target - hydration.TotalML,
+			Percent:     math.Round(float64(hydration.TotalML)/float64(target)*1000) / 10,
+			GoalReached: hydration.TotalML >= target,
+		}
+		if progress.RemainingML < 0 {
+			progress.RemainingML = 0
+		}
+		hydration.Progress = progress
+	}
+
+	return hydration, nil
+}
+
+// targetML gets the recommended daily intake in ml, or 0 without a profile
+func (s *WaterService) targetML(profile *entity.UserProfile) int {
+	return waterTargetML(s.calorieService, profile)
+}
+
+// waterTargetML gets the recommended daily intake in ml, or 0 without a profile
+func waterTargetML(calorieService *CalorieService, profile *entity.UserProfile) int {
+	if profile == nil {
+		return 0
+	}
+	liters := calorieService.CalculateWaterIntake(profile.Weight, profile.ActivityLevel)
+	return int(math.Round(liters * 1000))
+}
+
+// findProfile gets the user's profile, or nil if onboarding is incomplete
+func (s *WaterService) findProfile(ctx context.Context, userID uuid.UUID) (*entity.UserProfile, error) {
+	profile, err := s.userRepo.FindProfileByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+func validWaterAmount(ml int) bool {
+	return ml > 0 && ml <= entity.MaxWaterLogML
+}
@@ -0,0 +1,531 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/bytetrack/backend/internal/pkg/netguard"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidWebhook is returned when a webhook request can't be used
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+const (
+	// maxWebhooks caps the webhooks per owner
+	maxWebhooks = 10
+	// maxWebhookDescription caps a webhook's description length
+	maxWebhookDescription = 255
+	// webhookBatchSize caps the events fanned out and deliveries sent per pass
+	webhookBatchSize = 100
+	// webhookWorkers caps concurrent delivery requests
+	webhookWorkers = 8
+	// retryBaseDelay is the wait before the first retry; each retry doubles it
+	retryBaseDelay = time.Minute
+	// retryMaxDelay caps the wait between retries
+	retryMaxDelay = 6 * time.Hour
+	// maxLoggedResponse caps the response body kept in an app webhook
+	// delivery's log
+	maxLoggedResponse = 1024
+	// webhookPurgeInterval is how often old events are purged
+	webhookPurgeInterval = time.Hour
+)
+
+// WebhookService manages webhooks and delivers events to them. Changes are
+// recorded in an outbox in the same transaction as the write they describe,
+// then fanned out to subscribed webhooks and delivered with retries.
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+	transactor  *repository.Transactor
+	client      *http.Client
+	maxAttempts int
+	retention   time.Duration
+	lastPurge   time.Time
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhookRepo *repository.WebhookRepository, transactor *repository.Transactor, timeout time.Duration, maxAttempts int, retention time.Duration) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		transactor:  transactor,
+		client: &http.Client{
+			Timeout: timeout,
+			// Webhook URLs are user-supplied, so only public addresses are dialed
+			Transport: netguard.NewTransport(),
+			// Redirects are reported as failures rather than followed
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts: maxAttempts,
+		retention:   retention,
+	}
+}
+
+// Record runs a write and records the event it returns in one transaction,
+// so an event is delivered if and only if the write is committed
+func (s *WebhookService) Record(ctx context.Context, userID uuid.UUID, eventType entity.EventType, write func(ctx context.Context) (interface{}, error)) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		data, err := write(ctx)
+		if err != nil {
+			return err
+		}
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		return s.webhookRepo.CreateEvent(ctx, &entity.WebhookEvent{
+			ID:      uuid.New(),
+			UserID:  userID,
+			Type:    eventType,
+			Payload: payload,
+		})
+	})
+}
+
+// CreateWebhook registers a webhook for an owner (nil for an app webhook).
+// The signing secret is only returned here and when rotated.
+func (s *WebhookService) CreateWebhook(ctx context.Context, owner *uuid.UUID, req *entity.CreateWebhookRequest) (*entity.Webhook, error) {
+	if err := validateWebhookURL(req.URL, owner); err != nil {
+		return nil, err
+	}
+	events, err := validateEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Description) > maxWebhookDescription {
+		return nil, fmt.Errorf("%w: description must be at most %d characters", ErrInvalidWebhook, maxWebhookDescription)
+	}
+
+	count, err := s.webhookRepo.CountByOwner(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxWebhooks {
+		return nil, fmt.Errorf("%w: at most %d webhooks can be registered", ErrInvalidWebhook, maxWebhooks)
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	webhook := &entity.Webhook{
+		ID:          uuid.New(),
+		UserID:      owner,
+		URL:         req.URL,
+		Events:      events,
+		Secret:      secret,
+		Description: req.Description,
+		Active:      true,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// GetWebhooks gets an owner's webhooks
+func (s *WebhookService) GetWebhooks(ctx context.Context, owner *uuid.UUID) ([]*entity.Webhook, error) {
+	webhooks, err := s.webhookRepo.FindByOwner(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook gets an owner's webhook
+func (s *WebhookService) GetWebhook(ctx context.Context, owner *uuid.UUID, id uuid.UUID) (*entity.Webhook, error) {
+	webhook, err := s.webhookRepo.FindByID(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// UpdateWebhook updates an owner's webhook. Deactivated webhooks keep
+// queueing deliveries, which are sent once the webhook is active again.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, owner *uuid.UUID, id uuid.UUID, req *entity.UpdateWebhookRequest) (*entity.Webhook, error) {
+	webhook, err := s.webhookRepo.FindByID(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL, owner); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		if webhook.Events, err = validateEvents(*req.Events); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		if len(*req.Description) > maxWebhookDescription {
+			return nil, fmt.Errorf("%w: description must be at most %d characters", ErrInvalidWebhook, maxWebhookDescription)
+		}
+		webhook.Description = *req.Description
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// RotateSecret replaces a webhook's signing secret and returns the webhook
+// with the new secret
+func (s *WebhookService) RotateSecret(ctx context.Context, owner *uuid.UUID, id uuid.UUID) (*entity.Webhook, error) {
+	webhook, err := s.webhookRepo.FindByID(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Secret, err = newWebhookSecret(); err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook deletes an owner's webhook and its deliveries
+func (s *WebhookService) DeleteWebhook(ctx context.Context, owner *uuid.UUID, id uuid.UUID) error {
+	return s.webhookRepo.Delete(ctx, id, owner)
+}
+
+// GetDeliveries gets a webhook's most recent deliveries, optionally by status
+func (s *WebhookService) GetDeliveries(ctx context.Context, owner *uuid.UUID, id uuid.UUID, status *entity.DeliveryStatus, limit int) ([]*entity.WebhookDelivery, error) {
+	if status != nil && *status != entity.DeliveryPending && *status != entity.DeliverySucceeded && *status != entity.DeliveryDead {
+		return nil, fmt.Errorf("%w: status must be pending, succeeded or dead", ErrInvalidWebhook)
+	}
+	if _, err := s.webhookRepo.FindByID(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.FindDeliveries(ctx, id, status, limit)
+}
+
+// GetDelivery gets a webhook's delivery with its attempt log. Response
+// bodies are only shown for app webhooks; users see the status codes.
+func (s *WebhookService) GetDelivery(ctx context.Context, owner *uuid.UUID, id, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.FindByID(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	delivery, err := s.webhookRepo.FindDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil {
+		for _, attempt := range delivery.Log {
+			attempt.ResponseBody = nil
+		}
+	}
+	return delivery, nil
+}
+
+// ReplayDelivery queues a delivery to be sent again with a fresh set of
+// retries, whatever its status
+func (s *WebhookService) ReplayDelivery(ctx context.Context, owner *uuid.UUID, id, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.FindByID(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.Requeue(ctx, id, deliveryID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.FindDelivery(ctx, id, deliveryID)
+}
+
+// ReplayDead queues every dead-lettered delivery of a webhook again
+func (s *WebhookService) ReplayDead(ctx context.Context, owner *uuid.UUID, id uuid.UUID) (*entity.ReplayResult, error) {
+	if _, err := s.webhookRepo.FindByID(ctx, id, owner); err != nil {
+		return nil, err
+	}
+	requeued, err := s.webhookRepo.RequeueDead(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.ReplayResult{Requeued: int(requeued)}, nil
+}
+
+// Dispatch fans new events out to subscribed webhooks, sends due deliveries
+// and purges old events, returning how many deliveries were attempted
+func (s *WebhookService) Dispatch(ctx context.Context, now time.Time) (int, error) {
+	for {
+		fanned, err := s.fanOut(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if fanned < webhookBatchSize {
+			break
+		}
+	}
+
+	// Claimed deliveries are leased so other instances skip them while
+	// they are sent; a crash mid-send retries them once the lease expires
+	lease := now.Add(2*s.client.Timeout + time.Minute)
+	due, err := s.webhookRepo.ClaimDeliveries(ctx, now, lease, webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	work := make(chan *repository.DueDelivery)
+	for i := 0; i < webhookWorkers && i < len(due); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range work {
+				if err := s.deliver(ctx, d); err != nil {
+					log.Printf("Failed to record webhook delivery %s: %v", d.Delivery.ID, err)
+				}
+			}
+		}()
+	}
+	for _, d := range due {
+		work <- d
+	}
+	close(work)
+	wg.Wait()
+
+	if now.Sub(s.lastPurge) >= webhookPurgeInterval {
+		s.lastPurge = now
+		if _, err := s.webhookRepo.PurgeEvents(ctx, now.Add(-s.retention)); err != nil {
+			log.Printf("Failed to purge webhook events: %v", err)
+		}
+	}
+	return len(due), nil
+}
+
+// StartDispatcher runs Dispatch on the given interval until the context is cancelled
+func (s *WebhookService) StartDispatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.Dispatch(ctx, now); err != nil {
+				log.Printf("Webhook dispatcher failed: %v", err)
+			}
+		}
+	}
+}
+
+// fanOut queues a batch of new events for their subscribed webhooks and
+// returns how many events were handled
+func (s *WebhookService) fanOut(ctx context.Context) (int, error) {
+	var count int
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		events, err := s.webhookRepo.ClaimEvents(ctx, webhookBatchSize)
+		if err != nil {
+			return err
+		}
+		count = len(events)
+
+		subscribers := make(map[uuid.UUID][]*entity.Webhook)
+		for _, event := range events {
+			webhooks, ok := subscribers[event.UserID]
+			if !ok {
+				if webhooks, err = s.webhookRepo.FindSubscribers(ctx, event.UserID); err != nil {
+					return err
+				}
+				subscribers[event.UserID] = webhooks
+			}
+			for _, webhook := range webhooks {
+				if !webhook.Subscribes(event.Type) {
+					continue
+				}
+				if err := s.webhookRepo.CreateDelivery(ctx, webhook.ID, event.ID); err != nil {
+					return err
+				}
+			}
+			if err := s.webhookRepo.MarkDispatched(ctx, event.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// deliver sends one delivery, logs the attempt and schedules a retry or
+// dead-letters the delivery on failure
+func (s *WebhookService) deliver(ctx context.Context, due *repository.DueDelivery) error {
+	delivery := due.Delivery
+	delivery.Attempts++
+	attempt := &entity.WebhookAttempt{
+		ID:         uuid.New(),
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+	}
+
+	started := time.Now()
+	statusCode, body, err := s.send(ctx, due)
+	attempt.DurationMS = int(time.Since(started).Milliseconds())
+	// Response bodies are only kept for app webhooks, so a user can't read
+	// what an endpoint of their choosing returned to the server
+	if body != "" && due.Webhook.UserID == nil {
+		attempt.ResponseBody = &body
+	}
+
+	delivery.LastStatusCode, delivery.LastError = nil, nil
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+		delivery.LastStatusCode = &statusCode
+	}
+	if err == nil && (statusCode < 200 || statusCode > 299) {
+		err = fmt.Errorf("endpoint responded %d", statusCode)
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = entity.DeliverySucceeded
+		delivery.DeliveredAt = &now
+	case statusCode == http.StatusGone || delivery.Attempts >= s.maxAttempts:
+		// 410 Gone asks us to stop; otherwise retries are exhausted
+		delivery.Status = entity.DeliveryDead
+	default:
+		delivery.Status = entity.DeliveryPending
+		delivery.NextAttemptAt = now.Add(retryDelay(delivery.Attempts))
+	}
+	if err != nil {
+		message := err.Error()
+		attempt.Error = &message
+		delivery.LastError = &message
+	}
+
+	if err := s.webhookRepo.CreateAttempt(ctx, attempt); err != nil {
+		return err
+	}
+	return s.webhookRepo.UpdateDelivery(ctx, delivery)
+}
+
+// send POSTs an event to a webhook, signed with its secret, and returns the
+// status code and the start of the response body
+func (s *WebhookService) send(ctx context.Context, due *repository.DueDelivery) (int, string, error) {
+	body, err := json.Marshal(due.Event)
+	if err != nil {
+		return 0, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, due.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ByteTrack-Webhooks/1.0")
+	req.Header.Set("X-ByteTrack-Event", string(due.Event.Type))
+	req.Header.Set("X-ByteTrack-Delivery", due.Delivery.ID.String())
+	req.Header.Set("X-ByteTrack-Timestamp", timestamp)
+	req.Header.Set("X-ByteTrack-Signature", "t="+timestamp+",v1="+signWebhook(due.Webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	response, _ := io.ReadAll(io.LimitReader(resp.Body, maxLoggedResponse))
+	return resp.StatusCode, string(response), nil
+}
+
+// signWebhook signs a payload: hex HMAC-SHA256 of "<timestamp>.<body>".
+// Receivers recompute it with their secret and reject stale timestamps.
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay is the exponential backoff after a failed attempt, capped and
+// jittered by up to ±20% so failing endpoints aren't retried in lockstep
+func retryDelay(attempts int) time.Duration {
+	delay := retryMaxDelay
+	if attempts <= 16 {
+		if d := retryBaseDelay << (attempts - 1); d < retryMaxDelay {
+			delay = d
+		}
+	}
+	jitter := time.Duration((mathrand.Float64()*0.4 - 0.2) * float64(delay))
+	return delay + jitter
+}
+
+// newWebhookSecret generates a random signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// validateWebhookURL checks a webhook URL is absolute https, or http for
+// app webhooks, and doesn't name a non-public address. Hostnames are
+// checked again when dialed.
+func validateWebhookURL(raw string, owner *uuid.UUID) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	if owner != nil && u.Scheme != "https" {
+		return fmt.Errorf("%w: url must use https", ErrInvalidWebhook)
+	}
+	if !netguard.IsPublicHost(u.Hostname()) {
+		return fmt.Errorf("%w: url must not point to a private address", ErrInvalidWebhook)
+	}
+	return nil
+}
+
+// validateEvents checks event types are known and removes repeats. An empty
+// list subscribes to every event.
+func validateEvents(events []entity.EventType) ([]entity.EventType, error) {
+	known := make(map[entity.EventType]bool, len(entity.EventTypes))
+	for _, e := range entity.EventTypes {
+		known[e] = true
+	}
+
+	seen := make(map[entity.EventType]bool, len(events))
+	valid := []entity.EventType{}
+	for _, e := range events {
+		if !known[e] {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			valid = append(valid, e)
+		}
+	}
+	return valid, nil
+}
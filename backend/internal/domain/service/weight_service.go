@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/repository"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidWeight is returned when a weight is outside the supported range
+var ErrInvalidWeight = errors.New("weight must be between 30 and 300 kg")
+
+const (
+	// trendSmoothing is the daily EMA smoothing factor (as in The Hacker's Diet)
+	trendSmoothing = 0.1
+	// trendWarmupDays of earlier entries seed the trend so it is settled at the window start
+	trendWarmupDays = 30
+	// stableWeeklyRate is the rate (kg/week) below which the trend counts as stable
+	stableWeeklyRate = 0.1
+	// profileUpdateAttempts bounds retries when the profile changes concurrently
+	profileUpdateAttempts = 3
+)
+
+// WeightService handles body weight tracking
+type WeightService struct {
+	weightRepo     *repository.WeightRepository
+	userRepo       *repository.UserRepository
+	calorieService *CalorieService
+	achievements   *AchievementService
+	events         *WebhookService
+}
+
+// NewWeightService creates a new weight service
+func NewWeightService(weightRepo *repository.WeightRepository, userRepo *repository.UserRepository, calorieService *CalorieService, achievements *AchievementService, events *WebhookService) *WeightService {
+	return &WeightService{
+		weightRepo:     weightRepo,
+		userRepo:       userRepo,
+		calorieService: calorieService,
+		achievements:   achievements,
+		events:         events,
+	}
+}
+
+// CreateEntry records a weight. When it is the most recent entry and
+// UpdateProfile is not false, the profile weight is updated and its
+// targets recalculated.
+func (s *WeightService) CreateEntry(ctx context.Context, userID uuid.UUID, req *entity.CreateWeightRequest) (*entity.WeightEntryResult, error) {
+	if !validWeight(req.Weight) {
+		return nil, ErrInvalidWeight
+	}
+
+	entry := &entity.WeightEntry{
+		ID:     uuid.New(),
+		UserID: userID,
+		Weight: req.Weight,
+		Note:   req.Note,
+	}
+
+	// Set date - use provided date or today
+	if req.Date != nil {
+		entry.Date = *req.Date
+	} else {
+		entry.Date = time.Now()
+	}
+
+	err := s.events.Record(ctx, userID, entity.EventWeightLogged, func(ctx context.Context) (interface{}, error) {
+		return entry, s.weightRepo.Upsert(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.achievements.WeightChanged(ctx, userID)
+	result := &entity.WeightEntryResult{Entry: entry}
+
+	if req.UpdateProfile == nil || *req.UpdateProfile {
+		latest, err := s.weightRepo.FindLatest(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if latest.ID == entry.ID {
+			profile, err := s.applyWeight(ctx, userID, entry.Weight)
+			if err != nil {
+				return nil, err
+			}
+			result.Profile = profile
+		}
+	}
+
+	return result, nil
+}
+
+// GetEntries gets weight entries with their trend weight between two dates (inclusive)
+func (s *WeightService) GetEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entity.WeightEntry, error) {
+	return s.entriesWithTrend(ctx, userID, from, to)
+}
+
+// GetEntryByID gets a weight entry by ID
+func (s *WeightService) GetEntryByID(ctx context.Context, entryID, userID uuid.UUID) (*entity.WeightEntry, error) {
+	entry, err := s.weightRepo.FindByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if entry.UserID != userID {
+		return nil, repository.ErrUserNotFound
+	}
+
+	return entry, nil
+}
+
+// UpdateEntry updates a weight entry
+func (s *WeightService) UpdateEntry(ctx context.Context, entryID, userID uuid.UUID, req *entity.UpdateWeightRequest) (*entity.WeightEntry, error) {
+	entry, err := s.GetEntryByID(ctx, entryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Weight != nil {
+		if !validWeight(*req.Weight) {
+			return nil, ErrInvalidWeight
+		}
+		entry.Weight = *req.Weight
+	}
+	if req.Date != nil {
+		entry.Date = *req.Date
+	}
+	if req.Note != nil {
+		entry.Note = req.Note
+	}
+
+	err = s.events.Record(ctx, userID, entity.EventWeightUpdated, func(ctx context.Context) (interface{}, error) {
+		return entry, s.weightRepo.Update(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.achievements.WeightChanged(ctx, userID)
+	return entry, nil
+}
+
+// DeleteEntry deletes a weight entry
+func (s *WeightService) DeleteEntry(ctx context.Context, entryID, userID uuid.UUID) error {
+	err := s.events.Record(ctx, userID, entity.EventWeightDeleted, func(ctx context.Context) (interface{}, error) {
+		return map[string]uuid.UUID{"id": entryID}, s.weightRepo.Delete(ctx, entryID, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.achievements.WeightChanged(ctx, userID)
+	return nil
+}
+
+// GetTrend gets the smoothed weight trend and weekly rate of change over the
+// last `days` days
+func (s *WeightService) GetTrend(ctx context.Context, userID uuid.UUID, days int) (*entity.WeightTrend, error) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -(days - 1))
+
+	entries, err := s.entriesWithTrend(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &entity.WeightTrend{
+		From:      from,
+		To:        to,
+		Entries:   entries,
+		Direction: entity.TrendStable,
+	}
+	if len(entries) == 0 {
+		return trend, nil
+	}
+
+	first, last := entries[0], entries[len(entries)-1]
+	trend.StartWeight = &first.Weight
+	trend.CurrentWeight = &last.Weight
+	trend.TrendWeight = last.TrendWeight
+	trend.Change = round2(*last.TrendWeight - *first.TrendWeight)
+	trend.WeeklyRate = round2(weeklyRate(entries))
+
+	switch {
+	case trend.WeeklyRate <= -stableWeeklyRate:
+		trend.Direction = entity.TrendLosing
+	case trend.WeeklyRate >= stableWeeklyRate:
+		trend.Direction = entity.TrendGaining
+	}
+
+	return trend, nil
+}
+
+// entriesWithTrend loads entries in the window plus a warm-up period, fills
+// in the exponentially smoothed trend weight and returns the window's entries
+func (s *WeightService) entriesWithTrend(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entity.WeightEntry, error) {
+	entries, err := s.weightRepo.FindByUserID(ctx, userID, from.AddDate(0, 0, -trendWarmupDays), to)
+	if err != nil {
+		return nil, err
+	}
+
+	smoothTrend(entries)
+
+	window := []*entity.WeightEntry{}
+	start := truncateDay(from)
+	for _, entry := range entries {
+		if !entry.Date.Before(start) {
+			window = append(window, entry)
+		}
+	}
+
+	return window, nil
+}
+
+// applyWeight sets the profile's weight and recalculates its targets,
+// retrying if the profile is modified concurrently. Users who have not
+// completed onboarding have no profile and are skipped.
+func (s *WeightService) applyWeight(ctx context.Context, userID uuid.UUID, weight float64) (*entity.UserProfile, error) {
+	profile, err := updateProfile(ctx, s.userRepo, s.calorieService, s.events, userID, func(profile *entity.UserProfile) error {
+		profile.Weight = weight
+		return nil
+	})
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, nil
+	}
+	return profile, err
+}
+
+// smoothTrend fills TrendWeight on entries sorted by date using an
+// exponential moving average. Gaps of several days apply the daily
+// smoothing factor once per elapsed day.
+func smoothTrend(entries []*entity.WeightEntry) {
+	var trend float64
+	var prev time.Time
+
+	for i, entry := range entries {
+		if i == 0 {
+			trend = entry.Weight
+		} else {
+			days := entry.Date.Sub(prev).Hours() / 24
+			if days < 1 {
+				days = 1
+			}
+			alpha := 1 - math.Pow(1-trendSmoothing, days)
+			trend += alpha * (entry.Weight - trend)
+		}
+		prev = entry.Date
+
+		value := round2(trend)
+		entry.TrendWeight = &value
+	}
+}
+
+// weeklyRate is the least-squares slope of the trend weight, in kg per week
+func weeklyRate(entries []*entity.WeightEntry) float64 {
+	if len(entries) < 2 {
+		return 0
+	}
+
+	origin := entries[0].Date
+	var n, sumX, sumY, sumXY, sumXX float64
+	for _, entry := range entries {
+		x := entry.Date.Sub(origin).Hours() / 24
+		y := *entry.TrendWeight
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator * 7
+}
+
+func validWeight(weight float64) bool {
+	return weight >= 30 && weight <= 300
+}
+
+// truncateDay returns the calendar date of t as midnight UTC, matching DATE columns
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
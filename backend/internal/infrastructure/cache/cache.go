@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bytetrack/backend/internal/infrastructure/config"
+)
+
+// Store is a key-value cache with per-entry expiry
+type Store interface {
+	// Get returns the value stored under key, or false when it is missing or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl; a ttl of zero or less never expires
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys; deleting a missing key is not an error
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePrefix removes every key starting with prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+	// Close releases the store's resources
+	Close() error
+}
+
+// Cleaner is implemented by stores that must delete expired entries
+// themselves
+type Cleaner interface {
+	Cleanup(ctx context.Context) (int64, error)
+}
+
+// StartCleanup runs Cleanup on the given interval until the context is cancelled
+func StartCleanup(ctx context.Context, cleaner Cleaner, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := cleaner.Cleanup(ctx)
+			if err != nil {
+				log.Printf("Cache cleanup failed: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Deleted %d expired cache entries", deleted)
+			}
+		}
+	}
+}
+
+// New creates the cache store selected in the configuration. db is used by
+// the Postgres backend.
+func New(cfg *config.Config, db DB) (Store, error) {
+	switch cfg.Cache.Backend {
+	case "", "memory":
+		return NewMemoryStore(cfg.Cache.MaxEntries), nil
+	case "postgres":
+		return NewPostgresStore(db), nil
+	case "redis":
+		return NewRedisStore(RedisOptions{
+			Addr:     cfg.Redis.Host,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.Cache.Backend)
+	}
+}
+
+// namespaced prefixes every key of a store with a namespace
+type namespaced struct {
+	store  Store
+	prefix string
+}
+
+// Namespace returns a view of store whose keys are prefixed with ns, so
+// callers can share a store without key collisions and clear their own
+// entries with DeletePrefix(ctx, "")
+func Namespace(store Store, ns string) Store {
+	return &namespaced{store: store, prefix: ns + ":"}
+}
+
+func (n *namespaced) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return n.store.Get(ctx, n.prefix+key)
+}
+
+func (n *namespaced) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return n.store.Set(ctx, n.prefix+key, value, ttl)
+}
+
+func (n *namespaced) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = n.prefix + key
+	}
+	return n.store.Delete(ctx, prefixed...)
+}
+
+func (n *namespaced) DeletePrefix(ctx context.Context, prefix string) error {
+	return n.store.DeletePrefix(ctx, n.prefix+prefix)
+}
+
+// Close does nothing; the underlying store is closed by its owner
+func (n *namespaced) Close() error {
+	return nil
+}
+
+// GetJSON decodes the value stored under key into dest, reporting whether
+// it was found
+func GetJSON(ctx context.Context, store Store, key string, dest interface{}) (bool, error) {
+	data, ok, err := store.Get(ctx, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetJSON stores v as JSON under key for ttl
+func SetJSON(ctx context.Context, store Store, key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return store.Set(ctx, key, data, ttl)
+}
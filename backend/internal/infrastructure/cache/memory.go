@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxEntries bounds a memory store created without a limit
+const defaultMaxEntries = 10000
+
+// MemoryStore is an in-process LRU cache. Entries are evicted least recently
+// used first once the store is full, and dropped when read after expiry or
+// swept by Cleanup.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // front is most recently used
+	entries    map[string]*list.Element
+	stats      Stats
+}
+
+// Stats counts a memory store's lookups and removals since it was created
+type Stats struct {
+	Entries    int    `json:"entries"`
+	MaxEntries int    `json:"max_entries"`
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+	Evictions  uint64 `json:"evictions"` // removed to make room
+	Expired    uint64 `json:"expired"`   // removed after expiry
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero never expires
+}
+
+// NewMemoryStore creates a memory store holding at most maxEntries entries
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored under key
+func (m *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		m.stats.Misses++
+		return nil, false, nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if entry.expired(time.Now()) {
+		m.remove(elem)
+		m.stats.Expired++
+		m.stats.Misses++
+		return nil, false, nil
+	}
+	m.order.MoveToFront(elem)
+	m.stats.Hits++
+	return entry.value, true, nil
+}
+
+// Set stores value under key for ttl, evicting the least recently used
+// entry when the store is full
+func (m *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	// Copy so callers can't modify the cached value
+	value = append([]byte(nil), value...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		m.order.MoveToFront(elem)
+		return nil
+	}
+
+	m.entries[key] = m.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	for m.order.Len() > m.maxEntries {
+		m.remove(m.order.Back())
+		m.stats.Evictions++
+	}
+	return nil
+}
+
+// Delete removes keys
+func (m *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := m.entries[key]; ok {
+			m.remove(elem)
+		}
+	}
+	return nil
+}
+
+// DeletePrefix removes every key starting with prefix
+func (m *MemoryStore) DeletePrefix(ctx context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, elem := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(elem)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet dropped
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// Stats returns the store's counters
+func (m *MemoryStore) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats
+	stats.Entries = m.order.Len()
+	stats.MaxEntries = m.maxEntries
+	return stats
+}
+
+// Cleanup drops expired entries and returns how many were dropped, so
+// entries that are never read again don't hold memory until evicted
+func (m *MemoryStore) Cleanup(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var dropped int64
+	for _, elem := range m.entries {
+		if elem.Value.(*memoryEntry).expired(now) {
+			m.remove(elem)
+			dropped++
+		}
+	}
+	m.stats.Expired += uint64(dropped)
+	return dropped, nil
+}
+
+// Close does nothing for a memory store
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+func (m *MemoryStore) remove(elem *list.Element) {
+	m.order.Remove(elem)
+	delete(m.entries, elem.Value.(*memoryEntry).key)
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
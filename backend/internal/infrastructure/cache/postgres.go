@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DB is the database interface used by the Postgres store
+type DB interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// PostgresStore caches entries in the cache_entries table, so they are
+// shared between instances and survive restarts. Expired entries are
+// ignored on read and deleted by Cleanup.
+type PostgresStore struct {
+	db DB
+}
+
+// NewPostgresStore creates a new Postgres store
+func NewPostgresStore(db DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Get returns the value stored under key
+func (p *PostgresStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := p.db.QueryRow(ctx, `
+		SELECT value FROM cache_entries
+		WHERE key = $1 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+	`, key).Scan(&value)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores value under key for ttl
+func (p *PostgresStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expiresAt *time.Time
+	if ttl > 0 {
+		t := time.Now().Add(ttl)
+		expiresAt = &t
+	}
+
+	_, err := p.db.Exec(ctx, `
+		INSERT INTO cache_entries (key, value, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+	`, key, value, expiresAt)
+	return err
+}
+
+// Delete removes keys
+func (p *PostgresStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := p.db.Exec(ctx, `DELETE FROM cache_entries WHERE key = ANY($1)`, keys)
+	return err
+}
+
+// DeletePrefix removes every key starting with prefix
+func (p *PostgresStore) DeletePrefix(ctx context.Context, prefix string) error {
+	_, err := p.db.Exec(ctx, `DELETE FROM cache_entries WHERE key LIKE $1`, escapeLike(prefix)+"%")
+	return err
+}
+
+// Cleanup deletes expired entries and returns how many were deleted
+func (p *PostgresStore) Cleanup(ctx context.Context) (int64, error) {
+	tag, err := p.db.Exec(ctx, `DELETE FROM cache_entries WHERE expires_at <= CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Close does nothing; the pool is closed by its owner
+func (p *PostgresStore) Close() error {
+	return nil
+}
+
+// escapeLike escapes LIKE wildcards so s matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,224 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// redisTestDB is the database the tests write to, away from the default 0
+const redisTestDB = 15
+
+// newTestRedis connects to the server at REDIS_ADDR, skipping the test when
+// it is unset. Keys are namespaced per test and deleted afterwards.
+func newTestRedis(t *testing.T, db, poolSize int) (*RedisStore, string) {
+	t.Helper()
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR is not set")
+	}
+
+	s, err := NewRedisStore(RedisOptions{
+		Addr:     addr,
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       db,
+		PoolSize: poolSize,
+	})
+	if err != nil {
+		t.Fatalf("NewRedisStore: %v", err)
+	}
+	prefix := "bytetrack-test:" + uuid.NewString() + ":"
+	t.Cleanup(func() {
+		s.DeletePrefix(context.Background(), prefix)
+		s.Close()
+	})
+	return s, prefix
+}
+
+func TestRedisGetSet(t *testing.T) {
+	s, prefix := newTestRedis(t, redisTestDB, 0)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		value []byte
+	}{
+		{"text", []byte("hello")},
+		{"empty", []byte{}},
+		{"binary with CRLF", []byte("a\r\nb\x00c")},
+		{"thai", []byte("ข้าวมันไก่")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := prefix + tt.name
+			if err := s.Set(ctx, key, tt.value, time.Minute); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			got, ok, err := s.Get(ctx, key)
+			if err != nil || !ok {
+				t.Fatalf("Get = %q, %v, %v; want hit", got, ok, err)
+			}
+			if string(got) != string(tt.value) {
+				t.Errorf("Get = %q, want %q", got, tt.value)
+			}
+		})
+	}
+
+	if _, ok, err := s.Get(ctx, prefix+"missing"); ok || err != nil {
+		t.Errorf("Get missing = %v, %v; want miss", ok, err)
+	}
+
+	if err := s.Delete(ctx, prefix+"text"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok, _ := s.Get(ctx, prefix+"text"); ok {
+		t.Error("Get after Delete hit")
+	}
+}
+
+func TestRedisTTL(t *testing.T) {
+	s, prefix := newTestRedis(t, redisTestDB, 0)
+	ctx := context.Background()
+
+	if err := s.Set(ctx, prefix+"short", []byte("v"), 100*time.Millisecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.Set(ctx, prefix+"forever", []byte("v"), 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, ok, _ := s.Get(ctx, prefix+"short"); !ok {
+		t.Fatal("Get before expiry missed")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if _, ok, _ := s.Get(ctx, prefix+"short"); ok {
+		t.Error("Get after expiry hit")
+	}
+	if _, ok, _ := s.Get(ctx, prefix+"forever"); !ok {
+		t.Error("Get of key without TTL missed")
+	}
+}
+
+func TestRedisDeletePrefix(t *testing.T) {
+	s, prefix := newTestRedis(t, redisTestDB, 0)
+	ctx := context.Background()
+
+	// More keys than one SCAN page, plus keys that only look alike
+	n := 3*redisScanCount + 7
+	for i := 0; i < n; i++ {
+		if err := s.Set(ctx, fmt.Sprintf("%sfoods:%d", prefix, i), []byte("v"), time.Minute); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+	kept := []string{prefix + "food", prefix + "meals:1", prefix + "foods*literal"}
+	for _, key := range kept[:2] {
+		if err := s.Set(ctx, key, []byte("v"), time.Minute); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+
+	if err := s.DeletePrefix(ctx, prefix+"foods:"); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if _, ok, _ := s.Get(ctx, fmt.Sprintf("%sfoods:%d", prefix, i)); ok {
+			t.Fatalf("key %d survived DeletePrefix", i)
+		}
+	}
+	for _, key := range kept[:2] {
+		if _, ok, _ := s.Get(ctx, key); !ok {
+			t.Errorf("DeletePrefix removed %q", key)
+		}
+	}
+
+	// Glob characters in the prefix match literally
+	if err := s.Set(ctx, kept[2], []byte("v"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.DeletePrefix(ctx, prefix+"foo*"); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	if _, ok, _ := s.Get(ctx, prefix+"food"); !ok {
+		t.Error("DeletePrefix treated * as a wildcard")
+	}
+}
+
+func TestRedisErrorReplyKeepsConnection(t *testing.T) {
+	// One pooled connection, so every command below shares it
+	s, prefix := newTestRedis(t, redisTestDB, 1)
+	ctx := context.Background()
+
+	if err := s.Set(ctx, prefix+"name", []byte("not a number"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	_, err := s.do(ctx, "INCR", prefix+"name")
+	var replyErr redisError
+	if !errors.As(err, &replyErr) {
+		t.Fatalf("INCR on text = %v, want an error reply", err)
+	}
+	if len(s.idle) != 1 {
+		t.Fatalf("%d idle connections after an error reply, want 1", len(s.idle))
+	}
+
+	got, ok, err := s.Get(ctx, prefix+"name")
+	if err != nil || !ok || string(got) != "not a number" {
+		t.Errorf("Get after error reply = %q, %v, %v", got, ok, err)
+	}
+	if _, err := s.do(ctx, "NOSUCHCOMMAND"); !errors.As(err, &replyErr) {
+		t.Errorf("unknown command = %v, want an error reply", err)
+	}
+	if reply, err := s.do(ctx, "PING"); err != nil || reply != "PONG" {
+		t.Errorf("PING after error reply = %v, %v", reply, err)
+	}
+}
+
+func TestRedisAuthAndSelect(t *testing.T) {
+	s, prefix := newTestRedis(t, redisTestDB, 0)
+	other, _ := newTestRedis(t, redisTestDB-1, 0)
+	ctx := context.Background()
+
+	if err := s.Set(ctx, prefix+"db", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, ok, _ := other.Get(ctx, prefix+"db"); ok {
+		t.Error("key written in one database was read from another")
+	}
+	reply, err := s.do(ctx, "CLIENT", "INFO")
+	if err == nil {
+		if info, _ := reply.([]byte); !hasField(strings.Fields(string(info)), "db="+strconv.Itoa(redisTestDB)) {
+			t.Errorf("CLIENT INFO = %q, want db=%d", info, redisTestDB)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts RedisOptions
+	}{
+		{"wrong password", RedisOptions{Password: "wrong-" + uuid.NewString()}},
+		{"database out of range", RedisOptions{Password: os.Getenv("REDIS_PASSWORD"), DB: 100000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Addr = os.Getenv("REDIS_ADDR")
+			if _, err := NewRedisStore(tt.opts); err == nil {
+				t.Error("NewRedisStore succeeded, want an error")
+			}
+		})
+	}
+}
+
+func hasField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,14 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -9,12 +16,23 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	CORS     CORSConfig
-	OFF      OFFConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	Redis        RedisConfig
+	Cache        CacheConfig
+	JWT          JWTConfig
+	CORS         CORSConfig
+	Food         FoodConfig
+	OFF          OFFConfig
+	USDA         USDAConfig
+	Trash        TrashConfig
+	Storage      StorageConfig
+	Photo        PhotoConfig
+	TDEE         TDEEConfig
+	Notification NotificationConfig
+	Report       ReportConfig
+	Webhook      WebhookConfig
+	Admin        AdminConfig
 }
 
 // ServerConfig holds server configuration
@@ -36,9 +54,24 @@ type DatabaseConfig struct {
 type RedisConfig struct {
 	Host     string
 	Password string
+	DB       int
+
+	// Deprecated: CacheTTL is read from REDIS_CACHE_TTL and only serves as
+	// the default for CACHE_SEARCH_TTL and CACHE_CATALOG_TTL
 	CacheTTL time.Duration
 }
 
+// CacheConfig holds the cache backend and entry lifetimes
+type CacheConfig struct {
+	Backend    string // memory, postgres or redis
+	MaxEntries int    // memory backend only
+	SearchTTL  time.Duration
+	CatalogTTL time.Duration
+	StatsTTL   time.Duration
+
+	CleanupInterval time.Duration // memory and postgres backends
+}
+
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
 	Secret     string
@@ -51,10 +84,102 @@ type CORSConfig struct {
 	AllowedOrigins []string
 }
 
+// FoodConfig holds food search configuration
+type FoodConfig struct {
+	LocalTimeout time.Duration // Thai catalog and custom food providers
+}
+
 // OFFConfig holds Open Food Facts configuration
 type OFFConfig struct {
-	CacheEnabled bool
-	CacheTTL     time.Duration
+	BaseURL          string
+	Timeout          time.Duration // per attempt
+	MaxRetries       int
+	BreakerThreshold int           // consecutive failed calls that open the circuit
+	BreakerCooldown  time.Duration // how long the circuit stays open
+	ProviderTimeout  time.Duration // overall, including retries
+
+	CacheEnabled         bool
+	CacheTTL             time.Duration
+	CacheStaleTTL        time.Duration // served while refreshing after CacheTTL
+	CacheMaxEntries      int           // in-process barcode LRU size
+	CacheCleanupInterval time.Duration
+}
+
+// USDAConfig holds USDA FoodData Central configuration. Without an API key
+// only foods imported from a dataset are searched.
+type USDAConfig struct {
+	Enabled         bool
+	APIKey          string
+	BaseURL         string
+	DataTypes       []string // searched data types
+	Timeout         time.Duration
+	ProviderTimeout time.Duration
+}
+
+// TrashConfig holds soft delete retention configuration
+type TrashConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// TDEEConfig holds adaptive TDEE configuration
+type TDEEConfig struct {
+	EstimateWeeks  int
+	AdjustInterval time.Duration
+}
+
+// NotificationConfig holds reminder scheduling and delivery configuration
+type NotificationConfig struct {
+	Channels          []string // push, email and/or log
+	SchedulerInterval time.Duration
+	VAPIDPublicKey    string
+	VAPIDPrivateKey   string
+	VAPIDSubject      string
+	SMTPHost          string
+	SMTPPort          string
+	SMTPUsername      string
+	SMTPPassword      string
+	SMTPFrom          string
+}
+
+// ReportConfig holds nutrition report rendering and scheduling configuration
+type ReportConfig struct {
+	FontPath          string // TrueType font for PDFs; Thai text needs one with Thai glyphs
+	SchedulerInterval time.Duration
+	SendHour          int // local hour scheduled reports are sent from
+}
+
+// WebhookConfig holds outbound webhook delivery configuration
+type WebhookConfig struct {
+	DispatchInterval time.Duration
+	Timeout          time.Duration // per delivery attempt
+	MaxAttempts      int           // before a delivery is dead-lettered
+	Retention        time.Duration // how long delivered events and logs are kept
+}
+
+// AdminConfig holds admin API configuration
+type AdminConfig struct {
+	Token string // enables the /admin routes when set
+}
+
+// StorageConfig holds blob storage configuration
+type StorageConfig struct {
+	Driver         string // local or s3
+	LocalDir       string
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3UsePathStyle bool
+}
+
+// PhotoConfig holds meal photo upload configuration
+type PhotoConfig struct {
+	MaxUploadSize int64
+	MaxPixels     int
+	URLSecret     string
+	URLTTL        time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -69,6 +194,15 @@ func Load() (*Config, error) {
 		return defaultValue
 	}
 
+	getEnvInt64 := func(key string, defaultValue int64) int64 {
+		if value := os.Getenv(key); value != "" {
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return n
+			}
+		}
+		return defaultValue
+	}
+
 	getEnvDuration := func(key string, defaultValue time.Duration) time.Duration {
 		if value := os.Getenv(key); value != "" {
 			if duration, err := time.ParseDuration(value); err == nil {
@@ -78,7 +212,14 @@ func Load() (*Config, error) {
 		return defaultValue
 	}
 
-	return &Config{
+	// REDIS_CACHE_TTL predates the per-kind CACHE_* TTLs and is kept as
+	// their default
+	redisCacheTTL := getEnvDuration("REDIS_CACHE_TTL", 0)
+	if redisCacheTTL != 0 {
+		log.Println("REDIS_CACHE_TTL is deprecated; set CACHE_SEARCH_TTL and CACHE_CATALOG_TTL instead")
+	}
+
+	cfg := &Config{
 		Server: ServerConfig{
 			Port: getEnv("SERVER_PORT", "8080"),
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
@@ -93,7 +234,17 @@ func Load() (*Config, error) {
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost:6379"),
 			Password: getEnv("REDIS_PASSWORD", ""),
-			CacheTTL: getEnvDuration("REDIS_CACHE_TTL", 168*time.Hour),
+			DB:       int(getEnvInt64("REDIS_DB", 0)),
+			CacheTTL: redisCacheTTL,
+		},
+		Cache: CacheConfig{
+			Backend:    getEnv("CACHE_BACKEND", "memory"),
+			MaxEntries: int(getEnvInt64("CACHE_MAX_ENTRIES", 10000)),
+			SearchTTL:  getEnvDuration("CACHE_SEARCH_TTL", orDefault(redisCacheTTL, time.Hour)),
+			CatalogTTL: getEnvDuration("CACHE_CATALOG_TTL", orDefault(redisCacheTTL, 24*time.Hour)),
+			StatsTTL:   getEnvDuration("CACHE_STATS_TTL", 10*time.Minute),
+
+			CleanupInterval: getEnvDuration("CACHE_CLEANUP_INTERVAL", time.Hour),
 		},
 		JWT: JWTConfig{
 			Secret:     getEnv("JWT_SECRET", "your-super-secret-key-change-this"),
@@ -105,9 +256,125 @@ func Load() (*Config, error) {
 				getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000"),
 			},
 		},
+		Food: FoodConfig{
+			LocalTimeout: getEnvDuration("FOOD_LOCAL_TIMEOUT", 2*time.Second),
+		},
 		OFF: OFFConfig{
-			CacheEnabled: getEnv("OFF_CACHE_ENABLED", "true") == "true",
-			CacheTTL:     getEnvDuration("OFF_CACHE_TTL", 168*time.Hour),
+			BaseURL:          getEnv("OFF_BASE_URL", "https://world.openfoodfacts.org"),
+			Timeout:          getEnvDuration("OFF_TIMEOUT", 10*time.Second),
+			MaxRetries:       int(getEnvInt64("OFF_MAX_RETRIES", 2)),
+			BreakerThreshold: int(getEnvInt64("OFF_BREAKER_THRESHOLD", 5)),
+			BreakerCooldown:  getEnvDuration("OFF_BREAKER_COOLDOWN", 30*time.Second),
+			ProviderTimeout:  getEnvDuration("OFF_PROVIDER_TIMEOUT", 15*time.Second),
+
+			CacheEnabled:         getEnv("OFF_CACHE_ENABLED", "true") == "true",
+			CacheTTL:             getEnvDuration("OFF_CACHE_TTL", 168*time.Hour),
+			CacheStaleTTL:        getEnvDuration("OFF_CACHE_STALE_TTL", 24*time.Hour),
+			CacheMaxEntries:      int(getEnvInt64("OFF_CACHE_MAX_ENTRIES", 5000)),
+			CacheCleanupInterval: getEnvDuration("OFF_CACHE_CLEANUP_INTERVAL", time.Hour),
+		},
+		USDA: USDAConfig{
+			Enabled:         getEnv("USDA_ENABLED", "true") == "true",
+			APIKey:          getEnv("USDA_API_KEY", ""),
+			BaseURL:         getEnv("USDA_BASE_URL", "https://api.nal.usda.gov/fdc"),
+			DataTypes:       strings.Split(getEnv("USDA_DATA_TYPES", "Foundation,SR Legacy"), ","),
+			Timeout:         getEnvDuration("USDA_TIMEOUT", 10*time.Second),
+			ProviderTimeout: getEnvDuration("USDA_PROVIDER_TIMEOUT", 10*time.Second),
+		},
+		Trash: TrashConfig{
+			Retention:     getEnvDuration("TRASH_RETENTION", 720*time.Hour),
+			PurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		},
+		Storage: StorageConfig{
+			Driver:         getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:       getEnv("STORAGE_LOCAL_DIR", "./data/blobs"),
+			S3Endpoint:     getEnv("S3_ENDPOINT", ""),
+			S3Region:       getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:       getEnv("S3_BUCKET", ""),
+			S3AccessKey:    getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:    getEnv("S3_SECRET_KEY", ""),
+			S3UsePathStyle: getEnv("S3_USE_PATH_STYLE", "false") == "true",
+		},
+		Photo: PhotoConfig{
+			MaxUploadSize: getEnvInt64("PHOTO_MAX_UPLOAD_SIZE", 10<<20),
+			MaxPixels:     int(getEnvInt64("PHOTO_MAX_PIXELS", 40_000_000)),
+			URLSecret:     getEnv("PHOTO_URL_SECRET", ""), // derived from JWT_SECRET when unset
+			URLTTL:        getEnvDuration("PHOTO_URL_TTL", 15*time.Minute),
+		},
+		TDEE: TDEEConfig{
+			EstimateWeeks:  int(getEnvInt64("TDEE_ESTIMATE_WEEKS", 4)),
+			AdjustInterval: getEnvDuration("TDEE_ADJUST_INTERVAL", time.Hour),
+		},
+		Notification: NotificationConfig{
+			Channels:          strings.Split(getEnv("NOTIFY_CHANNELS", "log"), ","),
+			SchedulerInterval: getEnvDuration("NOTIFY_SCHEDULER_INTERVAL", time.Minute),
+			VAPIDPublicKey:    getEnv("VAPID_PUBLIC_KEY", ""),
+			VAPIDPrivateKey:   getEnv("VAPID_PRIVATE_KEY", ""),
+			VAPIDSubject:      getEnv("VAPID_SUBJECT", ""),
+			SMTPHost:          getEnv("SMTP_HOST", ""),
+			SMTPPort:          getEnv("SMTP_PORT", "587"),
+			SMTPUsername:      getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:      getEnv("SMTP_PASSWORD", ""),
+			SMTPFrom:          getEnv("SMTP_FROM", "ByteTrack <no-reply@bytetrack.app>"),
+		},
+		Report: ReportConfig{
+			FontPath:          getEnv("REPORT_FONT_PATH", ""),
+			SchedulerInterval: getEnvDuration("REPORT_SCHEDULER_INTERVAL", 15*time.Minute),
+			SendHour:          int(getEnvInt64("REPORT_SEND_HOUR", 7)),
+		},
+		Webhook: WebhookConfig{
+			DispatchInterval: getEnvDuration("WEBHOOK_DISPATCH_INTERVAL", 5*time.Second),
+			Timeout:          getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxAttempts:      int(getEnvInt64("WEBHOOK_MAX_ATTEMPTS", 10)),
+			Retention:        getEnvDuration("WEBHOOK_RETENTION", 30*24*time.Hour),
 		},
-	}, nil
+		Admin: AdminConfig{
+			// WEBHOOK_ADMIN_TOKEN is the older name, from when only webhooks
+			// had admin routes
+			Token: getEnv("ADMIN_TOKEN", getEnv("WEBHOOK_ADMIN_TOKEN", "")),
+		},
+	}
+
+	// Background jobs tick at these intervals, and a ticker can't be zero
+	// or negative
+	intervals := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"TRASH_PURGE_INTERVAL", cfg.Trash.PurgeInterval},
+		{"TDEE_ADJUST_INTERVAL", cfg.TDEE.AdjustInterval},
+		{"NOTIFY_SCHEDULER_INTERVAL", cfg.Notification.SchedulerInterval},
+		{"REPORT_SCHEDULER_INTERVAL", cfg.Report.SchedulerInterval},
+		{"WEBHOOK_DISPATCH_INTERVAL", cfg.Webhook.DispatchInterval},
+		{"OFF_CACHE_CLEANUP_INTERVAL", cfg.OFF.CacheCleanupInterval},
+		{"CACHE_CLEANUP_INTERVAL", cfg.Cache.CleanupInterval},
+	}
+	for _, interval := range intervals {
+		if interval.value <= 0 {
+			return nil, fmt.Errorf("%s must be positive, got %s", interval.name, interval.value)
+		}
+	}
+
+	// Photo URLs are signed with their own key, so a leaked photo URL key
+	// can't be used to forge access tokens
+	if cfg.Photo.URLSecret == "" {
+		cfg.Photo.URLSecret = deriveKey(cfg.JWT.Secret, "photo-urls")
+	}
+
+	return cfg, nil
+}
+
+// orDefault returns d, or fallback when d is zero
+func orDefault(d, fallback time.Duration) time.Duration {
+	if d != 0 {
+		return d
+	}
+	return fallback
+}
+
+// deriveKey derives a key for one purpose from a shared secret
+func deriveKey(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
 }
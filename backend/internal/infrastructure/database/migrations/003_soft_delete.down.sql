@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_custom_foods_deleted_at;
+DROP INDEX IF EXISTS idx_meals_deleted_at;
+
+ALTER TABLE custom_foods DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE meals DROP COLUMN IF EXISTS deleted_at;
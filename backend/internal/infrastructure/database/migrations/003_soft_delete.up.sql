@@ -0,0 +1,8 @@
+-- Soft delete support for meals and custom foods
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE custom_foods ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Partial indexes for trash listing and purge
+CREATE INDEX IF NOT EXISTS idx_meals_deleted_at ON meals(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_custom_foods_deleted_at ON custom_foods(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS version;
+ALTER TABLE meals DROP COLUMN IF EXISTS version;
@@ -0,0 +1,4 @@
+-- Row versions for optimistic concurrency control
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+ALTER TABLE meals DROP COLUMN IF EXISTS thumbnail_key;
+ALTER TABLE meals DROP COLUMN IF EXISTS photo_key;
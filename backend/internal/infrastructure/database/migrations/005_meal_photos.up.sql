@@ -0,0 +1,4 @@
+-- Uploaded meal photos stored in the blob store
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS photo_key TEXT;
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS thumbnail_key TEXT;
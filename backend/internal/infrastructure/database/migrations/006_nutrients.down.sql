@@ -0,0 +1,4 @@
+ALTER TABLE thai_foods DROP COLUMN IF EXISTS nutrients;
+ALTER TABLE favorite_foods DROP COLUMN IF EXISTS nutrients;
+ALTER TABLE custom_foods DROP COLUMN IF EXISTS nutrients;
+ALTER TABLE meals DROP COLUMN IF EXISTS nutrients;
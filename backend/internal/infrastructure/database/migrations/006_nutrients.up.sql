@@ -0,0 +1,6 @@
+-- Extensible nutrient storage keyed by nutrient registry IDs
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS nutrients JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE custom_foods ADD COLUMN IF NOT EXISTS nutrients JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE favorite_foods ADD COLUMN IF NOT EXISTS nutrients JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE thai_foods ADD COLUMN IF NOT EXISTS nutrients JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS water_logs;
+DROP TABLE IF EXISTS water_containers;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS exercise_logs;
+DROP TABLE IF EXISTS exercise_activities;
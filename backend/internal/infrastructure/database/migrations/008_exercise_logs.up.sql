@@ -0,0 +1,72 @@
+-- Exercise logging with a MET activity catalog
+
+-- Activity catalog; built-in rows have no user_id, custom activities belong to a user
+CREATE TABLE IF NOT EXISTS exercise_activities (
+    id VARCHAR(64) PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    name_en VARCHAR(255) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+
+    -- Metabolic equivalents per intensity
+    met_light DECIMAL(4,1) NOT NULL CHECK (met_light > 0 AND met_light <= 25),
+    met_moderate DECIMAL(4,1) NOT NULL CHECK (met_moderate > 0 AND met_moderate <= 25),
+    met_vigorous DECIMAL(4,1) NOT NULL CHECK (met_vigorous > 0 AND met_vigorous <= 25),
+
+    emoji VARCHAR(10),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_exercise_activities_user ON exercise_activities(user_id);
+
+-- Logged sessions keep a snapshot of the activity, MET and weight used
+CREATE TABLE IF NOT EXISTS exercise_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_id VARCHAR(64) REFERENCES exercise_activities(id) ON DELETE SET NULL,
+    activity_name VARCHAR(255) NOT NULL,
+    activity_name_en VARCHAR(255) NOT NULL,
+    intensity VARCHAR(20) NOT NULL CHECK (intensity IN ('light', 'moderate', 'vigorous')),
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0 AND duration_minutes <= 1440),
+    met DECIMAL(4,1) NOT NULL,
+    weight DECIMAL(5,2) NOT NULL,
+    calories_burned INTEGER NOT NULL,
+    notes TEXT,
+    date DATE NOT NULL DEFAULT CURRENT_DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_exercise_logs_user_date ON exercise_logs(user_id, date DESC);
+
+-- Seed from the 2011 Compendium of Physical Activities (nearest entry per intensity)
+INSERT INTO exercise_activities (id, name, name_en, category, met_light, met_moderate, met_vigorous, emoji) VALUES
+('walking', 'เดิน', 'Walking', 'cardio', 2.8, 3.5, 5.0, '🚶'),
+('hiking', 'เดินป่า', 'Hiking', 'cardio', 5.3, 6.0, 7.8, '🥾'),
+('jogging', 'วิ่งเหยาะ', 'Jogging', 'cardio', 6.0, 7.0, 8.3, '🏃'),
+('running', 'วิ่ง', 'Running', 'cardio', 8.3, 9.8, 11.8, '🏃'),
+('cycling', 'ปั่นจักรยาน', 'Cycling', 'cardio', 4.0, 8.0, 10.0, '🚴'),
+('stationary_cycling', 'ปั่นจักรยานอยู่กับที่', 'Stationary cycling', 'cardio', 3.5, 6.8, 8.8, '🚴'),
+('swimming', 'ว่ายน้ำ', 'Swimming', 'cardio', 6.0, 8.3, 9.8, '🏊'),
+('rowing_machine', 'เครื่องกรรเชียงบก', 'Rowing machine', 'cardio', 4.8, 7.0, 8.5, '🚣'),
+('elliptical', 'เครื่องเดินวงรี', 'Elliptical trainer', 'cardio', 4.0, 5.0, 6.0, '🏋️'),
+('stair_climbing', 'ขึ้นบันได', 'Stair climbing', 'cardio', 4.0, 6.0, 8.8, '🪜'),
+('jump_rope', 'กระโดดเชือก', 'Jumping rope', 'cardio', 8.8, 11.8, 12.3, '🪢'),
+('aerobics', 'แอโรบิก', 'Aerobics', 'cardio', 5.0, 6.5, 7.3, '💃'),
+('dancing', 'เต้นรำ', 'Dancing', 'cardio', 3.0, 5.0, 7.3, '💃'),
+('strength', 'เวทเทรนนิ่ง', 'Strength training', 'strength', 3.5, 5.0, 6.0, '🏋️'),
+('calisthenics', 'บอดี้เวท', 'Calisthenics', 'strength', 2.8, 3.8, 8.0, '🤸'),
+('yoga', 'โยคะ', 'Yoga', 'flexibility', 2.5, 3.0, 4.0, '🧘'),
+('pilates', 'พิลาทิส', 'Pilates', 'flexibility', 2.8, 3.0, 3.8, '🧘'),
+('muay_thai', 'มวยไทย', 'Muay Thai', 'sports', 5.3, 7.8, 10.3, '🥊'),
+('boxing', 'ชกมวย', 'Boxing', 'sports', 5.5, 7.8, 12.8, '🥊'),
+('badminton', 'แบดมินตัน', 'Badminton', 'sports', 4.5, 5.5, 7.0, '🏸'),
+('tennis', 'เทนนิส', 'Tennis', 'sports', 4.5, 7.3, 8.0, '🎾'),
+('table_tennis', 'ปิงปอง', 'Table tennis', 'sports', 4.0, 4.0, 4.0, '🏓'),
+('basketball', 'บาสเกตบอล', 'Basketball', 'sports', 4.5, 6.5, 8.0, '🏀'),
+('soccer', 'ฟุตบอล', 'Soccer', 'sports', 5.0, 7.0, 10.0, '⚽'),
+('volleyball', 'วอลเลย์บอล', 'Volleyball', 'sports', 3.0, 4.0, 6.0, '🏐'),
+('sepak_takraw', 'ตะกร้อ', 'Sepak takraw', 'sports', 4.0, 5.0, 7.0, '🏐'),
+('housework', 'ทำงานบ้าน', 'Housework', 'daily', 2.3, 3.3, 3.8, '🧹'),
+('gardening', 'ทำสวน', 'Gardening', 'daily', 2.3, 3.8, 5.0, '🌱')
+ON CONFLICT (id) DO NOTHING;
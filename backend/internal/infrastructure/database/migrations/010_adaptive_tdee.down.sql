@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_profiles_adaptive_tdee;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS tdee_reviewed_at;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS adjusted_tdee;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS adaptive_tdee;
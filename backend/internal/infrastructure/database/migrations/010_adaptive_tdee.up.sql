@@ -0,0 +1,7 @@
+-- Opt-in adaptive TDEE estimated from logged intake and weight trend
+
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS adaptive_tdee BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS adjusted_tdee INTEGER;
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS tdee_reviewed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_user_profiles_adaptive_tdee ON user_profiles(tdee_reviewed_at) WHERE adaptive_tdee;
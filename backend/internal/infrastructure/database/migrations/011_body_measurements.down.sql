@@ -0,0 +1,3 @@
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS body_fat_percent;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS bmr_formula;
+DROP TABLE IF EXISTS body_measurements;
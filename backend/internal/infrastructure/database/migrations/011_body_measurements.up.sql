@@ -0,0 +1,21 @@
+-- Body composition measurements and BMR formula selection
+
+CREATE TABLE IF NOT EXISTS body_measurements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL DEFAULT CURRENT_DATE,
+    body_fat_percent DECIMAL(4,1) CHECK (body_fat_percent BETWEEN 2 AND 70),
+    waist_cm DECIMAL(5,1) CHECK (waist_cm BETWEEN 20 AND 250),
+    hip_cm DECIMAL(5,1) CHECK (hip_cm BETWEEN 20 AND 250),
+    neck_cm DECIMAL(5,1) CHECK (neck_cm BETWEEN 20 AND 250),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_body_measurements_user_date ON body_measurements(user_id, date);
+
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS bmr_formula VARCHAR(20) NOT NULL DEFAULT 'mifflin_st_jeor'
+    CHECK (bmr_formula IN ('mifflin_st_jeor', 'katch_mcardle', 'cunningham'));
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS body_fat_percent DECIMAL(4,1);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS achievement_counters;
+DROP TABLE IF EXISTS streak_days;
+DROP TABLE IF EXISTS user_achievements;
+DROP TABLE IF EXISTS achievements;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,62 @@
+-- Logging streaks and achievements
+
+-- Streaks and "today" are evaluated in the user's timezone
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Bangkok';
+
+-- Achievement catalog; an achievement unlocks when its metric reaches the threshold
+CREATE TABLE IF NOT EXISTS achievements (
+    id VARCHAR(64) PRIMARY KEY,
+    category VARCHAR(32) NOT NULL,
+    metric VARCHAR(32) NOT NULL,
+    threshold DECIMAL(8,2) NOT NULL CHECK (threshold > 0),
+    name VARCHAR(255) NOT NULL,
+    name_en VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    description_en TEXT NOT NULL,
+    emoji VARCHAR(10),
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+-- Earned badges
+CREATE TABLE IF NOT EXISTS user_achievements (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement_id VARCHAR(64) NOT NULL REFERENCES achievements(id) ON DELETE CASCADE,
+    unlocked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, achievement_id)
+);
+
+-- Days that count towards each streak kind
+CREATE TABLE IF NOT EXISTS streak_days (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL CHECK (kind IN ('logging', 'target', 'protein', 'water')),
+    date DATE NOT NULL,
+    PRIMARY KEY (user_id, kind, date)
+);
+
+-- Event counts that cannot be derived from stored data
+CREATE TABLE IF NOT EXISTS achievement_counters (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    metric VARCHAR(32) NOT NULL,
+    value INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, metric)
+);
+
+INSERT INTO achievements (id, category, metric, threshold, name, name_en, description, description_en, emoji, sort_order) VALUES
+('first_meal', 'logging', 'meals_logged', 1, 'มื้อแรก', 'First Bite', 'บันทึกมื้ออาหารมื้อแรก', 'Log your first meal', '🍽️', 10),
+('meals_100', 'logging', 'meals_logged', 100, 'นักบันทึกตัวยง', 'Centurion', 'บันทึกมื้ออาหารครบ 100 มื้อ', 'Log 100 meals', '📒', 20),
+('meals_500', 'logging', 'meals_logged', 500, 'ปรมาจารย์การบันทึก', 'Log Master', 'บันทึกมื้ออาหารครบ 500 มื้อ', 'Log 500 meals', '📚', 30),
+('streak_3', 'streak', 'logging_streak', 3, 'เริ่มติดนิสัย', 'Getting Started', 'บันทึกอาหารติดต่อกัน 3 วัน', 'Log meals 3 days in a row', '🔥', 40),
+('streak_7', 'streak', 'logging_streak', 7, 'ครบหนึ่งสัปดาห์', 'One Week Strong', 'บันทึกอาหารติดต่อกัน 7 วัน', 'Log meals 7 days in a row', '🔥', 50),
+('streak_30', 'streak', 'logging_streak', 30, 'ครบหนึ่งเดือน', 'Monthly Habit', 'บันทึกอาหารติดต่อกัน 30 วัน', 'Log meals 30 days in a row', '🏅', 60),
+('streak_100', 'streak', 'logging_streak', 100, 'ร้อยวันไม่มีพัก', 'Hundred Days', 'บันทึกอาหารติดต่อกัน 100 วัน', 'Log meals 100 days in a row', '🏆', 70),
+('target_7', 'nutrition', 'target_days', 7, 'คุมแคลได้', 'On Target', 'ทานแคลอรี่อยู่ในเป้าหมาย 7 วัน', 'Stay within your calorie target on 7 days', '🎯', 80),
+('target_streak_7', 'nutrition', 'target_streak', 7, 'แม่นยำทั้งสัปดาห์', 'Bullseye Week', 'ทานแคลอรี่อยู่ในเป้าหมาย 7 วันติดต่อกัน', 'Stay within your calorie target 7 days in a row', '🎯', 90),
+('target_30', 'nutrition', 'target_days', 30, 'วินัยเหล็ก', 'Iron Discipline', 'ทานแคลอรี่อยู่ในเป้าหมาย 30 วัน', 'Stay within your calorie target on 30 days', '💎', 100),
+('protein_streak_7', 'nutrition', 'protein_streak', 7, 'โปรตีนครบทุกวัน', 'Protein Pro', 'ทานโปรตีนถึงเป้าหมาย 7 วันติดต่อกัน', 'Hit your protein goal 7 days in a row', '💪', 110),
+('water_streak_7', 'hydration', 'water_streak', 7, 'ชุ่มชื่นทั้งสัปดาห์', 'Well Hydrated', 'ดื่มน้ำถึงเป้าหมาย 7 วันติดต่อกัน', 'Reach your water goal 7 days in a row', '💧', 120),
+('first_scan', 'logging', 'barcode_scans', 1, 'สแกนครั้งแรก', 'First Scan', 'บันทึกอาหารจากการสแกนบาร์โค้ดครั้งแรก', 'Log a meal from a barcode scan', '📷', 130),
+('first_weigh_in', 'weight', 'weigh_ins', 1, 'ก้าวแรกบนตาชั่ง', 'First Weigh-In', 'บันทึกน้ำหนักครั้งแรก', 'Record your first weight', '⚖️', 140),
+('weigh_ins_30', 'weight', 'weigh_ins', 30, 'ชั่งสม่ำเสมอ', 'Steady Tracker', 'บันทึกน้ำหนักครบ 30 ครั้ง', 'Record your weight 30 times', '📈', 150),
+('lost_1kg', 'weight', 'weight_lost', 1, 'ลดได้ 1 กก.', 'First Kilo', 'น้ำหนักลดลง 1 กิโลกรัมจากครั้งแรกที่บันทึก', 'Lose 1 kg from your first recorded weight', '🌱', 160),
+('lost_5kg', 'weight', 'weight_lost', 5, 'ลดได้ 5 กก.', 'Five Down', 'น้ำหนักลดลง 5 กิโลกรัมจากครั้งแรกที่บันทึก', 'Lose 5 kg from your first recorded weight', '🌟', 170)
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS fasting_preferences;
+DROP TABLE IF EXISTS fasts;
+DROP INDEX IF EXISTS idx_meals_user_eaten_at;
+ALTER TABLE meals DROP COLUMN IF EXISTS eaten_at;
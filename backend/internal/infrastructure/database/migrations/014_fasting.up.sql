@@ -0,0 +1,32 @@
+-- Meal times and intermittent fasting
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS eaten_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_meals_user_eaten_at ON meals(user_id, eaten_at DESC)
+    WHERE eaten_at IS NOT NULL AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS fasts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    protocol VARCHAR(20) NOT NULL CHECK (protocol IN ('16:8', '18:6', '20:4', 'omad', 'custom')),
+    target_hours DECIMAL(4,1) NOT NULL CHECK (target_hours > 0 AND target_hours <= 72),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    broken BOOLEAN NOT NULL DEFAULT FALSE, -- a meal was logged inside the fasting window
+    broken_by_meal_id UUID REFERENCES meals(id) ON DELETE SET NULL,
+    auto_started BOOLEAN NOT NULL DEFAULT FALSE,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fasts_user_started ON fasts(user_id, started_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fasts_user_active ON fasts(user_id) WHERE ended_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS fasting_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    protocol VARCHAR(20) NOT NULL DEFAULT '16:8' CHECK (protocol IN ('16:8', '18:6', '20:4', 'omad', 'custom')),
+    target_hours DECIMAL(4,1) NOT NULL DEFAULT 16 CHECK (target_hours > 0 AND target_hours <= 72),
+    auto_start BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,14 @@
+-- Emailed nutrition report subscriptions
+
+CREATE TABLE IF NOT EXISTS report_schedules (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    weekly BOOLEAN NOT NULL DEFAULT FALSE,
+    monthly BOOLEAN NOT NULL DEFAULT FALSE,
+    language VARCHAR(2) CHECK (language IN ('th', 'en')),
+    attach_pdf BOOLEAN NOT NULL DEFAULT TRUE,
+    last_weekly_on DATE, -- local date the last weekly report was sent
+    last_monthly_on DATE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_schedules_enabled ON report_schedules(user_id) WHERE weekly OR monthly;
@@ -0,0 +1,11 @@
+-- Shared cache entries for the Postgres cache backend
+
+CREATE TABLE IF NOT EXISTS cache_entries (
+    key TEXT PRIMARY KEY,
+    value BYTEA NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE -- NULL never expires
+);
+
+-- Prefix deletes (namespace invalidation) and expiry cleanup
+CREATE INDEX IF NOT EXISTS idx_cache_entries_key_pattern ON cache_entries(key text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_cache_entries_expires ON cache_entries(expires_at) WHERE expires_at IS NOT NULL;
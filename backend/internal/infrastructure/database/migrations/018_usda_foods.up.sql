@@ -0,0 +1,13 @@
+-- USDA FoodData Central foods, cached from the API or imported from a dataset
+
+CREATE TABLE IF NOT EXISTS usda_foods (
+    fdc_id INTEGER PRIMARY KEY,
+    description TEXT NOT NULL,
+    data_type VARCHAR(50) NOT NULL,
+    barcode VARCHAR(50),
+    food JSONB NOT NULL,
+    source VARCHAR(20) NOT NULL, -- api or dataset
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_usda_foods_barcode ON usda_foods(barcode) WHERE barcode IS NOT NULL;
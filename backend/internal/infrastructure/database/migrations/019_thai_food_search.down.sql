@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_thai_foods_search_text;
+ALTER TABLE thai_foods DROP COLUMN IF EXISTS search_text;
+CREATE INDEX IF NOT EXISTS idx_thai_foods_name ON thai_foods USING gin(to_tsvector('english', name || ' ' || name_en));
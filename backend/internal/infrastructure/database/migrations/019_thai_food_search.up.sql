@@ -0,0 +1,12 @@
+-- Thai-aware food search. search_text holds each food's segmented,
+-- tone-folded words and romanized phonetic keys; the API fills it in at
+-- startup as segmentation happens in Go.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- The english tsvector index could not tokenize Thai and was never used
+DROP INDEX IF EXISTS idx_thai_foods_name;
+
+ALTER TABLE thai_foods ADD COLUMN IF NOT EXISTS search_text TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_thai_foods_search_text ON thai_foods USING gin(search_text gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_meals_user_food;
+ALTER TABLE meals DROP COLUMN IF EXISTS food_id;
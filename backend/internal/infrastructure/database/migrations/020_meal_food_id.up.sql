@@ -0,0 +1,7 @@
+-- Record the food each meal was logged from, for recent and frequent foods
+-- and personalized search ranking
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS food_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_meals_user_food ON meals(user_id, food_id, date DESC)
+    WHERE food_id IS NOT NULL AND deleted_at IS NULL;
@@ -83,6 +83,96 @@ func GetMigrations() []Migration {
 			Up:   migration002Up,
 			Down: migration002Down,
 		},
+		{
+			Name: "003_soft_delete",
+			Up:   migration003Up,
+			Down: migration003Down,
+		},
+		{
+			Name: "004_row_versions",
+			Up:   migration004Up,
+			Down: migration004Down,
+		},
+		{
+			Name: "005_meal_photos",
+			Up:   migration005Up,
+			Down: migration005Down,
+		},
+		{
+			Name: "006_nutrients",
+			Up:   migration006Up,
+			Down: migration006Down,
+		},
+		{
+			Name: "007_water_logs",
+			Up:   migration007Up,
+			Down: migration007Down,
+		},
+		{
+			Name: "008_exercise_logs",
+			Up:   migration008Up,
+			Down: migration008Down,
+		},
+		{
+			Name: "009_weight_entries",
+			Up:   migration009Up,
+			Down: migration009Down,
+		},
+		{
+			Name: "010_adaptive_tdee",
+			Up:   migration010Up,
+			Down: migration010Down,
+		},
+		{
+			Name: "011_body_measurements",
+			Up:   migration011Up,
+			Down: migration011Down,
+		},
+		{
+			Name: "012_achievements",
+			Up:   migration012Up,
+			Down: migration012Down,
+		},
+		{
+			Name: "013_notifications",
+			Up:   migration013Up,
+			Down: migration013Down,
+		},
+		{
+			Name: "014_fasting",
+			Up:   migration014Up,
+			Down: migration014Down,
+		},
+		{
+			Name: "015_reports",
+			Up:   migration015Up,
+			Down: migration015Down,
+		},
+		{
+			Name: "016_webhooks",
+			Up:   migration016Up,
+			Down: migration016Down,
+		},
+		{
+			Name: "017_cache_entries",
+			Up:   migration017Up,
+			Down: migration017Down,
+		},
+		{
+			Name: "018_usda_foods",
+			Up:   migration018Up,
+			Down: migration018Down,
+		},
+		{
+			Name: "019_thai_food_search",
+			Up:   migration019Up,
+			Down: migration019Down,
+		},
+		{
+			Name: "020_meal_food_id",
+			Up:   migration020Up,
+			Down: migration020Down,
+		},
 	}
 }
 
@@ -331,5 +421,570 @@ INSERT INTO thai_foods (id, name, name_en, category, calories, protein, carbs, f
 
 	migration002Down = `
 DROP TABLE IF EXISTS thai_foods;
+`
+
+	migration003Up = `
+-- Soft delete support for meals and custom foods
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE custom_foods ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Partial indexes for trash listing and purge
+CREATE INDEX IF NOT EXISTS idx_meals_deleted_at ON meals(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_custom_foods_deleted_at ON custom_foods(deleted_at) WHERE deleted_at IS NOT NULL;
+`
+
+	migration003Down = `
+DROP INDEX IF EXISTS idx_custom_foods_deleted_at;
+DROP INDEX IF EXISTS idx_meals_deleted_at;
+
+ALTER TABLE custom_foods DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE meals DROP COLUMN IF EXISTS deleted_at;
+`
+
+	migration004Up = `
+-- Row versions for optimistic concurrency control
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+`
+
+	migration004Down = `
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS version;
+ALTER TABLE meals DROP COLUMN IF EXISTS version;
+`
+
+	migration005Up = `
+-- Uploaded meal photos stored in the blob store
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS photo_key TEXT;
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS thumbnail_key TEXT;
+`
+
+	migration005Down = `
+ALTER TABLE meals DROP COLUMN IF EXISTS thumbnail_key;
+ALTER TABLE meals DROP COLUMN IF EXISTS photo_key;
+`
+
+	migration006Up = `
+-- Extensible nutrient storage keyed by nutrient registry IDs
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS nutrients JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE custom_foods ADD COLUMN IF NOT EXISTS nutrients JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE favorite_foods ADD COLUMN IF NOT EXISTS nutrients JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE thai_foods ADD COLUMN IF NOT EXISTS nutrients JSONB NOT NULL DEFAULT '{}';
+`
+
+	migration006Down = `
+ALTER TABLE thai_foods DROP COLUMN IF EXISTS nutrients;
+ALTER TABLE favorite_foods DROP COLUMN IF EXISTS nutrients;
+ALTER TABLE custom_foods DROP COLUMN IF EXISTS nutrients;
+ALTER TABLE meals DROP COLUMN IF EXISTS nutrients;
+`
+
+	migration007Up = `
+-- Water intake logging with reusable container sizes
+
+CREATE TABLE IF NOT EXISTS water_containers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    amount_ml INTEGER NOT NULL CHECK (amount_ml > 0 AND amount_ml <= 5000),
+    emoji VARCHAR(10),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_water_containers_user ON water_containers(user_id);
+
+CREATE TABLE IF NOT EXISTS water_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount_ml INTEGER NOT NULL CHECK (amount_ml > 0 AND amount_ml <= 5000),
+    container_id UUID REFERENCES water_containers(id) ON DELETE SET NULL,
+    date DATE NOT NULL DEFAULT CURRENT_DATE,
+    logged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_water_logs_user_date ON water_logs(user_id, date DESC);
+`
+
+	migration007Down = `
+DROP TABLE IF EXISTS water_logs;
+DROP TABLE IF EXISTS water_containers;
+`
+
+	migration008Up = `
+-- Exercise logging with a MET activity catalog
+
+-- Activity catalog; built-in rows have no user_id, custom activities belong to a user
+CREATE TABLE IF NOT EXISTS exercise_activities (
+    id VARCHAR(64) PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    name_en VARCHAR(255) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+
+    -- Metabolic equivalents per intensity
+    met_light DECIMAL(4,1) NOT NULL CHECK (met_light > 0 AND met_light <= 25),
+    met_moderate DECIMAL(4,1) NOT NULL CHECK (met_moderate > 0 AND met_moderate <= 25),
+    met_vigorous DECIMAL(4,1) NOT NULL CHECK (met_vigorous > 0 AND met_vigorous <= 25),
+
+    emoji VARCHAR(10),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_exercise_activities_user ON exercise_activities(user_id);
+
+-- Logged sessions keep a snapshot of the activity, MET and weight used
+CREATE TABLE IF NOT EXISTS exercise_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_id VARCHAR(64) REFERENCES exercise_activities(id) ON DELETE SET NULL,
+    activity_name VARCHAR(255) NOT NULL,
+    activity_name_en VARCHAR(255) NOT NULL,
+    intensity VARCHAR(20) NOT NULL CHECK (intensity IN ('light', 'moderate', 'vigorous')),
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0 AND duration_minutes <= 1440),
+    met DECIMAL(4,1) NOT NULL,
+    weight DECIMAL(5,2) NOT NULL,
+    calories_burned INTEGER NOT NULL,
+    notes TEXT,
+    date DATE NOT NULL DEFAULT CURRENT_DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_exercise_logs_user_date ON exercise_logs(user_id, date DESC);
+
+-- Seed from the 2011 Compendium of Physical Activities (nearest entry per intensity)
+INSERT INTO exercise_activities (id, name, name_en, category, met_light, met_moderate, met_vigorous, emoji) VALUES
+('walking', 'เดิน', 'Walking', 'cardio', 2.8, 3.5, 5.0, '🚶'),
+('hiking', 'เดินป่า', 'Hiking', 'cardio', 5.3, 6.0, 7.8, '🥾'),
+('jogging', 'วิ่งเหยาะ', 'Jogging', 'cardio', 6.0, 7.0, 8.3, '🏃'),
+('running', 'วิ่ง', 'Running', 'cardio', 8.3, 9.8, 11.8, '🏃'),
+('cycling', 'ปั่นจักรยาน', 'Cycling', 'cardio', 4.0, 8.0, 10.0, '🚴'),
+('stationary_cycling', 'ปั่นจักรยานอยู่กับที่', 'Stationary cycling', 'cardio', 3.5, 6.8, 8.8, '🚴'),
+('swimming', 'ว่ายน้ำ', 'Swimming', 'cardio', 6.0, 8.3, 9.8, '🏊'),
+('rowing_machine', 'เครื่องกรรเชียงบก', 'Rowing machine', 'cardio', 4.8, 7.0, 8.5, '🚣'),
+('elliptical', 'เครื่องเดินวงรี', 'Elliptical trainer', 'cardio', 4.0, 5.0, 6.0, '🏋️'),
+('stair_climbing', 'ขึ้นบันได', 'Stair climbing', 'cardio', 4.0, 6.0, 8.8, '🪜'),
+('jump_rope', 'กระโดดเชือก', 'Jumping rope', 'cardio', 8.8, 11.8, 12.3, '🪢'),
+('aerobics', 'แอโรบิก', 'Aerobics', 'cardio', 5.0, 6.5, 7.3, '💃'),
+('dancing', 'เต้นรำ', 'Dancing', 'cardio', 3.0, 5.0, 7.3, '💃'),
+('strength', 'เวทเทรนนิ่ง', 'Strength training', 'strength', 3.5, 5.0, 6.0, '🏋️'),
+('calisthenics', 'บอดี้เวท', 'Calisthenics', 'strength', 2.8, 3.8, 8.0, '🤸'),
+('yoga', 'โยคะ', 'Yoga', 'flexibility', 2.5, 3.0, 4.0, '🧘'),
+('pilates', 'พิลาทิส', 'Pilates', 'flexibility', 2.8, 3.0, 3.8, '🧘'),
+('muay_thai', 'มวยไทย', 'Muay Thai', 'sports', 5.3, 7.8, 10.3, '🥊'),
+('boxing', 'ชกมวย', 'Boxing', 'sports', 5.5, 7.8, 12.8, '🥊'),
+('badminton', 'แบดมินตัน', 'Badminton', 'sports', 4.5, 5.5, 7.0, '🏸'),
+('tennis', 'เทนนิส', 'Tennis', 'sports', 4.5, 7.3, 8.0, '🎾'),
+('table_tennis', 'ปิงปอง', 'Table tennis', 'sports', 4.0, 4.0, 4.0, '🏓'),
+('basketball', 'บาสเกตบอล', 'Basketball', 'sports', 4.5, 6.5, 8.0, '🏀'),
+('soccer', 'ฟุตบอล', 'Soccer', 'sports', 5.0, 7.0, 10.0, '⚽'),
+('volleyball', 'วอลเลย์บอล', 'Volleyball', 'sports', 3.0, 4.0, 6.0, '🏐'),
+('sepak_takraw', 'ตะกร้อ', 'Sepak takraw', 'sports', 4.0, 5.0, 7.0, '🏐'),
+('housework', 'ทำงานบ้าน', 'Housework', 'daily', 2.3, 3.3, 3.8, '🧹'),
+('gardening', 'ทำสวน', 'Gardening', 'daily', 2.3, 3.8, 5.0, '🌱')
+ON CONFLICT (id) DO NOTHING;
+`
+
+	migration008Down = `
+DROP TABLE IF EXISTS exercise_logs;
+DROP TABLE IF EXISTS exercise_activities;
+`
+
+	migration009Up = `
+-- Body weight time series, one entry per user per day
+
+CREATE TABLE IF NOT EXISTS weight_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    weight DECIMAL(5,2) NOT NULL CHECK (weight >= 30 AND weight <= 300),
+    date DATE NOT NULL DEFAULT CURRENT_DATE,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_weight_entries_user_date ON weight_entries(user_id, date DESC);
+`
+
+	migration009Down = `
+DROP TABLE IF EXISTS weight_entries;
+`
+
+	migration010Up = `
+-- Opt-in adaptive TDEE estimated from logged intake and weight trend
+
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS adaptive_tdee BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS adjusted_tdee INTEGER;
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS tdee_reviewed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_user_profiles_adaptive_tdee ON user_profiles(tdee_reviewed_at) WHERE adaptive_tdee;
+`
+
+	migration010Down = `
+DROP INDEX IF EXISTS idx_user_profiles_adaptive_tdee;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS tdee_reviewed_at;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS adjusted_tdee;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS adaptive_tdee;
+`
+
+	migration011Up = `
+-- Body composition measurements and BMR formula selection
+
+CREATE TABLE IF NOT EXISTS body_measurements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL DEFAULT CURRENT_DATE,
+    body_fat_percent DECIMAL(4,1) CHECK (body_fat_percent BETWEEN 2 AND 70),
+    waist_cm DECIMAL(5,1) CHECK (waist_cm BETWEEN 20 AND 250),
+    hip_cm DECIMAL(5,1) CHECK (hip_cm BETWEEN 20 AND 250),
+    neck_cm DECIMAL(5,1) CHECK (neck_cm BETWEEN 20 AND 250),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_body_measurements_user_date ON body_measurements(user_id, date);
+
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS bmr_formula VARCHAR(20) NOT NULL DEFAULT 'mifflin_st_jeor'
+    CHECK (bmr_formula IN ('mifflin_st_jeor', 'katch_mcardle', 'cunningham'));
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS body_fat_percent DECIMAL(4,1);
+`
+
+	migration011Down = `
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS body_fat_percent;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS bmr_formula;
+DROP TABLE IF EXISTS body_measurements;
+`
+
+	migration012Up = `
+-- Logging streaks and achievements
+
+-- Streaks and "today" are evaluated in the user's timezone
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Bangkok';
+
+-- Achievement catalog; an achievement unlocks when its metric reaches the threshold
+CREATE TABLE IF NOT EXISTS achievements (
+    id VARCHAR(64) PRIMARY KEY,
+    category VARCHAR(32) NOT NULL,
+    metric VARCHAR(32) NOT NULL,
+    threshold DECIMAL(8,2) NOT NULL CHECK (threshold > 0),
+    name VARCHAR(255) NOT NULL,
+    name_en VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    description_en TEXT NOT NULL,
+    emoji VARCHAR(10),
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+-- Earned badges
+CREATE TABLE IF NOT EXISTS user_achievements (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement_id VARCHAR(64) NOT NULL REFERENCES achievements(id) ON DELETE CASCADE,
+    unlocked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, achievement_id)
+);
+
+-- Days that count towards each streak kind
+CREATE TABLE IF NOT EXISTS streak_days (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL CHECK (kind IN ('logging', 'target', 'protein', 'water')),
+    date DATE NOT NULL,
+    PRIMARY KEY (user_id, kind, date)
+);
+
+-- Event counts that cannot be derived from stored data
+CREATE TABLE IF NOT EXISTS achievement_counters (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    metric VARCHAR(32) NOT NULL,
+    value INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, metric)
+);
+
+INSERT INTO achievements (id, category, metric, threshold, name, name_en, description, description_en, emoji, sort_order) VALUES
+('first_meal', 'logging', 'meals_logged', 1, 'มื้อแรก', 'First Bite', 'บันทึกมื้ออาหารมื้อแรก', 'Log your first meal', '🍽️', 10),
+('meals_100', 'logging', 'meals_logged', 100, 'นักบันทึกตัวยง', 'Centurion', 'บันทึกมื้ออาหารครบ 100 มื้อ', 'Log 100 meals', '📒', 20),
+('meals_500', 'logging', 'meals_logged', 500, 'ปรมาจารย์การบันทึก', 'Log Master', 'บันทึกมื้ออาหารครบ 500 มื้อ', 'Log 500 meals', '📚', 30),
+('streak_3', 'streak', 'logging_streak', 3, 'เริ่มติดนิสัย', 'Getting Started', 'บันทึกอาหารติดต่อกัน 3 วัน', 'Log meals 3 days in a row', '🔥', 40),
+('streak_7', 'streak', 'logging_streak', 7, 'ครบหนึ่งสัปดาห์', 'One Week Strong', 'บันทึกอาหารติดต่อกัน 7 วัน', 'Log meals 7 days in a row', '🔥', 50),
+('streak_30', 'streak', 'logging_streak', 30, 'ครบหนึ่งเดือน', 'Monthly Habit', 'บันทึกอาหารติดต่อกัน 30 วัน', 'Log meals 30 days in a row', '🏅', 60),
+('streak_100', 'streak', 'logging_streak', 100, 'ร้อยวันไม่มีพัก', 'Hundred Days', 'บันทึกอาหารติดต่อกัน 100 วัน', 'Log meals 100 days in a row', '🏆', 70),
+('target_7', 'nutrition', 'target_days', 7, 'คุมแคลได้', 'On Target', 'ทานแคลอรี่อยู่ในเป้าหมาย 7 วัน', 'Stay within your calorie target on 7 days', '🎯', 80),
+('target_streak_7', 'nutrition', 'target_streak', 7, 'แม่นยำทั้งสัปดาห์', 'Bullseye Week', 'ทานแคลอรี่อยู่ในเป้าหมาย 7 วันติดต่อกัน', 'Stay within your calorie target 7 days in a row', '🎯', 90),
+('target_30', 'nutrition', 'target_days', 30, 'วินัยเหล็ก', 'Iron Discipline', 'ทานแคลอรี่อยู่ในเป้าหมาย 30 วัน', 'Stay within your calorie target on 30 days', '💎', 100),
+('protein_streak_7', 'nutrition', 'protein_streak', 7, 'โปรตีนครบทุกวัน', 'Protein Pro', 'ทานโปรตีนถึงเป้าหมาย 7 วันติดต่อกัน', 'Hit your protein goal 7 days in a row', '💪', 110),
+('water_streak_7', 'hydration', 'water_streak', 7, 'ชุ่มชื่นทั้งสัปดาห์', 'Well Hydrated', 'ดื่มน้ำถึงเป้าหมาย 7 วันติดต่อกัน', 'Reach your water goal 7 days in a row', '💧', 120),
+('first_scan', 'logging', 'barcode_scans', 1, 'สแกนครั้งแรก', 'First Scan', 'บันทึกอาหารจากการสแกนบาร์โค้ดครั้งแรก', 'Log a meal from a barcode scan', '📷', 130),
+('first_weigh_in', 'weight', 'weigh_ins', 1, 'ก้าวแรกบนตาชั่ง', 'First Weigh-In', 'บันทึกน้ำหนักครั้งแรก', 'Record your first weight', '⚖️', 140),
+('weigh_ins_30', 'weight', 'weigh_ins', 30, 'ชั่งสม่ำเสมอ', 'Steady Tracker', 'บันทึกน้ำหนักครบ 30 ครั้ง', 'Record your weight 30 times', '📈', 150),
+('lost_1kg', 'weight', 'weight_lost', 1, 'ลดได้ 1 กก.', 'First Kilo', 'น้ำหนักลดลง 1 กิโลกรัมจากครั้งแรกที่บันทึก', 'Lose 1 kg from your first recorded weight', '🌱', 160),
+('lost_5kg', 'weight', 'weight_lost', 5, 'ลดได้ 5 กก.', 'Five Down', 'น้ำหนักลดลง 5 กิโลกรัมจากครั้งแรกที่บันทึก', 'Lose 5 kg from your first recorded weight', '🌟', 170)
+ON CONFLICT (id) DO NOTHING;
+`
+
+	migration012Down = `
+DROP TABLE IF EXISTS achievement_counters;
+DROP TABLE IF EXISTS streak_days;
+DROP TABLE IF EXISTS user_achievements;
+DROP TABLE IF EXISTS achievements;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS timezone;
+`
+
+	migration013Up = `
+-- Reminders, notification settings and Web Push subscriptions
+
+CREATE TABLE IF NOT EXISTS reminders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('meal', 'water', 'weigh_in')),
+    meal_type VARCHAR(20) CHECK (meal_type IN ('breakfast', 'lunch', 'dinner', 'snack')),
+    remind_at TIME NOT NULL, -- local time in the profile timezone
+    weekdays INT[] NOT NULL DEFAULT '{}', -- 0 = Sunday; empty means every day
+    language VARCHAR(5) CHECK (language IN ('th', 'en')), -- NULL uses the profile language
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_on DATE, -- local date the reminder was last handled
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((kind = 'meal') = (meal_type IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_user ON reminders(user_id);
+CREATE INDEX IF NOT EXISTS idx_reminders_enabled ON reminders(remind_at) WHERE enabled;
+
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    quiet_start TIME,
+    quiet_end TIME,
+    push_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    email_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS push_subscriptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    endpoint TEXT NOT NULL UNIQUE,
+    p256dh VARCHAR(128) NOT NULL,
+    auth VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_subscriptions_user ON push_subscriptions(user_id);
+`
+
+	migration013Down = `
+DROP TABLE IF EXISTS push_subscriptions;
+DROP TABLE IF EXISTS notification_settings;
+DROP TABLE IF EXISTS reminders;
+`
+
+	migration014Up = `
+-- Meal times and intermittent fasting
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS eaten_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_meals_user_eaten_at ON meals(user_id, eaten_at DESC)
+    WHERE eaten_at IS NOT NULL AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS fasts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    protocol VARCHAR(20) NOT NULL CHECK (protocol IN ('16:8', '18:6', '20:4', 'omad', 'custom')),
+    target_hours DECIMAL(4,1) NOT NULL CHECK (target_hours > 0 AND target_hours <= 72),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    broken BOOLEAN NOT NULL DEFAULT FALSE, -- a meal was logged inside the fasting window
+    broken_by_meal_id UUID REFERENCES meals(id) ON DELETE SET NULL,
+    auto_started BOOLEAN NOT NULL DEFAULT FALSE,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fasts_user_started ON fasts(user_id, started_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fasts_user_active ON fasts(user_id) WHERE ended_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS fasting_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    protocol VARCHAR(20) NOT NULL DEFAULT '16:8' CHECK (protocol IN ('16:8', '18:6', '20:4', 'omad', 'custom')),
+    target_hours DECIMAL(4,1) NOT NULL DEFAULT 16 CHECK (target_hours > 0 AND target_hours <= 72),
+    auto_start BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+	migration014Down = `
+DROP TABLE IF EXISTS fasting_preferences;
+DROP TABLE IF EXISTS fasts;
+DROP INDEX IF EXISTS idx_meals_user_eaten_at;
+ALTER TABLE meals DROP COLUMN IF EXISTS eaten_at;
+`
+
+	migration015Up = `
+-- Emailed nutrition report subscriptions
+
+CREATE TABLE IF NOT EXISTS report_schedules (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    weekly BOOLEAN NOT NULL DEFAULT FALSE,
+    monthly BOOLEAN NOT NULL DEFAULT FALSE,
+    language VARCHAR(2) CHECK (language IN ('th', 'en')),
+    attach_pdf BOOLEAN NOT NULL DEFAULT TRUE,
+    last_weekly_on DATE, -- local date the last weekly report was sent
+    last_monthly_on DATE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_schedules_enabled ON report_schedules(user_id) WHERE weekly OR monthly;
+`
+
+	migration015Down = `
+DROP TABLE IF EXISTS report_schedules;
+`
+
+	migration016Up = `
+-- Outbound webhooks with a transactional outbox
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE, -- NULL for app webhooks, which receive every user's events
+    url TEXT NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}', -- empty receives every event
+    secret VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user ON webhooks(user_id);
+
+-- Events are written in the same transaction as the change they describe
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP WITH TIME ZONE -- set once deliveries are queued
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_pending ON webhook_events(created_at) WHERE dispatched_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL REFERENCES webhook_events(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INTEGER,
+    last_error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id UUID PRIMARY KEY,
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    response_body TEXT,
+    duration_ms INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts(delivery_id, attempt);
+`
+
+	migration016Down = `
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS webhooks;
+`
+
+	migration017Up = `
+-- Shared cache entries for the Postgres cache backend
+
+CREATE TABLE IF NOT EXISTS cache_entries (
+    key TEXT PRIMARY KEY,
+    value BYTEA NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE -- NULL never expires
+);
+
+-- Prefix deletes (namespace invalidation) and expiry cleanup
+CREATE INDEX IF NOT EXISTS idx_cache_entries_key_pattern ON cache_entries(key text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_cache_entries_expires ON cache_entries(expires_at) WHERE expires_at IS NOT NULL;
+`
+
+	migration017Down = `
+DROP TABLE IF EXISTS cache_entries;
+`
+
+	migration018Up = `
+-- USDA FoodData Central foods, cached from the API or imported from a dataset
+
+CREATE TABLE IF NOT EXISTS usda_foods (
+    fdc_id INTEGER PRIMARY KEY,
+    description TEXT NOT NULL,
+    data_type VARCHAR(50) NOT NULL,
+    barcode VARCHAR(50),
+    food JSONB NOT NULL,
+    source VARCHAR(20) NOT NULL, -- api or dataset
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_usda_foods_barcode ON usda_foods(barcode) WHERE barcode IS NOT NULL;
+`
+
+	migration018Down = `
+DROP TABLE IF EXISTS usda_foods;
+`
+
+	migration019Up = `
+-- Thai-aware food search. search_text holds each food's segmented,
+-- tone-folded words and romanized phonetic keys; the API fills it in at
+-- startup as segmentation happens in Go.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- The english tsvector index could not tokenize Thai and was never used
+DROP INDEX IF EXISTS idx_thai_foods_name;
+
+ALTER TABLE thai_foods ADD COLUMN IF NOT EXISTS search_text TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_thai_foods_search_text ON thai_foods USING gin(search_text gin_trgm_ops);
+`
+
+	migration019Down = `
+DROP INDEX IF EXISTS idx_thai_foods_search_text;
+ALTER TABLE thai_foods DROP COLUMN IF EXISTS search_text;
+CREATE INDEX IF NOT EXISTS idx_thai_foods_name ON thai_foods USING gin(to_tsvector('english', name || ' ' || name_en));
+`
+
+	migration020Up = `
+-- Record the food each meal was logged from, for recent and frequent foods
+-- and personalized search ranking
+
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS food_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_meals_user_food ON meals(user_id, food_id, date DESC)
+    WHERE food_id IS NOT NULL AND deleted_at IS NULL;
+`
+
+	migration020Down = `
+DROP INDEX IF EXISTS idx_meals_user_food;
+ALTER TABLE meals DROP COLUMN IF EXISTS food_id;
 `
 )
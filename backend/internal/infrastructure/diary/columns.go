@@ -0,0 +1,146 @@
+package diary
+
+import (
+	"strings"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+)
+
+// Fields a column can be mapped to, besides registry nutrient IDs
+const (
+	fieldDate     = "date"
+	fieldTime     = "time"
+	fieldEatenAt  = "eaten_at"
+	fieldMealType = "meal_type"
+	fieldName     = "name"
+	fieldNameEn   = "name_en"
+	fieldGrams    = "grams"
+	fieldQuantity = "quantity"
+	fieldUnits    = "units"
+	fieldCalories = "calories"
+	fieldProtein  = "protein"
+	fieldCarbs    = "carbs"
+	fieldFat      = "fat"
+	fieldFiber    = "fiber"
+	fieldSugar    = "sugar"
+	fieldSodium   = "sodium"
+	fieldDeleted  = "deleted"
+	fieldNote     = "note"
+	fieldIgnore   = "ignore"
+)
+
+var coreFields = map[string]bool{
+	fieldDate: true, fieldTime: true, fieldEatenAt: true, fieldMealType: true,
+	fieldName: true, fieldNameEn: true, fieldGrams: true, fieldQuantity: true,
+	fieldUnits: true, fieldCalories: true, fieldProtein: true, fieldCarbs: true,
+	fieldFat: true, fieldFiber: true, fieldSugar: true, fieldSodium: true,
+	fieldDeleted: true, fieldNote: true, fieldIgnore: true,
+}
+
+// validField reports whether a mapping target is a known field
+func validField(field string) bool {
+	if coreFields[field] {
+		return true
+	}
+	_, ok := entity.LookupNutrient(entity.NutrientID(field))
+	return ok
+}
+
+// byteTrackColumns maps ByteTrack CSV headers, and the Thai headers people
+// tend to use in their own spreadsheets, to fields
+var byteTrackColumns = func() map[string]string {
+	columns := map[string]string{
+		"วันที่":  fieldDate,
+		"เวลา":    fieldTime,
+		"มื้อ":    fieldMealType,
+		"ชื่อ":    fieldName,
+		"อาหาร":   fieldName,
+		"กรัม":    fieldGrams,
+		"แคลอรี่": fieldCalories,
+		"โปรตีน":  fieldProtein,
+		"คาร์บ":   fieldCarbs,
+		"ไขมัน":   fieldFat,
+		"ใยอาหาร": fieldFiber,
+		"น้ำตาล":  fieldSugar,
+		"โซเดียม": fieldSodium,
+	}
+	for _, column := range Columns() {
+		columns[column] = column
+	}
+	return columns
+}()
+
+// myFitnessPalColumns maps the MyFitnessPal "Nutrition Summary" export,
+// which has one row per meal slot and day rather than per food. Vitamin and
+// mineral columns are percentages of the daily value and are ignored.
+var myFitnessPalColumns = map[string]string{
+	"date":                fieldDate,
+	"meal":                fieldMealType,
+	"time":                fieldTime,
+	"calories":            fieldCalories,
+	"fat":                 fieldFat,
+	"saturated fat":       string(entity.NutrientSaturatedFat),
+	"polyunsaturated fat": string(entity.NutrientPolyunsaturated),
+	"monounsaturated fat": string(entity.NutrientMonounsaturated),
+	"trans fat":           string(entity.NutrientTransFat),
+	"cholesterol":         string(entity.NutrientCholesterol),
+	"sodium":              fieldSodium,
+	"potassium":           string(entity.NutrientPotassium),
+	"carbohydrates":       fieldCarbs,
+	"fiber":               fieldFiber,
+	"sugar":               fieldSugar,
+	"protein":             fieldProtein,
+	"note":                fieldNote,
+}
+
+// loseItColumns maps the Lose It! food log export
+var loseItColumns = map[string]string{
+	"date":          fieldDate,
+	"name":          fieldName,
+	"type":          fieldMealType,
+	"quantity":      fieldQuantity,
+	"units":         fieldUnits,
+	"calories":      fieldCalories,
+	"deleted":       fieldDeleted,
+	"fat":           fieldFat,
+	"protein":       fieldProtein,
+	"carbohydrates": fieldCarbs,
+	"saturated fat": string(entity.NutrientSaturatedFat),
+	"sugars":        fieldSugar,
+	"fiber":         fieldFiber,
+	"cholesterol":   string(entity.NutrientCholesterol),
+	"sodium":        fieldSodium,
+}
+
+var sourceColumns = map[entity.ImportSource]map[string]string{
+	entity.ImportByteTrack:    byteTrackColumns,
+	entity.ImportMyFitnessPal: myFitnessPalColumns,
+	entity.ImportLoseIt:       loseItColumns,
+}
+
+// normalizeHeader lowercases a header and drops a trailing unit such as
+// "(g)" or "(mg)"
+func normalizeHeader(header string) string {
+	h := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, utf8BOM)))
+	if i := strings.LastIndex(h, "("); i > 0 && strings.HasSuffix(h, ")") {
+		h = strings.TrimSpace(h[:i])
+	}
+	return strings.Join(strings.Fields(h), " ")
+}
+
+// detectSource guesses which app exported a file from its normalized headers
+func detectSource(headers []string) (entity.ImportSource, bool) {
+	has := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		has[h] = true
+	}
+	switch {
+	case has["meal_type"] || has["มื้อ"]:
+		return entity.ImportByteTrack, true
+	case has["name"] && has["type"] && (has["quantity"] || has["units"]):
+		return entity.ImportLoseIt, true
+	case has["meal"] && has["carbohydrates"]:
+		return entity.ImportMyFitnessPal, true
+	}
+	return "", false
+}
@@ -0,0 +1,190 @@
+package diary
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// buddhistEraOffset converts Buddhist era years, common in Thai files, to
+// Gregorian years
+const buddhistEraOffset = 543
+
+var errInvalidDate = errors.New("invalid date")
+
+type clockTime struct {
+	hour   int
+	minute int
+}
+
+var (
+	clockPattern     = regexp.MustCompile(`(?i)(\d{1,2}):(\d{2})(?::\d{2}(?:\.\d+)?)?\s*([ap]\.?m\.?)?`)
+	thaiClockPattern = regexp.MustCompile(`(\d{1,2})\.(\d{2})\s*น\.?`) // 20.30 น.
+	isoTPattern      = regexp.MustCompile(`(\d)T(\d)`)
+)
+
+// thaiMonths lists full and abbreviated Thai month names, replaced by
+// English abbreviations before parsing. Full names come first so they
+// aren't partly replaced by an abbreviation.
+var thaiMonths = []struct{ thai, english string }{
+	{"มกราคม", "jan"}, {"กุมภาพันธ์", "feb"}, {"มีนาคม", "mar"}, {"เมษายน", "apr"},
+	{"พฤษภาคม", "may"}, {"มิถุนายน", "jun"}, {"กรกฎาคม", "jul"}, {"สิงหาคม", "aug"},
+	{"กันยายน", "sep"}, {"ตุลาคม", "oct"}, {"พฤศจิกายน", "nov"}, {"ธันวาคม", "dec"},
+	{"ม.ค.", "jan"}, {"ก.พ.", "feb"}, {"มี.ค.", "mar"}, {"เม.ย.", "apr"},
+	{"พ.ค.", "may"}, {"มิ.ย.", "jun"}, {"ก.ค.", "jul"}, {"ส.ค.", "aug"},
+	{"ก.ย.", "sep"}, {"ต.ค.", "oct"}, {"พ.ย.", "nov"}, {"ธ.ค.", "dec"},
+}
+
+var englishMonths = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// parseDate reads a date in ISO, numeric or written form, such as
+// 2024-01-05, 05/01/2567, "Jan 5, 2024" or "5 ม.ค. 2567", with an optional
+// time of day. Years of 2400 and later are Buddhist era. Timestamps with a
+// UTC offset are converted to loc.
+func parseDate(s string, l locale, loc *time.Location) (time.Time, *clockTime, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		t = t.In(loc)
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), &clockTime{t.Hour(), t.Minute()}, nil
+	}
+
+	s = isoTPattern.ReplaceAllString(s, "$1 $2")
+	var clock *clockTime
+	m := clockPattern.FindStringIndex(s)
+	if m == nil {
+		m = thaiClockPattern.FindStringIndex(s)
+	}
+	if m != nil {
+		c, ok := parseClock(s[m[0]:m[1]])
+		if !ok {
+			return time.Time{}, nil, errInvalidDate
+		}
+		clock = c
+		s = s[:m[0]] + " " + s[m[1]:]
+	}
+
+	lower := strings.ToLower(s)
+	for _, m := range thaiMonths {
+		lower = strings.ReplaceAll(lower, m.thai, " "+m.english+" ")
+	}
+
+	var nums []string
+	var month time.Month
+	for _, token := range strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if token[0] >= '0' && token[0] <= '9' {
+			// Ordinals such as 1st or 5th
+			token = strings.TrimRightFunc(token, unicode.IsLetter)
+			if _, err := strconv.Atoi(token); err != nil {
+				return time.Time{}, nil, errInvalidDate
+			}
+			nums = append(nums, token)
+			continue
+		}
+		// Month names; weekday names and era markers such as พ.ศ. are ignored
+		if len(token) >= 3 {
+			if m, ok := englishMonths[token[:3]]; ok && month == 0 {
+				month = m
+			}
+		}
+	}
+
+	var day, year string
+	switch {
+	case month != 0 && len(nums) == 2:
+		day, year = nums[0], nums[1]
+		if len(nums[0]) == 4 {
+			day, year = nums[1], nums[0]
+		}
+	case month == 0 && len(nums) == 3:
+		var m string
+		switch {
+		case len(nums[0]) == 4:
+			year, m, day = nums[0], nums[1], nums[2]
+		case l.dayFirst && atoi(nums[1]) <= 12, !l.dayFirst && atoi(nums[0]) > 12:
+			day, m, year = nums[0], nums[1], nums[2]
+		default:
+			m, day, year = nums[0], nums[1], nums[2]
+		}
+		month = time.Month(atoi(m))
+	default:
+		return time.Time{}, nil, errInvalidDate
+	}
+
+	y := atoi(year)
+	switch {
+	case len(year) == 2:
+		y = expandYear(y, l.thai)
+	case y >= 2400:
+		y -= buddhistEraOffset
+	}
+	d := atoi(day)
+
+	date := time.Date(y, month, d, 0, 0, 0, 0, time.UTC)
+	if month < 1 || month > 12 || y < 1900 || y > 2200 || date.Day() != d || date.Month() != month {
+		return time.Time{}, nil, errInvalidDate
+	}
+	return date, clock, nil
+}
+
+// expandYear reads a two-digit year as the latest year not after next year.
+// Thai files may use either era, so 67 is 2567 BE (2024) but 24 is 2024.
+func expandYear(yy int, thai bool) int {
+	limit := time.Now().Year() + 1
+	best := 0
+	candidates := []int{1900 + yy, 2000 + yy}
+	if thai {
+		candidates = append(candidates, 2400+yy-buddhistEraOffset, 2500+yy-buddhistEraOffset)
+	}
+	for _, y := range candidates {
+		if y <= limit && y > best {
+			best = y
+		}
+	}
+	return best
+}
+
+// parseClock reads a time of day such as 08:30, 8:30 PM or 20.30 น.
+func parseClock(s string) (*clockTime, bool) {
+	s = strings.TrimSpace(s)
+	m := clockPattern.FindStringSubmatch(s)
+	if m == nil || len(m[0]) != len(s) {
+		m = thaiClockPattern.FindStringSubmatch(s)
+		if m == nil || len(m[0]) != len(s) {
+			return nil, false
+		}
+		m = append(m, "")
+	}
+	hour, minute := atoi(m[1]), atoi(m[2])
+	switch strings.ToLower(strings.ReplaceAll(m[3], ".", "")) {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		} else if hour > 12 {
+			return nil, false
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		} else if hour > 12 {
+			return nil, false
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return nil, false
+	}
+	return &clockTime{hour: hour, minute: minute}, true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,111 @@
+package diary
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	thai := locale{dayFirst: true, thai: true}
+	us := locale{}
+	bangkok := time.FixedZone("ICT", 7*60*60)
+
+	tests := []struct {
+		name   string
+		in     string
+		locale locale
+		want   string // 2006-01-02
+		clock  *clockTime
+	}{
+		{"iso", "2024-01-05", us, "2024-01-05", nil},
+		{"iso with time", "2024-01-05T08:30", us, "2024-01-05", &clockTime{8, 30}},
+		{"rfc3339 converted to local day", "2024-01-04T20:30:00Z", us, "2024-01-05", &clockTime{3, 30}},
+
+		// Buddhist era years
+		{"numeric buddhist era", "05/01/2567", thai, "2024-01-05", nil},
+		{"thai abbreviated month, two-digit year", "5 ม.ค. 67", thai, "2024-01-05", nil},
+		{"thai full month", "5 มกราคม 2567", thai, "2024-01-05", nil},
+		{"thai month with era marker", "5 ม.ค. พ.ศ. 2567", thai, "2024-01-05", nil},
+		{"two-digit gregorian year in thai file", "5 ม.ค. 24", thai, "2024-01-05", nil},
+
+		// Day and month order
+		{"day first", "05/01/2024", thai, "2024-01-05", nil},
+		{"month first", "05/01/2024", us, "2024-05-01", nil},
+		{"month first, day over 12", "13/01/2024", us, "2024-01-13", nil},
+		{"day first, day over 12 second", "01/13/2024", thai, "2024-01-13", nil},
+		{"year first", "2024/01/05", us, "2024-01-05", nil},
+		{"english month", "Jan 5, 2024", us, "2024-01-05", nil},
+		{"english ordinal", "Friday, 5th January 2024", us, "2024-01-05", nil},
+
+		// 12-hour clocks
+		{"12 am is midnight", "1/5/2024 12:00 AM", us, "2024-01-05", &clockTime{0, 0}},
+		{"12 pm is noon", "1/5/2024 12:30 PM", us, "2024-01-05", &clockTime{12, 30}},
+		{"pm afternoon", "1/5/2024 8:15 p.m.", us, "2024-01-05", &clockTime{20, 15}},
+		{"am morning", "1/5/2024 8:15am", us, "2024-01-05", &clockTime{8, 15}},
+		{"thai clock", "05/01/2567 20.30 น.", thai, "2024-01-05", &clockTime{20, 30}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, clock, err := parseDate(tt.in, tt.locale, bangkok)
+			if err != nil {
+				t.Fatalf("parseDate(%q) error: %v", tt.in, err)
+			}
+			if got := date.Format("2006-01-02"); got != tt.want {
+				t.Errorf("parseDate(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+			switch {
+			case tt.clock == nil && clock != nil:
+				t.Errorf("parseDate(%q) clock = %v, want none", tt.in, *clock)
+			case tt.clock != nil && (clock == nil || *clock != *tt.clock):
+				t.Errorf("parseDate(%q) clock = %v, want %v", tt.in, clock, *tt.clock)
+			}
+		})
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"no date", "breakfast"},
+		{"day out of range", "31/02/2024"},
+		{"month out of range", "2024-13-01"},
+		{"13 pm", "1/5/2024 13:00 PM"},
+		{"minute out of range", "1/5/2024 10:75"},
+		{"too few numbers", "05/2024"},
+		{"year out of range", "05/01/1800"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if date, _, err := parseDate(tt.in, locale{dayFirst: true}, time.UTC); err == nil {
+				t.Errorf("parseDate(%q) = %s, want an error", tt.in, date.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestExpandYear(t *testing.T) {
+	next := time.Now().Year() + 1
+	tests := []struct {
+		name string
+		yy   int
+		thai bool
+		want int
+	}{
+		{"gregorian this century", 24, false, 2024},
+		{"gregorian last century", 99, false, 1999},
+		{"next year", next % 100, false, next},
+		{"after next year is last century", (next + 1) % 100, false, next + 1 - 100},
+		{"buddhist era", 67, true, 2024},
+		{"buddhist era last century", 42, true, 1999},
+		{"gregorian in thai file", 24, true, 2024},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandYear(tt.yy, tt.thai); got != tt.want {
+				t.Errorf("expandYear(%d, %v) = %d, want %d", tt.yy, tt.thai, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package diary
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+)
+
+// utf8BOM lets spreadsheet apps detect UTF-8, so Thai names display correctly
+const utf8BOM = "\ufeff"
+
+// coreColumns are the ByteTrack CSV columns before the registry nutrients
+var coreColumns = []string{
+	"date", "eaten_at", "meal_type", "name", "name_en", "grams",
+	"calories", "protein", "carbs", "fat", "fiber", "sugar", "sodium",
+}
+
+// Columns returns the ByteTrack CSV header: the core columns followed by
+// every registry nutrient
+func Columns() []string {
+	columns := append([]string{}, coreColumns...)
+	for _, n := range entity.NutrientRegistry {
+		columns = append(columns, string(n.ID))
+	}
+	return columns
+}
+
+// WriteCSV writes meals in the ByteTrack CSV format, which Parse reads back
+// without loss. Times are written in loc.
+func WriteCSV(w io.Writer, meals []*entity.Meal, loc *time.Location) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Columns()); err != nil {
+		return err
+	}
+
+	for _, meal := range meals {
+		record := []string{
+			meal.Date.Format("2006-01-02"),
+			"",
+			string(meal.MealType),
+			meal.Name,
+			meal.NameEn,
+			formatFloat(meal.Grams),
+			strconv.Itoa(meal.Calories),
+			formatFloat(meal.Protein),
+			formatFloat(meal.Carbs),
+			formatFloat(meal.Fat),
+			"", "", "",
+		}
+		if meal.EatenAt != nil {
+			record[1] = meal.EatenAt.In(loc).Format(time.RFC3339)
+		}
+		if meal.Fiber != nil {
+			record[10] = formatFloat(*meal.Fiber)
+		}
+		if meal.Sugar != nil {
+			record[11] = formatFloat(*meal.Sugar)
+		}
+		if meal.Sodium != nil {
+			record[12] = strconv.Itoa(*meal.Sodium)
+		}
+		for _, n := range entity.NutrientRegistry {
+			amount, ok := meal.Nutrients[n.ID]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, formatFloat(amount))
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
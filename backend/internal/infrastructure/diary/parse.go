@@ -0,0 +1,442 @@
+package diary
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+)
+
+// ErrInvalidFile is returned when a file can't be read as a food diary at
+// all. Problems with individual rows are reported per row instead.
+var ErrInvalidFile = errors.New("invalid import file")
+
+// maxNameLength matches the meals.name column
+const maxNameLength = 255
+
+// Row is one parsed data row. Meal is nil when the row is skipped or has errors.
+type Row struct {
+	Line   int
+	Meal   *entity.Meal
+	Skip   bool
+	Errors []string
+}
+
+// Parse reads a food diary CSV exported from ByteTrack, MyFitnessPal or
+// Lose It!, returning the source it was read as. Without a locale, dates
+// are read day-first except in the US apps' exports. Times are read in loc.
+func Parse(data []byte, opts *entity.ImportOptions, loc *time.Location, maxRows int) (entity.ImportSource, []Row, error) {
+	data = bytes.TrimPrefix(data, []byte(utf8BOM))
+	if !utf8.Valid(data) {
+		return "", nil, fmt.Errorf("%w: file must be UTF-8 encoded", ErrInvalidFile)
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = detectDelimiter(data)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return "", nil, fmt.Errorf("%w: file is empty", ErrInvalidFile)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	headers := make([]string, len(header))
+	for i, h := range header {
+		headers[i] = normalizeHeader(h)
+	}
+
+	source := opts.Source
+	if source == "" || source == entity.ImportAuto {
+		detected, ok := detectSource(headers)
+		switch {
+		case ok:
+			source = detected
+		case len(opts.Mapping) > 0:
+			source = entity.ImportByteTrack
+		default:
+			return "", nil, fmt.Errorf("%w: could not detect the app the file was exported from; set source or a column mapping", ErrInvalidFile)
+		}
+	}
+	columns, ok := sourceColumns[source]
+	if !ok {
+		return "", nil, fmt.Errorf("%w: unknown source %q", ErrInvalidFile, source)
+	}
+
+	fields, err := resolveFields(headers, columns, opts.Mapping)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// MyFitnessPal and Lose It! are US apps with month-first dates
+	tag := opts.Locale
+	if tag == "" {
+		tag = "th"
+		if source == entity.ImportMyFitnessPal || source == entity.ImportLoseIt {
+			tag = "en-US"
+		}
+	}
+	l := parseLocale(tag)
+	var rows []Row
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if blank(record) {
+			continue
+		}
+		if len(rows) == maxRows {
+			return "", nil, fmt.Errorf("%w: file has more than %d rows", ErrInvalidFile, maxRows)
+		}
+
+		line, _ := r.FieldPos(0)
+		row := parseRow(record, fields, source, l, loc)
+		row.Line = line
+		rows = append(rows, row)
+	}
+
+	return source, rows, nil
+}
+
+// resolveFields assigns a field to each column. The mapping, keyed by the
+// file's header, takes precedence over the source's own columns.
+func resolveFields(headers []string, columns map[string]string, mapping map[string]string) ([]string, error) {
+	mapped := make(map[string]string, len(mapping))
+	for header, field := range mapping {
+		if !validField(field) {
+			return nil, fmt.Errorf("%w: unknown field %q for column %q", ErrInvalidFile, field, header)
+		}
+		mapped[normalizeHeader(header)] = field
+	}
+
+	fields := make([]string, len(headers))
+	found := make(map[string]bool)
+	for i, h := range headers {
+		field, ok := mapped[h]
+		if !ok {
+			field = columns[h]
+		}
+		fields[i] = field
+		found[field] = true
+	}
+
+	for _, required := range []string{fieldDate, fieldMealType, fieldCalories} {
+		if !found[required] && !(required == fieldDate && found[fieldEatenAt]) {
+			return nil, fmt.Errorf("%w: no column for %s", ErrInvalidFile, required)
+		}
+	}
+	return fields, nil
+}
+
+func parseRow(record []string, fields []string, source entity.ImportSource, l locale, loc *time.Location) Row {
+	values := make(map[string]string, len(fields))
+	for i, field := range fields {
+		if field == "" || field == fieldIgnore || i >= len(record) {
+			continue
+		}
+		if v := strings.TrimSpace(record[i]); v != "" && values[field] == "" {
+			values[field] = v
+		}
+	}
+
+	var row Row
+	fail := func(format string, args ...interface{}) {
+		row.Errors = append(row.Errors, fmt.Sprintf(format, args...))
+	}
+
+	if truthy(values[fieldDeleted]) {
+		row.Skip = true
+		return row
+	}
+
+	meal := &entity.Meal{}
+
+	switch mealType, skip, ok := parseMealType(values[fieldMealType]); {
+	case skip:
+		row.Skip = true
+		return row
+	case values[fieldMealType] == "":
+		fail("meal_type is required")
+	case !ok:
+		fail("unknown meal type %q", values[fieldMealType])
+	default:
+		meal.MealType = mealType
+	}
+
+	// The date, and the time eaten when the file has one
+	var clock *clockTime
+	if v := values[fieldEatenAt]; v != "" {
+		date, c, err := parseDate(v, l, loc)
+		switch {
+		case err != nil:
+			fail("invalid eaten_at %q", v)
+		case c == nil:
+			fail("eaten_at %q has no time", v)
+		default:
+			meal.Date, clock = date, c
+		}
+	}
+	if v := values[fieldDate]; v != "" {
+		date, c, err := parseDate(v, l, loc)
+		if err != nil {
+			fail("invalid date %q", v)
+		} else {
+			meal.Date = date
+			if clock == nil {
+				clock = c
+			}
+		}
+	} else if values[fieldEatenAt] == "" {
+		fail("date is required")
+	}
+	if v := values[fieldTime]; v != "" && values[fieldEatenAt] == "" {
+		c, ok := parseClock(v)
+		if !ok {
+			fail("invalid time %q", v)
+		} else {
+			clock = c
+		}
+	}
+	if clock != nil && !meal.Date.IsZero() {
+		eatenAt := time.Date(meal.Date.Year(), meal.Date.Month(), meal.Date.Day(), clock.hour, clock.minute, 0, 0, loc)
+		meal.EatenAt = &eatenAt
+	}
+
+	// MyFitnessPal rows total a meal slot, so they have no food name
+	meal.Name = values[fieldName]
+	if meal.Name == "" {
+		meal.Name = values[fieldNote]
+	}
+	if meal.Name == "" && source == entity.ImportMyFitnessPal && meal.MealType != "" {
+		meal.Name = mealTypeLabels[meal.MealType] + " (MyFitnessPal)"
+	}
+	meal.NameEn = values[fieldNameEn]
+	switch {
+	case meal.Name == "":
+		fail("name is required")
+	case utf8.RuneCountInString(meal.Name) > maxNameLength:
+		fail("name must be at most %d characters", maxNameLength)
+	}
+	if utf8.RuneCountInString(meal.NameEn) > maxNameLength {
+		fail("name_en must be at most %d characters", maxNameLength)
+	}
+
+	number := func(field string) (float64, bool) {
+		v := values[field]
+		if v == "" {
+			return 0, false
+		}
+		n, err := parseNumber(v, l)
+		if err != nil {
+			fail("invalid %s %q: %v", field, v, err)
+			return 0, false
+		}
+		return n, true
+	}
+
+	if calories, ok := number(fieldCalories); ok {
+		meal.Calories = int(math.Round(calories))
+	} else if values[fieldCalories] == "" {
+		fail("calories is required")
+	}
+	meal.Protein, _ = number(fieldProtein)
+	meal.Carbs, _ = number(fieldCarbs)
+	meal.Fat, _ = number(fieldFat)
+	if fiber, ok := number(fieldFiber); ok {
+		meal.Fiber = &fiber
+	}
+	if sugar, ok := number(fieldSugar); ok {
+		meal.Sugar = &sugar
+	}
+	if sodium, ok := number(fieldSodium); ok {
+		mg := int(math.Round(sodium))
+		meal.Sodium = &mg
+	}
+
+	if grams, ok := number(fieldGrams); ok {
+		meal.Grams = grams
+	} else if quantity, ok := number(fieldQuantity); ok {
+		meal.Grams = math.Round(quantity*unitGrams(values[fieldUnits])*10) / 10
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if _, ok := entity.LookupNutrient(entity.NutrientID(field)); !ok || seen[field] {
+			continue
+		}
+		seen[field] = true
+		if amount, ok := number(field); ok {
+			if meal.Nutrients == nil {
+				meal.Nutrients = entity.Nutrients{}
+			}
+			meal.Nutrients[entity.NutrientID(field)] = amount
+		}
+	}
+
+	if len(row.Errors) == 0 {
+		row.Meal = meal
+	}
+	return row
+}
+
+var mealTypeLabels = map[entity.MealType]string{
+	entity.MealTypeBreakfast: "Breakfast",
+	entity.MealTypeLunch:     "Lunch",
+	entity.MealTypeDinner:    "Dinner",
+	entity.MealTypeSnack:     "Snacks",
+}
+
+// mealTypeNames maps English and Thai meal names to meal types
+var mealTypeNames = map[string]entity.MealType{
+	"breakfast": entity.MealTypeBreakfast, "เช้า": entity.MealTypeBreakfast, "มื้อเช้า": entity.MealTypeBreakfast, "อาหารเช้า": entity.MealTypeBreakfast,
+	"lunch": entity.MealTypeLunch, "กลางวัน": entity.MealTypeLunch, "มื้อกลางวัน": entity.MealTypeLunch, "อาหารกลางวัน": entity.MealTypeLunch, "เที่ยง": entity.MealTypeLunch,
+	"dinner": entity.MealTypeDinner, "supper": entity.MealTypeDinner, "เย็น": entity.MealTypeDinner, "มื้อเย็น": entity.MealTypeDinner, "อาหารเย็น": entity.MealTypeDinner, "ค่ำ": entity.MealTypeDinner,
+	"snack": entity.MealTypeSnack, "snacks": entity.MealTypeSnack, "ว่าง": entity.MealTypeSnack, "ของว่าง": entity.MealTypeSnack, "มื้อว่าง": entity.MealTypeSnack,
+}
+
+// parseMealType reads a meal slot. skip is set for exercise entries, which
+// Lose It! exports alongside food.
+func parseMealType(s string) (mealType entity.MealType, skip bool, ok bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if mealType, ok := mealTypeNames[s]; ok {
+		return mealType, false, true
+	}
+	// Custom slot names such as "Afternoon snack"
+	for _, name := range []string{"breakfast", "lunch", "dinner", "snack"} {
+		if strings.Contains(s, name) {
+			return mealTypeNames[name], false, true
+		}
+	}
+	if strings.Contains(s, "exercise") {
+		return "", true, false
+	}
+	return "", false, false
+}
+
+// locale holds the parsing conventions of a locale
+type locale struct {
+	dayFirst     bool
+	decimalComma bool
+	thai         bool // two-digit years are Buddhist era
+}
+
+// decimalCommaLanguages write 1.234,5 for 1234.5
+var decimalCommaLanguages = map[string]bool{
+	"de": true, "fr": true, "es": true, "it": true, "nl": true, "pt": true, "ru": true,
+	"id": true, "tr": true, "pl": true, "sv": true, "da": true, "nb": true, "fi": true,
+	"cs": true, "vi": true,
+}
+
+// parseLocale reads a locale tag such as "th", "en-US" or "de-DE". Only
+// US English puts the month first.
+func parseLocale(tag string) locale {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	lang := tag
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		lang = tag[:i]
+	}
+	return locale{
+		dayFirst:     tag != "en-us" && tag != "en",
+		decimalComma: decimalCommaLanguages[lang],
+		thai:         lang == "th",
+	}
+}
+
+// parseNumber reads a non-negative number, ignoring a trailing unit such as
+// "g" or "kcal"
+func parseNumber(s string, l locale) (float64, error) {
+	s = strings.TrimRightFunc(s, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsSpace(r) || r == '%'
+	})
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	if l.decimalComma {
+		// A lone dot not grouping thousands is a decimal point
+		if i := strings.LastIndexByte(s, '.'); i >= 0 && !strings.Contains(s, ",") && len(s)-i-1 != 3 {
+			s = s[:i] + "," + s[i+1:]
+		}
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errors.New("not a number")
+	}
+	if v < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	return v, nil
+}
+
+// unitGrams returns grams per unit for weight and volume units, or 0 when
+// the unit (such as "serving") has no fixed weight
+func unitGrams(unit string) float64 {
+	switch strings.ToLower(strings.TrimSuffix(strings.TrimSpace(unit), ".")) {
+	case "g", "gram", "grams", "กรัม", "ml", "milliliter", "milliliters", "มล":
+		return 1
+	case "kg", "kilogram", "kilograms":
+		return 1000
+	case "oz", "ounce", "ounces":
+		return 28.3495
+	case "lb", "lbs", "pound", "pounds":
+		return 453.592
+	}
+	return 0
+}
+
+func truthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "y", "x":
+		return true
+	}
+	return false
+}
+
+func blank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// detectDelimiter picks the delimiter used in the header line. Spreadsheets
+// in decimal comma locales save CSV with semicolons.
+func detectDelimiter(data []byte) rune {
+	line := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line = data[:i]
+	}
+	best, count := ',', bytes.Count(line, []byte{','})
+	for _, d := range []rune{';', '\t'} {
+		if n := bytes.Count(line, []byte(string(d))); n > count {
+			best, count = d, n
+		}
+	}
+	return best
+}
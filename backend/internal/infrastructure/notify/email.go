@@ -0,0 +1,159 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// EmailOptions holds SMTP settings for the email notifier
+type EmailOptions struct {
+	Host     string
+	Port     string
+	Username string // no authentication when empty
+	Password string
+	From     string // e.g. ByteTrack <no-reply@example.com>
+}
+
+// EmailNotifier sends notifications as email over SMTP
+type EmailNotifier struct {
+	opts EmailOptions
+	from *mail.Address
+}
+
+// NewEmailNotifier creates a new email notifier
+func NewEmailNotifier(opts EmailOptions) (*EmailNotifier, error) {
+	if opts.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	if opts.Port == "" {
+		opts.Port = "587"
+	}
+
+	from, err := mail.ParseAddress(opts.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp from address: %w", err)
+	}
+
+	return &EmailNotifier{opts: opts, from: from}, nil
+}
+
+// Channel returns the email channel name
+func (n *EmailNotifier) Channel() string {
+	return ChannelEmail
+}
+
+// Send emails the notification. Titles and bodies may be Thai, so the
+// subject is RFC 2047 encoded and the body base64 encoded UTF-8. Messages
+// with HTML or attachments are sent as multipart MIME.
+func (n *EmailNotifier) Send(ctx context.Context, to *Recipient, msg *Message) error {
+	if to.Email == "" {
+		return ErrNoAddress
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", n.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.Email)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTML == "" && len(msg.Attachments) == 0 {
+		buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+		writeBase64(&buf, []byte(msg.Body+"\r\n"))
+	} else if err := writeMultipart(&buf, msg); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if n.opts.Username != "" {
+		auth = smtp.PlainAuth("", n.opts.Username, n.opts.Password, n.opts.Host)
+	}
+
+	addr := net.JoinHostPort(n.opts.Host, n.opts.Port)
+	if err := smtp.SendMail(addr, auth, n.from.Address, []string{to.Email}, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// writeMultipart writes a multipart/mixed body holding the text and HTML
+// alternatives followed by any attachments
+func writeMultipart(buf *bytes.Buffer, msg *Message) error {
+	var alt bytes.Buffer
+	altWriter := multipart.NewWriter(&alt)
+	if err := writeBase64Part(altWriter, "text/plain; charset=UTF-8", "", []byte(msg.Body)); err != nil {
+		return err
+	}
+	if msg.HTML != "" {
+		if err := writeBase64Part(altWriter, "text/html; charset=UTF-8", "", []byte(msg.HTML)); err != nil {
+			return err
+		}
+	}
+	if err := altWriter.Close(); err != nil {
+		return err
+	}
+
+	mixed := multipart.NewWriter(buf)
+	fmt.Fprintf(buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary())
+
+	w, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + altWriter.Boundary()},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(alt.Bytes()); err != nil {
+		return err
+	}
+
+	for _, attachment := range msg.Attachments {
+		disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})
+		if err := writeBase64Part(mixed, attachment.ContentType, disposition, attachment.Data); err != nil {
+			return err
+		}
+	}
+
+	return mixed.Close()
+}
+
+// writeBase64Part adds a base64 encoded part to a multipart body
+func writeBase64Part(mw *multipart.Writer, contentType, disposition string, data []byte) error {
+	header := textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+	if disposition != "" {
+		header.Set("Content-Disposition", disposition)
+	}
+
+	w, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	var encoded bytes.Buffer
+	writeBase64(&encoded, data)
+	_, err = w.Write(encoded.Bytes())
+	return err
+}
+
+// writeBase64 writes data base64 encoded in 76 character lines
+func writeBase64(buf *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
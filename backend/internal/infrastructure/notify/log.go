@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// LogNotifier writes notifications to the application log, for local testing
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new log notifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Channel returns the log channel name
+func (n *LogNotifier) Channel() string {
+	return ChannelLog
+}
+
+// Send logs the notification
+func (n *LogNotifier) Send(ctx context.Context, to *Recipient, msg *Message) error {
+	log.Printf("Notification for user %s: %s - %s (%s)", to.UserID, msg.Title, msg.Body, msg.URL)
+	return nil
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/infrastructure/config"
+	"github.com/google/uuid"
+)
+
+// Delivery channels
+const (
+	ChannelPush  = "push"
+	ChannelEmail = "email"
+	ChannelLog   = "log"
+)
+
+// ErrNoAddress is returned when a recipient has no address for a channel,
+// such as no push subscriptions or no email
+var ErrNoAddress = errors.New("recipient has no address for this channel")
+
+// Message is a notification's content
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url,omitempty"` // app path opened when the notification is clicked
+	Tag   string `json:"tag,omitempty"` // replaces an earlier notification with the same tag
+
+	// Email-only content: an HTML alternative to the body and attachments
+	HTML        string       `json:"-"`
+	Attachments []Attachment `json:"-"`
+}
+
+// Attachment is a file attached to an email notification
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Recipient identifies who a notification is delivered to on each channel
+type Recipient struct {
+	UserID        uuid.UUID
+	Email         string
+	Subscriptions []*entity.PushSubscription
+}
+
+// Notifier delivers notifications over one channel
+type Notifier interface {
+	// Channel names the delivery channel
+	Channel() string
+	// Send delivers msg to the recipient, returning ErrNoAddress when the
+	// recipient cannot be reached on this channel
+	Send(ctx context.Context, to *Recipient, msg *Message) error
+}
+
+// New creates the notifiers for the channels enabled in the configuration.
+// onExpired is called with push subscriptions the push service reports as gone.
+func New(cfg *config.Config, onExpired func(ctx context.Context, endpoints []string) error) ([]Notifier, error) {
+	var notifiers []Notifier
+	for _, channel := range cfg.Notification.Channels {
+		switch strings.TrimSpace(channel) {
+		case "":
+			continue
+		case ChannelLog:
+			notifiers = append(notifiers, NewLogNotifier())
+		case ChannelEmail:
+			notifier, err := NewEmailNotifier(EmailOptions{
+				Host:     cfg.Notification.SMTPHost,
+				Port:     cfg.Notification.SMTPPort,
+				Username: cfg.Notification.SMTPUsername,
+				Password: cfg.Notification.SMTPPassword,
+				From:     cfg.Notification.SMTPFrom,
+			})
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, notifier)
+		case ChannelPush:
+			notifier, err := NewWebPushNotifier(WebPushOptions{
+				PublicKey:  cfg.Notification.VAPIDPublicKey,
+				PrivateKey: cfg.Notification.VAPIDPrivateKey,
+				Subject:    cfg.Notification.VAPIDSubject,
+				OnExpired:  onExpired,
+			})
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, notifier)
+		default:
+			return nil, fmt.Errorf("unknown notification channel %q", channel)
+		}
+	}
+	return notifiers, nil
+}
@@ -0,0 +1,302 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/pkg/netguard"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// pushRecordSize is the aes128gcm record size; payloads are sent as a single record
+	pushRecordSize = 4096
+	// maxPushPayload leaves room in the record for the delimiter and GCM tag,
+	// and in the 4096 byte body push services accept for the header
+	maxPushPayload = pushRecordSize - 86 - 1 - 16
+	// vapidTokenTTL is how long a VAPID token is valid; push services accept up to 24 hours
+	vapidTokenTTL = 12 * time.Hour
+)
+
+// errSubscriptionGone is returned when the push service no longer knows a subscription
+var errSubscriptionGone = errors.New("push subscription expired")
+
+// WebPushOptions holds VAPID (RFC 8292) settings for the Web Push notifier
+type WebPushOptions struct {
+	PublicKey  string // base64url uncompressed P-256 point, shared with browsers
+	PrivateKey string // base64url P-256 scalar
+	Subject    string // mailto: or https: contact for push service operators
+	TTL        time.Duration
+	// OnExpired is called with subscriptions the push service reports as gone
+	OnExpired func(ctx context.Context, endpoints []string) error
+}
+
+// WebPushNotifier sends notifications through browser push services using
+// VAPID authentication and aes128gcm payload encryption (RFC 8291)
+type WebPushNotifier struct {
+	opts   WebPushOptions
+	key    *ecdsa.PrivateKey
+	client *http.Client
+}
+
+// NewWebPushNotifier creates a new Web Push notifier
+func NewWebPushNotifier(opts WebPushOptions) (*WebPushNotifier, error) {
+	if opts.PublicKey == "" || opts.PrivateKey == "" {
+		return nil, errors.New("vapid public and private keys are required")
+	}
+	if opts.Subject == "" {
+		return nil, errors.New("vapid subject is required")
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+
+	private, err := decodeBase64URL(opts.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vapid private key: %w", err)
+	}
+	ecdhKey, err := ecdh.P256().NewPrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vapid private key: %w", err)
+	}
+
+	public := ecdhKey.PublicKey().Bytes()
+	configured, err := decodeBase64URL(opts.PublicKey)
+	if err != nil || !bytes.Equal(configured, public) {
+		return nil, errors.New("vapid public key does not match the private key")
+	}
+
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:]),
+		},
+		D: new(big.Int).SetBytes(private),
+	}
+
+	return &WebPushNotifier{
+		opts: opts,
+		key:  key,
+		client: &http.Client{
+			Timeout: 15 * time.Second,
+			// Endpoints come from browsers, so only public addresses are dialed
+			Transport: netguard.NewTransport(),
+		},
+	}, nil
+}
+
+// Channel returns the push channel name
+func (n *WebPushNotifier) Channel() string {
+	return ChannelPush
+}
+
+// Send pushes the notification to each of the recipient's subscriptions. It
+// succeeds when at least one subscription receives it.
+func (n *WebPushNotifier) Send(ctx context.Context, to *Recipient, msg *Message) error {
+	if len(to.Subscriptions) == 0 {
+		return ErrNoAddress
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPushPayload {
+		return fmt.Errorf("push payload of %d bytes exceeds %d", len(payload), maxPushPayload)
+	}
+
+	delivered := 0
+	var expired []string
+	var errs []error
+	for _, sub := range to.Subscriptions {
+		err := n.push(ctx, sub, payload)
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, errSubscriptionGone):
+			expired = append(expired, sub.Endpoint)
+		default:
+			errs = append(errs, err)
+		}
+	}
+
+	if len(expired) > 0 && n.opts.OnExpired != nil {
+		if err := n.opts.OnExpired(ctx, expired); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove expired subscriptions: %w", err))
+		}
+	}
+
+	switch {
+	case delivered > 0:
+		for _, err := range errs {
+			log.Printf("Web Push delivery failed for user %s: %v", to.UserID, err)
+		}
+		return nil
+	case len(errs) == 0:
+		return ErrNoAddress
+	}
+	return errors.Join(errs...)
+}
+
+// push encrypts and sends a payload to one subscription
+func (n *WebPushNotifier) push(ctx context.Context, sub *entity.PushSubscription, payload []byte) error {
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return fmt.Errorf("invalid push endpoint %q", sub.Endpoint)
+	}
+
+	body, err := encryptPushPayload(sub, payload)
+	if err != nil {
+		return err
+	}
+
+	token, err := n.vapidToken(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(n.opts.TTL.Seconds())))
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("Authorization", "vapid t="+token+", k="+n.opts.PublicKey)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return errSubscriptionGone
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("push service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+}
+
+// vapidToken signs a VAPID JWT for a push service origin
+func (n *WebPushNotifier) vapidToken(audience string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": audience,
+		"exp": time.Now().Add(vapidTokenTTL).Unix(),
+		"sub": n.opts.Subject,
+	})
+	return token.SignedString(n.key)
+}
+
+// encryptPushPayload encrypts a payload for a subscription with a fresh
+// ephemeral key and salt
+func encryptPushPayload(sub *entity.PushSubscription, payload []byte) ([]byte, error) {
+	clientPublic, err := decodeBase64URL(sub.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	authSecret, err := decodeBase64URL(sub.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription auth secret: %w", err)
+	}
+
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return encryptAES128GCM(clientPublic, authSecret, serverKey, salt, payload)
+}
+
+// encryptAES128GCM encrypts a payload as a single aes128gcm record (RFC 8188)
+// with keys derived as in RFC 8291
+func encryptAES128GCM(clientPublic, authSecret []byte, serverKey *ecdh.PrivateKey, salt, payload []byte) ([]byte, error) {
+	uaPublic, err := ecdh.P256().NewPublicKey(clientPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	secret, err := serverKey.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := serverKey.PublicKey().Bytes()
+
+	keyInfo := append([]byte("WebPush: info\x00"), clientPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := hkdfSHA256(authSecret, secret, keyInfo, 32)
+	cek := hkdfSHA256(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdfSHA256(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Header: salt, record size, key ID length and the server public key as key ID
+	header := make([]byte, 0, len(salt)+5+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, pushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	record := make([]byte, 0, len(payload)+1)
+	record = append(record, payload...)
+	record = append(record, 0x02) // last record delimiter, no padding
+
+	return gcm.Seal(header, nonce, record, nil), nil
+}
+
+// hkdfSHA256 derives up to 32 bytes with HKDF-SHA-256 (RFC 5869)
+func hkdfSHA256(salt, ikm, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(ikm)
+	prk := extract.Sum(nil)
+
+	expand := hmac.New(sha256.New, prk)
+	expand.Write(info)
+	expand.Write([]byte{1})
+	return expand.Sum(nil)[:length]
+}
+
+// decodeBase64URL decodes base64url with or without padding, also accepting
+// standard base64 as some clients send keys that way
+func decodeBase64URL(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"testing"
+)
+
+// TestEncryptAES128GCM checks encryption against the example in RFC 8291
+// Appendix A: with the same keys and salt the body must match exactly
+func TestEncryptAES128GCM(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverPrivate string
+		serverPublic  string
+		clientPublic  string
+		authSecret    string
+		salt          string
+		plaintext     string
+		want          string
+	}{
+		{
+			name:          "RFC 8291 Appendix A",
+			serverPrivate: "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw",
+			serverPublic:  "BP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A8",
+			clientPublic:  "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+			authSecret:    "BTBZMqHH6r4Tts7J_aSIgg",
+			salt:          "DGv6ra1nlYgDCS1FRnbzlw",
+			plaintext:     "When I grow up, I want to be a watermelon",
+			want: "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_" +
+				"yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverKey, err := ecdh.P256().NewPrivateKey(mustDecode(t, tt.serverPrivate))
+			if err != nil {
+				t.Fatalf("server key: %v", err)
+			}
+			if !bytes.Equal(serverKey.PublicKey().Bytes(), mustDecode(t, tt.serverPublic)) {
+				t.Fatal("server private key does not match its public key")
+			}
+
+			got, err := encryptAES128GCM(mustDecode(t, tt.clientPublic), mustDecode(t, tt.authSecret), serverKey, mustDecode(t, tt.salt), []byte(tt.plaintext))
+			if err != nil {
+				t.Fatalf("encryptAES128GCM: %v", err)
+			}
+			if want := mustDecode(t, tt.want); !bytes.Equal(got, want) {
+				t.Errorf("encryptAES128GCM =\n%x\nwant\n%x", got, want)
+			}
+		})
+	}
+}
+
+func TestEncryptAES128GCMRejectsInvalidKey(t *testing.T) {
+	serverKey, err := ecdh.P256().NewPrivateKey(mustDecode(t, "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := encryptAES128GCM([]byte{4, 1, 2, 3}, make([]byte, 16), serverKey, make([]byte, 16), []byte("hi")); err == nil {
+		t.Error("encryptAES128GCM accepted a malformed subscription key")
+	}
+}
+
+func mustDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := decodeBase64URL(s)
+	if err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+	return b
+}
@@ -0,0 +1,245 @@
+// Package openfoodfacts is a client for the Open Food Facts API. Failed
+// requests are retried with jittered backoff, and a circuit breaker stops
+// calling the API while it is down.
+package openfoodfacts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBaseURL          = "https://world.openfoodfacts.org"
+	defaultUserAgent        = "ByteTrack/1.0 (https://bytetrack.app)"
+	defaultTimeout          = 10 * time.Second
+	defaultRetryBaseDelay   = 250 * time.Millisecond
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+
+	// maxRetryWait caps how long a Retry-After is honoured before giving up
+	maxRetryWait = 5 * time.Second
+	// maxResponseSize bounds a response body
+	maxResponseSize = 10 << 20
+
+	searchFields = "code,product_name,product_name_en,brands,categories,image_url,nutriments,serving_size,serving_quantity"
+)
+
+var (
+	// ErrNotFound is returned when a product does not exist
+	ErrNotFound = errors.New("open food facts: product not found")
+	// ErrRateLimited is returned when Open Food Facts throttles requests
+	ErrRateLimited = errors.New("open food facts: rate limited")
+	// ErrUnavailable is returned when Open Food Facts is down or responds
+	// with something other than the expected JSON
+	ErrUnavailable = errors.New("open food facts: unavailable")
+	// ErrCircuitOpen is returned without calling Open Food Facts while the
+	// circuit breaker is open. It wraps ErrUnavailable.
+	ErrCircuitOpen = fmt.Errorf("%w: circuit open", ErrUnavailable)
+)
+
+// Options configures a client
+type Options struct {
+	BaseURL          string
+	UserAgent        string
+	Timeout          time.Duration // per attempt
+	MaxRetries       int           // retries after the first attempt
+	RetryBaseDelay   time.Duration // doubled after each retry
+	BreakerThreshold int           // consecutive failed calls that open the circuit
+	BreakerCooldown  time.Duration // how long the circuit stays open
+}
+
+// Client calls the Open Food Facts API
+type Client struct {
+	opts    Options
+	client  *http.Client
+	breaker *breaker
+}
+
+// New creates a new Open Food Facts client
+func New(opts Options) *Client {
+	if opts.BaseURL == "" {
+		opts.BaseURL = defaultBaseURL
+	}
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	if opts.UserAgent == "" {
+		opts.UserAgent = defaultUserAgent
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.RetryBaseDelay <= 0 {
+		opts.RetryBaseDelay = defaultRetryBaseDelay
+	}
+	if opts.BreakerThreshold <= 0 {
+		opts.BreakerThreshold = defaultBreakerThreshold
+	}
+	if opts.BreakerCooldown <= 0 {
+		opts.BreakerCooldown = defaultBreakerCooldown
+	}
+
+	return &Client{
+		opts: opts,
+		client: &http.Client{
+			Timeout: opts.Timeout,
+		},
+		breaker: newBreaker(opts.BreakerThreshold, opts.BreakerCooldown),
+	}
+}
+
+// Search searches products by name
+func (c *Client) Search(ctx context.Context, query string, page, pageSize int) (*SearchResponse, error) {
+	q := url.Values{}
+	q.Set("search_terms", query)
+	q.Set("search_simple", "1")
+	q.Set("action", "process")
+	q.Set("json", "1")
+	q.Set("page", strconv.Itoa(page))
+	q.Set("page_size", strconv.Itoa(pageSize))
+	q.Set("fields", searchFields)
+
+	var data SearchResponse
+	if err := c.get(ctx, "/cgi/search.pl?"+q.Encode(), &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Product looks up a product by barcode
+func (c *Client) Product(ctx context.Context, barcode string) (*Product, error) {
+	var data productResponse
+	if err := c.get(ctx, "/api/v2/product/"+url.PathEscape(barcode)+".json", &data); err != nil {
+		return nil, err
+	}
+	if data.Status != 1 {
+		return nil, ErrNotFound
+	}
+	return &data.Product, nil
+}
+
+// Degraded reports whether the circuit breaker is rejecting calls
+func (c *Client) Degraded() bool {
+	return c.breaker.isOpen(time.Now())
+}
+
+// get calls an API path through the circuit breaker, retrying failures that
+// may be transient, and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	if !c.breaker.allow(time.Now()) {
+		return ErrCircuitOpen
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.attempt(ctx, path, out)
+		if err == nil || errors.Is(err, ErrNotFound) {
+			c.breaker.success()
+			return err
+		}
+		if ctx.Err() != nil {
+			c.breaker.abandon()
+			return ctx.Err()
+		}
+		retryable := errors.Is(err, ErrUnavailable) || errors.Is(err, ErrRateLimited)
+		if !retryable {
+			// Other client errors mean the upstream is up
+			c.breaker.success()
+			return err
+		}
+
+		delay := retryDelay(c.opts.RetryBaseDelay, attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		if attempt >= c.opts.MaxRetries || delay > maxRetryWait {
+			c.breaker.failure(time.Now())
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			c.breaker.abandon()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends one request, classifying failures and returning how long the
+// server asked us to wait when rate limited
+func (c *Client) attempt(ctx context.Context, path string, out interface{}) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.opts.BaseURL+path, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return 0, ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return parseRetryAfter(resp.Header.Get("Retry-After")), ErrRateLimited
+	case resp.StatusCode >= 500:
+		return 0, fmt.Errorf("%w: responded %d", ErrUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return 0, fmt.Errorf("open food facts: responded %d", resp.StatusCode)
+	}
+
+	// Outages are often served as HTML error pages with a 200 status
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return 0, fmt.Errorf("%w: unexpected content type %q", ErrUnavailable, resp.Header.Get("Content-Type"))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return 0, fmt.Errorf("%w: invalid response: %v", ErrUnavailable, err)
+	}
+	return 0, nil
+}
+
+// retryDelay returns the backoff before retry attempt+1, with ±20% jitter
+func retryDelay(base time.Duration, attempt int) time.Duration {
+	delay := base << attempt
+	jitter := time.Duration((mathrand.Float64()*0.4 - 0.2) * float64(delay))
+	return delay + jitter
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as a date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package openfoodfacts
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Product is an Open Food Facts product
+type Product struct {
+	Code            string      `json:"code"`
+	ProductName     string      `json:"product_name"`
+	ProductNameEn   string      `json:"product_name_en"`
+	Brands          string      `json:"brands"`
+	Categories      string      `json:"categories"`
+	ImageURL        string      `json:"image_url"`
+	Nutriments      *Nutriments `json:"nutriments"`
+	ServingSize     string      `json:"serving_size"`
+	ServingQuantity float64     `json:"serving_quantity"`
+}
+
+// Nutriments are a product's nutrition facts
+type Nutriments struct {
+	EnergyKcal100g       float64 `json:"energy-kcal_100g"`
+	EnergyKcalServing    float64 `json:"energy-kcal_serving"`
+	Proteins100g         float64 `json:"proteins_100g"`
+	ProteinsServing      float64 `json:"proteins_serving"`
+	Carbohydrates100g    float64 `json:"carbohydrates_100g"`
+	CarbohydratesServing float64 `json:"carbohydrates_serving"`
+	Fat100g              float64 `json:"fat_100g"`
+	FatServing           float64 `json:"fat_serving"`
+	Fiber100g            float64 `json:"fiber_100g"`
+	Sugars100g           float64 `json:"sugars_100g"`
+	Sodium100g           float64 `json:"sodium_100g"`
+
+	// values holds every numeric nutriment by its OFF key
+	values map[string]float64
+}
+
+// UnmarshalJSON decodes OFF nutriments, which mix numbers and numeric
+// strings, keeping every value so extra nutrients can be looked up by key
+func (n *Nutriments) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	n.values = make(map[string]float64, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case float64:
+			n.values[key] = v
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				n.values[key] = f
+			}
+		}
+	}
+
+	n.EnergyKcal100g = n.values["energy-kcal_100g"]
+	n.EnergyKcalServing = n.values["energy-kcal_serving"]
+	n.Proteins100g = n.values["proteins_100g"]
+	n.ProteinsServing = n.values["proteins_serving"]
+	n.Carbohydrates100g = n.values["carbohydrates_100g"]
+	n.CarbohydratesServing = n.values["carbohydrates_serving"]
+	n.Fat100g = n.values["fat_100g"]
+	n.FatServing = n.values["fat_serving"]
+	n.Fiber100g = n.values["fiber_100g"]
+	n.Sugars100g = n.values["sugars_100g"]
+	n.Sodium100g = n.values["sodium_100g"]
+	return nil
+}
+
+// Value returns the nutriment stored under an OFF key such as "iron_100g"
+func (n *Nutriments) Value(key string) (float64, bool) {
+	v, ok := n.values[key]
+	return v, ok
+}
+
+// SearchResponse is a page of search results
+type SearchResponse struct {
+	Count    int       `json:"count"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
+	Products []Product `json:"products"`
+}
+
+// productResponse is a single product lookup
+type productResponse struct {
+	Status  int     `json:"status"`
+	Product Product `json:"product"`
+}
@@ -0,0 +1,159 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+)
+
+// HTML renders a report as a standalone HTML page. Styles are inline so the
+// page also works as an email body.
+func HTML(r *entity.Report) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, newView(r)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) },
+	"points":  svgPoints,
+}).Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} {{.Period}}</title>
+</head>
+<body style="margin:0;padding:24px;background:#f5f7f5;font-family:'Sarabun','Noto Sans Thai',Helvetica,Arial,sans-serif;color:#1f2933;">
+<div style="max-width:720px;margin:0 auto;background:#ffffff;border-radius:12px;padding:32px;">
+  <h1 style="margin:0;font-size:24px;color:#15803d;">{{.Title}}</h1>
+  <p style="margin:4px 0 0;font-size:16px;">{{.Period}}</p>
+  <p style="margin:4px 0 24px;font-size:12px;color:#6b7280;">{{.Generated}}</p>
+
+  <table role="presentation" style="width:100%;border-collapse:collapse;margin-bottom:24px;"><tr>
+  {{- range .Stats}}
+    <td style="padding:12px;background:#f0fdf4;border:4px solid #ffffff;border-radius:8px;">
+      <div style="font-size:12px;color:#6b7280;">{{.Label}}</div>
+      <div style="font-size:20px;font-weight:bold;">{{.Value}}</div>
+    </td>
+  {{- end}}
+  </tr></table>
+
+  <h2 style="font-size:18px;margin:24px 0 8px;">{{.Labels.totals}}</h2>
+  {{- if not .HasTargets}}
+  <p style="font-size:13px;color:#6b7280;">{{.Labels.no_targets}}</p>
+  {{- end}}
+  <table style="width:100%;border-collapse:collapse;font-size:14px;">
+    <tr style="background:#f3f4f6;text-align:right;">
+      <th style="padding:8px;text-align:left;">{{.Labels.nutrient}}</th>
+      <th style="padding:8px;">{{.Labels.total}}</th>
+      <th style="padding:8px;">{{.Labels.target}}</th>
+      <th style="padding:8px;">{{.Labels.average}}</th>
+      <th style="padding:8px;">{{.Labels.percent}}</th>
+    </tr>
+    {{- range .Nutrients}}
+    <tr style="text-align:right;border-bottom:1px solid #e5e7eb;">
+      <td style="padding:8px;text-align:left;">{{.Name}}</td>
+      <td style="padding:8px;">{{.Total}}</td>
+      <td style="padding:8px;">{{.Target}}</td>
+      <td style="padding:8px;">{{.Average}}</td>
+      <td style="padding:8px;{{if .Over}}color:#dc2626;font-weight:bold;{{end}}">{{.Percent}}</td>
+    </tr>
+    {{- end}}
+  </table>
+
+  <h2 style="font-size:18px;margin:24px 0 8px;">{{.Labels.macro_split}}</h2>
+  <table style="width:100%;border-collapse:collapse;font-size:14px;">
+    {{- range .Split}}
+    <tr>
+      <td style="padding:4px 8px 4px 0;width:120px;">{{.Name}}</td>
+      <td style="padding:4px 0;">
+        <div style="background:#e5e7eb;border-radius:4px;height:14px;position:relative;">
+          <div style="background:#22c55e;border-radius:4px;height:14px;width:{{printf "%.1f" .Actual}}%;"></div>
+        </div>
+      </td>
+      <td style="padding:4px 0 4px 8px;width:140px;text-align:right;">{{printf "%.0f" .Actual}}%{{if .Target}} / {{printf "%.0f" .Target}}%{{end}}</td>
+    </tr>
+    {{- end}}
+  </table>
+  {{- if .HasTargets}}
+  <p style="font-size:12px;color:#6b7280;margin:4px 0 0;">{{.Labels.actual}} / {{.Labels.target}}</p>
+  {{- end}}
+
+  <h2 style="font-size:18px;margin:24px 0 8px;">{{.Labels.daily}}</h2>
+  <table role="presentation" style="width:100%;border-collapse:collapse;table-layout:fixed;">
+    <tr style="height:160px;vertical-align:bottom;">
+      {{- range .Days}}
+      <td style="padding:0 1px;text-align:center;" title="{{.Calories}} kcal">
+        <div style="background:#22c55e;border-radius:3px 3px 0 0;height:{{percent .Height}};min-height:1px;"></div>
+      </td>
+      {{- end}}
+    </tr>
+    <tr>
+      {{- range .Days}}
+      <td style="font-size:10px;color:#6b7280;text-align:center;padding-top:4px;">{{.Label}}</td>
+      {{- end}}
+    </tr>
+  </table>
+
+  <h2 style="font-size:18px;margin:24px 0 8px;">{{.Labels.top_foods}}</h2>
+  {{- if .Foods}}
+  <table style="width:100%;border-collapse:collapse;font-size:14px;">
+    <tr style="background:#f3f4f6;text-align:right;">
+      <th style="padding:8px;text-align:left;">{{.Labels.food}}</th>
+      <th style="padding:8px;">{{.Labels.times}}</th>
+      <th style="padding:8px;">{{.Labels.calories}}</th>
+      <th style="padding:8px;">{{.Labels.share}}</th>
+    </tr>
+    {{- range .Foods}}
+    <tr style="text-align:right;border-bottom:1px solid #e5e7eb;">
+      <td style="padding:8px;text-align:left;">{{.Name}}</td>
+      <td style="padding:8px;">{{.Count}}</td>
+      <td style="padding:8px;">{{.Calories}}</td>
+      <td style="padding:8px;">{{.Share}}</td>
+    </tr>
+    {{- end}}
+  </table>
+  {{- else}}
+  <p style="font-size:13px;color:#6b7280;">{{.Labels.no_meals}}</p>
+  {{- end}}
+
+  <h2 style="font-size:18px;margin:24px 0 8px;">{{.Labels.weight}}</h2>
+  {{- with .Weight}}
+  <table role="presentation" style="width:100%;border-collapse:collapse;margin-bottom:8px;"><tr>
+    <td style="padding:8px;"><div style="font-size:12px;color:#6b7280;">{{$.Labels.start}}</div><div style="font-size:16px;font-weight:bold;">{{.Start}}</div></td>
+    <td style="padding:8px;"><div style="font-size:12px;color:#6b7280;">{{$.Labels.end}}</div><div style="font-size:16px;font-weight:bold;">{{.End}}</div></td>
+    <td style="padding:8px;"><div style="font-size:12px;color:#6b7280;">{{$.Labels.trend_change}}</div><div style="font-size:16px;font-weight:bold;">{{.Change}}</div></td>
+    <td style="padding:8px;"><div style="font-size:12px;color:#6b7280;">{{$.Labels.weigh_ins}}</div><div style="font-size:16px;font-weight:bold;">{{.Entries}}</div></td>
+  </tr></table>
+  <svg viewBox="0 0 100 40" preserveAspectRatio="none" style="width:100%;height:120px;background:#f9fafb;">
+    <polyline fill="none" stroke="#9ca3af" stroke-width="0.4" points="{{points .Weights}}"/>
+    <polyline fill="none" stroke="#15803d" stroke-width="0.8" points="{{points .Trend}}"/>
+  </svg>
+  {{- else}}
+  <p style="font-size:13px;color:#6b7280;">{{.Labels.no_weight}}</p>
+  {{- end}}
+
+  <p style="margin:32px 0 0;font-size:12px;color:#9ca3af;text-align:center;">{{.Footer}}</p>
+</div>
+</body>
+</html>
+`))
+
+// svgPoints lays out values scaled to 0-1 across a 100x40 viewBox
+func svgPoints(values []float64) string {
+	points := make([]string, len(values))
+	for i, v := range values {
+		x := 50.0
+		if len(values) > 1 {
+			x = float64(i) * 100 / float64(len(values)-1)
+		}
+		points[i] = fmt.Sprintf("%.2f,%.2f", x, 38-v*36)
+	}
+	return strings.Join(points, " ")
+}
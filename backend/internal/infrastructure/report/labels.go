@@ -0,0 +1,150 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+)
+
+// labels holds report text in Thai and English
+var labels = map[string]map[string]string{
+	"th": {
+		"weekly":       "รายงานโภชนาการประจำสัปดาห์",
+		"monthly":      "รายงานโภชนาการประจำเดือน",
+		"generated":    "สร้างเมื่อ",
+		"days_logged":  "วันที่บันทึก",
+		"on_target":    "วันที่แคลอรี่อยู่ในเป้าหมาย",
+		"totals":       "ยอดรวมเทียบกับเป้าหมาย",
+		"nutrient":     "สารอาหาร",
+		"total":        "รวม",
+		"target":       "เป้าหมาย",
+		"average":      "เฉลี่ยต่อวัน",
+		"percent":      "% ของเป้าหมาย",
+		"macro_split":  "สัดส่วนพลังงานจากสารอาหารหลัก",
+		"actual":       "ที่ได้รับ",
+		"top_foods":    "อาหารที่ให้พลังงานมากที่สุด",
+		"food":         "อาหาร",
+		"times":        "ครั้ง",
+		"share":        "สัดส่วน",
+		"daily":        "แคลอรี่รายวัน",
+		"weight":       "แนวโน้มน้ำหนัก",
+		"start":        "เริ่มต้น",
+		"end":          "ล่าสุด",
+		"trend_change": "การเปลี่ยนแปลงตามแนวโน้ม",
+		"weigh_ins":    "จำนวนครั้งที่ชั่ง",
+		"no_meals":     "ไม่มีการบันทึกอาหารในช่วงนี้",
+		"no_weight":    "ไม่มีการบันทึกน้ำหนักในช่วงนี้",
+		"no_targets":   "ตั้งค่าโปรไฟล์ให้เสร็จเพื่อเปรียบเทียบกับเป้าหมาย",
+		"footer":       "สร้างโดย ByteTrack",
+		"calories":     "แคลอรี่",
+		"protein":      "โปรตีน",
+		"carbs":        "คาร์โบไฮเดรต",
+		"fat":          "ไขมัน",
+		"fiber":        "ใยอาหาร",
+		"sugar":        "น้ำตาล",
+		"sodium":       "โซเดียม",
+		"summary":      "บันทึก %d จาก %d วัน เฉลี่ย %d แคลอรี่ต่อวัน",
+	},
+	"en": {
+		"weekly":       "Weekly nutrition report",
+		"monthly":      "Monthly nutrition report",
+		"generated":    "Generated",
+		"days_logged":  "Days logged",
+		"on_target":    "Days on calorie target",
+		"totals":       "Totals against targets",
+		"nutrient":     "Nutrient",
+		"total":        "Total",
+		"target":       "Target",
+		"average":      "Daily average",
+		"percent":      "% of target",
+		"macro_split":  "Calories by macronutrient",
+		"actual":       "Actual",
+		"top_foods":    "Top calorie contributors",
+		"food":         "Food",
+		"times":        "Times",
+		"share":        "Share",
+		"daily":        "Daily calories",
+		"weight":       "Weight trend",
+		"start":        "Start",
+		"end":          "Latest",
+		"trend_change": "Trend change",
+		"weigh_ins":    "Weigh-ins",
+		"no_meals":     "No meals logged this period",
+		"no_weight":    "No weigh-ins this period",
+		"no_targets":   "Complete your profile to compare against targets",
+		"footer":       "Generated by ByteTrack",
+		"calories":     "Calories",
+		"protein":      "Protein",
+		"carbs":        "Carbs",
+		"fat":          "Fat",
+		"fiber":        "Fiber",
+		"sugar":        "Sugar",
+		"sodium":       "Sodium",
+		"summary":      "Logged %d of %d days, averaging %d calories a day",
+	},
+}
+
+var thaiMonths = [12]string{"ม.ค.", "ก.พ.", "มี.ค.", "เม.ย.", "พ.ค.", "มิ.ย.", "ก.ค.", "ส.ค.", "ก.ย.", "ต.ค.", "พ.ย.", "ธ.ค."}
+
+var thaiWeekdays = [7]string{"อา.", "จ.", "อ.", "พ.", "พฤ.", "ศ.", "ส."}
+
+// language normalizes a report language, defaulting to Thai
+func language(lang string) string {
+	if lang == "en" {
+		return "en"
+	}
+	return "th"
+}
+
+// label returns a label in the report language
+func label(lang, key string) string {
+	return labels[language(lang)][key]
+}
+
+// Title returns a report's title
+func Title(r *entity.Report) string {
+	return label(r.Language, string(r.Period)) + " " + PeriodLabel(r)
+}
+
+// Summary returns a one-line summary of a report, used as the email body
+func Summary(r *entity.Report) string {
+	average := 0
+	for _, n := range r.Nutrients {
+		if n.Nutrient == "calories" {
+			average = int(n.Average + 0.5)
+		}
+	}
+	return fmt.Sprintf(label(r.Language, "summary"), r.DaysLogged, r.Days, average)
+}
+
+// PeriodLabel formats a report's date range, using Buddhist era years in Thai
+func PeriodLabel(r *entity.Report) string {
+	return formatDate(r.Language, r.From) + " – " + formatDate(r.Language, r.To)
+}
+
+func formatDate(lang string, t time.Time) string {
+	if language(lang) == "th" {
+		return fmt.Sprintf("%d %s %d", t.Day(), thaiMonths[t.Month()-1], t.Year()+543)
+	}
+	return t.Format("2 Jan 2006")
+}
+
+// formatDay formats a day of the period for chart labels
+func formatDay(lang string, t time.Time, period entity.ReportPeriod) string {
+	if period == entity.ReportMonthly {
+		return fmt.Sprintf("%d", t.Day())
+	}
+	if language(lang) == "th" {
+		return thaiWeekdays[t.Weekday()]
+	}
+	return t.Format("Mon")
+}
+
+// foodName picks a food's name in the report language
+func foodName(lang string, food *entity.ReportFood) string {
+	if language(lang) == "en" && food.NameEn != "" {
+		return food.NameEn
+	}
+	return food.Name
+}
@@ -0,0 +1,262 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/bytetrack/backend/internal/pkg/pdf"
+)
+
+var (
+	green     = pdf.Color{R: 21, G: 128, B: 61}
+	lightBar  = pdf.Color{R: 34, G: 197, B: 94}
+	grey      = pdf.Color{R: 107, G: 114, B: 128}
+	lightGrey = pdf.Color{R: 229, G: 231, B: 235}
+	red       = pdf.Color{R: 220, G: 38, B: 38}
+)
+
+const (
+	margin     = 48.0
+	lineHeight = 18.0
+)
+
+// PDFRenderer renders reports as PDF. Thai text needs a TrueType font with
+// Thai glyphs; without one, Thai reports are rendered with English labels.
+type PDFRenderer struct {
+	font *pdf.TrueTypeFont
+}
+
+// NewPDFRenderer creates a PDF renderer, embedding the TrueType font at
+// fontPath when set
+func NewPDFRenderer(fontPath string) (*PDFRenderer, error) {
+	if fontPath == "" {
+		return &PDFRenderer{}, nil
+	}
+
+	data, err := os.ReadFile(fontPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report font: %w", err)
+	}
+	font, err := pdf.ParseTrueType(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report font: %w", err)
+	}
+	return &PDFRenderer{font: font}, nil
+}
+
+// SupportsThai reports whether PDFs can render Thai text
+func (p *PDFRenderer) SupportsThai() bool {
+	return p.font != nil && p.font.Glyph('ก') != 0
+}
+
+// Render renders a report as PDF
+func (p *PDFRenderer) Render(r *entity.Report) []byte {
+	if language(r.Language) == "th" && !p.SupportsThai() {
+		english := *r
+		english.Language = "en"
+		r = &english
+	}
+	v := newView(r)
+
+	doc := pdf.New(p.font)
+	doc.SetTitle(v.Title + " " + v.Period)
+	w := &pdfWriter{doc: doc}
+	w.newPage()
+
+	w.text(margin, 20, v.Title, green, true)
+	w.y += 8
+	w.text(margin, 12, v.Period, pdf.Black, false)
+	w.text(margin, 9, v.Generated, grey, false)
+	w.y += 8
+
+	// Stats across the page
+	width, _ := doc.Size()
+	col := (width - 2*margin) / float64(len(v.Stats))
+	for i, s := range v.Stats {
+		x := margin + float64(i)*col
+		doc.Text(x, w.y+10, 9, s.Label, grey, false)
+		doc.Text(x, w.y+28, 14, s.Value, pdf.Black, true)
+	}
+	w.y += 44
+
+	// Totals against targets
+	w.heading(v.Labels["totals"])
+	if !v.HasTargets {
+		w.text(margin, 9, v.Labels["no_targets"], grey, false)
+	}
+	cols := []float64{margin, 270, 360, 450, width - margin}
+	w.row(cols, 9, grey, true, v.Labels["nutrient"], v.Labels["total"], v.Labels["target"], v.Labels["average"], v.Labels["percent"])
+	for _, n := range v.Nutrients {
+		if !n.Over {
+			w.row(cols, 10, pdf.Black, false, n.Name, n.Total, n.Target, n.Average, n.Percent)
+			continue
+		}
+		// Highlight the percentage of an exceeded target
+		w.row(cols, 10, pdf.Black, false, n.Name, n.Total, n.Target, n.Average, "")
+		doc.Text(width-margin-doc.TextWidth(n.Percent, 10), w.y-lineHeight+13, 10, n.Percent, red, true)
+	}
+
+	// Macro split bars with the target marked
+	w.heading(v.Labels["macro_split"])
+	barX, barW := margin+110, width-2*margin-190
+	for _, s := range v.Split {
+		w.ensure(lineHeight)
+		doc.Text(margin, w.y+11, 10, s.Name, pdf.Black, false)
+		doc.Rect(barX, w.y+3, barW, 9, lightGrey)
+		doc.Rect(barX, w.y+3, barW*clamp(s.Actual/100), 9, lightBar)
+		value := strconv.FormatFloat(s.Actual, 'f', 0, 64) + "%"
+		if s.Target > 0 {
+			x := barX + barW*clamp(s.Target/100)
+			doc.Line(x, w.y, x, w.y+15, 1.5, green)
+			value += " / " + strconv.FormatFloat(s.Target, 'f', 0, 64) + "%"
+		}
+		doc.Text(width-margin-doc.TextWidth(value, 10), w.y+11, 10, value, pdf.Black, false)
+		w.y += lineHeight
+	}
+
+	// Daily calories bar chart with the target line
+	w.heading(v.Labels["daily"])
+	chartH := 100.0
+	w.ensure(chartH + 20)
+	chartW := width - 2*margin
+	slot := chartW / float64(len(v.Days))
+	for i, d := range v.Days {
+		h := chartH * d.Height
+		x := margin + float64(i)*slot
+		doc.Rect(x+slot*0.15, w.y+chartH-h, slot*0.7, h, lightBar)
+		doc.Text(x+(slot-doc.TextWidth(d.Label, 7))/2, w.y+chartH+10, 7, d.Label, grey, false)
+	}
+	if v.TargetLine > 0 {
+		y := w.y + chartH*(1-v.TargetLine)
+		doc.Line(margin, y, width-margin, y, 0.8, green)
+	}
+	doc.Line(margin, w.y+chartH, width-margin, w.y+chartH, 0.5, grey)
+	w.y += chartH + 16
+
+	// Top calorie contributors
+	w.heading(v.Labels["top_foods"])
+	if len(v.Foods) == 0 {
+		w.text(margin, 10, v.Labels["no_meals"], grey, false)
+	} else {
+		cols := []float64{margin, 380, 460, width - margin}
+		w.row(cols, 9, grey, true, v.Labels["food"], v.Labels["times"], v.Labels["calories"], v.Labels["share"])
+		for _, f := range v.Foods {
+			w.row(cols, 10, pdf.Black, false, f.Name, strconv.Itoa(f.Count), f.Calories, f.Share)
+		}
+	}
+
+	// Weight trend
+	w.heading(v.Labels["weight"])
+	if v.Weight == nil {
+		w.text(margin, 10, v.Labels["no_weight"], grey, false)
+	} else {
+		wv := v.Weight
+		stats := []stat{
+			{v.Labels["start"], wv.Start}, {v.Labels["end"], wv.End},
+			{v.Labels["trend_change"], wv.Change}, {v.Labels["weigh_ins"], strconv.Itoa(wv.Entries)},
+		}
+		w.ensure(40 + chartH)
+		col := (width - 2*margin) / float64(len(stats))
+		for i, s := range stats {
+			x := margin + float64(i)*col
+			doc.Text(x, w.y+10, 9, s.Label, grey, false)
+			doc.Text(x, w.y+26, 12, s.Value, pdf.Black, true)
+		}
+		w.y += 36
+
+		doc.Rect(margin, w.y, chartW, chartH, pdf.Color{R: 249, G: 250, B: 251})
+		doc.Text(margin+2, w.y+9, 7, wv.Max, grey, false)
+		doc.Text(margin+2, w.y+chartH-3, 7, wv.Min, grey, false)
+		p.polyline(doc, wv.Weights, margin, w.y, chartW, chartH, 0.6, grey)
+		p.polyline(doc, wv.Trend, margin, w.y, chartW, chartH, 1.5, green)
+		w.y += chartH + 8
+	}
+
+	w.y += 16
+	w.ensure(lineHeight)
+	w.text(margin, 8, v.Footer, grey, false)
+
+	return doc.Bytes()
+}
+
+// polyline draws values scaled to 0-1 across a chart area
+func (p *PDFRenderer) polyline(doc *pdf.Document, values []float64, x, y, w, h, width float64, color pdf.Color) {
+	point := func(i int) (float64, float64) {
+		px := x + w/2
+		if len(values) > 1 {
+			px = x + 8 + float64(i)*(w-16)/float64(len(values)-1)
+		}
+		return px, y + h - 6 - values[i]*(h-12)
+	}
+	if len(values) == 1 {
+		px, py := point(0)
+		doc.Rect(px-2, py-2, 4, 4, color)
+		return
+	}
+	for i := 1; i < len(values); i++ {
+		x1, y1 := point(i - 1)
+		x2, y2 := point(i)
+		doc.Line(x1, y1, x2, y2, width, color)
+	}
+}
+
+// pdfWriter lays content out top to bottom, adding pages as needed
+type pdfWriter struct {
+	doc *pdf.Document
+	y   float64
+}
+
+func (w *pdfWriter) newPage() {
+	w.doc.AddPage()
+	w.y = margin
+}
+
+// ensure starts a new page unless height fits on the current one
+func (w *pdfWriter) ensure(height float64) {
+	_, pageHeight := w.doc.Size()
+	if w.y+height > pageHeight-margin {
+		w.newPage()
+	}
+}
+
+func (w *pdfWriter) text(x, size float64, text string, color pdf.Color, bold bool) {
+	w.ensure(size + 6)
+	w.y += size + 4
+	w.doc.Text(x, w.y, size, text, color, bold)
+	w.y += 2
+}
+
+func (w *pdfWriter) heading(text string) {
+	w.y += 14
+	w.ensure(lineHeight * 3)
+	w.text(margin, 13, text, green, true)
+	w.y += 4
+}
+
+// row draws a table row. The first cell is left aligned at cols[0] and the
+// rest are right aligned at their column's position.
+func (w *pdfWriter) row(cols []float64, size float64, color pdf.Color, bold bool, cells ...string) {
+	w.ensure(lineHeight)
+	baseline := w.y + 13
+	for i, cell := range cells {
+		if i == 0 {
+			w.doc.Text(cols[0], baseline, size, cell, color, bold)
+			continue
+		}
+		w.doc.Text(cols[i]-w.doc.TextWidth(cell, size), baseline, size, cell, color, bold)
+	}
+	w.doc.Line(margin, w.y+lineHeight, cols[len(cols)-1], w.y+lineHeight, 0.3, lightGrey)
+	w.y += lineHeight
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
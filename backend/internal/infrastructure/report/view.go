@@ -0,0 +1,231 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+)
+
+// view is a report formatted for display, shared by the HTML and PDF renderers
+type view struct {
+	Lang       string
+	Title      string
+	Period     string
+	Generated  string
+	Footer     string
+	Labels     map[string]string
+	Stats      []stat
+	Nutrients  []nutrientRow
+	HasTargets bool
+	Split      []splitRow
+	Foods      []foodRow
+	Days       []dayBar
+	TargetLine float64 // target as a fraction of the chart height; 0 without a target
+	Weight     *weightView
+}
+
+type stat struct {
+	Label string
+	Value string
+}
+
+type nutrientRow struct {
+	Name    string
+	Total   string
+	Target  string
+	Average string
+	Percent string
+	Over    bool // a limit was exceeded or a goal overshot
+}
+
+type splitRow struct {
+	Name   string
+	Actual float64
+	Target float64 // 0 without targets
+}
+
+type foodRow struct {
+	Name     string
+	Count    int
+	Calories string
+	Share    string
+}
+
+type dayBar struct {
+	Label    string
+	Calories int
+	Height   float64 // fraction of the chart height
+}
+
+type weightView struct {
+	Start   string
+	End     string
+	Change  string
+	Entries int
+	Trend   []float64 // trend weights scaled to 0-1
+	Weights []float64 // weigh-ins scaled to 0-1
+	Min     string
+	Max     string
+}
+
+// nutrientUnits holds display units by nutrient
+var nutrientUnits = map[string]string{
+	"calories": "kcal",
+	"sodium":   "mg",
+}
+
+func newView(r *entity.Report) *view {
+	lang := language(r.Language)
+	v := &view{
+		Lang:      lang,
+		Title:     label(lang, string(r.Period)),
+		Period:    PeriodLabel(r),
+		Generated: label(lang, "generated") + " " + formatDate(lang, r.GeneratedAt),
+		Footer:    label(lang, "footer"),
+		Labels:    labels[lang],
+		Stats: []stat{
+			{Label: label(lang, "days_logged"), Value: fmt.Sprintf("%d / %d", r.DaysLogged, r.Days)},
+		},
+	}
+
+	for _, n := range r.Nutrients {
+		unit := nutrientUnits[n.Nutrient]
+		if unit == "" {
+			unit = "g"
+		}
+		row := nutrientRow{
+			Name:    label(lang, n.Nutrient),
+			Total:   formatAmount(n.Total, unit),
+			Average: formatAmount(n.Average, unit),
+			Target:  "–",
+			Percent: "–",
+		}
+		if n.Target != nil && n.Percent != nil {
+			v.HasTargets = true
+			row.Target = formatAmount(*n.Target, unit)
+			row.Percent = strconv.FormatFloat(*n.Percent, 'f', 0, 64) + "%"
+			row.Over = *n.Percent > 100 && (n.IsLimit || *n.Percent > 110)
+		}
+		v.Nutrients = append(v.Nutrients, row)
+
+		if n.Nutrient == "calories" && n.DailyTarget != nil {
+			v.Stats = append(v.Stats, stat{Label: label(lang, "on_target"), Value: fmt.Sprintf("%d / %d", r.DaysOnTarget, r.DaysLogged)})
+		}
+	}
+	v.Stats = append(v.Stats, stat{Label: label(lang, "average"), Value: averageCalories(r)})
+
+	v.Split = []splitRow{
+		{Name: label(lang, "protein"), Actual: r.MacroSplit.ProteinPercent},
+		{Name: label(lang, "carbs"), Actual: r.MacroSplit.CarbsPercent},
+		{Name: label(lang, "fat"), Actual: r.MacroSplit.FatPercent},
+	}
+	if r.TargetSplit != nil {
+		v.Split[0].Target = r.TargetSplit.ProteinPercent
+		v.Split[1].Target = r.TargetSplit.CarbsPercent
+		v.Split[2].Target = r.TargetSplit.FatPercent
+	}
+
+	for _, food := range r.TopFoods {
+		v.Foods = append(v.Foods, foodRow{
+			Name:     foodName(lang, food),
+			Count:    food.Count,
+			Calories: formatAmount(float64(food.Calories), "kcal"),
+			Share:    strconv.FormatFloat(food.Percent, 'f', 0, 64) + "%",
+		})
+	}
+
+	// Scale bars so the tallest day or the target fills most of the chart
+	scale := 0
+	target := 0
+	for _, day := range r.Daily {
+		if day.Calories > scale {
+			scale = day.Calories
+		}
+		if day.Target > target {
+			target = day.Target
+		}
+	}
+	if target > scale {
+		scale = target
+	}
+	for _, day := range r.Daily {
+		bar := dayBar{Label: formatDay(lang, day.Date, r.Period), Calories: day.Calories}
+		if scale > 0 {
+			bar.Height = float64(day.Calories) / float64(scale)
+		}
+		v.Days = append(v.Days, bar)
+	}
+	if scale > 0 {
+		v.TargetLine = float64(target) / float64(scale)
+	}
+
+	if w := r.Weight; w != nil && len(w.Points) > 0 {
+		wv := &weightView{
+			Start:   formatAmount(w.StartWeight, "kg"),
+			End:     formatAmount(w.EndWeight, "kg"),
+			Change:  signed(w.TrendChange) + " kg",
+			Entries: w.Entries,
+		}
+		low, high := math.Inf(1), math.Inf(-1)
+		for _, p := range w.Points {
+			low = math.Min(low, math.Min(p.Weight, p.Trend))
+			high = math.Max(high, math.Max(p.Weight, p.Trend))
+		}
+		// Pad the range so a flat trend sits mid-chart
+		low, high = math.Floor(low-0.5), math.Ceil(high+0.5)
+		for _, p := range w.Points {
+			wv.Weights = append(wv.Weights, (p.Weight-low)/(high-low))
+			wv.Trend = append(wv.Trend, (p.Trend-low)/(high-low))
+		}
+		wv.Min = strconv.FormatFloat(low, 'f', 0, 64)
+		wv.Max = strconv.FormatFloat(high, 'f', 0, 64)
+		v.Weight = wv
+	}
+
+	return v
+}
+
+func averageCalories(r *entity.Report) string {
+	for _, n := range r.Nutrients {
+		if n.Nutrient == "calories" {
+			return formatAmount(n.Average, "kcal")
+		}
+	}
+	return "–"
+}
+
+// formatAmount formats a value with thousands separators and its unit
+func formatAmount(v float64, unit string) string {
+	decimals := 0
+	if unit == "g" || unit == "kg" {
+		decimals = 1
+	}
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i:]
+	}
+	var b strings.Builder
+	if v < 0 {
+		b.WriteByte('-')
+	}
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String() + frac + " " + unit
+}
+
+func signed(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	if v > 0 {
+		return "+" + s
+	}
+	return s
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// AchievementRepository handles achievement, streak and counter data operations
+type AchievementRepository struct {
+	db DB
+}
+
+// NewAchievementRepository creates a new achievement repository
+func NewAchievementRepository(db DB) *AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+// FindAchievements finds the achievement catalog in display order
+func (r *AchievementRepository) FindAchievements(ctx context.Context) ([]*entity.Achievement, error) {
+	sql := `
+		SELECT id, category, metric, threshold, name, name_en, description, description_en, emoji, sort_order
+		FROM achievements
+		ORDER BY sort_order, id
+	`
+
+	rows, err := r.db.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var achievements []*entity.Achievement
+	for rows.Next() {
+		a := &entity.Achievement{}
+		err := rows.Scan(
+			&a.ID, &a.Category, &a.Metric, &a.Threshold, &a.Name, &a.NameEn,
+			&a.Description, &a.DescriptionEn, &a.Emoji, &a.SortOrder,
+		)
+		if err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, a)
+	}
+
+	return achievements, rows.Err()
+}
+
+// FindUnlocked finds when each of the user's achievements was unlocked
+func (r *AchievementRepository) FindUnlocked(ctx context.Context, userID uuid.UUID) (map[string]time.Time, error) {
+	sql := `SELECT achievement_id, unlocked_at FROM user_achievements WHERE user_id = $1`
+
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unlocked := map[string]time.Time{}
+	for rows.Next() {
+		var id string
+		var at time.Time
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, err
+		}
+		unlocked[id] = at
+	}
+
+	return unlocked, rows.Err()
+}
+
+// Unlock records an earned achievement; unlocking it again is a no-op
+func (r *AchievementRepository) Unlock(ctx context.Context, userID uuid.UUID, achievementID string) (time.Time, error) {
+	sql := `
+		INSERT INTO user_achievements (user_id, achievement_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, achievement_id) DO UPDATE SET unlocked_at = user_achievements.unlocked_at
+		RETURNING unlocked_at
+	`
+
+	var at time.Time
+	err := r.db.QueryRow(ctx, sql, userID, achievementID).Scan(&at)
+	return at, err
+}
+
+// SetStreakDay marks whether a date counts towards a streak kind
+func (r *AchievementRepository) SetStreakDay(ctx context.Context, userID uuid.UUID, kind entity.StreakKind, date time.Time, qualifies bool) error {
+	sql := `DELETE FROM streak_days WHERE user_id = $1 AND kind = $2 AND date = $3`
+	if qualifies {
+		sql = `
+			INSERT INTO streak_days (user_id, kind, date)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+		`
+	}
+
+	_, err := r.db.Exec(ctx, sql, userID, kind, date)
+	return err
+}
+
+// FindStreakDays finds a user's qualifying days for a streak kind since a date, oldest first
+func (r *AchievementRepository) FindStreakDays(ctx context.Context, userID uuid.UUID, kind entity.StreakKind, from time.Time) ([]time.Time, error) {
+	sql := `
+		SELECT date
+		FROM streak_days
+		WHERE user_id = $1 AND kind = $2 AND date >= $3
+		ORDER BY date
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, kind, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+// CountStreakDays counts a user's qualifying days for a streak kind
+func (r *AchievementRepository) CountStreakDays(ctx context.Context, userID uuid.UUID, kind entity.StreakKind) (int, error) {
+	sql := `SELECT COUNT(*) FROM streak_days WHERE user_id = $1 AND kind = $2`
+
+	var count int
+	err := r.db.QueryRow(ctx, sql, userID, kind).Scan(&count)
+	return count, err
+}
+
+// IncrementCounter increments a user's event counter
+func (r *AchievementRepository) IncrementCounter(ctx context.Context, userID uuid.UUID, metric entity.AchievementMetric) error {
+	sql := `
+		INSERT INTO achievement_counters (user_id, metric, value)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (user_id, metric) DO UPDATE SET value = achievement_counters.value + 1
+	`
+
+	_, err := r.db.Exec(ctx, sql, userID, metric)
+	return err
+}
+
+// FindCounters finds a user's event counters
+func (r *AchievementRepository) FindCounters(ctx context.Context, userID uuid.UUID) (map[entity.AchievementMetric]int, error) {
+	sql := `SELECT metric, value FROM achievement_counters WHERE user_id = $1`
+
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counters := map[entity.AchievementMetric]int{}
+	for rows.Next() {
+		var metric entity.AchievementMetric
+		var value int
+		if err := rows.Scan(&metric, &value); err != nil {
+			return nil, err
+		}
+		counters[metric] = value
+	}
+
+	return counters, rows.Err()
+}
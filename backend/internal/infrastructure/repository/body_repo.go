@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// BodyRepository handles body measurement data operations
+type BodyRepository struct {
+	db DB
+}
+
+// NewBodyRepository creates a new body measurement repository
+func NewBodyRepository(db DB) *BodyRepository {
+	return &BodyRepository{db: db}
+}
+
+// Upsert records body measurements, replacing any existing measurement for the same date
+func (r *BodyRepository) Upsert(ctx context.Context, measurement *entity.BodyMeasurement) error {
+	sql := `
+		INSERT INTO body_measurements (id, user_id, date, body_fat_percent, waist_cm, hip_cm, neck_cm, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id, date) DO UPDATE
+		SET body_fat_percent = EXCLUDED.body_fat_percent, waist_cm = EXCLUDED.waist_cm,
+			hip_cm = EXCLUDED.hip_cm, neck_cm = EXCLUDED.neck_cm, note = EXCLUDED.note,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		measurement.ID, measurement.UserID, measurement.Date,
+		measurement.BodyFatPercent, measurement.WaistCM, measurement.HipCM, measurement.NeckCM, measurement.Note,
+	).Scan(&measurement.ID, &measurement.CreatedAt, &measurement.UpdatedAt)
+}
+
+// FindByID finds a body measurement by ID
+func (r *BodyRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.BodyMeasurement, error) {
+	sql := `
+		SELECT id, user_id, date, body_fat_percent, waist_cm, hip_cm, neck_cm, note, created_at, updated_at
+		FROM body_measurements
+		WHERE id = $1
+	`
+
+	measurement, err := scanBodyMeasurement(r.db.QueryRow(ctx, sql, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return measurement, nil
+}
+
+// FindByUserID finds a user's body measurements between two dates (inclusive), oldest first
+func (r *BodyRepository) FindByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entity.BodyMeasurement, error) {
+	sql := `
+		SELECT id, user_id, date, body_fat_percent, waist_cm, hip_cm, neck_cm, note, created_at, updated_at
+		FROM body_measurements
+		WHERE user_id = $1 AND date BETWEEN $2 AND $3
+		ORDER BY date
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var measurements []*entity.BodyMeasurement
+	for rows.Next() {
+		measurement, err := scanBodyMeasurement(rows)
+		if err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, measurement)
+	}
+
+	return measurements, rows.Err()
+}
+
+// FindLatest finds a user's most recent body measurement
+func (r *BodyRepository) FindLatest(ctx context.Context, userID uuid.UUID) (*entity.BodyMeasurement, error) {
+	sql := `
+		SELECT id, user_id, date, body_fat_percent, waist_cm, hip_cm, neck_cm, note, created_at, updated_at
+		FROM body_measurements
+		WHERE user_id = $1
+		ORDER BY date DESC
+		LIMIT 1
+	`
+
+	measurement, err := scanBodyMeasurement(r.db.QueryRow(ctx, sql, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return measurement, nil
+}
+
+// Update updates a body measurement. Moving it onto a date that already has
+// a measurement returns ErrDuplicateDate.
+func (r *BodyRepository) Update(ctx context.Context, measurement *entity.BodyMeasurement) error {
+	sql := `
+		UPDATE body_measurements
+		SET date = $2, body_fat_percent = $3, waist_cm = $4, hip_cm = $5, neck_cm = $6, note = $7,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, sql,
+		measurement.ID, measurement.Date,
+		measurement.BodyFatPercent, measurement.WaistCM, measurement.HipCM, measurement.NeckCM, measurement.Note,
+	).Scan(&measurement.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return ErrDuplicateDate
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete deletes a body measurement owned by the user
+func (r *BodyRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	sql := `DELETE FROM body_measurements WHERE id = $1 AND user_id = $2`
+	tag, err := r.db.Exec(ctx, sql, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// scanBodyMeasurement scans a body measurement row
+func scanBodyMeasurement(row pgx.Row) (*entity.BodyMeasurement, error) {
+	measurement := &entity.BodyMeasurement{}
+	err := row.Scan(
+		&measurement.ID, &measurement.UserID, &measurement.Date,
+		&measurement.BodyFatPercent, &measurement.WaistCM, &measurement.HipCM, &measurement.NeckCM,
+		&measurement.Note, &measurement.CreatedAt, &measurement.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return measurement, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ExerciseRepository handles exercise data operations
+type ExerciseRepository struct {
+	db DB
+}
+
+// NewExerciseRepository creates a new exercise repository
+func NewExerciseRepository(db DB) *ExerciseRepository {
+	return &ExerciseRepository{db: db}
+}
+
+// Activity catalog operations
+
+// FindActivities finds the built-in activities plus the user's custom ones
+func (r *ExerciseRepository) FindActivities(ctx context.Context, userID uuid.UUID) ([]*entity.ExerciseActivity, error) {
+	sql := `
+		SELECT id, user_id, name, name_en, category, met_light, met_moderate, met_vigorous, emoji, created_at
+		FROM exercise_activities
+		WHERE user_id IS NULL OR user_id = $1
+		ORDER BY user_id NULLS FIRST, category, name_en
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activities []*entity.ExerciseActivity
+	for rows.Next() {
+		activity, err := scanActivity(rows)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, activity)
+	}
+
+	return activities, rows.Err()
+}
+
+// FindActivityByID finds a built-in activity or one of the user's custom activities
+func (r *ExerciseRepository) FindActivityByID(ctx context.Context, id string, userID uuid.UUID) (*entity.ExerciseActivity, error) {
+	sql := `
+		SELECT id, user_id, name, name_en, category, met_light, met_moderate, met_vigorous, emoji, created_at
+		FROM exercise_activities
+		WHERE id = $1 AND (user_id IS NULL OR user_id = $2)
+	`
+
+	activity, err := scanActivity(r.db.QueryRow(ctx, sql, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+// CreateActivity creates a custom activity
+func (r *ExerciseRepository) CreateActivity(ctx context.Context, activity *entity.ExerciseActivity) error {
+	sql := `
+		INSERT INTO exercise_activities (id, user_id, name, name_en, category, met_light, met_moderate, met_vigorous, emoji)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		activity.ID, activity.UserID, activity.Name, activity.NameEn, activity.Category,
+		activity.METLight, activity.METModerate, activity.METVigorous, activity.Emoji,
+	).Scan(&activity.CreatedAt)
+}
+
+// DeleteActivity deletes a custom activity; built-in activities cannot be deleted
+func (r *ExerciseRepository) DeleteActivity(ctx context.Context, id string, userID uuid.UUID) error {
+	sql := `DELETE FROM exercise_activities WHERE id = $1 AND user_id = $2`
+	tag, err := r.db.Exec(ctx, sql, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func scanActivity(row pgx.Row) (*entity.ExerciseActivity, error) {
+	activity := &entity.ExerciseActivity{}
+	err := row.Scan(
+		&activity.ID, &activity.UserID, &activity.Name, &activity.NameEn, &activity.Category,
+		&activity.METLight, &activity.METModerate, &activity.METVigorous, &activity.Emoji, &activity.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	activity.IsCustom = activity.UserID != nil
+	return activity, nil
+}
+
+// Exercise log operations
+
+// CreateLog creates an exercise log
+func (r *ExerciseRepository) CreateLog(ctx context.Context, log *entity.ExerciseLog) error {
+	sql := `
+		INSERT INTO exercise_logs (id, user_id, activity_id, activity_name, activity_name_en, intensity,
+			duration_minutes, met, weight, calories_burned, notes, date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		log.ID, log.UserID, log.ActivityID, log.ActivityName, log.ActivityNameEn, log.Intensity,
+		log.DurationMinutes, log.MET, log.Weight, log.CaloriesBurned, log.Notes, log.Date,
+	).Scan(&log.CreatedAt, &log.UpdatedAt)
+}
+
+// FindLogByID finds an exercise log by ID
+func (r *ExerciseRepository) FindLogByID(ctx context.Context, id uuid.UUID) (*entity.ExerciseLog, error) {
+	sql := `
+		SELECT id, user_id, activity_id, activity_name, activity_name_en, intensity,
+			duration_minutes, met, weight, calories_burned, notes, date, created_at, updated_at
+		FROM exercise_logs
+		WHERE id = $1
+	`
+
+	log, err := scanExerciseLog(r.db.QueryRow(ctx, sql, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return log, nil
+}
+
+// FindLogsByUserID finds exercise logs for a user, optionally filtered by date
+func (r *ExerciseRepository) FindLogsByUserID(ctx context.Context, userID uuid.UUID, date *time.Time) ([]*entity.ExerciseLog, error) {
+	sql := `
+		SELECT id, user_id, activity_id, activity_name, activity_name_en, intensity,
+			duration_minutes, met, weight, calories_burned, notes, date, created_at, updated_at
+		FROM exercise_logs
+		WHERE user_id = $1
+	`
+	args := []interface{}{userID}
+
+	if date != nil {
+		sql += " AND date = $2"
+		args = append(args, *date)
+	}
+
+	sql += " ORDER BY date DESC, created_at DESC"
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*entity.ExerciseLog
+	for rows.Next() {
+		log, err := scanExerciseLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+// UpdateLog updates an exercise log
+func (r *ExerciseRepository) UpdateLog(ctx context.Context, log *entity.ExerciseLog) error {
+	sql := `
+		UPDATE exercise_logs
+		SET activity_id = $2, activity_name = $3, activity_name_en = $4, intensity = $5,
+			duration_minutes = $6, met = $7, weight = $8, calories_burned = $9, notes = $10, date = $11,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, sql,
+		log.ID, log.ActivityID, log.ActivityName, log.ActivityNameEn, log.Intensity,
+		log.DurationMinutes, log.MET, log.Weight, log.CaloriesBurned, log.Notes, log.Date,
+	).Scan(&log.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// DeleteLog deletes an exercise log owned by the user
+func (r *ExerciseRepository) DeleteLog(ctx context.Context, id, userID uuid.UUID) error {
+	sql := `DELETE FROM exercise_logs WHERE id = $1 AND user_id = $2`
+	tag, err := r.db.Exec(ctx, sql, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func scanExerciseLog(row pgx.Row) (*entity.ExerciseLog, error) {
+	log := &entity.ExerciseLog{}
+	err := row.Scan(
+		&log.ID, &log.UserID, &log.ActivityID, &log.ActivityName, &log.ActivityNameEn, &log.Intensity,
+		&log.DurationMinutes, &log.MET, &log.Weight, &log.CaloriesBurned, &log.Notes, &log.Date,
+		&log.CreatedAt, &log.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return log, nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrFastInProgress is returned when starting a fast while another is active
+var ErrFastInProgress = errors.New("a fast is already in progress")
+
+// FastingRepository handles fast and fasting preference data operations
+type FastingRepository struct {
+	db DB
+}
+
+// NewFastingRepository creates a new fasting repository
+func NewFastingRepository(db DB) *FastingRepository {
+	return &FastingRepository{db: db}
+}
+
+const fastColumns = `id, user_id, protocol, target_hours, started_at, ended_at, broken, broken_by_meal_id, auto_started, note, created_at, updated_at`
+
+// Create creates a new fast; only one fast per user may be active
+func (r *FastingRepository) Create(ctx context.Context, fast *entity.Fast) error {
+	sql := `
+		INSERT INTO fasts (id, user_id, protocol, target_hours, started_at, ended_at, broken, broken_by_meal_id, auto_started, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, sql,
+		fast.ID, fast.UserID, fast.Protocol, fast.TargetHours, fast.StartedAt, fast.EndedAt,
+		fast.Broken, fast.BrokenByMealID, fast.AutoStarted, fast.Note,
+	).Scan(&fast.CreatedAt, &fast.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrFastInProgress
+	}
+	return err
+}
+
+// FindActive finds a user's active fast
+func (r *FastingRepository) FindActive(ctx context.Context, userID uuid.UUID) (*entity.Fast, error) {
+	sql := `SELECT ` + fastColumns + ` FROM fasts WHERE user_id = $1 AND ended_at IS NULL`
+
+	return r.findOne(ctx, sql, userID)
+}
+
+// FindByID finds a user's fast by ID
+func (r *FastingRepository) FindByID(ctx context.Context, id, userID uuid.UUID) (*entity.Fast, error) {
+	sql := `SELECT ` + fastColumns + ` FROM fasts WHERE id = $1 AND user_id = $2`
+
+	return r.findOne(ctx, sql, id, userID)
+}
+
+// FindByUserID finds a user's fasts started in [from, to), newest first
+func (r *FastingRepository) FindByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entity.Fast, error) {
+	sql := `
+		SELECT ` + fastColumns + `
+		FROM fasts
+		WHERE user_id = $1 AND started_at >= $2 AND started_at < $3
+		ORDER BY started_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fasts []*entity.Fast
+	for rows.Next() {
+		fast, err := scanFast(rows)
+		if err != nil {
+			return nil, err
+		}
+		fasts = append(fasts, fast)
+	}
+
+	return fasts, rows.Err()
+}
+
+// Update updates a fast's window and outcome
+func (r *FastingRepository) Update(ctx context.Context, fast *entity.Fast) error {
+	sql := `
+		UPDATE fasts
+		SET started_at = $3, ended_at = $4, broken = $5, broken_by_meal_id = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, sql,
+		fast.ID, fast.UserID, fast.StartedAt, fast.EndedAt, fast.Broken, fast.BrokenByMealID,
+	).Scan(&fast.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// Delete deletes a user's fast
+func (r *FastingRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	sql := `DELETE FROM fasts WHERE id = $1 AND user_id = $2`
+
+	tag, err := r.db.Exec(ctx, sql, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *FastingRepository) findOne(ctx context.Context, sql string, args ...interface{}) (*entity.Fast, error) {
+	fast, err := scanFast(r.db.QueryRow(ctx, sql, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return fast, nil
+}
+
+func scanFast(row pgx.Row) (*entity.Fast, error) {
+	fast := &entity.Fast{}
+	err := row.Scan(
+		&fast.ID, &fast.UserID, &fast.Protocol, &fast.TargetHours, &fast.StartedAt, &fast.EndedAt,
+		&fast.Broken, &fast.BrokenByMealID, &fast.AutoStarted, &fast.Note,
+		&fast.CreatedAt, &fast.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return fast, nil
+}
+
+// FindPreferences finds a user's fasting preferences
+func (r *FastingRepository) FindPreferences(ctx context.Context, userID uuid.UUID) (*entity.FastingPreferences, error) {
+	sql := `SELECT user_id, protocol, target_hours, auto_start, updated_at FROM fasting_preferences WHERE user_id = $1`
+
+	prefs := &entity.FastingPreferences{}
+	err := r.db.QueryRow(ctx, sql, userID).Scan(
+		&prefs.UserID, &prefs.Protocol, &prefs.TargetHours, &prefs.AutoStart, &prefs.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// UpsertPreferences creates or replaces a user's fasting preferences
+func (r *FastingRepository) UpsertPreferences(ctx context.Context, prefs *entity.FastingPreferences) error {
+	sql := `
+		INSERT INTO fasting_preferences (user_id, protocol, target_hours, auto_start)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET protocol = EXCLUDED.protocol, target_hours = EXCLUDED.target_hours,
+			auto_start = EXCLUDED.auto_start, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, sql, prefs.UserID, prefs.Protocol, prefs.TargetHours, prefs.AutoStart).
+		Scan(&prefs.UpdatedAt)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/bytetrack/backend/internal/domain/entity"
@@ -24,15 +25,16 @@ func NewMealRepository(db DB) *MealRepository {
 func (r *MealRepository) Create(ctx context.Context, meal *entity.Meal) error {
 	sql := `
 		INSERT INTO meals (id, user_id, name, name_en, calories, grams, meal_type,
-			protein, carbs, fat, fiber, sugar, sodium, image_url, date)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-		RETURNING created_at, updated_at
+			protein, carbs, fat, fiber, sugar, sodium, image_url, date, nutrients, eaten_at, food_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, COALESCE($16, '{}'::JSONB), $17, $18)
+		RETURNING created_at, updated_at, version
 	`
 
-	err := r.db.QueryRow(ctx, sql,
+	err := conn(ctx, r.db).QueryRow(ctx, sql,
 		meal.ID, meal.UserID, meal.Name, meal.NameEn, meal.Calories, meal.Grams, meal.MealType,
 		meal.Protein, meal.Carbs, meal.Fat, meal.Fiber, meal.Sugar, meal.Sodium, meal.ImageURL, meal.Date,
-	).Scan(&meal.CreatedAt, &meal.UpdatedAt)
+		meal.Nutrients, meal.EatenAt, meal.FoodID,
+	).Scan(&meal.CreatedAt, &meal.UpdatedAt, &meal.Version)
 
 	return err
 }
@@ -41,16 +43,18 @@ func (r *MealRepository) Create(ctx context.Context, meal *entity.Meal) error {
 func (r *MealRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Meal, error) {
 	sql := `
 		SELECT id, user_id, name, name_en, calories, grams, meal_type,
-			protein, carbs, fat, fiber, sugar, sodium, image_url, date, created_at, updated_at
+			protein, carbs, fat, fiber, sugar, sodium, image_url, date, created_at, updated_at, version,
+			photo_key, thumbnail_key, nutrients, eaten_at, food_id
 		FROM meals
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	meal := &entity.Meal{}
 	err := r.db.QueryRow(ctx, sql, id).Scan(
 		&meal.ID, &meal.UserID, &meal.Name, &meal.NameEn, &meal.Calories, &meal.Grams, &meal.MealType,
 		&meal.Protein, &meal.Carbs, &meal.Fat, &meal.Fiber, &meal.Sugar, &meal.Sodium, &meal.ImageURL,
-		&meal.Date, &meal.CreatedAt, &meal.UpdatedAt,
+		&meal.Date, &meal.CreatedAt, &meal.UpdatedAt, &meal.Version,
+		&meal.PhotoKey, &meal.ThumbnailKey, &meal.Nutrients, &meal.EatenAt, &meal.FoodID,
 	)
 
 	if err != nil {
@@ -67,9 +71,10 @@ func (r *MealRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Me
 func (r *MealRepository) FindByUserID(ctx context.Context, userID uuid.UUID, date *time.Time) ([]*entity.Meal, error) {
 	sql := `
 		SELECT id, user_id, name, name_en, calories, grams, meal_type,
-			protein, carbs, fat, fiber, sugar, sodium, image_url, date, created_at, updated_at
+			protein, carbs, fat, fiber, sugar, sodium, image_url, date, created_at, updated_at, version,
+			photo_key, thumbnail_key, nutrients, eaten_at, food_id
 		FROM meals
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 	`
 	args := []interface{}{userID}
 
@@ -92,7 +97,8 @@ func (r *MealRepository) FindByUserID(ctx context.Context, userID uuid.UUID, dat
 		err := rows.Scan(
 			&meal.ID, &meal.UserID, &meal.Name, &meal.NameEn, &meal.Calories, &meal.Grams, &meal.MealType,
 			&meal.Protein, &meal.Carbs, &meal.Fat, &meal.Fiber, &meal.Sugar, &meal.Sodium, &meal.ImageURL,
-			&meal.Date, &meal.CreatedAt, &meal.UpdatedAt,
+			&meal.Date, &meal.CreatedAt, &meal.UpdatedAt, &meal.Version,
+			&meal.PhotoKey, &meal.ThumbnailKey, &meal.Nutrients, &meal.EatenAt, &meal.FoodID,
 		)
 		if err != nil {
 			return nil, err
@@ -103,13 +109,51 @@ func (r *MealRepository) FindByUserID(ctx context.Context, userID uuid.UUID, dat
 	return meals, nil
 }
 
+// FindByUserIDRange finds meals in an optional date range, oldest first
+func (r *MealRepository) FindByUserIDRange(ctx context.Context, userID uuid.UUID, from, to *time.Time) ([]*entity.Meal, error) {
+	sql := `
+		SELECT id, user_id, name, name_en, calories, grams, meal_type,
+			protein, carbs, fat, fiber, sugar, sodium, image_url, date, created_at, updated_at, version,
+			photo_key, thumbnail_key, nutrients, eaten_at, food_id
+		FROM meals
+		WHERE user_id = $1 AND deleted_at IS NULL
+			AND ($2::DATE IS NULL OR date >= $2)
+			AND ($3::DATE IS NULL OR date <= $3)
+		ORDER BY date, eaten_at NULLS LAST, created_at
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var meals []*entity.Meal
+	for rows.Next() {
+		meal := &entity.Meal{}
+		err := rows.Scan(
+			&meal.ID, &meal.UserID, &meal.Name, &meal.NameEn, &meal.Calories, &meal.Grams, &meal.MealType,
+			&meal.Protein, &meal.Carbs, &meal.Fat, &meal.Fiber, &meal.Sugar, &meal.Sodium, &meal.ImageURL,
+			&meal.Date, &meal.CreatedAt, &meal.UpdatedAt, &meal.Version,
+			&meal.PhotoKey, &meal.ThumbnailKey, &meal.Nutrients, &meal.EatenAt, &meal.FoodID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		meals = append(meals, meal)
+	}
+
+	return meals, rows.Err()
+}
+
 // FindByUserIDAndMealType finds meals by user ID and meal type
 func (r *MealRepository) FindByUserIDAndMealType(ctx context.Context, userID uuid.UUID, mealType entity.MealType, date *time.Time) ([]*entity.Meal, error) {
 	sql := `
 		SELECT id, user_id, name, name_en, calories, grams, meal_type,
-			protein, carbs, fat, fiber, sugar, sodium, image_url, date, created_at, updated_at
+			protein, carbs, fat, fiber, sugar, sodium, image_url, date, created_at, updated_at, version,
+			photo_key, thumbnail_key, nutrients, eaten_at, food_id
 		FROM meals
-		WHERE user_id = $1 AND meal_type = $2
+		WHERE user_id = $1 AND meal_type = $2 AND deleted_at IS NULL
 	`
 	args := []interface{}{userID, mealType}
 
@@ -132,7 +176,8 @@ func (r *MealRepository) FindByUserIDAndMealType(ctx context.Context, userID uui
 		err := rows.Scan(
 			&meal.ID, &meal.UserID, &meal.Name, &meal.NameEn, &meal.Calories, &meal.Grams, &meal.MealType,
 			&meal.Protein, &meal.Carbs, &meal.Fat, &meal.Fiber, &meal.Sugar, &meal.Sodium, &meal.ImageURL,
-			&meal.Date, &meal.CreatedAt, &meal.UpdatedAt,
+			&meal.Date, &meal.CreatedAt, &meal.UpdatedAt, &meal.Version,
+			&meal.PhotoKey, &meal.ThumbnailKey, &meal.Nutrients, &meal.EatenAt, &meal.FoodID,
 		)
 		if err != nil {
 			return nil, err
@@ -143,30 +188,283 @@ func (r *MealRepository) FindByUserIDAndMealType(ctx context.Context, userID uui
 	return meals, nil
 }
 
-// Update updates a meal
-func (r *MealRepository) Update(ctx context.Context, meal *entity.Meal) error {
+// Update updates a meal if its version still matches expectedVersion.
+// A nil expectedVersion updates unconditionally.
+func (r *MealRepository) Update(ctx context.Context, meal *entity.Meal, expectedVersion *int) error {
 	sql := `
 		UPDATE meals
 		SET name = $2, name_en = $3, calories = $4, grams = $5, meal_type = $6,
 			protein = $7, carbs = $8, fat = $9, fiber = $10, sugar = $11, sodium = $12,
-			image_url = $13, date = $14
-		WHERE id = $1
+			image_url = $13, date = $14, nutrients = COALESCE($16, '{}'::JSONB), eaten_at = $17,
+			version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($15::INTEGER IS NULL OR version = $15)
+		RETURNING updated_at, version
 	`
 
-	_, err := r.db.Exec(ctx, sql,
+	err := conn(ctx, r.db).QueryRow(ctx, sql,
 		meal.ID, meal.Name, meal.NameEn, meal.Calories, meal.Grams, meal.MealType,
 		meal.Protein, meal.Carbs, meal.Fat, meal.Fiber, meal.Sugar, meal.Sodium,
-		meal.ImageURL, meal.Date,
-	)
+		meal.ImageURL, meal.Date, expectedVersion, meal.Nutrients, meal.EatenAt,
+	).Scan(&meal.UpdatedAt, &meal.Version)
 
-	return err
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		return err
+	}
+
+	return nil
 }
 
-// Delete deletes a meal
+// Delete soft-deletes a meal by moving it to the trash
 func (r *MealRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	sql := `DELETE FROM meals WHERE id = $1`
-	_, err := r.db.Exec(ctx, sql, id)
-	return err
+	sql := `UPDATE meals SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	tag, err := conn(ctx, r.db).Exec(ctx, sql, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// Restore restores a soft-deleted meal from the trash
+func (r *MealRepository) Restore(ctx context.Context, id, userID uuid.UUID) (*entity.Meal, error) {
+	sql := `
+		UPDATE meals
+		SET deleted_at = NULL
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		RETURNING id, user_id, name, name_en, calories, grams, meal_type,
+			protein, carbs, fat, fiber, sugar, sodium, image_url, date, created_at, updated_at, version,
+			photo_key, thumbnail_key, nutrients, eaten_at, food_id
+	`
+
+	meal := &entity.Meal{}
+	err := conn(ctx, r.db).QueryRow(ctx, sql, id, userID).Scan(
+		&meal.ID, &meal.UserID, &meal.Name, &meal.NameEn, &meal.Calories, &meal.Grams, &meal.MealType,
+		&meal.Protein, &meal.Carbs, &meal.Fat, &meal.Fiber, &meal.Sugar, &meal.Sodium, &meal.ImageURL,
+		&meal.Date, &meal.CreatedAt, &meal.UpdatedAt, &meal.Version,
+		&meal.PhotoKey, &meal.ThumbnailKey, &meal.Nutrients, &meal.EatenAt, &meal.FoodID,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return meal, nil
+}
+
+// FindDeleted finds soft-deleted meals by user ID
+func (r *MealRepository) FindDeleted(ctx context.Context, userID uuid.UUID) ([]*entity.Meal, error) {
+	sql := `
+		SELECT id, user_id, name, name_en, calories, grams, meal_type,
+			protein, carbs, fat, fiber, sugar, sodium, image_url, date, created_at, updated_at, version,
+			photo_key, thumbnail_key, nutrients, eaten_at, food_id, deleted_at
+		FROM meals
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var meals []*entity.Meal
+	for rows.Next() {
+		meal := &entity.Meal{}
+		err := rows.Scan(
+			&meal.ID, &meal.UserID, &meal.Name, &meal.NameEn, &meal.Calories, &meal.Grams, &meal.MealType,
+			&meal.Protein, &meal.Carbs, &meal.Fat, &meal.Fiber, &meal.Sugar, &meal.Sodium, &meal.ImageURL,
+			&meal.Date, &meal.CreatedAt, &meal.UpdatedAt, &meal.Version,
+			&meal.PhotoKey, &meal.ThumbnailKey, &meal.Nutrients, &meal.EatenAt, &meal.FoodID, &meal.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		meals = append(meals, meal)
+	}
+
+	return meals, nil
+}
+
+// PurgeDeleted permanently deletes meals that were soft-deleted before the given time.
+// It returns the number of meals removed and the blob keys of their photos.
+func (r *MealRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, []string, error) {
+	sql := `
+		DELETE FROM meals
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		RETURNING photo_key, thumbnail_key
+	`
+
+	rows, err := r.db.Query(ctx, sql, before)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	var count int64
+	var keys []string
+	for rows.Next() {
+		var photoKey, thumbnailKey *string
+		if err := rows.Scan(&photoKey, &thumbnailKey); err != nil {
+			return count, keys, err
+		}
+		count++
+		if photoKey != nil {
+			keys = append(keys, *photoKey)
+		}
+		if thumbnailKey != nil {
+			keys = append(keys, *thumbnailKey)
+		}
+	}
+
+	return count, keys, rows.Err()
+}
+
+// UpdatePhoto sets the photo and thumbnail blob keys of a meal
+func (r *MealRepository) UpdatePhoto(ctx context.Context, meal *entity.Meal) error {
+	sql := `
+		UPDATE meals
+		SET photo_key = $3, thumbnail_key = $4, version = version + 1
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING updated_at, version
+	`
+
+	err := r.db.QueryRow(ctx, sql, meal.ID, meal.UserID, meal.PhotoKey, meal.ThumbnailKey).Scan(
+		&meal.UpdatedAt, &meal.Version,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CountByUserID counts a user's meals, excluding deleted ones
+func (r *MealRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	sql := `SELECT COUNT(*) FROM meals WHERE user_id = $1 AND deleted_at IS NULL`
+
+	var count int
+	err := r.db.QueryRow(ctx, sql, userID).Scan(&count)
+	return count, err
+}
+
+// FindLastEatenAt finds when a user last ate, from meals logged with a time
+func (r *MealRepository) FindLastEatenAt(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+	sql := `SELECT MAX(eaten_at) FROM meals WHERE user_id = $1 AND deleted_at IS NULL`
+
+	var eatenAt *time.Time
+	err := r.db.QueryRow(ctx, sql, userID).Scan(&eatenAt)
+	return eatenAt, err
+}
+
+// FindTopFoods finds the foods contributing the most calories between two
+// dates (inclusive), grouping meals by name
+func (r *MealRepository) FindTopFoods(ctx context.Context, userID uuid.UUID, from, to time.Time, limit int) ([]*entity.ReportFood, error) {
+	sql := `
+		SELECT name, COALESCE(MAX(name_en), ''), COUNT(*), COALESCE(SUM(calories), 0)
+		FROM meals
+		WHERE user_id = $1 AND date BETWEEN $2 AND $3 AND deleted_at IS NULL
+		GROUP BY name
+		ORDER BY SUM(calories) DESC, name
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foods []*entity.ReportFood
+	for rows.Next() {
+		food := &entity.ReportFood{}
+		if err := rows.Scan(&food.Name, &food.NameEn, &food.Count, &food.Calories); err != nil {
+			return nil, err
+		}
+		foods = append(foods, food)
+	}
+
+	return foods, rows.Err()
+}
+
+// FindLoggedFoods finds the foods a user logged meals from since a date,
+// each with the meal last logged from it. Foods are ordered most recently
+// logged first, or with byCount most logged first.
+func (r *MealRepository) FindLoggedFoods(ctx context.Context, userID uuid.UUID, since time.Time, limit int, byCount bool) ([]*entity.LoggedFood, error) {
+	order := "s.last_logged_at DESC, s.count DESC"
+	if byCount {
+		order = "s.count DESC, s.last_logged_at DESC"
+	}
+
+	sql := `
+		SELECT s.food_id, s.count, s.last_logged_at, s.breakfast, s.lunch, s.dinner, s.snack,
+			m.name, COALESCE(m.name_en, ''), m.calories, m.grams, m.protein, m.carbs, m.fat,
+			m.fiber, m.sugar, m.sodium, m.nutrients
+		FROM (
+			SELECT food_id, COUNT(*) AS count,
+				MAX(COALESCE(eaten_at, date::TIMESTAMPTZ)) AS last_logged_at,
+				COUNT(*) FILTER (WHERE meal_type = 'breakfast') AS breakfast,
+				COUNT(*) FILTER (WHERE meal_type = 'lunch') AS lunch,
+				COUNT(*) FILTER (WHERE meal_type = 'dinner') AS dinner,
+				COUNT(*) FILTER (WHERE meal_type = 'snack') AS snack
+			FROM meals
+			WHERE user_id = $1 AND food_id IS NOT NULL AND deleted_at IS NULL AND date >= $2
+			GROUP BY food_id
+		) s
+		CROSS JOIN LATERAL (
+			SELECT name, name_en, calories, grams, protein, carbs, fat, fiber, sugar, sodium, nutrients
+			FROM meals
+			WHERE user_id = $1 AND food_id = s.food_id AND deleted_at IS NULL
+			ORDER BY date DESC, eaten_at DESC NULLS LAST, created_at DESC
+			LIMIT 1
+		) m
+		ORDER BY ` + order + `
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foods []*entity.LoggedFood
+	for rows.Next() {
+		logged := &entity.LoggedFood{}
+		food := &logged.Food
+		var breakfast, lunch, dinner, snack int
+		err := rows.Scan(
+			&food.ID, &logged.Count, &logged.LastLoggedAt, &breakfast, &lunch, &dinner, &snack,
+			&food.Name, &food.NameEn, &food.Nutrition.Calories, &food.Nutrition.ServingSize,
+			&food.Nutrition.Protein, &food.Nutrition.Carbs, &food.Nutrition.Fat,
+			&food.Nutrition.Fiber, &food.Nutrition.Sugar, &food.Nutrition.Sodium, &food.Nutrition.Nutrients,
+		)
+		if err != nil {
+			return nil, err
+		}
+		food.Nutrition.ServingUnit = "g"
+		logged.MealTypes = map[entity.MealType]int{
+			entity.MealTypeBreakfast: breakfast,
+			entity.MealTypeLunch:     lunch,
+			entity.MealTypeDinner:    dinner,
+			entity.MealTypeSnack:     snack,
+		}
+		foods = append(foods, logged)
+	}
+
+	return foods, rows.Err()
 }
 
 // GetDailyTotals gets daily nutrition totals for a user
@@ -176,17 +474,118 @@ func (r *MealRepository) GetDailyTotals(ctx context.Context, userID uuid.UUID, d
 			COALESCE(SUM(calories), 0) as calories,
 			COALESCE(SUM(protein), 0) as protein,
 			COALESCE(SUM(carbs), 0) as carbs,
-			COALESCE(SUM(fat), 0) as fat
+			COALESCE(SUM(fat), 0) as fat,
+			COALESCE(SUM(fiber), 0) as fiber,
+			COALESCE(SUM(sugar), 0) as sugar,
+			COALESCE(SUM(sodium), 0) as sodium
 		FROM meals
-		WHERE user_id = $1 AND date = $2
+		WHERE user_id = $1 AND date = $2 AND deleted_at IS NULL
 	`
 
 	totals := &entity.DailyMacros{}
 	err := r.db.QueryRow(ctx, sql, userID, date).Scan(
 		&totals.Calories, &totals.Protein, &totals.Carbs, &totals.Fat,
+		&totals.Fiber, &totals.Sugar, &totals.Sodium,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	nutrients, err := r.getNutrientTotalsByDate(ctx, userID, date, date)
+	if err != nil {
+		return nil, err
+	}
+	totals.Nutrients = nutrients[date.Format("2006-01-02")]
 
-	return totals, err
+	return totals, nil
+}
+
+// GetRangeTotals gets per-day nutrition totals for a user between two dates (inclusive).
+// Days without meals are omitted.
+func (r *MealRepository) GetRangeTotals(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entity.DayTotals, error) {
+	sql := `
+		SELECT
+			date,
+			COUNT(*) as meal_count,
+			COALESCE(SUM(calories), 0) as calories,
+			COALESCE(SUM(protein), 0) as protein,
+			COALESCE(SUM(carbs), 0) as carbs,
+			COALESCE(SUM(fat), 0) as fat,
+			COALESCE(SUM(fiber), 0) as fiber,
+			COALESCE(SUM(sugar), 0) as sugar,
+			COALESCE(SUM(sodium), 0) as sodium
+		FROM meals
+		WHERE user_id = $1 AND date BETWEEN $2 AND $3 AND deleted_at IS NULL
+		GROUP BY date
+		ORDER BY date
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []*entity.DayTotals
+	for rows.Next() {
+		day := &entity.DayTotals{}
+		err := rows.Scan(
+			&day.Date, &day.MealCount,
+			&day.Totals.Calories, &day.Totals.Protein, &day.Totals.Carbs, &day.Totals.Fat,
+			&day.Totals.Fiber, &day.Totals.Sugar, &day.Totals.Sodium,
+		)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	nutrients, err := r.getNutrientTotalsByDate(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range days {
+		day.Totals.Nutrients = nutrients[day.Date.Format("2006-01-02")]
+	}
+
+	return days, nil
+}
+
+// getNutrientTotalsByDate sums registry nutrients per day, keyed by YYYY-MM-DD
+func (r *MealRepository) getNutrientTotalsByDate(ctx context.Context, userID uuid.UUID, from, to time.Time) (map[string]entity.Nutrients, error) {
+	sql := `
+		SELECT m.date, n.key, SUM(n.value::NUMERIC)
+		FROM meals m, jsonb_each_text(m.nutrients) n
+		WHERE m.user_id = $1 AND m.date BETWEEN $2 AND $3 AND m.deleted_at IS NULL
+		GROUP BY m.date, n.key
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]entity.Nutrients)
+	for rows.Next() {
+		var date time.Time
+		var id entity.NutrientID
+		var amount float64
+		if err := rows.Scan(&date, &id, &amount); err != nil {
+			return nil, err
+		}
+
+		key := date.Format("2006-01-02")
+		if totals[key] == nil {
+			totals[key] = entity.Nutrients{}
+		}
+		totals[key][id] = amount
+	}
+
+	return totals, rows.Err()
 }
 
 // Favorite Food operations
@@ -195,8 +594,8 @@ func (r *MealRepository) GetDailyTotals(ctx context.Context, userID uuid.UUID, d
 func (r *MealRepository) AddFavorite(ctx context.Context, fav *entity.FavoriteFood) error {
 	sql := `
 		INSERT INTO favorite_foods (id, user_id, food_id, name, name_en, category, calories,
-			protein, carbs, fat, fiber, sugar, sodium, serving_size, serving_unit, emoji)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			protein, carbs, fat, fiber, sugar, sodium, serving_size, serving_unit, emoji, nutrients)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, COALESCE($17, '{}'::JSONB))
 		ON CONFLICT (user_id, food_id) DO NOTHING
 		RETURNING created_at
 	`
@@ -204,7 +603,7 @@ func (r *MealRepository) AddFavorite(ctx context.Context, fav *entity.FavoriteFo
 	err := r.db.QueryRow(ctx, sql,
 		fav.ID, fav.UserID, fav.FoodID, fav.Name, fav.NameEn, fav.Category, fav.Calories,
 		fav.Protein, fav.Carbs, fav.Fat, fav.Fiber, fav.Sugar, fav.Sodium,
-		fav.ServingSize, fav.ServingUnit, fav.Emoji,
+		fav.ServingSize, fav.ServingUnit, fav.Emoji, fav.Nutrients,
 	).Scan(&fav.CreatedAt)
 
 	return err
@@ -214,7 +613,7 @@ func (r *MealRepository) AddFavorite(ctx context.Context, fav *entity.FavoriteFo
 func (r *MealRepository) FindFavorites(ctx context.Context, userID uuid.UUID) ([]*entity.FavoriteFood, error) {
 	sql := `
 		SELECT id, user_id, food_id, name, name_en, category, calories,
-			protein, carbs, fat, fiber, sugar, sodium, serving_size, serving_unit, emoji, created_at
+			protein, carbs, fat, fiber, sugar, sodium, serving_size, serving_unit, emoji, nutrients, created_at
 		FROM favorite_foods
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -232,7 +631,7 @@ func (r *MealRepository) FindFavorites(ctx context.Context, userID uuid.UUID) ([
 		err := rows.Scan(
 			&fav.ID, &fav.UserID, &fav.FoodID, &fav.Name, &fav.NameEn, &fav.Category, &fav.Calories,
 			&fav.Protein, &fav.Carbs, &fav.Fat, &fav.Fiber, &fav.Sugar, &fav.Sodium,
-			&fav.ServingSize, &fav.ServingUnit, &fav.Emoji, &fav.CreatedAt,
+			&fav.ServingSize, &fav.ServingUnit, &fav.Emoji, &fav.Nutrients, &fav.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -256,14 +655,14 @@ func (r *MealRepository) RemoveFavorite(ctx context.Context, userID uuid.UUID, f
 func (r *MealRepository) CreateCustomFood(ctx context.Context, food *entity.CustomFood) error {
 	sql := `
 		INSERT INTO custom_foods (id, user_id, name, calories, protein, carbs, fat,
-			fiber, sugar, sodium, serving_size, serving_unit)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			fiber, sugar, sodium, serving_size, serving_unit, nutrients)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, COALESCE($13, '{}'::JSONB))
 		RETURNING created_at, updated_at
 	`
 
 	err := r.db.QueryRow(ctx, sql,
 		food.ID, food.UserID, food.Name, food.Calories, food.Protein, food.Carbs, food.Fat,
-		food.Fiber, food.Sugar, food.Sodium, food.ServingSize, food.ServingUnit,
+		food.Fiber, food.Sugar, food.Sodium, food.ServingSize, food.ServingUnit, food.Nutrients,
 	).Scan(&food.CreatedAt, &food.UpdatedAt)
 
 	return err
@@ -273,9 +672,9 @@ func (r *MealRepository) CreateCustomFood(ctx context.Context, food *entity.Cust
 func (r *MealRepository) FindCustomFoods(ctx context.Context, userID uuid.UUID) ([]*entity.CustomFood, error) {
 	sql := `
 		SELECT id, user_id, name, calories, protein, carbs, fat,
-			fiber, sugar, sodium, serving_size, serving_unit, created_at, updated_at
+			fiber, sugar, sodium, serving_size, serving_unit, nutrients, created_at, updated_at
 		FROM custom_foods
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -290,7 +689,67 @@ func (r *MealRepository) FindCustomFoods(ctx context.Context, userID uuid.UUID)
 		food := &entity.CustomFood{}
 		err := rows.Scan(
 			&food.ID, &food.UserID, &food.Name, &food.Calories, &food.Protein, &food.Carbs, &food.Fat,
-			&food.Fiber, &food.Sugar, &food.Sodium, &food.ServingSize, &food.ServingUnit,
+			&food.Fiber, &food.Sugar, &food.Sodium, &food.ServingSize, &food.ServingUnit, &food.Nutrients,
+			&food.CreatedAt, &food.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		foods = append(foods, food)
+	}
+
+	return foods, nil
+}
+
+// FindCustomFoodByID finds a user's custom food by ID
+func (r *MealRepository) FindCustomFoodByID(ctx context.Context, id, userID uuid.UUID) (*entity.CustomFood, error) {
+	sql := `
+		SELECT id, user_id, name, calories, protein, carbs, fat,
+			fiber, sugar, sodium, serving_size, serving_unit, nutrients, created_at, updated_at
+		FROM custom_foods
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	food := &entity.CustomFood{}
+	err := r.db.QueryRow(ctx, sql, id, userID).Scan(
+		&food.ID, &food.UserID, &food.Name, &food.Calories, &food.Protein, &food.Carbs, &food.Fat,
+		&food.Fiber, &food.Sugar, &food.Sodium, &food.ServingSize, &food.ServingUnit, &food.Nutrients,
+		&food.CreatedAt, &food.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return food, nil
+}
+
+// SearchCustomFoods searches a user's custom foods by name
+func (r *MealRepository) SearchCustomFoods(ctx context.Context, userID uuid.UUID, query string) ([]*entity.CustomFood, error) {
+	sql := `
+		SELECT id, user_id, name, calories, protein, carbs, fat,
+			fiber, sugar, sodium, serving_size, serving_unit, nutrients, created_at, updated_at
+		FROM custom_foods
+		WHERE user_id = $1 AND deleted_at IS NULL AND name ILIKE '%' || $2 || '%'
+		ORDER BY name
+		LIMIT 50
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foods []*entity.CustomFood
+	for rows.Next() {
+		food := &entity.CustomFood{}
+		err := rows.Scan(
+			&food.ID, &food.UserID, &food.Name, &food.Calories, &food.Protein, &food.Carbs, &food.Fat,
+			&food.Fiber, &food.Sugar, &food.Sodium, &food.ServingSize, &food.ServingUnit, &food.Nutrients,
 			&food.CreatedAt, &food.UpdatedAt,
 		)
 		if err != nil {
@@ -307,23 +766,101 @@ func (r *MealRepository) UpdateCustomFood(ctx context.Context, food *entity.Cust
 	sql := `
 		UPDATE custom_foods
 		SET name = $2, calories = $3, protein = $4, carbs = $5, fat = $6,
-			fiber = $7, sugar = $8, sodium = $9, serving_size = $10, serving_unit = $11
-		WHERE id = $1 AND user_id = $12
+			fiber = $7, sugar = $8, sodium = $9, serving_size = $10, serving_unit = $11,
+			nutrients = COALESCE($13, '{}'::JSONB)
+		WHERE id = $1 AND user_id = $12 AND deleted_at IS NULL
 	`
 
 	_, err := r.db.Exec(ctx, sql,
 		food.ID, food.Name, food.Calories, food.Protein, food.Carbs, food.Fat,
 		food.Fiber, food.Sugar, food.Sodium, food.ServingSize, food.ServingUnit, food.UserID,
+		food.Nutrients,
 	)
 
 	return err
 }
 
-// DeleteCustomFood deletes a custom food
+// DeleteCustomFood soft-deletes a custom food by moving it to the trash
 func (r *MealRepository) DeleteCustomFood(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
-	sql := `DELETE FROM custom_foods WHERE id = $1 AND user_id = $2`
-	_, err := r.db.Exec(ctx, sql, id, userID)
-	return err
+	sql := `UPDATE custom_foods SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+	tag, err := r.db.Exec(ctx, sql, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// RestoreCustomFood restores a soft-deleted custom food from the trash
+func (r *MealRepository) RestoreCustomFood(ctx context.Context, id, userID uuid.UUID) (*entity.CustomFood, error) {
+	sql := `
+		UPDATE custom_foods
+		SET deleted_at = NULL
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		RETURNING id, user_id, name, calories, protein, carbs, fat,
+			fiber, sugar, sodium, serving_size, serving_unit, nutrients, created_at, updated_at
+	`
+
+	food := &entity.CustomFood{}
+	err := r.db.QueryRow(ctx, sql, id, userID).Scan(
+		&food.ID, &food.UserID, &food.Name, &food.Calories, &food.Protein, &food.Carbs, &food.Fat,
+		&food.Fiber, &food.Sugar, &food.Sodium, &food.ServingSize, &food.ServingUnit, &food.Nutrients,
+		&food.CreatedAt, &food.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return food, nil
+}
+
+// FindDeletedCustomFoods finds soft-deleted custom foods by user ID
+func (r *MealRepository) FindDeletedCustomFoods(ctx context.Context, userID uuid.UUID) ([]*entity.CustomFood, error) {
+	sql := `
+		SELECT id, user_id, name, calories, protein, carbs, fat,
+			fiber, sugar, sodium, serving_size, serving_unit, nutrients, created_at, updated_at, deleted_at
+		FROM custom_foods
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foods []*entity.CustomFood
+	for rows.Next() {
+		food := &entity.CustomFood{}
+		err := rows.Scan(
+			&food.ID, &food.UserID, &food.Name, &food.Calories, &food.Protein, &food.Carbs, &food.Fat,
+			&food.Fiber, &food.Sugar, &food.Sodium, &food.ServingSize, &food.ServingUnit, &food.Nutrients,
+			&food.CreatedAt, &food.UpdatedAt, &food.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		foods = append(foods, food)
+	}
+
+	return foods, nil
+}
+
+// PurgeDeletedCustomFoods permanently deletes custom foods that were soft-deleted before the given time
+func (r *MealRepository) PurgeDeletedCustomFoods(ctx context.Context, before time.Time) (int64, error) {
+	sql := `DELETE FROM custom_foods WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	tag, err := r.db.Exec(ctx, sql, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
 }
 
 // Thai Food operations
@@ -332,7 +869,7 @@ func (r *MealRepository) DeleteCustomFood(ctx context.Context, id uuid.UUID, use
 func (r *MealRepository) FindAllThaiFoods(ctx context.Context) ([]*entity.ThaiFood, error) {
 	sql := `
 		SELECT id, name, name_en, category, calories, protein, carbs, fat,
-			fiber, sugar, sodium, serving_size, serving_unit, emoji
+			fiber, sugar, sodium, serving_size, serving_unit, emoji, nutrients
 		FROM thai_foods
 		ORDER BY category, name
 	`
@@ -349,7 +886,7 @@ func (r *MealRepository) FindAllThaiFoods(ctx context.Context) ([]*entity.ThaiFo
 		err := rows.Scan(
 			&food.ID, &food.Name, &food.NameEn, &food.Category, &food.Calories, &food.Protein,
 			&food.Carbs, &food.Fat, &food.Fiber, &food.Sugar, &food.Sodium,
-			&food.ServingSize, &food.ServingUnit, &food.Emoji,
+			&food.ServingSize, &food.ServingUnit, &food.Emoji, &food.Nutrients,
 		)
 		if err != nil {
 			return nil, err
@@ -360,11 +897,37 @@ func (r *MealRepository) FindAllThaiFoods(ctx context.Context) ([]*entity.ThaiFo
 	return foods, nil
 }
 
+// FindThaiFoodByID finds a Thai food by ID
+func (r *MealRepository) FindThaiFoodByID(ctx context.Context, id string) (*entity.ThaiFood, error) {
+	sql := `
+		SELECT id, name, name_en, category, calories, protein, carbs, fat,
+			fiber, sugar, sodium, serving_size, serving_unit, emoji, nutrients
+		FROM thai_foods
+		WHERE id = $1
+	`
+
+	food := &entity.ThaiFood{}
+	err := r.db.QueryRow(ctx, sql, id).Scan(
+		&food.ID, &food.Name, &food.NameEn, &food.Category, &food.Calories, &food.Protein,
+		&food.Carbs, &food.Fat, &food.Fiber, &food.Sugar, &food.Sodium,
+		&food.ServingSize, &food.ServingUnit, &food.Emoji, &food.Nutrients,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return food, nil
+}
+
 // FindThaiFoodsByCategory finds Thai foods by category
 func (r *MealRepository) FindThaiFoodsByCategory(ctx context.Context, category string) ([]*entity.ThaiFood, error) {
 	sql := `
 		SELECT id, name, name_en, category, calories, protein, carbs, fat,
-			fiber, sugar, sodium, serving_size, serving_unit, emoji
+			fiber, sugar, sodium, serving_size, serving_unit, emoji, nutrients
 		FROM thai_foods
 		WHERE category = $1
 		ORDER BY name
@@ -382,7 +945,7 @@ func (r *MealRepository) FindThaiFoodsByCategory(ctx context.Context, category s
 		err := rows.Scan(
 			&food.ID, &food.Name, &food.NameEn, &food.Category, &food.Calories, &food.Protein,
 			&food.Carbs, &food.Fat, &food.Fiber, &food.Sugar, &food.Sodium,
-			&food.ServingSize, &food.ServingUnit, &food.Emoji,
+			&food.ServingSize, &food.ServingUnit, &food.Emoji, &food.Nutrients,
 		)
 		if err != nil {
 			return nil, err
@@ -393,18 +956,39 @@ func (r *MealRepository) FindThaiFoodsByCategory(ctx context.Context, category s
 	return foods, nil
 }
 
-// SearchThaiFoods searches Thai foods by name
-func (r *MealRepository) SearchThaiFoods(ctx context.Context, query string) ([]*entity.ThaiFood, error) {
+// SearchThaiFoods searches Thai foods by search_text. Each query word has a
+// pattern and a fuzzy form; a food scores 2 for every word whose pattern
+// matches and 1 for every other word within pg_trgm's word similarity
+// threshold, and foods are ranked by score, then by similarity to the
+// whole query.
+func (r *MealRepository) SearchThaiFoods(ctx context.Context, patterns, fuzzy []string) ([]*entity.ThaiFood, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
 	sql := `
 		SELECT id, name, name_en, category, calories, protein, carbs, fat,
-			fiber, sugar, sodium, serving_size, serving_unit, emoji
-		FROM thai_foods
-		WHERE name ILIKE '%' || $1 || '%' OR name_en ILIKE '%' || $1 || '%'
-		ORDER BY name
+			fiber, sugar, sodium, serving_size, serving_unit, emoji, nutrients
+		FROM (
+			SELECT t.*,
+				(
+					SELECT COALESCE(SUM(CASE
+						WHEN t.search_text ~ q.pattern THEN 2
+						WHEN q.fuzzy <% t.search_text THEN 1
+						ELSE 0
+					END), 0)
+					FROM unnest($1::text[], $2::text[]) AS q(pattern, fuzzy)
+				) AS score,
+				word_similarity($4, t.search_text) AS similarity
+			FROM thai_foods t
+			WHERE t.search_text ~ $3 OR $4 <% t.search_text
+		) ranked
+		WHERE score > 0
+		ORDER BY score DESC, similarity DESC, name
 		LIMIT 50
 	`
 
-	rows, err := r.db.Query(ctx, sql, query)
+	rows, err := r.db.Query(ctx, sql, patterns, fuzzy, strings.Join(patterns, "|"), strings.Join(fuzzy, " "))
 	if err != nil {
 		return nil, err
 	}
@@ -416,7 +1000,7 @@ func (r *MealRepository) SearchThaiFoods(ctx context.Context, query string) ([]*
 		err := rows.Scan(
 			&food.ID, &food.Name, &food.NameEn, &food.Category, &food.Calories, &food.Protein,
 			&food.Carbs, &food.Fat, &food.Fiber, &food.Sugar, &food.Sodium,
-			&food.ServingSize, &food.ServingUnit, &food.Emoji,
+			&food.ServingSize, &food.ServingUnit, &food.Emoji, &food.Nutrients,
 		)
 		if err != nil {
 			return nil, err
@@ -426,3 +1010,27 @@ func (r *MealRepository) SearchThaiFoods(ctx context.Context, query string) ([]*
 
 	return foods, nil
 }
+
+// UpdateThaiFoodSearchText sets the search text of Thai foods by ID and
+// returns how many changed
+func (r *MealRepository) UpdateThaiFoodSearchText(ctx context.Context, texts map[string]string) (int64, error) {
+	ids := make([]string, 0, len(texts))
+	values := make([]string, 0, len(texts))
+	for id, text := range texts {
+		ids = append(ids, id)
+		values = append(values, text)
+	}
+
+	sql := `
+		UPDATE thai_foods t
+		SET search_text = v.search_text
+		FROM unnest($1::text[], $2::text[]) AS v(id, search_text)
+		WHERE t.id = v.id AND t.search_text IS DISTINCT FROM v.search_text
+	`
+
+	tag, err := r.db.Exec(ctx, sql, ids, values)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// NotificationRepository handles reminder, notification settings and push subscription data operations
+type NotificationRepository struct {
+	db DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+const reminderColumns = `id, user_id, kind, meal_type, to_char(remind_at, 'HH24:MI'), weekdays, language, enabled, last_sent_on, created_at, updated_at`
+
+// CreateReminder creates a new reminder
+func (r *NotificationRepository) CreateReminder(ctx context.Context, reminder *entity.Reminder) error {
+	sql := `
+		INSERT INTO reminders (id, user_id, kind, meal_type, remind_at, weekdays, language, enabled)
+		VALUES ($1, $2, $3, $4, $5::time, $6, $7, $8)
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		reminder.ID, reminder.UserID, reminder.Kind, reminder.MealType,
+		reminder.Time, reminder.Weekdays, reminder.Language, reminder.Enabled,
+	).Scan(&reminder.CreatedAt, &reminder.UpdatedAt)
+}
+
+// FindReminderByID finds a user's reminder by ID
+func (r *NotificationRepository) FindReminderByID(ctx context.Context, id, userID uuid.UUID) (*entity.Reminder, error) {
+	sql := `SELECT ` + reminderColumns + ` FROM reminders WHERE id = $1 AND user_id = $2`
+
+	reminder, err := scanReminder(r.db.QueryRow(ctx, sql, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return reminder, nil
+}
+
+// FindRemindersByUserID finds a user's reminders ordered by time
+func (r *NotificationRepository) FindRemindersByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Reminder, error) {
+	sql := `SELECT ` + reminderColumns + ` FROM reminders WHERE user_id = $1 ORDER BY remind_at, kind`
+
+	return r.queryReminders(ctx, sql, userID)
+}
+
+// FindEnabledReminders finds all enabled reminders, grouped by user
+func (r *NotificationRepository) FindEnabledReminders(ctx context.Context) ([]*entity.Reminder, error) {
+	sql := `SELECT ` + reminderColumns + ` FROM reminders WHERE enabled ORDER BY user_id, remind_at`
+
+	return r.queryReminders(ctx, sql)
+}
+
+// UpdateReminder updates a reminder; changing the time makes it eligible again today
+func (r *NotificationRepository) UpdateReminder(ctx context.Context, reminder *entity.Reminder) error {
+	sql := `
+		UPDATE reminders
+		SET meal_type = $3, weekdays = $5, language = $6, enabled = $7,
+			last_sent_on = CASE WHEN remind_at = $4::time THEN last_sent_on END,
+			remind_at = $4::time, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2
+		RETURNING last_sent_on, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, sql,
+		reminder.ID, reminder.UserID, reminder.MealType, reminder.Time,
+		reminder.Weekdays, reminder.Language, reminder.Enabled,
+	).Scan(&reminder.LastSentOn, &reminder.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// DeleteReminder deletes a user's reminder
+func (r *NotificationRepository) DeleteReminder(ctx context.Context, id, userID uuid.UUID) error {
+	sql := `DELETE FROM reminders WHERE id = $1 AND user_id = $2`
+
+	tag, err := r.db.Exec(ctx, sql, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ClaimReminder marks a reminder as handled for a local date. It returns false
+// when it was already handled that day, so concurrent schedulers send it once.
+func (r *NotificationRepository) ClaimReminder(ctx context.Context, id uuid.UUID, date time.Time) (bool, error) {
+	sql := `
+		UPDATE reminders
+		SET last_sent_on = $2
+		WHERE id = $1 AND last_sent_on IS DISTINCT FROM $2
+	`
+
+	tag, err := r.db.Exec(ctx, sql, id, date)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *NotificationRepository) queryReminders(ctx context.Context, sql string, args ...interface{}) ([]*entity.Reminder, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*entity.Reminder
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+func scanReminder(row pgx.Row) (*entity.Reminder, error) {
+	reminder := &entity.Reminder{}
+	err := row.Scan(
+		&reminder.ID, &reminder.UserID, &reminder.Kind, &reminder.MealType, &reminder.Time,
+		&reminder.Weekdays, &reminder.Language, &reminder.Enabled, &reminder.LastSentOn,
+		&reminder.CreatedAt, &reminder.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return reminder, nil
+}
+
+// FindSettings finds a user's notification settings
+func (r *NotificationRepository) FindSettings(ctx context.Context, userID uuid.UUID) (*entity.NotificationSettings, error) {
+	sql := `
+		SELECT user_id, to_char(quiet_start, 'HH24:MI'), to_char(quiet_end, 'HH24:MI'),
+			push_enabled, email_enabled, updated_at
+		FROM notification_settings
+		WHERE user_id = $1
+	`
+
+	settings := &entity.NotificationSettings{}
+	err := r.db.QueryRow(ctx, sql, userID).Scan(
+		&settings.UserID, &settings.QuietStart, &settings.QuietEnd,
+		&settings.PushEnabled, &settings.EmailEnabled, &settings.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// UpsertSettings creates or replaces a user's notification settings
+func (r *NotificationRepository) UpsertSettings(ctx context.Context, settings *entity.NotificationSettings) error {
+	sql := `
+		INSERT INTO notification_settings (user_id, quiet_start, quiet_end, push_enabled, email_enabled)
+		VALUES ($1, $2::time, $3::time, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET quiet_start = EXCLUDED.quiet_start, quiet_end = EXCLUDED.quiet_end,
+			push_enabled = EXCLUDED.push_enabled, email_enabled = EXCLUDED.email_enabled,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		settings.UserID, settings.QuietStart, settings.QuietEnd, settings.PushEnabled, settings.EmailEnabled,
+	).Scan(&settings.UpdatedAt)
+}
+
+// UpsertSubscription saves a push subscription. A browser re-subscribing with
+// the same endpoint replaces its keys and owner.
+func (r *NotificationRepository) UpsertSubscription(ctx context.Context, sub *entity.PushSubscription) error {
+	sql := `
+		INSERT INTO push_subscriptions (id, user_id, endpoint, p256dh, auth)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (endpoint) DO UPDATE
+		SET user_id = EXCLUDED.user_id, p256dh = EXCLUDED.p256dh, auth = EXCLUDED.auth
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, sql, sub.ID, sub.UserID, sub.Endpoint, sub.P256dh, sub.Auth).
+		Scan(&sub.ID, &sub.CreatedAt)
+}
+
+// FindSubscriptions finds a user's push subscriptions
+func (r *NotificationRepository) FindSubscriptions(ctx context.Context, userID uuid.UUID) ([]*entity.PushSubscription, error) {
+	sql := `
+		SELECT id, user_id, endpoint, p256dh, auth, created_at
+		FROM push_subscriptions
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []*entity.PushSubscription
+	for rows.Next() {
+		sub := &entity.PushSubscription{}
+		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.Endpoint, &sub.P256dh, &sub.Auth, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+// DeleteSubscription deletes a user's push subscription by endpoint
+func (r *NotificationRepository) DeleteSubscription(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	sql := `DELETE FROM push_subscriptions WHERE user_id = $1 AND endpoint = $2`
+
+	tag, err := r.db.Exec(ctx, sql, userID, endpoint)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteSubscriptionsByEndpoint deletes push subscriptions the push service reported as expired
+func (r *NotificationRepository) DeleteSubscriptionsByEndpoint(ctx context.Context, endpoints []string) error {
+	sql := `DELETE FROM push_subscriptions WHERE endpoint = ANY($1)`
+
+	_, err := r.db.Exec(ctx, sql, endpoints)
+	return err
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/jackc/pgx/v5"
+)
+
+// OFFCacheRepository stores Open Food Facts barcode lookups in off_cache
+type OFFCacheRepository struct {
+	db DB
+}
+
+// NewOFFCacheRepository creates a new Open Food Facts cache repository
+func NewOFFCacheRepository(db DB) *OFFCacheRepository {
+	return &OFFCacheRepository{db: db}
+}
+
+// Find finds an unexpired cached product by barcode with the time it was cached
+func (r *OFFCacheRepository) Find(ctx context.Context, barcode string, now time.Time) (*entity.FoodItem, time.Time, error) {
+	var data []byte
+	var cachedAt time.Time
+	err := r.db.QueryRow(ctx, `
+		SELECT product_data, cached_at FROM off_cache WHERE barcode = $1 AND expires_at > $2
+	`, barcode, now).Scan(&data, &cachedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, time.Time{}, ErrUserNotFound
+		}
+		return nil, time.Time{}, err
+	}
+
+	food := &entity.FoodItem{}
+	if err := json.Unmarshal(data, food); err != nil {
+		return nil, time.Time{}, err
+	}
+	return food, cachedAt, nil
+}
+
+// Upsert caches a product fetched at cachedAt until expiresAt, replacing any
+// earlier entry
+func (r *OFFCacheRepository) Upsert(ctx context.Context, barcode string, food *entity.FoodItem, cachedAt, expiresAt time.Time) error {
+	data, err := json.Marshal(food)
+	if err != nil {
+		return err
+	}
+
+	sql := `
+		INSERT INTO off_cache (barcode, product_data, cached_at, expires_at, name, brand, calories, protein, carbs, fat, image_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (barcode) DO UPDATE SET
+			product_data = EXCLUDED.product_data,
+			cached_at = EXCLUDED.cached_at,
+			expires_at = EXCLUDED.expires_at,
+			name = EXCLUDED.name,
+			brand = EXCLUDED.brand,
+			calories = EXCLUDED.calories,
+			protein = EXCLUDED.protein,
+			carbs = EXCLUDED.carbs,
+			fat = EXCLUDED.fat,
+			image_url = EXCLUDED.image_url
+	`
+
+	_, err = r.db.Exec(ctx, sql,
+		barcode, data, cachedAt, expiresAt, truncate(food.Name, 255), truncatePtr(food.Brand, 255),
+		food.Nutrition.Calories, food.Nutrition.Protein, food.Nutrition.Carbs, food.Nutrition.Fat, food.Image,
+	)
+	return err
+}
+
+// DeleteExpired deletes entries expired by now
+func (r *OFFCacheRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM off_cache WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// truncate cuts s to at most n characters to fit a VARCHAR column
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+func truncatePtr(s *string, n int) *string {
+	if s == nil {
+		return nil
+	}
+	t := truncate(*s, n)
+	return &t
+}
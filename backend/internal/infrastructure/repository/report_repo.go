@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ReportRepository handles report schedule data operations
+type ReportRepository struct {
+	db DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+const reportScheduleColumns = `user_id, weekly, monthly, language, attach_pdf, last_weekly_on, last_monthly_on, updated_at`
+
+// FindSchedule finds a user's report schedule
+func (r *ReportRepository) FindSchedule(ctx context.Context, userID uuid.UUID) (*entity.ReportSchedule, error) {
+	sql := `SELECT ` + reportScheduleColumns + ` FROM report_schedules WHERE user_id = $1`
+
+	schedule, err := scanReportSchedule(r.db.QueryRow(ctx, sql, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// FindEnabledSchedules finds all schedules with weekly or monthly reports on
+func (r *ReportRepository) FindEnabledSchedules(ctx context.Context) ([]*entity.ReportSchedule, error) {
+	sql := `SELECT ` + reportScheduleColumns + ` FROM report_schedules WHERE weekly OR monthly`
+
+	rows, err := r.db.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*entity.ReportSchedule
+	for rows.Next() {
+		schedule, err := scanReportSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+// UpsertSchedule creates or replaces a user's report schedule
+func (r *ReportRepository) UpsertSchedule(ctx context.Context, schedule *entity.ReportSchedule) error {
+	sql := `
+		INSERT INTO report_schedules (user_id, weekly, monthly, language, attach_pdf)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET weekly = EXCLUDED.weekly, monthly = EXCLUDED.monthly, language = EXCLUDED.language,
+			attach_pdf = EXCLUDED.attach_pdf, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		schedule.UserID, schedule.Weekly, schedule.Monthly, schedule.Language, schedule.AttachPDF,
+	).Scan(&schedule.UpdatedAt)
+}
+
+// ClaimSchedule marks a period's report as sent for a local date. It returns
+// false when it was already sent that day, so concurrent schedulers send it once.
+func (r *ReportRepository) ClaimSchedule(ctx context.Context, userID uuid.UUID, period entity.ReportPeriod, date time.Time) (bool, error) {
+	column := "last_weekly_on"
+	if period == entity.ReportMonthly {
+		column = "last_monthly_on"
+	}
+	sql := `
+		UPDATE report_schedules
+		SET ` + column + ` = $2
+		WHERE user_id = $1 AND ` + column + ` IS DISTINCT FROM $2
+	`
+
+	tag, err := r.db.Exec(ctx, sql, userID, date)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanReportSchedule(row pgx.Row) (*entity.ReportSchedule, error) {
+	schedule := &entity.ReportSchedule{}
+	err := row.Scan(
+		&schedule.UserID, &schedule.Weekly, &schedule.Monthly, &schedule.Language, &schedule.AttachPDF,
+		&schedule.LastWeeklyOn, &schedule.LastMonthlyOn, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/jackc/pgx/v5"
+)
+
+// USDAFoodRepository stores USDA FoodData Central foods in usda_foods
+type USDAFoodRepository struct {
+	db DB
+}
+
+// NewUSDAFoodRepository creates a new USDA food repository
+func NewUSDAFoodRepository(db DB) *USDAFoodRepository {
+	return &USDAFoodRepository{db: db}
+}
+
+// Upsert stores foods, replacing earlier copies. API results never replace
+// dataset imports, which carry every portion.
+func (r *USDAFoodRepository) Upsert(ctx context.Context, foods []*entity.USDAFood) error {
+	if len(foods) == 0 {
+		return nil
+	}
+
+	ids := make([]int32, len(foods))
+	descriptions := make([]string, len(foods))
+	dataTypes := make([]string, len(foods))
+	barcodes := make([]*string, len(foods))
+	data := make([]string, len(foods))
+	sources := make([]string, len(foods))
+	for i, f := range foods {
+		b, err := json.Marshal(f.Food)
+		if err != nil {
+			return err
+		}
+		ids[i] = int32(f.FdcID)
+		descriptions[i] = f.Food.Name
+		dataTypes[i] = truncate(f.DataType, 50)
+		if f.Food.Barcode != nil {
+			barcodes[i] = truncatePtr(f.Food.Barcode, 50)
+		}
+		data[i] = string(b)
+		sources[i] = f.Source
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO usda_foods (fdc_id, description, data_type, barcode, food, source, updated_at)
+		SELECT t.fdc_id, t.description, t.data_type, t.barcode, t.food::JSONB, t.source, CURRENT_TIMESTAMP
+		FROM unnest($1::INTEGER[], $2::TEXT[], $3::VARCHAR[], $4::VARCHAR[], $5::TEXT[], $6::VARCHAR[])
+			AS t(fdc_id, description, data_type, barcode, food, source)
+		ON CONFLICT (fdc_id) DO UPDATE SET
+			description = EXCLUDED.description,
+			data_type = EXCLUDED.data_type,
+			barcode = EXCLUDED.barcode,
+			food = EXCLUDED.food,
+			source = EXCLUDED.source,
+			updated_at = EXCLUDED.updated_at
+		WHERE usda_foods.source = 'api' OR EXCLUDED.source = 'dataset'
+	`, ids, descriptions, dataTypes, barcodes, data, sources)
+	return err
+}
+
+// FindByID finds a food by its FoodData Central ID
+func (r *USDAFoodRepository) FindByID(ctx context.Context, fdcID int) (*entity.FoodItem, error) {
+	return r.findOne(ctx, `SELECT food FROM usda_foods WHERE fdc_id = $1`, fdcID)
+}
+
+// FindByBarcode finds a branded food by its GTIN/UPC
+func (r *USDAFoodRepository) FindByBarcode(ctx context.Context, barcode string) (*entity.FoodItem, error) {
+	return r.findOne(ctx, `SELECT food FROM usda_foods WHERE barcode = $1 ORDER BY updated_at DESC LIMIT 1`, barcode)
+}
+
+// Search searches foods by description, shortest matching descriptions
+// first, and reports whether there are more
+func (r *USDAFoodRepository) Search(ctx context.Context, query string, limit, offset int) ([]entity.FoodItem, bool, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT food FROM usda_foods
+		WHERE description ILIKE '%' || $1 || '%'
+		ORDER BY description ILIKE $1 || '%' DESC, length(description), fdc_id
+		LIMIT $2 OFFSET $3
+	`, query, limit+1, offset)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	var foods []entity.FoodItem
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, false, err
+		}
+		var food entity.FoodItem
+		if err := json.Unmarshal(data, &food); err != nil {
+			return nil, false, err
+		}
+		foods = append(foods, food)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if len(foods) > limit {
+		return foods[:limit], true, nil
+	}
+	return foods, false, nil
+}
+
+func (r *USDAFoodRepository) findOne(ctx context.Context, sql string, args ...interface{}) (*entity.FoodItem, error) {
+	var data []byte
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&data); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	food := &entity.FoodItem{}
+	if err := json.Unmarshal(data, food); err != nil {
+		return nil, err
+	}
+	return food, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/bytetrack/backend/internal/domain/entity"
 	"github.com/google/uuid"
@@ -13,6 +14,8 @@ import (
 var (
 	ErrUserNotFound     = errors.New("user not found")
 	ErrEmailAlreadyUsed = errors.New("email already used")
+	ErrVersionConflict  = errors.New("version conflict")
+	ErrDuplicateDate    = errors.New("an entry already exists for this date")
 )
 
 // UserRepository handles user data operations
@@ -110,19 +113,20 @@ func (r *UserRepository) CreateProfile(ctx context.Context, profile *entity.User
 			bmr, tdee, target_calories,
 			protein_target, carbs_target, fat_target,
 			protein_calories, carbs_calories, fat_calories,
-			completed_onboarding
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
-		RETURNING created_at, updated_at
+			completed_onboarding, bmr_formula, timezone
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
+			COALESCE(NULLIF($20, ''), 'mifflin_st_jeor'), COALESCE(NULLIF($21, ''), 'Asia/Bangkok'))
+		RETURNING created_at, updated_at, version
 	`
 
-	err := r.db.QueryRow(ctx, sql,
+	err := conn(ctx, r.db).QueryRow(ctx, sql,
 		profile.UserID, profile.Age, profile.Gender, profile.Height, profile.Weight, profile.GoalWeight,
 		profile.ActivityLevel, profile.Goal, profile.PreferredLanguage,
 		profile.BMR, profile.TDEE, profile.TargetCalories,
 		profile.ProteinTarget, profile.CarbsTarget, profile.FatTarget,
 		profile.ProteinCalories, profile.CarbsCalories, profile.FatCalories,
-		profile.CompletedOnboarding,
-	).Scan(&profile.CreatedAt, &profile.UpdatedAt)
+		profile.CompletedOnboarding, profile.BMRFormula, profile.Timezone,
+	).Scan(&profile.CreatedAt, &profile.UpdatedAt, &profile.Version)
 
 	return err
 }
@@ -135,7 +139,9 @@ func (r *UserRepository) FindProfileByUserID(ctx context.Context, userID uuid.UU
 			   bmr, tdee, target_calories,
 			   protein_target, carbs_target, fat_target,
 			   protein_calories, carbs_calories, fat_calories,
-			   completed_onboarding, created_at, updated_at
+			   completed_onboarding, created_at, updated_at, version,
+			   adaptive_tdee, adjusted_tdee, tdee_reviewed_at,
+			   bmr_formula, body_fat_percent, timezone
 		FROM user_profiles
 		WHERE user_id = $1
 	`
@@ -147,7 +153,9 @@ func (r *UserRepository) FindProfileByUserID(ctx context.Context, userID uuid.UU
 		&profile.BMR, &profile.TDEE, &profile.TargetCalories,
 		&profile.ProteinTarget, &profile.CarbsTarget, &profile.FatTarget,
 		&profile.ProteinCalories, &profile.CarbsCalories, &profile.FatCalories,
-		&profile.CompletedOnboarding, &profile.CreatedAt, &profile.UpdatedAt,
+		&profile.CompletedOnboarding, &profile.CreatedAt, &profile.UpdatedAt, &profile.Version,
+		&profile.AdaptiveTDEE, &profile.AdjustedTDEE, &profile.TDEEReviewedAt,
+		&profile.BMRFormula, &profile.BodyFatPercent, &profile.Timezone,
 	)
 
 	if err != nil {
@@ -160,8 +168,9 @@ func (r *UserRepository) FindProfileByUserID(ctx context.Context, userID uuid.UU
 	return profile, nil
 }
 
-// UpdateProfile updates a user profile
-func (r *UserRepository) UpdateProfile(ctx context.Context, profile *entity.UserProfile) error {
+// UpdateProfile updates a user profile if its version still matches expectedVersion.
+// A nil expectedVersion updates unconditionally.
+func (r *UserRepository) UpdateProfile(ctx context.Context, profile *entity.UserProfile, expectedVersion *int) error {
 	sql := `
 		UPDATE user_profiles
 		SET age = $2, gender = $3, height = $4, weight = $5, goal_weight = $6,
@@ -169,20 +178,60 @@ func (r *UserRepository) UpdateProfile(ctx context.Context, profile *entity.User
 			bmr = $10, tdee = $11, target_calories = $12,
 			protein_target = $13, carbs_target = $14, fat_target = $15,
 			protein_calories = $16, carbs_calories = $17, fat_calories = $18,
-			completed_onboarding = $19
-		WHERE user_id = $1
+			completed_onboarding = $19,
+			adaptive_tdee = $21, adjusted_tdee = $22, tdee_reviewed_at = $23,
+			bmr_formula = $24, body_fat_percent = $25, timezone = $26,
+			version = version + 1
+		WHERE user_id = $1 AND ($20::INTEGER IS NULL OR version = $20)
+		RETURNING updated_at, version
 	`
 
-	_, err := r.db.Exec(ctx, sql,
+	err := conn(ctx, r.db).QueryRow(ctx, sql,
 		profile.UserID, profile.Age, profile.Gender, profile.Height, profile.Weight, profile.GoalWeight,
 		profile.ActivityLevel, profile.Goal, profile.PreferredLanguage,
 		profile.BMR, profile.TDEE, profile.TargetCalories,
 		profile.ProteinTarget, profile.CarbsTarget, profile.FatTarget,
-		profile.ProteinCalories, &profile.CarbsCalories, profile.FatCalories,
-		profile.CompletedOnboarding,
-	)
+		profile.ProteinCalories, profile.CarbsCalories, profile.FatCalories,
+		profile.CompletedOnboarding, expectedVersion,
+		profile.AdaptiveTDEE, profile.AdjustedTDEE, profile.TDEEReviewedAt,
+		profile.BMRFormula, profile.BodyFatPercent, profile.Timezone,
+	).Scan(&profile.UpdatedAt, &profile.Version)
 
-	return err
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		return err
+	}
+
+	return nil
+}
+
+// FindAdaptiveTDEEUsersDue finds users with adaptive TDEE enabled whose
+// last review was before the given time
+func (r *UserRepository) FindAdaptiveTDEEUsersDue(ctx context.Context, reviewedBefore time.Time) ([]uuid.UUID, error) {
+	sql := `
+		SELECT user_id
+		FROM user_profiles
+		WHERE adaptive_tdee AND (tdee_reviewed_at IS NULL OR tdee_reviewed_at < $1)
+	`
+
+	rows, err := r.db.Query(ctx, sql, reviewedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
 }
 
 // SaveRefreshToken saves a refresh token
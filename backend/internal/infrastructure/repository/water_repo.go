@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// WaterRepository handles water intake data operations
+type WaterRepository struct {
+	db DB
+}
+
+// NewWaterRepository creates a new water repository
+func NewWaterRepository(db DB) *WaterRepository {
+	return &WaterRepository{db: db}
+}
+
+// CreateLog logs a drink
+func (r *WaterRepository) CreateLog(ctx context.Context, log *entity.WaterLog) error {
+	sql := `
+		INSERT INTO water_logs (id, user_id, amount_ml, container_id, date)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING logged_at, created_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		log.ID, log.UserID, log.AmountML, log.ContainerID, log.Date,
+	).Scan(&log.LoggedAt, &log.CreatedAt)
+}
+
+// FindLogsByDate finds a user's drinks for a date
+func (r *WaterRepository) FindLogsByDate(ctx context.Context, userID uuid.UUID, date time.Time) ([]*entity.WaterLog, error) {
+	sql := `
+		SELECT id, user_id, amount_ml, container_id, date, logged_at, created_at
+		FROM water_logs
+		WHERE user_id = $1 AND date = $2
+		ORDER BY logged_at
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*entity.WaterLog
+	for rows.Next() {
+		log := &entity.WaterLog{}
+		err := rows.Scan(
+			&log.ID, &log.UserID, &log.AmountML, &log.ContainerID, &log.Date, &log.LoggedAt, &log.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+// DeleteLog deletes a drink owned by the user
+func (r *WaterRepository) DeleteLog(ctx context.Context, id, userID uuid.UUID) (time.Time, error) {
+	sql := `DELETE FROM water_logs WHERE id = $1 AND user_id = $2 RETURNING date`
+
+	var date time.Time
+	err := r.db.QueryRow(ctx, sql, id, userID).Scan(&date)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return date, ErrUserNotFound
+		}
+		return date, err
+	}
+	return date, nil
+}
+
+// GetDailyTotal gets a user's total water intake (ml) for a date
+func (r *WaterRepository) GetDailyTotal(ctx context.Context, userID uuid.UUID, date time.Time) (int, error) {
+	sql := `SELECT COALESCE(SUM(amount_ml), 0) FROM water_logs WHERE user_id = $1 AND date = $2`
+
+	var total int
+	err := r.db.QueryRow(ctx, sql, userID, date).Scan(&total)
+	return total, err
+}
+
+// GetRangeTotals gets per-day water totals between two dates (inclusive).
+// Days without drinks are omitted.
+func (r *WaterRepository) GetRangeTotals(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entity.DayWater, error) {
+	sql := `
+		SELECT date, COALESCE(SUM(amount_ml), 0), COUNT(*)
+		FROM water_logs
+		WHERE user_id = $1 AND date BETWEEN $2 AND $3
+		GROUP BY date
+		ORDER BY date
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []*entity.DayWater
+	for rows.Next() {
+		day := &entity.DayWater{}
+		if err := rows.Scan(&day.Date, &day.TotalML, &day.LogCount); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+// Container operations
+
+// CreateContainer saves a custom container size
+func (r *WaterRepository) CreateContainer(ctx context.Context, container *entity.WaterContainer) error {
+	sql := `
+		INSERT INTO water_containers (id, user_id, name, amount_ml, emoji)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		container.ID, container.UserID, container.Name, container.AmountML, container.Emoji,
+	).Scan(&container.CreatedAt)
+}
+
+// FindContainerByID finds a container owned by the user
+func (r *WaterRepository) FindContainerByID(ctx context.Context, id, userID uuid.UUID) (*entity.WaterContainer, error) {
+	sql := `
+		SELECT id, user_id, name, amount_ml, emoji, created_at
+		FROM water_containers
+		WHERE id = $1 AND user_id = $2
+	`
+
+	container := &entity.WaterContainer{}
+	err := r.db.QueryRow(ctx, sql, id, userID).Scan(
+		&container.ID, &container.UserID, &container.Name, &container.AmountML, &container.Emoji, &container.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return container, nil
+}
+
+// FindContainers finds a user's custom containers
+func (r *WaterRepository) FindContainers(ctx context.Context, userID uuid.UUID) ([]*entity.WaterContainer, error) {
+	sql := `
+		SELECT id, user_id, name, amount_ml, emoji, created_at
+		FROM water_containers
+		WHERE user_id = $1
+		ORDER BY amount_ml
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var containers []*entity.WaterContainer
+	for rows.Next() {
+		container := &entity.WaterContainer{}
+		err := rows.Scan(
+			&container.ID, &container.UserID, &container.Name, &container.AmountML, &container.Emoji, &container.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, container)
+	}
+
+	return containers, rows.Err()
+}
+
+// DeleteContainer deletes a container owned by the user
+func (r *WaterRepository) DeleteContainer(ctx context.Context, id, userID uuid.UUID) error {
+	sql := `DELETE FROM water_containers WHERE id = $1 AND user_id = $2`
+	tag, err := r.db.Exec(ctx, sql, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
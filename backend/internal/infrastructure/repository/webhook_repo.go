@@ -0,0 +1,420 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// WebhookRepository handles webhooks, the event outbox and deliveries.
+// Webhooks are scoped by owner: a user ID, or nil for app webhooks.
+type WebhookRepository struct {
+	db DB
+}
+
+// DueDelivery is a claimed delivery with its webhook and event
+type DueDelivery struct {
+	Delivery *entity.WebhookDelivery
+	Webhook  *entity.Webhook
+	Event    *entity.WebhookEvent
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `id, user_id, url, events, secret, description, active, created_at, updated_at`
+
+func scanWebhook(row pgx.Row) (*entity.Webhook, error) {
+	w := &entity.Webhook{}
+	var events []string
+	err := row.Scan(&w.ID, &w.UserID, &w.URL, &events, &w.Secret, &w.Description, &w.Active, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	w.Events = make([]entity.EventType, len(events))
+	for i, e := range events {
+		w.Events[i] = entity.EventType(e)
+	}
+	return w, nil
+}
+
+func eventStrings(events []entity.EventType) []string {
+	s := make([]string, len(events))
+	for i, e := range events {
+		s[i] = string(e)
+	}
+	return s
+}
+
+// Create creates a webhook
+func (r *WebhookRepository) Create(ctx context.Context, w *entity.Webhook) error {
+	sql := `
+		INSERT INTO webhooks (id, user_id, url, events, secret, description, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		w.ID, w.UserID, w.URL, eventStrings(w.Events), w.Secret, w.Description, w.Active,
+	).Scan(&w.CreatedAt, &w.UpdatedAt)
+}
+
+// FindByID finds an owner's webhook
+func (r *WebhookRepository) FindByID(ctx context.Context, id uuid.UUID, owner *uuid.UUID) (*entity.Webhook, error) {
+	sql := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2`
+
+	w, err := scanWebhook(r.db.QueryRow(ctx, sql, id, owner))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return w, nil
+}
+
+// FindByOwner finds an owner's webhooks, oldest first
+func (r *WebhookRepository) FindByOwner(ctx context.Context, owner *uuid.UUID) ([]*entity.Webhook, error) {
+	sql := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id IS NOT DISTINCT FROM $1 ORDER BY created_at`
+
+	rows, err := r.db.Query(ctx, sql, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*entity.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// CountByOwner counts an owner's webhooks
+func (r *WebhookRepository) CountByOwner(ctx context.Context, owner *uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM webhooks WHERE user_id IS NOT DISTINCT FROM $1`, owner).Scan(&count)
+	return count, err
+}
+
+// Update updates a webhook's URL, events, description, status and secret
+func (r *WebhookRepository) Update(ctx context.Context, w *entity.Webhook) error {
+	sql := `
+		UPDATE webhooks
+		SET url = $3, events = $4, description = $5, active = $6, secret = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, sql,
+		w.ID, w.UserID, w.URL, eventStrings(w.Events), w.Description, w.Active, w.Secret,
+	).Scan(&w.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// Delete deletes an owner's webhook with its deliveries
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID, owner *uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM webhooks WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2`, id, owner)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// CreateEvent writes an event to the outbox, joining the transaction
+// carried by ctx
+func (r *WebhookRepository) CreateEvent(ctx context.Context, e *entity.WebhookEvent) error {
+	sql := `
+		INSERT INTO webhook_events (id, user_id, type, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	return conn(ctx, r.db).QueryRow(ctx, sql, e.ID, e.UserID, e.Type, e.Payload).Scan(&e.CreatedAt)
+}
+
+// ClaimEvents locks the oldest undispatched events. It must run in a
+// transaction; concurrent dispatchers skip locked events.
+func (r *WebhookRepository) ClaimEvents(ctx context.Context, limit int) ([]*entity.WebhookEvent, error) {
+	sql := `
+		SELECT id, user_id, type, payload, created_at
+		FROM webhook_events
+		WHERE dispatched_at IS NULL
+		ORDER BY created_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*entity.WebhookEvent
+	for rows.Next() {
+		e := &entity.WebhookEvent{}
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Type, &e.Payload, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// FindSubscribers finds the active webhooks receiving a user's events: the
+// user's own and every app webhook
+func (r *WebhookRepository) FindSubscribers(ctx context.Context, userID uuid.UUID) ([]*entity.Webhook, error) {
+	sql := `SELECT ` + webhookColumns + ` FROM webhooks WHERE (user_id = $1 OR user_id IS NULL) AND active`
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*entity.Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// CreateDelivery queues an event for a webhook, once
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, webhookID, eventID uuid.UUID) error {
+	sql := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, sql, uuid.New(), webhookID, eventID)
+	return err
+}
+
+// MarkDispatched marks an event's deliveries as queued
+func (r *WebhookRepository) MarkDispatched(ctx context.Context, eventID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `UPDATE webhook_events SET dispatched_at = CURRENT_TIMESTAMP WHERE id = $1`, eventID)
+	return err
+}
+
+// ClaimDeliveries claims pending deliveries due by now for active webhooks,
+// pushing their next attempt to leaseUntil so other dispatchers skip them
+// while they are sent
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*DueDelivery, error) {
+	sql := `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id AND w.active
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2, updated_at = CURRENT_TIMESTAMP
+		FROM due, webhooks w, webhook_events e
+		WHERE d.id = due.id AND w.id = d.webhook_id AND e.id = d.event_id
+		RETURNING d.id, d.webhook_id, d.event_id, d.status, d.attempts, d.created_at,
+			w.url, w.secret, e.user_id, e.type, e.payload, e.created_at
+	`
+
+	rows, err := r.db.Query(ctx, sql, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []*DueDelivery
+	for rows.Next() {
+		d := &entity.WebhookDelivery{}
+		w := &entity.Webhook{}
+		e := &entity.WebhookEvent{}
+		err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.EventID, &d.Status, &d.Attempts, &d.CreatedAt,
+			&w.URL, &w.Secret, &e.UserID, &e.Type, &e.Payload, &e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		w.ID, e.ID, d.EventType = d.WebhookID, d.EventID, e.Type
+		due = append(due, &DueDelivery{Delivery: d, Webhook: w, Event: e})
+	}
+	return due, rows.Err()
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, d *entity.WebhookDelivery) error {
+	sql := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5, last_error = $6,
+			delivered_at = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, sql, d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatusCode, d.LastError, d.DeliveredAt)
+	return err
+}
+
+// CreateAttempt logs a delivery attempt
+func (r *WebhookRepository) CreateAttempt(ctx context.Context, a *entity.WebhookAttempt) error {
+	sql := `
+		INSERT INTO webhook_delivery_attempts (id, delivery_id, attempt, status_code, error, response_body, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(ctx, sql,
+		a.ID, a.DeliveryID, a.Attempt, a.StatusCode, a.Error, a.ResponseBody, a.DurationMS,
+	).Scan(&a.CreatedAt)
+}
+
+const deliveryColumns = `d.id, d.webhook_id, d.event_id, e.type, d.status, d.attempts, d.next_attempt_at,
+	d.last_status_code, d.last_error, d.delivered_at, d.created_at, d.updated_at`
+
+func scanDelivery(row pgx.Row) (*entity.WebhookDelivery, error) {
+	d := &entity.WebhookDelivery{}
+	err := row.Scan(
+		&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt, &d.UpdatedAt,
+	)
+	return d, err
+}
+
+// FindDeliveries finds a webhook's most recent deliveries, optionally by status
+func (r *WebhookRepository) FindDeliveries(ctx context.Context, webhookID uuid.UUID, status *entity.DeliveryStatus, limit int) ([]*entity.WebhookDelivery, error) {
+	sql := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN webhook_events e ON e.id = d.event_id
+		WHERE d.webhook_id = $1 AND ($2::VARCHAR IS NULL OR d.status = $2)
+		ORDER BY d.created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, sql, webhookID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*entity.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// FindDelivery finds a webhook's delivery with its attempt log
+func (r *WebhookRepository) FindDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	sql := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN webhook_events e ON e.id = d.event_id
+		WHERE d.id = $1 AND d.webhook_id = $2
+	`
+
+	d, err := scanDelivery(r.db.QueryRow(ctx, sql, deliveryID, webhookID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, delivery_id, attempt, status_code, error, response_body, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempt
+	`, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := &entity.WebhookAttempt{}
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.Attempt, &a.StatusCode, &a.Error, &a.ResponseBody, &a.DurationMS, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.Log = append(d.Log, a)
+	}
+	return d, rows.Err()
+}
+
+// Requeue queues a delivery to be sent again with a fresh set of retries
+func (r *WebhookRepository) Requeue(ctx context.Context, webhookID, deliveryID uuid.UUID) error {
+	sql := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND webhook_id = $2
+	`
+
+	tag, err := r.db.Exec(ctx, sql, deliveryID, webhookID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// RequeueDead queues every dead-lettered delivery of a webhook again
+func (r *WebhookRepository) RequeueDead(ctx context.Context, webhookID uuid.UUID) (int64, error) {
+	sql := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE webhook_id = $1 AND status = 'dead'
+	`
+
+	tag, err := r.db.Exec(ctx, sql, webhookID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// PurgeEvents deletes dispatched events created before a time, with their
+// deliveries and logs, unless a delivery is still pending
+func (r *WebhookRepository) PurgeEvents(ctx context.Context, before time.Time) (int64, error) {
+	sql := `
+		DELETE FROM webhook_events e
+		WHERE e.created_at < $1 AND e.dispatched_at IS NOT NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM webhook_deliveries d WHERE d.event_id = e.id AND d.status = 'pending'
+			)
+	`
+
+	tag, err := r.db.Exec(ctx, sql, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bytetrack/backend/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// WeightRepository handles body weight data operations
+type WeightRepository struct {
+	db DB
+}
+
+// NewWeightRepository creates a new weight repository
+func NewWeightRepository(db DB) *WeightRepository {
+	return &WeightRepository{db: db}
+}
+
+// Upsert records a weight entry, replacing any existing entry for the same date
+func (r *WeightRepository) Upsert(ctx context.Context, entry *entity.WeightEntry) error {
+	sql := `
+		INSERT INTO weight_entries (id, user_id, weight, date, note)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, date) DO UPDATE
+		SET weight = EXCLUDED.weight, note = EXCLUDED.note, updated_at = CURRENT_TIMESTAMP
+		RETURNING id, created_at, updated_at
+	`
+
+	return conn(ctx, r.db).QueryRow(ctx, sql,
+		entry.ID, entry.UserID, entry.Weight, entry.Date, entry.Note,
+	).Scan(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
+}
+
+// FindByID finds a weight entry by ID
+func (r *WeightRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.WeightEntry, error) {
+	sql := `
+		SELECT id, user_id, weight, date, note, created_at, updated_at
+		FROM weight_entries
+		WHERE id = $1
+	`
+
+	entry := &entity.WeightEntry{}
+	err := r.db.QueryRow(ctx, sql, id).Scan(
+		&entry.ID, &entry.UserID, &entry.Weight, &entry.Date, &entry.Note, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// FindByUserID finds a user's weight entries between two dates (inclusive), oldest first
+func (r *WeightRepository) FindByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*entity.WeightEntry, error) {
+	sql := `
+		SELECT id, user_id, weight, date, note, created_at, updated_at
+		FROM weight_entries
+		WHERE user_id = $1 AND date BETWEEN $2 AND $3
+		ORDER BY date
+	`
+
+	rows, err := r.db.Query(ctx, sql, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*entity.WeightEntry
+	for rows.Next() {
+		entry := &entity.WeightEntry{}
+		err := rows.Scan(
+			&entry.ID, &entry.UserID, &entry.Weight, &entry.Date, &entry.Note, &entry.CreatedAt, &entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// FindLatest finds a user's most recent weight entry
+func (r *WeightRepository) FindLatest(ctx context.Context, userID uuid.UUID) (*entity.WeightEntry, error) {
+	sql := `
+		SELECT id, user_id, weight, date, note, created_at, updated_at
+		FROM weight_entries
+		WHERE user_id = $1
+		ORDER BY date DESC
+		LIMIT 1
+	`
+
+	entry := &entity.WeightEntry{}
+	err := r.db.QueryRow(ctx, sql, userID).Scan(
+		&entry.ID, &entry.UserID, &entry.Weight, &entry.Date, &entry.Note, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// FindFirst finds a user's earliest weight entry
+func (r *WeightRepository) FindFirst(ctx context.Context, userID uuid.UUID) (*entity.WeightEntry, error) {
+	sql := `
+		SELECT id, user_id, weight, date, note, created_at, updated_at
+		FROM weight_entries
+		WHERE user_id = $1
+		ORDER BY date
+		LIMIT 1
+	`
+
+	entry := &entity.WeightEntry{}
+	err := r.db.QueryRow(ctx, sql, userID).Scan(
+		&entry.ID, &entry.UserID, &entry.Weight, &entry.Date, &entry.Note, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// CountByUserID counts a user's weight entries
+func (r *WeightRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	sql := `SELECT COUNT(*) FROM weight_entries WHERE user_id = $1`
+
+	var count int
+	err := r.db.QueryRow(ctx, sql, userID).Scan(&count)
+	return count, err
+}
+
+// Update updates a weight entry. Moving it onto a date that already has an
+// entry returns ErrDuplicateDate.
+func (r *WeightRepository) Update(ctx context.Context, entry *entity.WeightEntry) error {
+	sql := `
+		UPDATE weight_entries
+		SET weight = $2, date = $3, note = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, sql, entry.ID, entry.Weight, entry.Date, entry.Note).Scan(&entry.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return ErrDuplicateDate
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete deletes a weight entry owned by the user
+func (r *WeightRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	sql := `DELETE FROM weight_entries WHERE id = $1 AND user_id = $2`
+	tag, err := conn(ctx, r.db).Exec(ctx, sql, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,142 @@
+// Package usda is a client for the USDA FoodData Central API and a reader
+// for its downloadable datasets.
+package usda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBaseURL = "https://api.nal.usda.gov/fdc"
+	defaultTimeout = 10 * time.Second
+
+	// maxResponseSize bounds a response body
+	maxResponseSize = 10 << 20
+)
+
+var (
+	// ErrNotFound is returned when a food does not exist
+	ErrNotFound = errors.New("fooddata central: food not found")
+	// ErrRateLimited is returned when the API key's hourly limit is used up
+	ErrRateLimited = errors.New("fooddata central: rate limited")
+	// ErrUnavailable is returned when FoodData Central is down or responds
+	// with something other than the expected JSON
+	ErrUnavailable = errors.New("fooddata central: unavailable")
+)
+
+// Options configures a client
+type Options struct {
+	BaseURL   string
+	APIKey    string
+	Timeout   time.Duration
+	DataTypes []string // searched data types, e.g. Foundation and SR Legacy
+}
+
+// Client calls the FoodData Central API
+type Client struct {
+	opts   Options
+	client *http.Client
+}
+
+// New creates a new FoodData Central client
+func New(opts Options) *Client {
+	if opts.BaseURL == "" {
+		opts.BaseURL = defaultBaseURL
+	}
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+
+	return &Client{
+		opts: opts,
+		client: &http.Client{
+			Timeout: opts.Timeout,
+		},
+	}
+}
+
+// Search searches foods by description
+func (c *Client) Search(ctx context.Context, query string, page, pageSize int) (*SearchResponse, error) {
+	q := url.Values{}
+	q.Set("query", query)
+	q.Set("pageNumber", strconv.Itoa(page))
+	q.Set("pageSize", strconv.Itoa(pageSize))
+	if len(c.opts.DataTypes) > 0 {
+		q.Set("dataType", strings.Join(c.opts.DataTypes, ","))
+	}
+
+	var data SearchResponse
+	if err := c.get(ctx, "/v1/foods/search", q, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Food gets a food's details
+func (c *Client) Food(ctx context.Context, fdcID int) (*Food, error) {
+	var data Food
+	if err := c.get(ctx, "/v1/food/"+strconv.Itoa(fdcID), url.Values{}, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// get calls an API path and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, q url.Values, out interface{}) error {
+	q.Set("api_key", c.opts.APIKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.opts.BaseURL+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Don't leak the API key in the URL of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("%w: responded %d", ErrUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("fooddata central: responded %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return fmt.Errorf("%w: unexpected content type %q", ErrUnavailable, resp.Header.Get("Content-Type"))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%w: invalid response: %v", ErrUnavailable, err)
+	}
+	return nil
+}
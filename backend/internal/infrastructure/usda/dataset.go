@@ -0,0 +1,226 @@
+package usda
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReadJSONDataset streams the foods of a FoodData Central JSON download,
+// such as {"FoundationFoods": [...]} or {"SRLegacyFoods": [...]}, to fn
+// without loading the whole file
+func ReadJSONDataset(r io.Reader, fn func(*Food) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		if _, err := dec.Token(); err != nil { // the dataset name
+			return err
+		}
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			food := &Food{}
+			if err := dec.Decode(food); err != nil {
+				return fmt.Errorf("invalid food: %w", err)
+			}
+			if err := fn(food); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("invalid dataset: expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// ReadCSVDataset reads an unzipped FoodData Central CSV download from dir
+// and passes each food to fn. food.csv, nutrient.csv and food_nutrient.csv
+// are required; food_portion.csv, measure_unit.csv and branded_food.csv are
+// used when present.
+func ReadCSVDataset(dir string, fn func(*Food) error) error {
+	units := make(map[string]string)
+	err := readCSV(filepath.Join(dir, "nutrient.csv"), true, func(row csvRow) error {
+		units[row.get("id")] = normalizeUnit(row.get("unit_name"))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	measureUnits := make(map[string]string)
+	err = readCSV(filepath.Join(dir, "measure_unit.csv"), false, func(row csvRow) error {
+		measureUnits[row.get("id")] = row.get("name")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var foods []*Food
+	byID := make(map[int]*Food)
+	err = readCSV(filepath.Join(dir, "food.csv"), true, func(row csvRow) error {
+		id, err := strconv.Atoi(row.get("fdc_id"))
+		if err != nil {
+			return fmt.Errorf("invalid fdc_id %q", row.get("fdc_id"))
+		}
+		food := &Food{FdcID: id, DataType: row.get("data_type"), Description: row.get("description")}
+		foods = append(foods, food)
+		byID[id] = food
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = readCSV(filepath.Join(dir, "branded_food.csv"), false, func(row csvRow) error {
+		if food := byID[row.int("fdc_id")]; food != nil {
+			food.BrandOwner = row.get("brand_owner")
+			food.BrandName = row.get("brand_name")
+			food.GtinUpc = row.get("gtin_upc")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = readCSV(filepath.Join(dir, "food_nutrient.csv"), true, func(row csvRow) error {
+		food := byID[row.int("fdc_id")]
+		if food == nil {
+			return nil
+		}
+		amount, err := strconv.ParseFloat(row.get("amount"), 64)
+		if err != nil {
+			return nil
+		}
+		nutrientID := row.get("nutrient_id")
+		id, _ := strconv.Atoi(nutrientID)
+		food.FoodNutrients = append(food.FoodNutrients, FoodNutrient{NutrientID: id, Unit: units[nutrientID], Amount: amount})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = readCSV(filepath.Join(dir, "food_portion.csv"), false, func(row csvRow) error {
+		food := byID[row.int("fdc_id")]
+		if food == nil {
+			return nil
+		}
+		grams, err := strconv.ParseFloat(row.get("gram_weight"), 64)
+		if err != nil || grams <= 0 {
+			return nil
+		}
+		amount, _ := strconv.ParseFloat(row.get("amount"), 64)
+		portion := FoodPortion{
+			GramWeight:         grams,
+			Amount:             amount,
+			Modifier:           row.get("modifier"),
+			PortionDescription: row.get("portion_description"),
+		}
+		if name := measureUnits[row.get("measure_unit_id")]; name != "" {
+			portion.MeasureUnit = &struct {
+				Name string `json:"name"`
+			}{Name: name}
+		}
+		food.FoodPortions = append(food.FoodPortions, portion)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, food := range foods {
+		if err := fn(food); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// csvRow is a CSV record with its columns looked up by header name
+type csvRow struct {
+	columns map[string]int
+	record  []string
+}
+
+func (r csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r csvRow) int(column string) int {
+	n, _ := strconv.Atoi(r.get(column))
+	return n
+}
+
+// readCSV calls fn for every record of a CSV file with a header row. A
+// missing optional file is skipped.
+func readCSV(path string, required bool, fn func(csvRow) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if !required && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	// Files may start with a UTF-8 byte order mark, which would otherwise
+	// break the quoted first header
+	br := bufio.NewReader(f)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\ufeff" {
+		br.Discard(3)
+	}
+
+	r := csv.NewReader(br)
+	r.ReuseRecord = true
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		if err := fn(csvRow{columns: columns, record: record}); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package usda
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Food is a FoodData Central food. Search results, food details and dataset
+// records all decode into it.
+type Food struct {
+	FdcID         int            `json:"fdcId"`
+	Description   string         `json:"description"`
+	DataType      string         `json:"dataType"`
+	BrandOwner    string         `json:"brandOwner"`
+	BrandName     string         `json:"brandName"`
+	GtinUpc       string         `json:"gtinUpc"`
+	FoodNutrients []FoodNutrient `json:"foodNutrients"`
+	FoodPortions  []FoodPortion  `json:"foodPortions"` // food details and datasets
+	FoodMeasures  []FoodPortion  `json:"foodMeasures"` // search results
+}
+
+// Portions returns the food's household portions
+func (f *Food) Portions() []FoodPortion {
+	return append(append([]FoodPortion(nil), f.FoodPortions...), f.FoodMeasures...)
+}
+
+// FoodNutrient is the amount of a nutrient in 100 g of a food
+type FoodNutrient struct {
+	NutrientID int
+	Unit       string // G, MG, UG or KCAL
+	Amount     float64
+}
+
+// UnmarshalJSON decodes both the flat search result shape
+// ({"nutrientId", "unitName", "value"}) and the nested detail and dataset
+// shape ({"nutrient": {"id", "unitName"}, "amount"})
+func (n *FoodNutrient) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		NutrientID int      `json:"nutrientId"`
+		UnitName   string   `json:"unitName"`
+		Value      *float64 `json:"value"`
+		Amount     *float64 `json:"amount"`
+		Nutrient   *struct {
+			ID       int    `json:"id"`
+			UnitName string `json:"unitName"`
+		} `json:"nutrient"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	n.NutrientID, n.Unit = raw.NutrientID, raw.UnitName
+	if raw.Nutrient != nil {
+		n.NutrientID, n.Unit = raw.Nutrient.ID, raw.Nutrient.UnitName
+	}
+	n.Unit = normalizeUnit(n.Unit)
+	switch {
+	case raw.Amount != nil:
+		n.Amount = *raw.Amount
+	case raw.Value != nil:
+		n.Amount = *raw.Value
+	}
+	return nil
+}
+
+// normalizeUnit upper-cases a unit name, spelling micrograms UG
+func normalizeUnit(unit string) string {
+	return strings.ToUpper(strings.ReplaceAll(unit, "µ", "u"))
+}
+
+// FoodPortion is a household portion of a food and its weight
+type FoodPortion struct {
+	GramWeight         float64 `json:"gramWeight"`
+	Amount             float64 `json:"amount"`
+	Modifier           string  `json:"modifier"`
+	PortionDescription string  `json:"portionDescription"`
+	DisseminationText  string  `json:"disseminationText"`
+	MeasureUnit        *struct {
+		Name string `json:"name"`
+	} `json:"measureUnit"`
+}
+
+// Name describes the portion, e.g. "1 cup, chopped"
+func (p FoodPortion) Name() string {
+	if p.PortionDescription != "" && p.PortionDescription != "Quantity not specified" {
+		return p.PortionDescription
+	}
+	if p.DisseminationText != "" {
+		return p.DisseminationText
+	}
+
+	var parts []string
+	if p.Amount > 0 {
+		parts = append(parts, strconv.FormatFloat(p.Amount, 'f', -1, 64))
+	}
+	if p.MeasureUnit != nil && p.MeasureUnit.Name != "" && p.MeasureUnit.Name != "undetermined" {
+		parts = append(parts, p.MeasureUnit.Name)
+	}
+	if p.Modifier != "" {
+		parts = append(parts, p.Modifier)
+	}
+	return strings.Join(parts, " ")
+}
+
+// SearchResponse is a page of search results
+type SearchResponse struct {
+	TotalHits   int    `json:"totalHits"`
+	CurrentPage int    `json:"currentPage"`
+	TotalPages  int    `json:"totalPages"`
+	Foods       []Food `json:"foods"`
+}
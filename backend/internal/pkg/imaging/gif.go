@@ -0,0 +1,58 @@
+package imaging
+
+import "errors"
+
+// errMalformedGIF is returned when a GIF's block structure can't be walked
+var errMalformedGIF = errors.New("gif: malformed block structure")
+
+// gifFrames counts the frames of a GIF by walking its blocks without
+// decoding any image data. It stops counting once limit is passed.
+func gifFrames(data []byte, limit int) (int, error) {
+	// Header and logical screen descriptor
+	if len(data) < 13 {
+		return 0, errMalformedGIF
+	}
+	pos := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << (flags&0x07 + 1) // global color table
+	}
+
+	frames := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // extension: label, then sub-blocks
+			pos += 2
+		case 0x2C: // image descriptor, optional local color table, LZW code size, then sub-blocks
+			frames++
+			if frames > limit {
+				return frames, nil
+			}
+			if pos+10 > len(data) {
+				return 0, errMalformedGIF
+			}
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1)
+			}
+			pos++
+		case 0x3B: // trailer
+			return frames, nil
+		default:
+			return 0, errMalformedGIF
+		}
+
+		// Sub-blocks, each prefixed by its length, end with an empty one
+		for {
+			if pos >= len(data) {
+				return 0, errMalformedGIF
+			}
+			size := int(data[pos])
+			pos += size + 1
+			if size == 0 {
+				break
+			}
+		}
+	}
+	return frames, nil
+}
@@ -0,0 +1,223 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// ErrUnsupportedFormat is returned for uploads that are not a supported image type
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// ErrTooLarge is returned for images with more pixels or frames than allowed,
+// which could take far more memory to decode than their size suggests
+var ErrTooLarge = errors.New("image has too many pixels or frames")
+
+// AllowedTypes lists the MIME types accepted for upload
+var AllowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// DetectType sniffs the MIME type from the content rather than trusting the client
+func DetectType(data []byte) string {
+	return http.DetectContentType(data)
+}
+
+// Processed holds a re-encoded image and its thumbnail
+type Processed struct {
+	Image     []byte
+	Thumbnail []byte
+	Width     int
+	Height    int
+}
+
+// Options controls image processing
+type Options struct {
+	MaxDimension       int // longest side of the stored image
+	ThumbnailDimension int // longest side of the thumbnail
+	Quality            int // JPEG quality
+	MaxPixels          int // largest width times height decoded
+	MaxFrames          int // most frames in an animated GIF
+}
+
+// DefaultOptions are suitable for meal photos
+var DefaultOptions = Options{
+	MaxDimension:       2048,
+	ThumbnailDimension: 320,
+	Quality:            85,
+	MaxPixels:          40_000_000,
+	MaxFrames:          100,
+}
+
+// Process decodes an uploaded image, applies its EXIF orientation and re-encodes it
+// as JPEG together with a thumbnail. Re-encoding drops all metadata, including EXIF
+// location data. The dimensions, and a GIF's frame count, are checked before
+// decoding.
+func Process(data []byte, opts Options) (*Processed, error) {
+	contentType := DetectType(data)
+	if !AllowedTypes[contentType] {
+		return nil, ErrUnsupportedFormat
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxPixels > 0 && int64(config.Width)*int64(config.Height) > int64(opts.MaxPixels) {
+		return nil, ErrTooLarge
+	}
+
+	var src image.Image
+	switch contentType {
+	case "image/jpeg":
+		src, err = jpeg.Decode(bytes.NewReader(data))
+	case "image/png":
+		src, err = png.Decode(bytes.NewReader(data))
+	case "image/gif":
+		if opts.MaxFrames > 0 {
+			frames, ferr := gifFrames(data, opts.MaxFrames)
+			if ferr != nil {
+				return nil, ferr
+			}
+			if frames > opts.MaxFrames {
+				return nil, ErrTooLarge
+			}
+		}
+		src, err = gif.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Scaling before rotating keeps the only full-size copy the decoded
+	// image; the longest side is the same either way
+	full := Fit(src, opts.MaxDimension)
+	if contentType == "image/jpeg" {
+		if orientation := jpegOrientation(data); orientation > 1 {
+			full = applyOrientation(toRGBA(full), orientation)
+		}
+	}
+	thumb := Fit(full, opts.ThumbnailDimension)
+
+	fullBytes, err := encodeJPEG(full, opts.Quality)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbBytes, err := encodeJPEG(thumb, opts.Quality)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := full.Bounds()
+	return &Processed{
+		Image:     fullBytes,
+		Thumbnail: thumbBytes,
+		Width:     bounds.Dx(),
+		Height:    bounds.Dy(),
+	}, nil
+}
+
+// encodeJPEG encodes onto a white background so transparent PNG/GIF areas are not black
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	bounds := img.Bounds()
+	canvas := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toRGBA converts any image to an RGBA image with origin at (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// Fit scales an image down so its longest side is at most maxDim, preserving aspect ratio.
+// Images that already fit are returned unchanged.
+func Fit(img image.Image, maxDim int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if maxDim <= 0 || (w <= maxDim && h <= maxDim) {
+		return img
+	}
+
+	var dw, dh int
+	if w >= h {
+		dw = maxDim
+		dh = max(1, h*maxDim/w)
+	} else {
+		dh = maxDim
+		dw = max(1, w*maxDim/h)
+	}
+
+	return resizeBox(img, dw, dh)
+}
+
+// resizeBox downscales with an area-averaging box filter. Images other than
+// RGBA are converted a band of rows at a time rather than copied whole.
+func resizeBox(img image.Image, dw, dh int) *image.RGBA {
+	bounds := img.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	rgba, _ := img.(*image.RGBA)
+	var band *image.RGBA
+	if rgba == nil {
+		band = image.NewRGBA(image.Rect(0, 0, sw, (sh+dh-1)/dh+1))
+	}
+
+	for dy := 0; dy < dh; dy++ {
+		y0 := dy * sh / dh
+		y1 := max(y0+1, (dy+1)*sh/dh)
+
+		src, top := rgba, 0
+		if band != nil {
+			// Rows y0 to y1 of the source become rows 0 onwards of the band
+			draw.Draw(band, image.Rect(0, 0, sw, y1-y0), img, image.Pt(bounds.Min.X, bounds.Min.Y+y0), draw.Src)
+			src, top = band, y0
+		}
+		for dx := 0; dx < dw; dx++ {
+			x0 := dx * sw / dw
+			x1 := max(x0+1, (dx+1)*sw/dw)
+
+			var r, g, b, a, n uint32
+			for y := y0; y < y1; y++ {
+				off := src.PixOffset(src.Rect.Min.X+x0, src.Rect.Min.Y+y-top)
+				for x := x0; x < x1; x++ {
+					r += uint32(src.Pix[off])
+					g += uint32(src.Pix[off+1])
+					b += uint32(src.Pix[off+2])
+					a += uint32(src.Pix[off+3])
+					off += 4
+					n++
+				}
+			}
+
+			i := dst.PixOffset(dx, dy)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
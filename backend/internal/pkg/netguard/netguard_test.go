@@ -0,0 +1,144 @@
+package netguard
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		// Public
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"100.63.255.255", true},  // just below carrier-grade NAT
+		{"100.128.0.0", true},     // just above carrier-grade NAT
+		{"198.17.255.255", true},  // just below benchmarking
+		{"223.255.255.255", true}, // just below multicast
+		{"2606:4700:4700::1111", true},
+		{"::ffff:8.8.8.8", true},
+
+		// IPv4 special-purpose ranges
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"10.0.0.1", false},
+		{"100.64.0.1", false},
+		{"100.100.100.100", false},
+		{"100.127.255.255", false},
+		{"127.0.0.1", false},
+		{"127.255.255.254", false},
+		{"169.254.169.254", false},
+		{"172.16.0.1", false},
+		{"172.31.255.255", false},
+		{"192.0.0.1", false},
+		{"192.0.0.170", false},
+		{"192.0.2.1", false},
+		{"192.88.99.1", false},
+		{"192.168.1.1", false},
+		{"198.18.0.1", false},
+		{"198.19.255.255", false},
+		{"198.51.100.1", false},
+		{"203.0.113.1", false},
+		{"224.0.0.1", false},
+		{"239.255.255.250", false},
+		{"240.0.0.1", false},
+		{"255.255.255.255", false},
+
+		// IPv6 special-purpose ranges
+		{"::", false},
+		{"::1", false},
+		{"::10.0.0.1", false},       // IPv4-compatible
+		{"64:ff9b::a00:1", false},   // NAT64 of 10.0.0.1
+		{"64:ff9b::808:808", false}, // NAT64 of a public address is still refused
+		{"64:ff9b:1::1", false},
+		{"100::1", false},
+		{"2001::1", false},       // Teredo
+		{"2001:db8::1", false},   // documentation
+		{"2002:a00:1::1", false}, // 6to4 of 10.0.0.1
+		{"fc00::1", false},
+		{"fd12:3456:789a::1", false},
+		{"fe80::1", false},
+		{"fec0::1", false},
+		{"ff02::1", false},
+
+		// IPv4-mapped IPv6 addresses are checked as IPv4
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"::ffff:100.64.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"::ffff:198.18.0.1", false},
+		{"::ffff:240.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			ip := net.ParseIP(tt.ip)
+			if ip == nil {
+				t.Fatalf("invalid test address %q", tt.ip)
+			}
+			if got := IsPublic(ip); got != tt.want {
+				t.Errorf("IsPublic(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+			// net.ParseIP returns 16 bytes; the 4-byte form must agree
+			if ip4 := ip.To4(); ip4 != nil {
+				if got := IsPublic(ip4); got != tt.want {
+					t.Errorf("IsPublic(%s as 4 bytes) = %v, want %v", tt.ip, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestIsPublicHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"93.184.216.34", true},
+		{"localhost", false},
+		{"LOCALHOST.", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := IsPublicHost(tt.host); got != tt.want {
+				t.Errorf("IsPublicHost(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControl(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:4700:4700::1111]:443", true},
+		{"127.0.0.1:80", false},
+		{"100.64.0.1:443", false},
+		{"[::ffff:10.0.0.1]:443", false},
+		{"[64:ff9b::a00:1]:443", false},
+		{"not-an-address", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := Control("tcp", tt.address, nil)
+			if tt.allowed && err != nil {
+				t.Errorf("Control(%s) = %v, want allowed", tt.address, err)
+			}
+			if !tt.allowed && err == nil {
+				t.Errorf("Control(%s) allowed, want refused", tt.address)
+			}
+		})
+	}
+
+	if err := Control("tcp", "10.0.0.1:80", nil); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Control error = %v, want ErrForbiddenAddress", err)
+	}
+}
@@ -0,0 +1,47 @@
+package pdf
+
+// helveticaWidths holds Helvetica advance widths for ASCII 32-126 in
+// thousandths of the font size, from the standard AFM metrics
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0-9
+	278, 278, 584, 584, 584, 556, 1015, // : to @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A-M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N-Z
+	278, 278, 278, 469, 556, 333, // [ to `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a-m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n-z
+	334, 260, 334, 584, // { to ~
+}
+
+// helveticaWidth returns a rune's width, using a typical width outside ASCII
+func helveticaWidth(r rune) float64 {
+	if r >= 32 && r <= 126 {
+		return float64(helveticaWidths[r-32])
+	}
+	return 556
+}
+
+// winAnsiSpecial maps runes outside Latin-1 that Windows-1252 encodes in 0x80-0x9F
+var winAnsiSpecial = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// winAnsi encodes text as Windows-1252, replacing runes it lacks with '?'
+func winAnsi(text string) string {
+	b := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 32 && r <= 126, r >= 0xA0 && r <= 0xFF:
+			b = append(b, byte(r))
+		case winAnsiSpecial[r] != 0:
+			b = append(b, winAnsiSpecial[r])
+		default:
+			b = append(b, '?')
+		}
+	}
+	return string(b)
+}
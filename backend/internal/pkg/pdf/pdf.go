@@ -0,0 +1,322 @@
+// Package pdf writes simple PDF documents with text, lines and filled
+// rectangles. Text uses the standard Helvetica font, or an embedded
+// TrueType font for scripts Helvetica cannot render.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A4 page size in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Color is an RGB color
+type Color struct {
+	R, G, B uint8
+}
+
+// Black is the default text color
+var Black = Color{}
+
+// Document is a PDF being built page by page. Coordinates are in points from
+// the top-left corner of the page; text is positioned by its baseline.
+type Document struct {
+	width, height float64
+	font          *TrueTypeFont // nil uses Helvetica
+	used          map[uint16]rune
+	pages         []*bytes.Buffer
+	title         string
+}
+
+// New creates an A4 document. With a nil font, text is limited to the
+// Windows-1252 character set and other runes render as '?'.
+func New(font *TrueTypeFont) *Document {
+	return &Document{
+		width:  A4Width,
+		height: A4Height,
+		font:   font,
+		used:   make(map[uint16]rune),
+	}
+}
+
+// SetTitle sets the document title shown by PDF viewers
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// Size returns the page width and height
+func (d *Document) Size() (float64, float64) {
+	return d.width, d.height
+}
+
+// AddPage starts a new page; drawing goes to the latest page
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// PageCount returns the number of pages
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws text with its baseline at (x, y). Bold is simulated by
+// stroking the glyph outlines, which works for any font.
+func (d *Document) Text(x, y, size float64, text string, color Color, bold bool) {
+	if text == "" {
+		return
+	}
+
+	p := d.page()
+	fmt.Fprintf(p, "BT %s rg ", color.operands())
+	if bold {
+		fmt.Fprintf(p, "%s RG 2 Tr %s w ", color.operands(), num(size*0.03))
+	}
+	fmt.Fprintf(p, "/F1 %s Tf %s %s Td %s Tj", num(size), num(x), num(d.height-y), d.encode(text))
+	if bold {
+		p.WriteString(" 0 Tr")
+	}
+	p.WriteString(" ET\n")
+}
+
+// TextWidth returns the width of text at a font size
+func (d *Document) TextWidth(text string, size float64) float64 {
+	var width float64
+	for _, r := range text {
+		if d.font != nil {
+			width += d.font.advance(d.font.Glyph(r))
+		} else {
+			width += helveticaWidth(r)
+		}
+	}
+	return width * size / 1000
+}
+
+// Rect fills a rectangle whose top-left corner is at (x, y)
+func (d *Document) Rect(x, y, w, h float64, color Color) {
+	fmt.Fprintf(d.page(), "%s rg %s %s %s %s re f\n",
+		color.operands(), num(x), num(d.height-y-h), num(w), num(h))
+}
+
+// Line draws a straight line
+func (d *Document) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(d.page(), "%s RG %s w %s %s m %s %s l S\n",
+		color.operands(), num(width), num(x1), num(d.height-y1), num(x2), num(d.height-y2))
+}
+
+// encode returns text as a PDF string operand for the document's font
+func (d *Document) encode(text string) string {
+	if d.font == nil {
+		return "(" + escapeLiteral(winAnsi(text)) + ")"
+	}
+
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range text {
+		gid := d.font.Glyph(r)
+		if gid != 0 {
+			d.used[gid] = r
+		}
+		fmt.Fprintf(&b, "%04X", gid)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// Bytes serializes the document
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	w := &writer{offsets: make(map[int]int)}
+	w.buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	// Object numbers are fixed up front so objects can reference each other.
+	// The font is object 4, which page resources refer to as /F1.
+	catalog, pages, info, font := 1, 2, 3, 4
+	fontObjs := d.fontObjects(font)
+	next := font + len(fontObjs)
+
+	pageObjs := make([]int, len(d.pages))
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		pageObjs[i] = next + i*2
+		kids[i] = fmt.Sprintf("%d 0 R", pageObjs[i])
+	}
+
+	w.object(catalog, object{dict: fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages)})
+	w.object(pages, object{dict: fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(kids), num(d.width), num(d.height))})
+	w.object(info, object{dict: fmt.Sprintf("<< /Title %s /Producer (ByteTrack) >>", textString(d.title))})
+
+	for i, obj := range fontObjs {
+		w.object(font+i, obj)
+	}
+
+	for i, content := range d.pages {
+		w.object(pageObjs[i], object{dict: fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pages, font, pageObjs[i]+1)})
+		w.object(pageObjs[i]+1, object{stream: content.Bytes()})
+	}
+
+	return w.finish(catalog, info)
+}
+
+// object is a PDF object: a dictionary, or a stream with extra dictionary entries
+type object struct {
+	dict   string
+	stream []byte
+}
+
+// fontObjects returns the font objects numbered from first. An embedded
+// font needs the Type0 font, its CIDFont, a descriptor, the font file and a
+// ToUnicode map.
+func (d *Document) fontObjects(first int) []object {
+	if d.font == nil {
+		return []object{{dict: "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"}}
+	}
+
+	f := d.font
+	gids := make([]int, 0, len(d.used))
+	for gid := range d.used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	var widths strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", gid, int(f.advance(uint16(gid))))
+	}
+
+	cid, descriptor, file, toUnicode := first+1, first+2, first+3, first+4
+	return []object{
+		{dict: fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /EmbeddedFont /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			cid, toUnicode)},
+		{dict: fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /EmbeddedFont /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW 1000 /W [%s] >>",
+			descriptor, strings.TrimSpace(widths.String()))},
+		{dict: fmt.Sprintf("<< /Type /FontDescriptor /FontName /EmbeddedFont /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+			f.scale(f.ascent), f.scale(f.descent), f.scale(f.ascent), file)},
+		{dict: fmt.Sprintf("/Length1 %d", len(f.data)), stream: f.data},
+		{stream: d.toUnicode(gids)},
+	}
+}
+
+// toUnicode builds the CMap that lets viewers copy and search embedded-font text
+func (d *Document) toUnicode(gids []int) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// bfchar blocks hold at most 100 entries
+	for start := 0; start < len(gids); start += 100 {
+		end := start + 100
+		if end > len(gids) {
+			end = len(gids)
+		}
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, gid := range gids[start:end] {
+			fmt.Fprintf(&b, "<%04X> <%s>\n", gid, utf16Hex(d.used[uint16(gid)]))
+		}
+		b.WriteString("endbfchar\n")
+	}
+
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+// writer tracks object offsets for the cross-reference table
+type writer struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+// object writes an object, compressing streams
+func (w *writer) object(num int, obj object) {
+	w.offsets[num] = w.buf.Len()
+	if obj.stream == nil {
+		fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", num, obj.dict)
+		return
+	}
+
+	// zlib only fails when the underlying writer does, and bytes.Buffer doesn't
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(obj.stream)
+	zw.Close()
+
+	extra := ""
+	if obj.dict != "" {
+		extra = " " + obj.dict
+	}
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode%s >>\nstream\n", num, compressed.Len(), extra)
+	w.buf.Write(compressed.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+}
+
+// finish writes the cross-reference table and trailer
+func (w *writer) finish(root, info int) []byte {
+	size := len(w.offsets) + 1
+
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", size)
+	for i := 1; i < size; i++ {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", w.offsets[i])
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", size, root, info, xref)
+	return w.buf.Bytes()
+}
+
+func (c Color) operands() string {
+	return fmt.Sprintf("%s %s %s", num(float64(c.R)/255), num(float64(c.G)/255), num(float64(c.B)/255))
+}
+
+// num formats a number compactly, as PDF has no exponent notation
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
+
+func escapeLiteral(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", `\r`, "\n", `\n`).Replace(s)
+}
+
+// textString encodes a document information string as UTF-16BE
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, r := range s {
+		b.WriteString(utf16Hex(r))
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+func utf16Hex(r rune) string {
+	if r >= 0x10000 {
+		r -= 0x10000
+		return fmt.Sprintf("%04X%04X", 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+	}
+	return fmt.Sprintf("%04X", r)
+}
@@ -0,0 +1,226 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedFont is returned for fonts that cannot be embedded, such as
+// OpenType fonts with CFF outlines
+var ErrUnsupportedFont = errors.New("unsupported font")
+
+// TrueTypeFont is a parsed TrueType font. It is embedded whole, which lets
+// documents render scripts the standard PDF fonts lack, such as Thai.
+type TrueTypeFont struct {
+	data       []byte
+	unitsPerEm int
+	ascent     int
+	descent    int
+	bbox       [4]int
+	advances   []int // per glyph, in font units
+	glyphs     map[rune]uint16
+}
+
+// ParseTrueType parses a TrueType (.ttf) font
+func ParseTrueType(data []byte) (*TrueTypeFont, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("%w: file too short", ErrUnsupportedFont)
+	}
+	switch binary.BigEndian.Uint32(data) {
+	case 0x00010000, 0x74727565: // 1.0 and 'true'
+	default:
+		return nil, fmt.Errorf("%w: not a TrueType font", ErrUnsupportedFont)
+	}
+
+	tables := make(map[string][]byte)
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := 12 + i*16
+		if rec+16 > len(data) {
+			return nil, fmt.Errorf("%w: truncated table directory", ErrUnsupportedFont)
+		}
+		offset := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("%w: table out of range", ErrUnsupportedFont)
+		}
+		tables[string(data[rec:rec+4])] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("%w: missing %s table", ErrUnsupportedFont, tag)
+		}
+	}
+
+	head, hhea, maxp := tables["head"], tables["hhea"], tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, fmt.Errorf("%w: truncated header tables", ErrUnsupportedFont)
+	}
+
+	font := &TrueTypeFont{
+		data:       data,
+		unitsPerEm: int(binary.BigEndian.Uint16(head[18:])),
+		ascent:     int(int16(binary.BigEndian.Uint16(hhea[4:]))),
+		descent:    int(int16(binary.BigEndian.Uint16(hhea[6:]))),
+	}
+	if font.unitsPerEm == 0 {
+		return nil, fmt.Errorf("%w: invalid units per em", ErrUnsupportedFont)
+	}
+	for i := range font.bbox {
+		font.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+i*2:])))
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	numMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	hmtx := tables["hmtx"]
+	if numMetrics == 0 || numMetrics > numGlyphs || len(hmtx) < numMetrics*4 {
+		return nil, fmt.Errorf("%w: invalid horizontal metrics", ErrUnsupportedFont)
+	}
+	font.advances = make([]int, numGlyphs)
+	for i := range font.advances {
+		if i < numMetrics {
+			font.advances[i] = int(binary.BigEndian.Uint16(hmtx[i*4:]))
+		} else {
+			// Glyphs past the last metric share its advance
+			font.advances[i] = font.advances[numMetrics-1]
+		}
+	}
+
+	glyphs, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	font.glyphs = glyphs
+
+	return font, nil
+}
+
+// Glyph returns the glyph for a rune, or 0 (.notdef) when the font lacks it
+func (f *TrueTypeFont) Glyph(r rune) uint16 {
+	return f.glyphs[r]
+}
+
+// advance returns a glyph's advance width in thousandths of the font size
+func (f *TrueTypeFont) advance(gid uint16) float64 {
+	if int(gid) >= len(f.advances) {
+		return 0
+	}
+	return float64(f.advances[gid]) * 1000 / float64(f.unitsPerEm)
+}
+
+// scale converts font units to thousandths of the font size
+func (f *TrueTypeFont) scale(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
+
+// parseCmap reads the Unicode character map, preferring the full-repertoire
+// format 12 subtable over the BMP-only format 4
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, fmt.Errorf("%w: truncated cmap", ErrUnsupportedFont)
+	}
+
+	var bmp, full []byte
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables; i++ {
+		rec := 4 + i*8
+		if rec+8 > len(cmap) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(cmap[rec:])
+		encoding := binary.BigEndian.Uint16(cmap[rec+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[rec+4:]))
+		if offset+2 > len(cmap) {
+			continue
+		}
+		sub := cmap[offset:]
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		if !unicode {
+			continue
+		}
+		switch binary.BigEndian.Uint16(sub) {
+		case 4:
+			bmp = sub
+		case 12:
+			full = sub
+		}
+	}
+
+	switch {
+	case full != nil:
+		return parseCmap12(full)
+	case bmp != nil:
+		return parseCmap4(bmp)
+	}
+	return nil, fmt.Errorf("%w: no Unicode cmap", ErrUnsupportedFont)
+}
+
+func parseCmap4(sub []byte) (map[rune]uint16, error) {
+	if len(sub) < 14 {
+		return nil, fmt.Errorf("%w: truncated cmap format 4", ErrUnsupportedFont)
+	}
+	segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+	ends := 14
+	starts := ends + segCount*2 + 2
+	deltas := starts + segCount*2
+	rangeOffsets := deltas + segCount*2
+	if rangeOffsets+segCount*2 > len(sub) {
+		return nil, fmt.Errorf("%w: truncated cmap format 4", ErrUnsupportedFont)
+	}
+
+	glyphs := make(map[rune]uint16)
+	for i := 0; i < segCount; i++ {
+		end := int(binary.BigEndian.Uint16(sub[ends+i*2:]))
+		start := int(binary.BigEndian.Uint16(sub[starts+i*2:]))
+		delta := int(binary.BigEndian.Uint16(sub[deltas+i*2:]))
+		rangeOffset := int(binary.BigEndian.Uint16(sub[rangeOffsets+i*2:]))
+
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			var gid int
+			if rangeOffset == 0 {
+				gid = (c + delta) & 0xFFFF
+			} else {
+				// The offset is relative to this segment's idRangeOffset entry
+				addr := rangeOffsets + i*2 + rangeOffset + (c-start)*2
+				if addr+2 > len(sub) {
+					break
+				}
+				if gid = int(binary.BigEndian.Uint16(sub[addr:])); gid != 0 {
+					gid = (gid + delta) & 0xFFFF
+				}
+			}
+			if gid != 0 {
+				glyphs[rune(c)] = uint16(gid)
+			}
+		}
+	}
+	return glyphs, nil
+}
+
+func parseCmap12(sub []byte) (map[rune]uint16, error) {
+	if len(sub) < 16 {
+		return nil, fmt.Errorf("%w: truncated cmap format 12", ErrUnsupportedFont)
+	}
+	numGroups := int(binary.BigEndian.Uint32(sub[12:]))
+	if 16+numGroups*12 > len(sub) {
+		return nil, fmt.Errorf("%w: truncated cmap format 12", ErrUnsupportedFont)
+	}
+
+	glyphs := make(map[rune]uint16)
+	for i := 0; i < numGroups; i++ {
+		group := sub[16+i*12:]
+		start := binary.BigEndian.Uint32(group)
+		end := binary.BigEndian.Uint32(group[4:])
+		gid := binary.BigEndian.Uint32(group[8:])
+		if end < start || end > 0x10FFFF {
+			continue
+		}
+		for c := start; c <= end; c++ {
+			if g := gid + (c - start); g != 0 && g <= 0xFFFF {
+				glyphs[rune(c)] = uint16(g)
+			}
+		}
+	}
+	return glyphs, nil
+}
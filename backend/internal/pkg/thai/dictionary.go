@@ -0,0 +1,171 @@
+package thai
+
+// words maps Thai food vocabulary to romanizations, RTGS first followed by
+// common menu spellings that phonetic keys don't already cover. Compounds
+// such as ข้าวมันไก่ are left to segmentation so each part can be searched.
+var words = map[string][]string{
+	// Staples and cooking
+	"ข้าว":       {"khao"},
+	"สวย":        {"suai"},
+	"เหนียว":     {"niao"},
+	"กล้อง":      {"klong"},
+	"ต้ม":        {"tom"},
+	"ผัด":        {"phat"},
+	"ทอด":        {"thot"},
+	"ย่าง":       {"yang"},
+	"ปิ้ง":       {"ping"},
+	"นึ่ง":       {"nueng"},
+	"อบ":         {"op"},
+	"ตุ๋น":       {"tun"},
+	"คั่ว":       {"khua"},
+	"ราด":        {"rat"},
+	"หน้า":       {"na"},
+	"ยำ":         {"yam", "yum"},
+	"ตำ":         {"tam", "tum"},
+	"ลาบ":        {"lap", "larb"},
+	"ตก":         {"tok"},
+	"แกง":        {"kaeng", "gang"},
+	"ซุป":        {"sup", "soup"},
+	"จืด":        {"chuet"},
+	"เผ็ด":       {"phet"},
+	"หวาน":       {"wan"},
+	"เปรี้ยว":    {"priao"},
+	"เค็ม":       {"khem"},
+	"แซ่บ":       {"saep", "zap"},
+	"กรอบ":       {"krop"},
+	"สับ":        {"sap"},
+	"ใส":         {"sai"},
+	"ข้น":        {"khon"},
+	"น้ำ":        {"nam"},
+	"เส้น":       {"sen"},
+	"ใหญ่":       {"yai"},
+	"เล็ก":       {"lek"},
+	"หมี่":       {"mi"},
+	"บะหมี่":     {"bami"},
+	"ก๋วยเตี๋ยว": {"kuai tiao", "kuay teow"},
+	"วุ้นเส้น":   {"wun sen", "woon sen"},
+	"ขนมจีน":     {"khanom chin"},
+	"เย็นตาโฟ":   {"yen ta fo"},
+	"โจ๊ก":       {"chok", "joke"},
+	"เกี๊ยว":     {"kiao", "wonton"},
+
+	// Meat, seafood and eggs
+	"หมู":      {"mu"},
+	"ไก่":      {"kai"},
+	"เนื้อ":    {"nuea"},
+	"เป็ด":     {"pet"},
+	"กุ้ง":     {"kung"},
+	"ปลา":      {"pla"},
+	"หมึก":     {"muek"},
+	"ปู":       {"pu"},
+	"หอย":      {"hoi"},
+	"ทะเล":     {"thale"},
+	"ไข่":      {"khai"},
+	"ดาว":      {"dao"},
+	"เจียว":    {"chiao", "jeow"},
+	"ขา":       {"kha"},
+	"ซี่โครง":  {"si khrong"},
+	"สามชั้น":  {"sam chan"},
+	"แดง":      {"daeng"},
+	"สะเต๊ะ":   {"sate", "satay"},
+	"ลูกชิ้น":  {"luk chin"},
+	"ไส้กรอก":  {"sai krok"},
+	"ไส้อั่ว":  {"sai ua"},
+	"แหนม":     {"naem"},
+	"กุนเชียง": {"kun chiang"},
+	"เต้าหู้":  {"tao hu", "tofu"},
+
+	// Vegetables, herbs and seasoning
+	"ผัก":      {"phak"},
+	"บุ้ง":     {"bung"},
+	"คะน้า":    {"khana"},
+	"กะหล่ำ":   {"kalam"},
+	"ถั่ว":     {"thua"},
+	"งอก":      {"ngok"},
+	"มะเขือ":   {"makhuea"},
+	"เทศ":      {"thet"},
+	"หอม":      {"hom"},
+	"กระเทียม": {"krathiam"},
+	"พริก":     {"phrik"},
+	"กะเพรา":   {"kaphrao", "krapow"},
+	"กระเพรา":  {"kaphrao", "krapow"}, // common misspelling
+	"โหระพา":   {"horapha"},
+	"ตะไคร้":   {"takhrai"},
+	"ข่า":      {"kha"},
+	"มะนาว":    {"manao"},
+	"กะทิ":     {"kathi"},
+	"มะพร้าว":  {"maphrao"},
+	"ซีอิ๊ว":   {"si io", "see ew"},
+	"กะปิ":     {"kapi"},
+	"ร้า":      {"ra"},
+	"ไฟ":       {"fai"},
+	"เขียว":    {"khiao"},
+	"เหลือง":   {"lueang"},
+	"ป่า":      {"pa"},
+	"มัสมั่น":  {"matsaman", "massaman"},
+	"พะแนง":    {"phanaeng", "panang"},
+	"กะหรี่":   {"kari", "curry"},
+	"ผง":       {"phong"},
+	"ส้ม":      {"som"},
+	"มัน":      {"man"},
+	"ไทย":      {"thai"},
+	"จีน":      {"chin"},
+
+	// Fruit, desserts and drinks
+	"มะม่วง":  {"mamuang", "mango"},
+	"กล้วย":   {"kluai"},
+	"ทุเรียน": {"thurian", "durian"},
+	"มังคุด":  {"mangkhut"},
+	"ลำไย":    {"lamyai", "longan"},
+	"สับปะรด": {"sapparot"},
+	"แตงโม":   {"taeng mo"},
+	"ส้มโอ":   {"som o"},
+	"ขนม":     {"khanom"},
+	"ปัง":     {"pang"},
+	"จีบ":     {"chip"},
+	"บัวลอย":  {"bua loi"},
+	"ลอดช่อง": {"lot chong"},
+	"ทับทิม":  {"thapthim"},
+	"ไอศกรีม": {"aisakrim", "ice cream"},
+	"โรตี":    {"roti"},
+	"ซาลาเปา": {"salapao"},
+	"ชา":      {"cha"},
+	"กาแฟ":    {"kafae", "coffee"},
+	"นม":      {"nom"},
+	"เย็น":    {"yen"},
+	"มุก":     {"muk"},
+	"โกโก้":   {"koko", "cocoa"},
+	"สุกี้":   {"suki"},
+	"กระทะ":   {"kratha", "kata"},
+}
+
+// dictionary is words keyed by normalized spelling, so segmentation ignores
+// tone marks too
+var dictionary map[string][]string
+
+// maxWordLength is the longest dictionary word in runes
+var maxWordLength int
+
+func init() {
+	dictionary = make(map[string][]string, len(words))
+	for word, romanized := range words {
+		key := Normalize(word)
+		for _, r := range romanized {
+			if !contains(dictionary[key], r) {
+				dictionary[key] = append(dictionary[key], r)
+			}
+		}
+		if n := len([]rune(key)); n > maxWordLength {
+			maxWordLength = n
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package thai
+
+import (
+	"regexp"
+	"strings"
+)
+
+// romanFolds folds the spelling variations of romanized Thai: aspirated
+// and unaspirated consonants, g for k, j for ch, and the many ways of
+// writing the same vowel
+var romanFolds = strings.NewReplacer(
+	"ph", "p", "kh", "k", "th", "t", "bp", "p", "dt", "t", "ng", "ng",
+	"ch", "c", "sh", "c", "j", "c", "g", "k", "q", "k",
+	"x", "s", "z", "s", "v", "w",
+	"ow", "ao", "au", "ao", "aw", "ao", "ay", "ai",
+	"oo", "u", "ee", "i",
+)
+
+// RomanKey reduces a romanized Thai word to a phonetic key, so "krapow",
+// "kaphrao" and "gaprao" all become "kapao". Clusters like kr and pl are
+// often written without the r or l, and r after a vowel only marks a long
+// vowel, so both are dropped.
+func RomanKey(word string) string {
+	w := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return -1
+	}, word)
+	if w == "" {
+		return ""
+	}
+	w = romanFolds.Replace(w)
+
+	key := make([]byte, 0, len(w))
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+		if i > 0 {
+			switch {
+			case c == 'r' || c == 'h':
+				continue
+			case c == 'l' && !isRomanVowel(w[i-1]):
+				continue
+			}
+		}
+		if len(key) > 0 && key[len(key)-1] == c {
+			continue
+		}
+		key = append(key, c)
+	}
+
+	// Final d and b are pronounced t and p
+	switch key[len(key)-1] {
+	case 'd':
+		key[len(key)-1] = 't'
+	case 'b':
+		key[len(key)-1] = 'p'
+	}
+	return string(key)
+}
+
+func isRomanVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
+
+// Romanize transliterates the dictionary words in s, using their RTGS
+// spelling. Latin words are kept and unknown Thai words are left out.
+func Romanize(s string) string {
+	var out []string
+	for _, word := range Segment(s) {
+		if !ContainsThai(word) {
+			out = append(out, word)
+		} else if romanized, ok := dictionary[word]; ok {
+			out = append(out, romanized[0])
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// Key returns the phonetic keys of the words in s, Thai words by their RTGS
+// spelling, so a romanized query can be compared with a Thai name
+func Key(s string) string {
+	var keys []string
+	for _, word := range strings.Fields(Romanize(s)) {
+		if key := RomanKey(word); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return strings.Join(keys, " ")
+}
+
+// SearchText builds the text a food is searched by: the normalized words of
+// each name and the phonetic keys of every romanization of its Thai words
+// and of its latin words
+func SearchText(names ...string) string {
+	var tokens []string
+	seen := make(map[string]bool)
+	add := func(token string) {
+		if token != "" && !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, name := range names {
+		for _, word := range Segment(name) {
+			add(word)
+			if !ContainsThai(word) {
+				add(RomanKey(word))
+				continue
+			}
+			for _, romanized := range dictionary[word] {
+				for _, part := range strings.Fields(romanized) {
+					add(RomanKey(part))
+				}
+			}
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// Term is a query word and the forms it matches in SearchText
+type Term struct {
+	Word  string
+	Forms []string // the word, and for latin words its phonetic key
+	Thai  bool
+}
+
+// Terms splits a query into words for matching against SearchText
+func Terms(query string) []Term {
+	var terms []Term
+	for _, word := range Segment(query) {
+		term := Term{Word: word, Forms: []string{word}, Thai: ContainsThai(word)}
+		if key := RomanKey(word); !term.Thai && key != "" && key != word {
+			term.Forms = append(term.Forms, key)
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// Pattern is a POSIX regular expression matching the term in SearchText.
+// Latin terms match the start of a word, so partly typed words are found;
+// Thai terms match anywhere, as unknown Thai words are not split further.
+func (t Term) Pattern() string {
+	forms := make([]string, len(t.Forms))
+	for i, form := range t.Forms {
+		forms[i] = regexp.QuoteMeta(form)
+	}
+	pattern := "(" + strings.Join(forms, "|") + ")"
+	if t.Thai {
+		return pattern
+	}
+	return "(^| )" + pattern
+}
+
+// Fuzzy is the form of the term compared by trigram similarity: the
+// phonetic key of latin words, to tolerate typos in romanizations
+func (t Term) Fuzzy() string {
+	return t.Forms[len(t.Forms)-1]
+}
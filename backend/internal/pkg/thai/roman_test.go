@@ -0,0 +1,55 @@
+package thai
+
+import "testing"
+
+func TestRomanKey(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"krapow", "kapao"},
+		{"kaphrao", "kapao"},
+		{"gaprao", "kapao"},
+		{"Khao", "kao"},
+		{"moo", "mu"},
+		{"gai", "kai"},
+		{"phat", "pat"},
+		{"pad", "pat"},  // final d
+		{"larb", "lap"}, // final b, r after a vowel
+		{"ngok", "ngok"},
+		{"pla", "pa"},
+		{"thale", "tale"},
+		{"123", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := RomanKey(tt.word); got != tt.want {
+				t.Errorf("RomanKey(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRomanizeAndKey(t *testing.T) {
+	tests := []struct {
+		in        string
+		romanized string
+		key       string
+	}{
+		{"ผัดกะเพราหมู", "phat kaphrao mu", "pat kapao mu"},
+		{"ข้าวมันไก่", "khao man kai", "kao man kai"},
+		{"ข้าวผัดปูจ๋า", "khao phat pu", "kao pat pu"}, // unknown words are left out
+		{"Pad Thai", "pad thai", "pat tai"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := Romanize(tt.in); got != tt.romanized {
+				t.Errorf("Romanize(%q) = %q, want %q", tt.in, got, tt.romanized)
+			}
+			if got := Key(tt.in); got != tt.key {
+				t.Errorf("Key(%q) = %q, want %q", tt.in, got, tt.key)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package thai prepares Thai text for search: it folds away tone marks,
+// splits Thai into words using a food dictionary, and derives phonetic keys
+// so romanized spellings such as "krapow", "kaphrao" and "gaprao" match.
+package thai
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize folds case and whitespace and removes tone marks, along with the
+// mai taikhu and thanthakhat that are often left out when typing, so
+// spellings that differ only in those marks compare equal
+func Normalize(s string) string {
+	out := make([]rune, 0, len(s))
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case isIgnoredMark(r) || r == '\u200b': // zero width space
+			continue
+		case unicode.IsSpace(r):
+			space = len(out) > 0
+			continue
+		case r == 'า' && len(out) > 0 && out[len(out)-1] == '\u0e4d':
+			// Nikhahit and sara aa typed instead of sara am
+			out[len(out)-1] = 'ำ'
+			continue
+		}
+		if space {
+			out = append(out, ' ')
+			space = false
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// isIgnoredMark reports whether r is mai taikhu, a tone mark or thanthakhat
+func isIgnoredMark(r rune) bool {
+	return r >= '\u0e47' && r <= '\u0e4c'
+}
+
+// IsThai reports whether r is in the Thai block
+func IsThai(r rune) bool {
+	return r >= '\u0e00' && r <= '\u0e7f'
+}
+
+// ContainsThai reports whether s has any Thai characters
+func ContainsThai(s string) bool {
+	return strings.IndexFunc(s, IsThai) >= 0
+}
+
+// Segment normalizes s and splits it into words. Latin words and numbers are
+// split at spaces and punctuation; runs of Thai, which is written without
+// spaces, are split into dictionary words, keeping unknown stretches whole.
+func Segment(s string) []string {
+	var words []string
+	var run []rune
+	thaiRun := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if thaiRun {
+			words = append(words, segmentThai(run)...)
+		} else {
+			words = append(words, string(run))
+		}
+		run = run[:0]
+	}
+
+	for _, r := range Normalize(s) {
+		switch {
+		case r == 'ฯ' || r == 'ๆ': // paiyannoi and mai yamok end words
+			flush()
+		case IsThai(r):
+			if !thaiRun {
+				flush()
+				thaiRun = true
+			}
+			run = append(run, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if thaiRun {
+				flush()
+				thaiRun = false
+			}
+			run = append(run, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// segmentThai splits a run of Thai into words by maximal matching: the
+// split with the fewest characters outside dictionary words, then the
+// fewest words, wins
+func segmentThai(run []rune) []string {
+	type split struct {
+		unknown, words int
+		from           int // start of the last word
+		reached        bool
+	}
+	best := make([]split, len(run)+1)
+	best[0].reached = true
+	better := func(a, b split) bool {
+		if !b.reached {
+			return true
+		}
+		if a.unknown != b.unknown {
+			return a.unknown < b.unknown
+		}
+		return a.words < b.words
+	}
+
+	for i := 0; i < len(run); i++ {
+		if !best[i].reached {
+			continue
+		}
+		for n := 1; n <= maxWordLength && i+n <= len(run); n++ {
+			if _, ok := dictionary[string(run[i:i+n])]; !ok {
+				continue
+			}
+			next := split{unknown: best[i].unknown, words: best[i].words + 1, from: i, reached: true}
+			if better(next, best[i+n]) {
+				best[i+n] = next
+			}
+		}
+		// Skip one character as unknown
+		next := split{unknown: best[i].unknown + 1, words: best[i].words + 1, from: i, reached: true}
+		if better(next, best[i+1]) {
+			best[i+1] = next
+		}
+	}
+
+	var words []string
+	unknownEnd := -1 // end of a stretch of unknown characters being merged
+	for end := len(run); end > 0; {
+		from := best[end].from
+		word := string(run[from:end])
+		if _, known := dictionary[word]; known {
+			if unknownEnd >= 0 {
+				words = append(words, string(run[end:unknownEnd]))
+				unknownEnd = -1
+			}
+			words = append(words, word)
+		} else if unknownEnd < 0 {
+			unknownEnd = end
+		}
+		end = from
+	}
+	if unknownEnd >= 0 {
+		words = append(words, string(run[:unknownEnd]))
+	}
+
+	for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+		words[i], words[j] = words[j], words[i]
+	}
+	return words
+}
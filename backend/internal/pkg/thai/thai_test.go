@@ -0,0 +1,76 @@
+package thai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"tone marks", "ข้าวไก่", "ขาวไก"},
+		{"mai taikhu", "เป็ด", "เปด"},
+		{"thanthakhat", "สะเต๊ะ", "สะเตะ"},
+		{"nikhahit and sara aa", "นํา", "นำ"},
+		{"case and spaces", "  Pad   THAI ", "pad thai"},
+		{"zero width space", "ข้าว​มัน", "ขาวมัน"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"dictionary words", "ข้าวมันไก่", []string{"ขาว", "มัน", "ไก"}},
+		{"dish", "ผัดกะเพราหมูสับ", []string{"ผัด", "กะเพรา", "หมู", "สับ"}},
+		{"misspelling in dictionary", "ผัดกระเพราไก่ไข่ดาว", []string{"ผัด", "กระเพรา", "ไก", "ไข", "ดาว"}},
+		{"longest word wins", "ก๋วยเตี๋ยวเรือ", []string{"กวยเตียว", "เรือ"}},
+		{"unknown run kept whole", "ข้าวผัดปูจ๋า", []string{"ขาว", "ผัด", "ปู", "จา"}},
+		{"unknown runs around a word", "กกกข้าวคคค", []string{"กกก", "ขาว", "คคค"}},
+		{"latin and numbers", "Pad Thai ผัดไทย 2 จาน", []string{"pad", "thai", "ผัด", "ไทย", "2", "จาน"}},
+		{"punctuation splits latin", "  KAO  man-gai ", []string{"kao", "man", "gai"}},
+		{"mai yamok ends a word", "หมูๆไก่", []string{"หมู", "ไก"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Segment(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Segment(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSegmentThai(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string // normalized
+		want []string
+	}{
+		{"all known", "ขาวมันไกทอด", []string{"ขาว", "มัน", "ไก", "ทอด"}},
+		{"all unknown", "กขค", []string{"กขค"}},
+		{"fewest unknown characters", "วุนเสน", []string{"วุนเสน"}}, // not วุน เสน
+		{"fewest words", "ขนมจีน", []string{"ขนมจีน"}},              // not ขนม จีน
+		{"unknown prefix", "ฮฮขาว", []string{"ฮฮ", "ขาว"}},
+		{"unknown suffix", "ขาวฮฮ", []string{"ขาว", "ฮฮ"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := segmentThai([]rune(tt.in)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segmentThai(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}